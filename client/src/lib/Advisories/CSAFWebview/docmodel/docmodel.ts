@@ -161,6 +161,12 @@ const getTlp = (csafDoc: any): TLP => {
     case TLP.AMBER:
       label = TLP.AMBER;
       break;
+    case TLP.AMBER_STRICT:
+      label = TLP.AMBER_STRICT;
+      break;
+    case TLP.CLEAR:
+      label = TLP.CLEAR;
+      break;
     case TLP.GREEN:
       label = TLP.GREEN;
       break;
@@ -49,6 +49,8 @@ export const CSAFDocProps = {
 
 export const TLP = {
   AMBER: "AMBER",
+  AMBER_STRICT: "AMBER+STRICT",
+  CLEAR: "CLEAR",
   GREEN: "GREEN",
   RED: "RED",
   WHITE: "WHITE",
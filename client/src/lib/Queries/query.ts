@@ -23,6 +23,7 @@ const COLUMNS = {
     "tlp",
     "cvss_v2_score",
     "cvss_v3_score",
+    "cvss_v4_score",
     "ssvc",
     "four_cves",
     "state",
@@ -42,6 +43,7 @@ const COLUMNS = {
     "tlp",
     "cvss_v2_score",
     "cvss_v3_score",
+    "cvss_v4_score",
     "four_cves",
     "comments",
     "tracking_status"
@@ -58,6 +60,7 @@ const COLUMNS = {
     "tlp",
     "cvss_v2_score",
     "cvss_v3_score",
+    "cvss_v4_score",
     "ssvc",
     "four_cves",
     "comments",
@@ -83,6 +86,7 @@ enum SEARCHTYPES {
 const SEARCHPAGECOLUMNS = {
   ADVISORY: [
     "critical",
+    "cvss_v4_score",
     "cvss_v3_score",
     "cvss_v2_score",
     "ssvc",
@@ -100,6 +104,7 @@ const SEARCHPAGECOLUMNS = {
   ],
   DOCUMENT: [
     "critical",
+    "cvss_v4_score",
     "cvss_v3_score",
     "cvss_v2_score",
     "ssvc",
@@ -131,6 +136,7 @@ const SEARCHPAGECOLUMNS = {
     "tlp",
     "cvss_v2_score",
     "cvss_v3_score",
+    "cvss_v4_score",
     "ssvc",
     "four_cves"
   ]
@@ -0,0 +1,253 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package main implements a local CSAF provider for development and tests.
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+
+	"github.com/ISDuBA/ISDuBA/pkg/csafmock"
+	"github.com/ISDuBA/ISDuBA/pkg/version"
+)
+
+type options struct {
+	dir         string
+	addr        string
+	certFile    string
+	keyFile     string
+	writeCert   string
+	plain       bool
+	openPGPKey  string
+	badHashes   string
+	badSigs     string
+	slow        string
+	delay       time.Duration
+	errors      string
+	errorRate   float64
+	errorStatus int
+	showVersion bool
+}
+
+func compile(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
+
+func (o *options) faults() (csafmock.Faults, error) {
+	f := csafmock.Faults{
+		Delay:       o.delay,
+		ErrorRate:   o.errorRate,
+		ErrorStatus: o.errorStatus,
+	}
+	for _, re := range []struct {
+		expr string
+		dst  **regexp.Regexp
+	}{
+		{o.badHashes, &f.BadHashes},
+		{o.badSigs, &f.BadSignatures},
+		{o.slow, &f.Slow},
+		{o.errors, &f.Errors},
+	} {
+		var err error
+		if *re.dst, err = compile(re.expr); err != nil {
+			return csafmock.Faults{}, fmt.Errorf("invalid pattern %q: %w", re.expr, err)
+		}
+	}
+	return f, nil
+}
+
+func loadOpenPGPKey(file string) (*crypto.Key, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.NewKeyFromArmored(string(data))
+}
+
+// selfSigned creates a self-signed certificate for localhost.
+func selfSigned() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "csafmock"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+func writeCertificate(file string, cert *tls.Certificate) error {
+	return os.WriteFile(file, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Certificate[0],
+	}), 0644)
+}
+
+func run(o *options) error {
+	faults, err := o.faults()
+	if err != nil {
+		return err
+	}
+	docs, err := csafmock.LoadDocuments(o.dir)
+	if err != nil {
+		return fmt.Errorf("loading documents failed: %w", err)
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("no documents found in %q", o.dir)
+	}
+	key, err := loadOpenPGPKey(o.openPGPKey)
+	if err != nil {
+		return fmt.Errorf("loading OpenPGP key failed: %w", err)
+	}
+	provider, err := csafmock.NewProvider(docs, key)
+	if err != nil {
+		return err
+	}
+	provider.Faults = faults
+
+	srv := &http.Server{
+		Handler:           provider,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	scheme := "http"
+	if !o.plain {
+		scheme = "https"
+		var cert tls.Certificate
+		if o.certFile != "" {
+			cert, err = tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		} else {
+			cert, err = selfSigned()
+		}
+		if err != nil {
+			return fmt.Errorf("loading TLS certificate failed: %w", err)
+		}
+		if o.writeCert != "" {
+			if err := writeCertificate(o.writeCert, &cert); err != nil {
+				return fmt.Errorf("writing certificate failed: %w", err)
+			}
+		}
+		srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+
+	ln, err := net.Listen("tcp", o.addr)
+	if err != nil {
+		return err
+	}
+	base := scheme + "://" + ln.Addr().String()
+	slog.Info("serving documents", "number", len(docs), "dir", o.dir)
+	slog.Info("provider metadata", "url", base+csafmock.ProviderMetadataPath)
+	slog.Info("aggregator", "url", base+csafmock.AggregatorPath)
+	slog.Info("OpenPGP key", "fingerprint", provider.Fingerprint())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	done := make(chan error, 1)
+	go func() {
+		if o.plain {
+			done <- srv.Serve(ln)
+		} else {
+			done <- srv.ServeTLS(ln, "", "")
+		}
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-done; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func check(err error) {
+	if err != nil {
+		slog.Error("fatal", "err", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var o options
+	flag.StringVar(&o.dir, "dir", "docs/example-advisories", "directory with the CSAF documents to serve")
+	flag.StringVar(&o.addr, "addr", "localhost:8443", "address to listen on")
+	flag.StringVar(&o.certFile, "cert", "", "TLS certificate file (default self-signed)")
+	flag.StringVar(&o.keyFile, "key", "", "TLS key file")
+	flag.StringVar(&o.writeCert, "write-cert", "", "write the self-signed certificate to this file")
+	flag.BoolVar(&o.plain, "http", false, "serve plain HTTP instead of HTTPS")
+	flag.StringVar(&o.openPGPKey, "openpgp-key", "", "armored unlocked OpenPGP private key to sign with (default generated)")
+	flag.StringVar(&o.badHashes, "bad-hash", "", "serve wrong hashes for document paths matching this pattern")
+	flag.StringVar(&o.badSigs, "bad-signature", "", "serve wrong signatures for document paths matching this pattern")
+	flag.StringVar(&o.slow, "slow", "", "delay responses for paths matching this pattern")
+	flag.DurationVar(&o.delay, "delay", 5*time.Second, "delay of slow responses")
+	flag.StringVar(&o.errors, "errors", "", "fail requests for paths matching this pattern")
+	flag.Float64Var(&o.errorRate, "error-rate", 1, "probability of failing a matching request")
+	flag.IntVar(&o.errorStatus, "error-status", http.StatusServiceUnavailable, "status code of failing requests")
+	flag.BoolVar(&o.showVersion, "version", false, "show version information")
+	flag.Parse()
+	if o.showVersion {
+		fmt.Printf("%s version: %s\n", os.Args[0], version.SemVersion)
+		os.Exit(0)
+	}
+	if (o.certFile == "") != (o.keyFile == "") {
+		check(errors.New("-cert and -key have to be given together"))
+	}
+	check(run(&o))
+}
@@ -0,0 +1,134 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ISDuBA/ISDuBA/pkg/bundle"
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+)
+
+const bundleKeyUsage = "key to re-encrypt the secrets with (hex or @file)"
+
+// parseKeys parses the AES key of the configuration and the given bundle key.
+func parseKeys(ctx context.Context, cfg *config.Config, bundleKey string) ([]byte, []byte, error) {
+	var cipherKey, key []byte
+	var err error
+	if cfg.Sources.AESKey != "" {
+		if cipherKey, err = secrets.CipherKey(ctx, cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+	if bundleKey != "" {
+		if key, err = secrets.ParseCipherKey(bundleKey); err != nil {
+			return nil, nil, fmt.Errorf("bundle key is invalid: %w", err)
+		}
+	}
+	return cipherKey, key, nil
+}
+
+// openDB checks the migrations and opens the database.
+func openDB(ctx context.Context, cfg *config.Database) (*database.DB, error) {
+	if _, err := database.CheckMigrations(ctx, cfg); err != nil {
+		return nil, fmt.Errorf("migrating failed: %w", err)
+	}
+	return database.NewDB(ctx, cfg)
+}
+
+func exportBundle(cfg *config.Config, args []string) error {
+	var output, bundleKey string
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&output, "o", "-", "bundle file to write ('-' for stdout)")
+	fs.StringVar(&bundleKey, "key", "", bundleKeyUsage)
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cipherKey, key, err := parseKeys(ctx, cfg, bundleKey)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(ctx, &cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	if output == "-" {
+		return bundle.Export(ctx, db, os.Stdout, cipherKey, key, cfg.Comments.AttachmentsDir)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := bundle.Export(ctx, db, f, cipherKey, key, cfg.Comments.AttachmentsDir); err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+	return f.Close()
+}
+
+func importBundle(cfg *config.Config, args []string) error {
+	var bundleKey string
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.StringVar(&bundleKey, "key", "", bundleKeyUsage)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("missing bundle file ('-' for stdin)")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cipherKey, key, err := parseKeys(ctx, cfg, bundleKey)
+	if err != nil {
+		return err
+	}
+	if cipherKey == nil && key != nil {
+		return errors.New("sources.aes_key is needed to import secrets")
+	}
+
+	var r io.Reader = os.Stdin
+	if input := fs.Arg(0); input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if _, err := database.CheckMigrations(ctx, &cfg.Database); err != nil {
+		return fmt.Errorf("migrating failed: %w", err)
+	}
+
+	// Restoring the sequences needs the owner of the tables.
+	admin := cfg.Database
+	admin.User, admin.Password = admin.AdminUser, admin.AdminPassword
+	db, err := database.NewDB(ctx, &admin)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	return bundle.Import(ctx, db, r, cipherKey, key)
+}
@@ -26,9 +26,14 @@ import (
 	"github.com/ISDuBA/ISDuBA/pkg/aggregators"
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/enrichment"
 	"github.com/ISDuBA/ISDuBA/pkg/forwarder"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/peers"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
 	"github.com/ISDuBA/ISDuBA/pkg/sources"
 	"github.com/ISDuBA/ISDuBA/pkg/tempstore"
+	"github.com/ISDuBA/ISDuBA/pkg/trash"
 	"github.com/ISDuBA/ISDuBA/pkg/version"
 	"github.com/ISDuBA/ISDuBA/pkg/web"
 	"github.com/gocsaf/csaf/v3/csaf"
@@ -59,18 +64,37 @@ func run(cfg *config.Config) error {
 		return err
 	}
 	defer db.Close(ctx)
+	unlock, err := db.LockServer(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	tmpStore := tempstore.NewStore(&cfg.TempStore)
 	go tmpStore.Run(ctx)
 
-	forwardManager, err := forwarder.NewManager(cfg, db)
+	go trash.NewPurger(cfg, db).Run(ctx)
+
+	ssvcModels, err := models.LoadSSVCModels(cfg.SSVC.DefaultModel, cfg.SSVC.Models)
+	if err != nil {
+		return err
+	}
+
+	go peers.NewSyncer(&cfg.Sync, db, ssvcModels).Run(ctx)
+
+	go enrichment.NewImporter(cfg, db).Run(ctx)
+	go enrichment.NewCVSSIndexer(cfg, db).Run(ctx)
+
+	provider, err := secrets.New(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+
+	forwardManager, err := forwarder.NewManager(ctx, cfg, db, provider)
 	if err != nil {
 		return fmt.Errorf("creating forwarder failed: %w", err)
 	}
 	go forwardManager.Run(ctx)
 
-	agg := aggregators.NewManager(cfg, db)
-	go agg.Run(ctx)
-
 	// Is the remote validator configured?
 	var val csaf.RemoteValidator
 	if cfg.RemoteValidator.URL != "" {
@@ -83,15 +107,15 @@ func run(cfg *config.Config) error {
 	}
 
 	// Setup the source manager.
-	sm, err := sources.NewManager(cfg, db, val)
-	if err != nil {
-		return fmt.Errorf("creating source manager failed: %w", err)
-	}
+	sm := sources.NewManager(cfg, db, val, provider)
 	if err := sm.Boot(ctx); err != nil {
 		return fmt.Errorf("booting source manager failed: %w", err)
 	}
 	go sm.Run(ctx)
 
+	agg := aggregators.NewManager(cfg, db, sm)
+	go agg.Run(ctx)
+
 	cfg.Web.Configure()
 
 	ctrl := web.NewController(
@@ -101,6 +125,7 @@ func run(cfg *config.Config) error {
 		sm,
 		agg,
 		val,
+		ssvcModels,
 	)
 
 	addr := cfg.Web.Addr()
@@ -171,5 +196,16 @@ func main() {
 	cfg, err := config.Load(cfgFile)
 	check(err)
 	check(cfg.Log.Config())
-	check(run(cfg))
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+		check(run(cfg))
+	case "export":
+		check(exportBundle(cfg, flag.Args()[1:]))
+	case "import":
+		check(importBundle(cfg, flag.Args()[1:]))
+	case "rekey":
+		check(rekey(cfg, flag.Args()[1:]))
+	default:
+		check(fmt.Errorf("unknown command %q", cmd))
+	}
 }
@@ -0,0 +1,93 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+)
+
+const rekeyMsg = `================================================================
+Re-encrypted %d secrets. The new key is:
+%s
+Replace the old key
+  [sources]
+  aes_key = "<hex string above>"
+in your configuration or the file referenced by it before
+starting isdubad again or the secrets will not be accessible any more.
+================================================================
+`
+
+// rekey re-encrypts the secrets stored in the database with a new key.
+// It refuses to run while a server is running as the server keeps the old key.
+func rekey(cfg *config.Config, args []string) error {
+	var newKey string
+	fs := flag.NewFlagSet("rekey", flag.ExitOnError)
+	fs.StringVar(&newKey, "key", "", "new key (hex or @file, default generated)")
+	fs.Parse(args)
+
+	if cfg.Secrets.Provider != config.SecretsDatabase {
+		return fmt.Errorf(
+			"secrets are stored by provider %q: nothing to re-encrypt",
+			cfg.Secrets.Provider)
+	}
+	if cfg.Sources.AESKey == "" {
+		return errors.New("sources.aes_key is needed to decrypt the secrets")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	oldKey, err := secrets.CipherKey(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	var key []byte
+	if newKey == "" {
+		key, err = secrets.NewCipherKey()
+	} else {
+		key, err = secrets.ParseCipherKey(newKey)
+	}
+	if err != nil {
+		return fmt.Errorf("new key is invalid: %w", err)
+	}
+
+	db, err := openDB(ctx, &cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	// A running server would keep encrypting with the old key.
+	unlock, err := db.LockOffline(ctx)
+	if errors.Is(err, database.ErrServerRunning) {
+		return errors.New("isdubad is running: stop it before re-encrypting the secrets")
+	}
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	n, err := secrets.NewDatabase(db, oldKey).Rekey(ctx, key)
+	if err != nil {
+		return fmt.Errorf("re-encrypting secrets failed: %w", err)
+	}
+	fmt.Printf(rekeyMsg, n, hex.EncodeToString(key))
+	return nil
+}
@@ -0,0 +1,51 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package aggregators
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/csafmock"
+)
+
+func TestGetAggregator(t *testing.T) {
+	docs, err := csafmock.LoadDocuments("../../docs/example-advisories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := csafmock.NewProvider(docs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(provider)
+	defer server.Close()
+
+	var cfg config.Config
+	cache := newCache(0)
+	url := server.URL + csafmock.AggregatorPath
+
+	provider.Faults.Errors = regexp.MustCompile(`aggregator\.json$`)
+	provider.Faults.ErrorRate = 1
+	if _, err := cache.GetAggregator(url, &cfg); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	provider.Faults.Errors = nil
+	ca, err := cache.GetAggregator(url, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if urls := ca.SourceURLs(); !slices.Contains(urls, server.URL+csafmock.ProviderMetadataPath) {
+		t.Errorf("unexpected source URLs: %q", urls)
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/sources"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -34,15 +35,17 @@ type Manager struct {
 	fns  chan func(*Manager)
 	cfg  *config.Config
 	db   *database.DB
+	sm   *sources.Manager
 }
 
 // NewManager creates a new aggregators manager.
-func NewManager(cfg *config.Config, db *database.DB) *Manager {
+func NewManager(cfg *config.Config, db *database.DB, sm *sources.Manager) *Manager {
 	return &Manager{
 		Cache: newCache(cfg.Aggregators.Timeout),
 		fns:   make(chan func(*Manager)),
 		cfg:   cfg,
 		db:    db,
+		sm:    sm,
 	}
 }
 
@@ -79,13 +82,15 @@ func aggregatorChecksum(cagg *CachedAggregator) []byte {
 
 func (m *Manager) refresh(ctx context.Context) {
 	type aggregator struct {
-		id          int64
-		url         string
-		checksum    []byte
-		newChecksum []byte
+		id           int64
+		url          string
+		checksum     []byte
+		newChecksum  []byte
+		provisioning Provisioning
+		sourceURLs   []string
 	}
 	const (
-		selectSQL = `SELECT id, url, checksum FROM aggregators`
+		selectSQL = `SELECT id, url, checksum, active, provisioning::text FROM aggregators`
 		updateSQL = `UPDATE aggregators ` +
 			`SET (checksum, checksum_updated) = ($1, $2) ` +
 			`WHERE id = $3 AND active = TRUE`
@@ -97,8 +102,15 @@ func (m *Manager) refresh(ctx context.Context) {
 			rows, _ := conn.Query(ctx, selectSQL)
 			var err error
 			aggregators, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (aggregator, error) {
-				var agg aggregator
-				err := row.Scan(&agg.id, &agg.url, &agg.checksum)
+				var (
+					agg    aggregator
+					active bool
+				)
+				err := row.Scan(&agg.id, &agg.url, &agg.checksum, &active, &agg.provisioning)
+				// Only active aggregators provision sources.
+				if !active {
+					agg.provisioning = ProvisioningOff
+				}
 				return agg, err
 			})
 			return err
@@ -124,6 +136,9 @@ func (m *Manager) refresh(ctx context.Context) {
 				continue
 			}
 			agg.newChecksum = aggregatorChecksum(cagg)
+			if agg.provisioning != ProvisioningOff {
+				agg.sourceURLs = cagg.SourceURLs()
+			}
 		}
 	}
 	for range numWorkers {
@@ -145,9 +160,19 @@ func (m *Manager) refresh(ctx context.Context) {
 			batch.Queue(updateSQL, agg.newChecksum, now, agg.id)
 		}
 	}
-	if batch.Len() == 0 {
-		return
+	if batch.Len() > 0 {
+		m.storeChecksums(ctx, &batch)
 	}
+	for i := range aggregators {
+		// Aggregators which could not be fetched are skipped.
+		if agg := &aggregators[i]; agg.sourceURLs != nil {
+			m.provision(ctx, agg.id, agg.provisioning, agg.sourceURLs)
+		}
+	}
+}
+
+// storeChecksums stores the updated checksums in one transaction.
+func (m *Manager) storeChecksums(ctx context.Context, batch *pgx.Batch) {
 	if err := m.db.Run(
 		ctx,
 		func(ctx context.Context, conn *pgxpool.Conn) error {
@@ -156,7 +181,7 @@ func (m *Manager) refresh(ctx context.Context) {
 				return err
 			}
 			defer tx.Rollback(ctx)
-			if err := tx.SendBatch(ctx, &batch).Close(); err != nil {
+			if err := tx.SendBatch(ctx, batch).Close(); err != nil {
 				return err
 			}
 			return tx.Commit(ctx)
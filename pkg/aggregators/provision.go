@@ -0,0 +1,320 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package aggregators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/sources"
+)
+
+// Provisioning is the mode of the automatic source provisioning of an aggregator.
+type Provisioning string
+
+// The provisioning modes.
+const (
+	ProvisioningOff    Provisioning = "off"    // ProvisioningOff disables the provisioning.
+	ProvisioningReview Provisioning = "review" // ProvisioningReview queues the actions for review.
+	ProvisioningAuto   Provisioning = "auto"   // ProvisioningAuto applies the actions directly.
+)
+
+// The kinds of provisioning actions.
+const (
+	ActionCreateSource     = "create_source"
+	ActionDeactivateSource = "deactivate_source"
+	ActionActivateSource   = "activate_source"
+)
+
+// ErrNoSuchAction is returned if there is no pending action with a given id.
+var ErrNoSuchAction = errors.New("no such pending action")
+
+// ParseProvisioning parses a provisioning mode.
+func ParseProvisioning(s string) (Provisioning, error) {
+	switch p := Provisioning(s); p {
+	case ProvisioningOff, ProvisioningReview, ProvisioningAuto:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid provisioning mode %q", s)
+	}
+}
+
+// provision compares the source URLs listed by an aggregator with the
+// sources already provisioned from it and queues the needed actions.
+// Every action is only queued once per aggregator and URL so that
+// rejected actions and manually changed sources are left alone.
+// This is reset if a URL disappears from or reappears in the listing.
+func (m *Manager) provision(
+	ctx context.Context,
+	aggregatorID int64,
+	mode Provisioning,
+	urls []string,
+) {
+	const (
+		listedSQL      = `SELECT listed FROM aggregators WHERE id = $1 FOR UPDATE`
+		storeListedSQL = `UPDATE aggregators SET listed = $2 WHERE id = $1`
+		knownSQL       = `SELECT url FROM sources`
+		queuedSQL      = `SELECT action::text, url, state = 'pending' ` +
+			`FROM aggregator_actions WHERE aggregators_id = $1`
+		provisionedSQL = `SELECT DISTINCT sources.id, sources.url, sources.active ` +
+			`FROM aggregator_actions JOIN sources ON aggregator_actions.sources_id = sources.id ` +
+			`WHERE aggregators_id = $1 AND action = 'create_source' AND state = 'applied'`
+		// The sources which are deactivated by the last applied action of the aggregator.
+		deactivatedSQL = `SELECT sources_id FROM (` +
+			`SELECT DISTINCT ON (sources_id) sources_id, action FROM aggregator_actions ` +
+			`WHERE aggregators_id = $1 AND state = 'applied' AND sources_id IS NOT NULL ` +
+			`AND action IN ('deactivate_source', 'activate_source') ` +
+			`ORDER BY sources_id, id DESC) last ` +
+			`WHERE action = 'deactivate_source'`
+		// Drop pending actions which are outdated by the current listing.
+		outdatedSQL = `DELETE FROM aggregator_actions ` +
+			`WHERE aggregators_id = $1 AND state = 'pending' AND (` +
+			`(action IN ('create_source', 'activate_source') AND url <> ALL($2)) OR ` +
+			`(action = 'deactivate_source' AND url = ANY($2)))`
+		queueSQL = `INSERT INTO aggregator_actions (aggregators_id, action, url, sources_id) ` +
+			`VALUES ($1, $2::aggregator_action, $3, $4)`
+		pendingSQL = `SELECT id FROM aggregator_actions ` +
+			`WHERE aggregators_id = $1 AND state = 'pending' ORDER BY id`
+	)
+	type key struct{ action, url string }
+	var pending []int64
+	if err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.Begin(rctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			var listed []string
+			if err := tx.QueryRow(rctx, listedSQL, aggregatorID).Scan(&listed); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, outdatedSQL, aggregatorID, urls); err != nil {
+				return err
+			}
+			rows, _ := tx.Query(rctx, knownSQL)
+			known, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return err
+			}
+			queued := map[key]bool{}
+			rows, _ = tx.Query(rctx, queuedSQL, aggregatorID)
+			var (
+				k         key
+				isPending bool
+			)
+			if _, err := pgx.ForEachRow(rows, []any{&k.action, &k.url, &isPending}, func() error {
+				// Decisions made before the URL changed its presence do not count.
+				if isPending || !presenceChanged(listed, urls, k.url) {
+					queued[k] = true
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			rows, _ = tx.Query(rctx, deactivatedSQL, aggregatorID)
+			deactivated, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+			if err != nil {
+				return err
+			}
+			var batch pgx.Batch
+			for _, url := range urls {
+				if !slices.Contains(known, url) && !queued[key{ActionCreateSource, url}] {
+					batch.Queue(queueSQL, aggregatorID, ActionCreateSource, url, nil)
+				}
+			}
+			var (
+				sourceID int64
+				url      string
+				active   bool
+			)
+			rows, _ = tx.Query(rctx, provisionedSQL, aggregatorID)
+			if _, err := pgx.ForEachRow(rows, []any{&sourceID, &url, &active}, func() error {
+				switch isListed := slices.Contains(urls, url); {
+				case !isListed && active && !queued[key{ActionDeactivateSource, url}]:
+					batch.Queue(queueSQL, aggregatorID, ActionDeactivateSource, url, sourceID)
+				case isListed && !active && slices.Contains(deactivated, sourceID) &&
+					!queued[key{ActionActivateSource, url}]:
+					batch.Queue(queueSQL, aggregatorID, ActionActivateSource, url, sourceID)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, storeListedSQL, aggregatorID, urls); err != nil {
+				return err
+			}
+			if batch.Len() > 0 {
+				if err := tx.SendBatch(rctx, &batch).Close(); err != nil {
+					return err
+				}
+			}
+			if mode == ProvisioningAuto {
+				rows, _ := tx.Query(rctx, pendingSQL, aggregatorID)
+				if pending, err = pgx.CollectRows(rows, pgx.RowTo[int64]); err != nil {
+					return err
+				}
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		slog.Error("provisioning sources failed", "aggregator", aggregatorID, "error", err)
+		return
+	}
+	// Failed actions stay pending and are retried on the next refresh.
+	for _, id := range pending {
+		if err := m.apply(ctx, id, nil); err != nil {
+			slog.Warn("applying aggregator action failed", "id", id, "error", err)
+		}
+	}
+}
+
+// presenceChanged checks if a URL was listed at the last provisioning
+// and is not listed any more or the other way round. Nothing has
+// changed if there was no provisioning before.
+func presenceChanged(listed, urls []string, url string) bool {
+	return listed != nil && slices.Contains(listed, url) != slices.Contains(urls, url)
+}
+
+// apply applies a pending action.
+func (m *Manager) apply(ctx context.Context, id int64, actor *string) error {
+	const (
+		loadSQL = `SELECT action::text, url, sources_id FROM aggregator_actions ` +
+			`WHERE id = $1 AND state = 'pending'`
+		appliedSQL = `UPDATE aggregator_actions ` +
+			`SET state = 'applied', decided = current_timestamp, ` +
+			`sources_id = $2, actor = $3, message = $4 ` +
+			`WHERE id = $1`
+		failedSQL = `UPDATE aggregator_actions SET message = $2 WHERE id = $1`
+		eventSQL  = `INSERT INTO events_log (event, actor, sources_id) ` +
+			`VALUES ($1::events, $2, $3)`
+	)
+	var (
+		action   string
+		url      string
+		sourceID *int64
+	)
+	if err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			return conn.QueryRow(rctx, loadSQL, id).Scan(&action, &url, &sourceID)
+		}, 0,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNoSuchAction
+		}
+		return err
+	}
+
+	var (
+		message *string
+		failure error
+	)
+	switch action {
+	case ActionCreateSource:
+		newID, err := m.sm.ProvisionSource(url, &m.cfg.Aggregators.Provisioning)
+		if err != nil && newID == 0 {
+			failure = err
+			break
+		}
+		if err != nil {
+			// The source exists so the action is done.
+			msg := err.Error()
+			message = &msg
+		}
+		sourceID = &newID
+	case ActionDeactivateSource, ActionActivateSource:
+		var err error
+		if sourceID != nil {
+			_, err = m.sm.UpdateSource(*sourceID, func(su *sources.SourceUpdater) error {
+				return su.UpdateActive(action == ActionActivateSource)
+			})
+		}
+		if sourceID == nil || errors.Is(err, sources.NoSuchEntryError("")) {
+			msg := "source does not exist any more"
+			message, sourceID = &msg, nil
+		} else if err != nil {
+			failure = err
+		}
+	}
+
+	if err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			if failure != nil {
+				_, err := conn.Exec(rctx, failedSQL, id, failure.Error())
+				return err
+			}
+			tx, err := conn.Begin(rctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			if _, err := tx.Exec(rctx, appliedSQL, id, sourceID, actor, message); err != nil {
+				return err
+			}
+			// Applied actions are logged as events, too.
+			if _, err := tx.Exec(rctx, eventSQL, action, actor, sourceID); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		return err
+	}
+	if failure != nil {
+		return failure
+	}
+	slog.Info("aggregator action applied", "id", id, "action", action, "url", url)
+	return nil
+}
+
+// reject rejects a pending action.
+func (m *Manager) reject(ctx context.Context, id int64, actor *string) error {
+	const rejectSQL = `UPDATE aggregator_actions ` +
+		`SET state = 'rejected', decided = current_timestamp, actor = $2 ` +
+		`WHERE id = $1 AND state = 'pending'`
+	var rejected bool
+	if err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tag, err := conn.Exec(rctx, rejectSQL, id, actor)
+			rejected = tag.RowsAffected() > 0
+			return err
+		}, 0,
+	); err != nil {
+		return err
+	}
+	if !rejected {
+		return ErrNoSuchAction
+	}
+	return nil
+}
+
+// DecideAction applies or rejects a pending action.
+// It is run inside the manager so that it does not
+// interfere with the provisioning.
+func (m *Manager) DecideAction(ctx context.Context, id int64, actor *string, accept bool) error {
+	errCh := make(chan error)
+	m.fns <- func(m *Manager) {
+		if accept {
+			errCh <- m.apply(ctx, id, actor)
+		} else {
+			errCh <- m.reject(ctx, id, actor)
+		}
+	}
+	return <-errCh
+}
@@ -0,0 +1,32 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package aggregators
+
+import "testing"
+
+func TestPresenceChanged(t *testing.T) {
+	const a, b = "https://a.example/provider-metadata.json", "https://b.example/provider-metadata.json"
+	for _, x := range []struct {
+		name         string
+		listed, urls []string
+		url          string
+		expected     bool
+	}{
+		{"first provisioning", nil, []string{a}, a, false},
+		{"still listed", []string{a, b}, []string{a, b}, a, false},
+		{"still missing", []string{b}, []string{b}, a, false},
+		{"disappeared", []string{a, b}, []string{b}, a, true},
+		{"reappeared", []string{b}, []string{a, b}, a, true},
+		{"empty listing before", []string{}, []string{a}, a, true},
+	} {
+		if got := presenceChanged(x.listed, x.urls, x.url); got != x.expected {
+			t.Errorf("%s: expected %t got %t", x.name, x.expected, got)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package bundle implements the export and import of the state
+// of isdubad as a portable bundle.
+//
+// A bundle is a gzipped tar archive. It contains a manifest,
+// the configuration tables as JSON lines, the original documents
+// with their signatures and the state attached to the documents.
+// Everything which can be derived from the original documents
+// is rebuilt on import.
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+)
+
+// formatVersion is the version of the bundle format.
+// It has to be increased if the set of tables or their
+// layout change. Bundles of older formats are still
+// importable as they only lack tables.
+const formatVersion = 3
+
+const (
+	manifestFile = "manifest.json"
+	documentsDir = "documents/"
+	tableSuffix  = ".jsonl"
+	metaSuffix   = ".meta.json"
+	sigSuffix    = ".json.asc"
+	docSuffix    = ".json"
+)
+
+// keyCheck is encrypted with the bundle key to
+// detect a wrong key before anything is imported.
+const keyCheck = "ISDuBA bundle"
+
+// manifest describes a bundle.
+type manifest struct {
+	Format   int       `json:"format"`
+	Created  time.Time `json:"created"`
+	Version  string    `json:"version"`
+	Database int64     `json:"database"`
+	KeyCheck string    `json:"key_check,omitempty"`
+}
+
+// documentMeta is stored in front of every document.
+type documentMeta struct {
+	ID       int64      `json:"id"`
+	Filename *string    `json:"filename,omitempty"`
+	Deleted  *time.Time `json:"deleted,omitempty"`
+}
+
+// table is a database table which is stored as JSON lines.
+type table struct {
+	name  string
+	order string
+}
+
+// configTables are written in front of the documents
+// in the order needed to satisfy the foreign keys.
+var configTables = []table{
+	{name: "sources", order: "id"},
+	{name: "source_keys", order: "sources_id, fingerprint"},
+	{name: "source_keys_log", order: "id"},
+	{name: "secrets", order: "name"},
+	{name: "feeds", order: "id"},
+	{name: "download_queue", order: "feeds_id, url"},
+	{name: "source_pmds", order: "id"},
+	{name: "aggregators", order: "id"},
+	{name: "aggregator_actions", order: "id"},
+	{name: "stored_queries", order: "id"},
+	{name: "default_query_exclusion", order: `"user", id`},
+	{name: "sync_peers", order: "id"},
+	{name: "tags", order: "name"},
+}
+
+// stateTables are written after the documents as they refer to them.
+// The advisories come after the tables referring to the documents to
+// overwrite the state which is reset by importing the documents.
+// The triage data and the vulnerability cases of the advisories
+// follow as they refer to them.
+var stateTables = []table{
+	{name: "comments", order: "id"},
+	{name: "comment_mentions", order: "comments_id, mentioned"},
+	{name: "sync_comments", order: "sync_peers_id, remote_id"},
+	{name: "comment_reactions", order: "comments_id, actor, reaction"},
+	{name: "comment_attachments", order: "id"},
+	{name: "ssvc_history", order: "documents_id, change_number"},
+	{name: "ssvc_suggestions", order: "documents_id"},
+	{name: "downloads", order: "time, feeds_id, documents_id"},
+	{name: "quarantine", order: "id"},
+	{name: "events_log", order: "id"},
+	{name: "advisories", order: "id"},
+	{name: "advisory_checklists", order: "advisories_id, num"},
+	{name: "advisory_notes", order: "id"},
+	{name: "advisory_tags", order: "advisories_id, tag"},
+	{name: "vulnerability_cases", order: "id"},
+	{name: "vulnerability_case_advisories", order: "cases_id, advisories_id"},
+}
+
+// legacySecrets are the columns of the sources in bundles of older
+// versions which are stored in the secrets table now.
+var legacySecrets = []string{
+	"client_cert_private",
+	"client_cert_passphrase",
+}
+
+// recryptValue re-encrypts a hex encoded bytea value from one key to another.
+func recryptValue(from, to []byte, value string) ([]byte, error) {
+	if from == nil || to == nil {
+		return nil, errors.New("re-encrypting secrets needs both keys")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(value, `\x`))
+	if err != nil {
+		return nil, err
+	}
+	if data, err = secrets.Decrypt(from, data); err != nil {
+		return nil, err
+	}
+	return secrets.Encrypt(to, data)
+}
+
+// recrypt returns a function which re-encrypts the
+// given fields of a row from one key to another.
+func recrypt(from, to []byte, fields ...string) func(map[string]any) error {
+	return func(row map[string]any) error {
+		for _, field := range fields {
+			value, ok := row[field].(string)
+			if !ok {
+				continue
+			}
+			data, err := recryptValue(from, to, value)
+			if err != nil {
+				return err
+			}
+			row[field] = `\x` + hex.EncodeToString(data)
+		}
+		return nil
+	}
+}
+
+// writeFile writes a regular file into the tar archive.
+func writeFile(tw *tar.Writer, name string, modified time.Time, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o640,
+		Size:     int64(len(data)),
+		ModTime:  modified,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
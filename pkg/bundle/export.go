@@ -0,0 +1,236 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+	"github.com/ISDuBA/ISDuBA/pkg/version"
+)
+
+// Export writes the state of the database as a bundle to w.
+// The secrets stored in the database are decrypted with cipherKey and
+// re-encrypted with bundleKey. Both may be nil if there are no
+// secrets to export. The comment attachments stored as files
+// are read from attachmentsDir and stored in the bundle.
+func Export(
+	ctx context.Context,
+	db *database.DB,
+	w io.Writer,
+	cipherKey, bundleKey []byte,
+	attachmentsDir string,
+) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now().UTC()
+
+	if err := db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			// Use one snapshot for the whole bundle.
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{
+				IsoLevel:   pgx.RepeatableRead,
+				AccessMode: pgx.ReadOnly,
+			})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+
+			if err := exportManifest(rctx, tx, tw, now, bundleKey); err != nil {
+				return fmt.Errorf("exporting manifest failed: %w", err)
+			}
+			for _, t := range configTables {
+				var transform func(map[string]any) error
+				if t.name == "secrets" {
+					transform = recrypt(cipherKey, bundleKey, "value")
+				}
+				if err := exportTable(rctx, tx, tw, now, t, transform); err != nil {
+					return fmt.Errorf("exporting %s failed: %w", t.name, err)
+				}
+			}
+			if err := exportDocuments(rctx, tx, tw, now); err != nil {
+				return fmt.Errorf("exporting documents failed: %w", err)
+			}
+			for _, t := range stateTables {
+				var transform func(map[string]any) error
+				if t.name == "comment_attachments" {
+					transform = inlineAttachment(attachmentsDir)
+				}
+				if err := exportTable(rctx, tx, tw, now, t, transform); err != nil {
+					return fmt.Errorf("exporting %s failed: %w", t.name, err)
+				}
+			}
+			return nil
+		}, 0,
+	); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func exportManifest(
+	ctx context.Context,
+	tx pgx.Tx,
+	tw *tar.Writer,
+	now time.Time,
+	bundleKey []byte,
+) error {
+	m := manifest{
+		Format:  formatVersion,
+		Created: now,
+		Version: version.SemVersion,
+	}
+	const versionSQL = `SELECT max(version) FROM versions`
+	if err := tx.QueryRow(ctx, versionSQL).Scan(&m.Database); err != nil {
+		return err
+	}
+	if bundleKey != nil {
+		check, err := secrets.Encrypt(bundleKey, []byte(keyCheck))
+		if err != nil {
+			return err
+		}
+		m.KeyCheck = hex.EncodeToString(check)
+	}
+	data, err := json.MarshalIndent(&m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(tw, manifestFile, now, data)
+}
+
+// exportTable writes all rows of a table as JSON lines.
+// If transform is not nil it is applied to every row.
+func exportTable(
+	ctx context.Context,
+	tx pgx.Tx,
+	tw *tar.Writer,
+	now time.Time,
+	t table,
+	transform func(map[string]any) error,
+) error {
+	exportSQL := `SELECT row_to_json(t) FROM ` + t.name + ` t ORDER BY ` + t.order
+	rows, _ := tx.Query(ctx, exportSQL)
+	defer rows.Close()
+
+	// The size of a file has to be known in front.
+	var buf bytes.Buffer
+	for rows.Next() {
+		var line []byte
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		if transform != nil {
+			var row map[string]any
+			if err := unmarshal(line, &row); err != nil {
+				return err
+			}
+			if err := transform(row); err != nil {
+				return err
+			}
+			var err error
+			if line, err = json.Marshal(row); err != nil {
+				return err
+			}
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return writeFile(tw, t.name+tableSuffix, now, buf.Bytes())
+}
+
+// inlineAttachment returns a function which replaces the path
+// of an attachment stored as a file by the content of the file.
+func inlineAttachment(dir string) func(map[string]any) error {
+	return func(row map[string]any) error {
+		path, ok := row["path"].(string)
+		if !ok {
+			return nil
+		}
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			return fmt.Errorf("reading attachment failed: %w", err)
+		}
+		row["data"] = `\x` + hex.EncodeToString(data)
+		row["path"] = nil
+		return nil
+	}
+}
+
+// exportDocuments writes the original documents each one
+// preceded by its meta data and its signature.
+func exportDocuments(
+	ctx context.Context,
+	tx pgx.Tx,
+	tw *tar.Writer,
+	now time.Time,
+) error {
+	const documentsSQL = `SELECT id, filename, deleted, signature, original ` +
+		`FROM documents ORDER BY id`
+	rows, _ := tx.Query(ctx, documentsSQL)
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			meta      documentMeta
+			signature []byte
+			original  []byte
+		)
+		if err := rows.Scan(
+			&meta.ID, &meta.Filename, &meta.Deleted,
+			&signature, &original,
+		); err != nil {
+			return err
+		}
+		data, err := json.Marshal(&meta)
+		if err != nil {
+			return err
+		}
+		prefix := fmt.Sprintf("%s%d", documentsDir, meta.ID)
+		if err := writeFile(tw, prefix+metaSuffix, now, data); err != nil {
+			return err
+		}
+		if signature != nil {
+			if err := writeFile(tw, prefix+sigSuffix, now, signature); err != nil {
+				return err
+			}
+		}
+		if err := writeFile(tw, prefix+docSuffix, now, original); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// unmarshal decodes JSON keeping the numbers as they are.
+func unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,531 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+)
+
+// importer holds the state while importing a bundle.
+type importer struct {
+	tx        pgx.Tx
+	cipherKey []byte
+	bundleKey []byte
+
+	manifest  *manifest
+	meta      *documentMeta
+	signature []byte
+	// docIDs maps the ids of the documents in the bundle
+	// to the ids of the imported documents.
+	docIDs map[int64]int64
+	// advIDs maps the ids of the advisories in the bundle
+	// to the ids of the advisories created by the import.
+	advIDs map[int64]int64
+	// deleted are the trashed documents. They are moved
+	// to the trash bin after all comments are imported.
+	deleted map[int64]time.Time
+	// tables are the tables which are imported.
+	tables []string
+	// secrets are the credentials of the sources
+	// found in bundles of older versions.
+	secrets map[string][]byte
+}
+
+// Import restores the state stored in a bundle read from r into
+// a database without documents. The secrets are decrypted
+// with bundleKey and re-encrypted with cipherKey.
+// The bundle is imported in one transaction so nothing is
+// changed if the import fails.
+// As the table sequences have to be adjusted the database connection
+// needs to be owner of the tables.
+func Import(
+	ctx context.Context,
+	db *database.DB,
+	r io.Reader,
+	cipherKey, bundleKey []byte,
+) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("bundle is not gzipped: %w", err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	return db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.Begin(rctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			im := importer{
+				tx:        tx,
+				cipherKey: cipherKey,
+				bundleKey: bundleKey,
+				docIDs:    map[int64]int64{},
+				advIDs:    map[int64]int64{},
+				deleted:   map[int64]time.Time{},
+				secrets:   map[string][]byte{},
+			}
+			if err := im.checkEmpty(rctx); err != nil {
+				return err
+			}
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return fmt.Errorf("reading bundle failed: %w", err)
+				}
+				if hdr.Typeflag != tar.TypeReg {
+					continue
+				}
+				if err := im.entry(rctx, hdr.Name, tr); err != nil {
+					return fmt.Errorf("importing %q failed: %w", hdr.Name, err)
+				}
+			}
+			if err := im.finish(rctx); err != nil {
+				return err
+			}
+			if err := tx.Commit(rctx); err != nil {
+				return err
+			}
+			slog.Info("Bundle imported", "documents", len(im.docIDs))
+			return nil
+		}, 0,
+	)
+}
+
+// checkEmpty checks that there are no documents in the database.
+func (im *importer) checkEmpty(ctx context.Context) error {
+	const existsSQL = `SELECT EXISTS(SELECT 1 FROM documents)`
+	var exists bool
+	if err := im.tx.QueryRow(ctx, existsSQL).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("database already contains documents")
+	}
+	return nil
+}
+
+// entry dispatches a file of the bundle.
+func (im *importer) entry(ctx context.Context, name string, r io.Reader) error {
+	if name == manifestFile {
+		return im.readManifest(ctx, r)
+	}
+	if im.manifest == nil {
+		return errors.New("bundle does not start with a manifest")
+	}
+	switch {
+	case strings.HasPrefix(name, documentsDir):
+		return im.document(ctx, name, r)
+	case strings.HasSuffix(name, tableSuffix):
+		return im.table(ctx, strings.TrimSuffix(name, tableSuffix), r)
+	default:
+		slog.Warn("Ignoring unknown file in bundle", "file", name)
+		return nil
+	}
+}
+
+func (im *importer) readManifest(ctx context.Context, r io.Reader) error {
+	var m manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return err
+	}
+	if m.Format < 1 || m.Format > formatVersion {
+		return fmt.Errorf("unsupported bundle format %d", m.Format)
+	}
+	const versionSQL = `SELECT max(version) FROM versions`
+	var dbVersion int64
+	if err := im.tx.QueryRow(ctx, versionSQL).Scan(&dbVersion); err != nil {
+		return err
+	}
+	if m.Database > dbVersion {
+		return fmt.Errorf(
+			"bundle is from a newer database version (%d > %d)",
+			m.Database, dbVersion)
+	}
+	if m.KeyCheck != "" {
+		if im.bundleKey == nil {
+			return errors.New("bundle contains encrypted credentials: key needed")
+		}
+		data, err := hex.DecodeString(m.KeyCheck)
+		if err != nil {
+			return err
+		}
+		if check, err := secrets.Decrypt(im.bundleKey, data); err != nil || string(check) != keyCheck {
+			return errors.New("bundle key does not match")
+		}
+	}
+	slog.Info("Importing bundle",
+		"created", m.Created,
+		"version", m.Version,
+		"database", m.Database)
+	im.manifest = &m
+	return nil
+}
+
+// document handles the meta data, the signature and the
+// original of a document. They have to come in this order.
+func (im *importer) document(ctx context.Context, name string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	switch {
+	case strings.HasSuffix(name, metaSuffix):
+		var meta documentMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return err
+		}
+		im.meta, im.signature = &meta, nil
+		return nil
+	case strings.HasSuffix(name, sigSuffix):
+		if im.meta == nil {
+			return errors.New("signature without meta data")
+		}
+		im.signature = data
+		return nil
+	case strings.HasSuffix(name, docSuffix):
+	default:
+		slog.Warn("Ignoring unknown file in bundle", "file", name)
+		return nil
+	}
+	meta := im.meta
+	if meta == nil || name != fmt.Sprintf("%s%d%s", documentsDir, meta.ID, docSuffix) {
+		return errors.New("document without meta data")
+	}
+	im.meta = nil
+
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	signature := im.signature
+	store := func(ctx context.Context, tx pgx.Tx, id int64, duplicate bool) error {
+		if duplicate {
+			return fmt.Errorf("document %d is a duplicate", meta.ID)
+		}
+		const updateSQL = `UPDATE documents ` +
+			`SET (signature, filename) = ($1, $2) ` +
+			`WHERE id = $3`
+		_, err := tx.Exec(ctx, updateSQL, signature, meta.Filename, id)
+		return err
+	}
+	id, err := models.ImportDocumentData(
+		ctx, im.tx, document, data, nil, nil, store, false)
+	if err != nil {
+		return err
+	}
+	im.docIDs[meta.ID] = id
+	if meta.Deleted != nil {
+		im.deleted[id] = *meta.Deleted
+	}
+	return nil
+}
+
+// table imports the rows of a table.
+func (im *importer) table(ctx context.Context, name string, r io.Reader) error {
+	var prepare func() error
+	var transform func(map[string]any) error
+
+	switch name {
+	case "sources":
+		transform = im.extractSecrets
+		fallthrough
+	case "secrets", "source_keys", "source_keys_log", "feeds", "source_pmds", "aggregators", "aggregator_actions",
+		"stored_queries", "default_query_exclusion", "sync_peers", "tags":
+		// Replace the defaults created by the migrations.
+		prepare = func() error {
+			_, err := im.tx.Exec(ctx, `DELETE FROM `+name)
+			return err
+		}
+		if name == "secrets" {
+			transform = recrypt(im.bundleKey, im.cipherKey, "value")
+		}
+	case "comments", "ssvc_history":
+		transform = im.remapDocument(false)
+	case "quarantine":
+		transform = im.remapDocument(true)
+	case "comment_mentions":
+		// Replace the mentions extracted while importing the comments.
+		prepare = func() error {
+			_, err := im.tx.Exec(ctx, `DELETE FROM comment_mentions`)
+			return err
+		}
+	case "sync_comments", "comment_reactions", "comment_attachments":
+		// Refers to the comments by their kept ids.
+	case "download_queue":
+		// Refers to the feeds by their kept ids.
+	case "ssvc_suggestions":
+		transform = im.remapDocument(false)
+	case "downloads":
+		transform = im.remapDocument(true)
+	case "events_log":
+		// Drop the events created while importing the documents.
+		prepare = func() error {
+			_, err := im.tx.Exec(ctx, `DELETE FROM events_log`)
+			return err
+		}
+		remap := im.remapDocument(true)
+		transform = func(row map[string]any) error {
+			// The transactions of the exporting database are meaningless here.
+			// The imported events are all committed and ordered by their ids.
+			if _, ok := row["xact"]; ok {
+				row["xact"] = "0"
+			}
+			return remap(row)
+		}
+	case "advisories":
+		return im.advisories(ctx, r)
+	case "advisory_checklists", "advisory_notes", "advisory_tags",
+		"vulnerability_case_advisories":
+		transform = im.remapAdvisory
+	case "vulnerability_cases":
+		// Referred to by their kept ids.
+	default:
+		return fmt.Errorf("unknown table %q", name)
+	}
+
+	if prepare != nil {
+		if err := prepare(); err != nil {
+			return err
+		}
+	}
+
+	columns, err := tableColumns(ctx, im.tx, name)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	for {
+		var row map[string]any
+		if err := dec.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		if transform != nil {
+			if err := transform(row); err != nil {
+				return err
+			}
+		}
+		if err := insertRow(ctx, im.tx, name, columns, row); err != nil {
+			return err
+		}
+	}
+	im.tables = append(im.tables, name)
+	return nil
+}
+
+// extractSecrets moves the credentials of a source row of
+// an older bundle version into the secrets.
+func (im *importer) extractSecrets(row map[string]any) error {
+	for _, field := range legacySecrets {
+		value, ok := row[field].(string)
+		delete(row, field)
+		if !ok {
+			continue
+		}
+		num, ok := row["id"].(json.Number)
+		if !ok {
+			return errors.New("source without id")
+		}
+		data, err := recryptValue(im.bundleKey, im.cipherKey, value)
+		if err != nil {
+			return err
+		}
+		im.secrets["sources/"+num.String()+"/"+field] = data
+	}
+	return nil
+}
+
+// remapDocument returns a function which maps the document id of
+// a row to the id of the imported document. If optional is set
+// unknown documents are mapped to NULL.
+func (im *importer) remapDocument(optional bool) func(map[string]any) error {
+	return func(row map[string]any) error {
+		num, ok := row["documents_id"].(json.Number)
+		if !ok {
+			return nil
+		}
+		oldID, err := num.Int64()
+		if err != nil {
+			return err
+		}
+		if newID, ok := im.docIDs[oldID]; ok {
+			row["documents_id"] = newID
+		} else if optional {
+			row["documents_id"] = nil
+		} else {
+			return fmt.Errorf("unknown document %d", oldID)
+		}
+		return nil
+	}
+}
+
+// remapAdvisory replaces the advisory id of a row
+// with the one of the imported advisory.
+func (im *importer) remapAdvisory(row map[string]any) error {
+	num, ok := row["advisories_id"].(json.Number)
+	if !ok {
+		return nil
+	}
+	oldID, err := num.Int64()
+	if err != nil {
+		return err
+	}
+	newID, ok := im.advIDs[oldID]
+	if !ok {
+		return fmt.Errorf("unknown advisory %d", oldID)
+	}
+	row["advisories_id"] = newID
+	return nil
+}
+
+// advisories restores the workflow state of the advisories.
+func (im *importer) advisories(ctx context.Context, r io.Reader) error {
+	const updateSQL = `UPDATE advisories SET (state, recent) = ` +
+		`(SELECT state, recent FROM jsonb_populate_record(NULL::advisories, $1)) ` +
+		`WHERE publisher = $1->>'publisher' AND tracking_id = $1->>'tracking_id' ` +
+		`RETURNING id`
+
+	dec := json.NewDecoder(r)
+	for {
+		var row json.RawMessage
+		if err := dec.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		var old struct {
+			ID int64 `json:"id"`
+		}
+		if err := json.Unmarshal(row, &old); err != nil {
+			return err
+		}
+		var id int64
+		switch err := im.tx.QueryRow(ctx, updateSQL, []byte(row)).Scan(&id); {
+		case errors.Is(err, pgx.ErrNoRows):
+			// Advisory without imported documents.
+			continue
+		case err != nil:
+			return err
+		}
+		im.advIDs[old.ID] = id
+	}
+	return nil
+}
+
+// finish moves the trashed documents back into the trash bin and
+// adjusts the sequences of the imported tables.
+func (im *importer) finish(ctx context.Context) error {
+	if im.manifest == nil {
+		return errors.New("bundle contains no manifest")
+	}
+
+	const secretSQL = `INSERT INTO secrets (name, value) VALUES ($1, $2) ` +
+		`ON CONFLICT (name) DO NOTHING`
+	for name, value := range im.secrets {
+		if _, err := im.tx.Exec(ctx, secretSQL, name, value); err != nil {
+			return fmt.Errorf("storing secret failed: %w", err)
+		}
+	}
+
+	const trashSQL = `UPDATE documents SET deleted = $1 WHERE id = $2`
+	for id, deleted := range im.deleted {
+		if _, err := im.tx.Exec(ctx, trashSQL, deleted, id); err != nil {
+			return fmt.Errorf("trashing document failed: %w", err)
+		}
+	}
+
+	const identitiesSQL = `SELECT column_name FROM information_schema.columns ` +
+		`WHERE table_schema = current_schema() AND table_name = $1 ` +
+		`AND is_identity = 'YES'`
+	for _, name := range im.tables {
+		rows, _ := im.tx.Query(ctx, identitiesSQL, name)
+		identities, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		for _, column := range identities {
+			setvalSQL := `SELECT setval(pg_get_serial_sequence($1, $2), ` +
+				`coalesce(max(` + pgx.Identifier{column}.Sanitize() + `), 0) + 1, false) ` +
+				`FROM ` + name
+			if _, err := im.tx.Exec(ctx, setvalSQL, name, column); err != nil {
+				return fmt.Errorf("adjusting sequence of %s failed: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the columns of a table which can be written.
+func tableColumns(ctx context.Context, tx pgx.Tx, name string) ([]string, error) {
+	const columnsSQL = `SELECT column_name FROM information_schema.columns ` +
+		`WHERE table_schema = current_schema() AND table_name = $1 ` +
+		`AND is_generated = 'NEVER' ` +
+		`ORDER BY ordinal_position`
+	rows, _ := tx.Query(ctx, columnsSQL, name)
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// insertRow inserts the columns of a row which exist in the table.
+// This allows bundles of older database versions to be imported.
+func insertRow(
+	ctx context.Context,
+	tx pgx.Tx,
+	name string,
+	columns []string,
+	row map[string]any,
+) error {
+	var b bytes.Buffer
+	for _, column := range columns {
+		if _, ok := row[column]; !ok {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(pgx.Identifier{column}.Sanitize())
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	list := b.String()
+	insertSQL := `INSERT INTO ` + name + ` (` + list + `) ` +
+		`SELECT ` + list + ` FROM jsonb_populate_record(NULL::` + name + `, $1)`
+	_, err := tx.Exec(ctx, insertSQL, row)
+	return err
+}
@@ -93,3 +93,10 @@ func (c *ExpirationCache[K, V]) SetWithExpiration(k K, v V, expiration time.Dura
 		value:   v,
 	}
 }
+
+// Delete removes the value for a given key.
+func (c *ExpirationCache[K, V]) Delete(k K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, k)
+}
@@ -9,11 +9,14 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -117,15 +120,52 @@ func (g *General) controlDialing(_, address string, _ syscall.RawConn) error {
 
 // Transport returns an [http.DefaultTransport] like [http.Transport] with
 // an installed dialing control to limit access to the configured constraints.
+// The requests are sent over the configured proxy.
 func (g *General) Transport() *http.Transport {
-	// This mainly an http.DefaultTransport with a dialer control.
-	return &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
+	return g.TransportVia(nil)
+}
+
+// TransportVia is like [General.Transport] but sends the requests over
+// the given proxy. A nil proxy stands for the configured one.
+// The proxies themselves are exempted from the dialing control
+// as they may live in blocked ranges. The targets reached over them
+// are checked before the request is sent.
+func (g *General) TransportVia(proxy *Proxy) *http.Transport {
+	if proxy == nil {
+		proxy = &g.Proxy
+	}
+	var (
+		selectProxy = proxy.proxyFunc()
+		proxies     sync.Map
+		dialer      = &net.Dialer{
 			Control:   g.controlDialing,
 			Timeout:   30 * time.Second,
 			KeepAlive: 30 * time.Second,
-		}).DialContext,
+		}
+		proxyDialer = &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+	)
+	// This mainly an http.DefaultTransport with a dialer control.
+	return &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			u, err := selectProxy(req)
+			if err != nil || u == nil {
+				return u, err
+			}
+			if err := g.checkTarget(req.Context(), req.URL, proxy.RemoteDNS); err != nil {
+				return nil, err
+			}
+			proxies.Store(proxyAddress(u), struct{}{})
+			return u, nil
+		},
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if _, ok := proxies.Load(address); ok {
+				return proxyDialer.DialContext(ctx, network, address)
+			}
+			return dialer.DialContext(ctx, network, address)
+		},
 		ForceAttemptHTTP2:     true,
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
@@ -10,11 +10,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -23,18 +26,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gocsaf/csaf/v3/csaf"
 
+	"github.com/ISDuBA/ISDuBA/pkg/cvss"
 	"github.com/ISDuBA/ISDuBA/pkg/ginkeycloak"
 	"github.com/ISDuBA/ISDuBA/pkg/models"
 )
 
 // General are the overarching settings.
 type General struct {
-	AdvisoryUploadLimit   HumanSize   `toml:"advisory_upload_limit"`
-	AnonymousEventLogging bool        `toml:"anonymous_event_logging"`
-	AllowedPorts          []PortRange `toml:"allowed_ports"`
-	BlockLoopback         bool        `toml:"block_loopback"`
-	BlockedRanges         []IPRange   `toml:"blocked_ranges"`
-	AllowedIPs            []net.IP    `toml:"allowed_ips"`
+	AdvisoryUploadLimit   HumanSize     `toml:"advisory_upload_limit"`
+	AnonymousEventLogging bool          `toml:"anonymous_event_logging"`
+	AllowedPorts          []PortRange   `toml:"allowed_ports"`
+	BlockLoopback         bool          `toml:"block_loopback"`
+	BlockedRanges         []IPRange     `toml:"blocked_ranges"`
+	AllowedIPs            []net.IP      `toml:"allowed_ips"`
+	KeepTrash             time.Duration `toml:"keep_trash"`
+	Proxy                 Proxy         `toml:"proxy"`
 }
 
 // Log are the config options for the logging.
@@ -92,6 +98,7 @@ type Sources struct {
 	MaxRatePerSource  float64               `toml:"max_rate_per_source"`
 	OpenPGPCaching    time.Duration         `toml:"openpgp_caching"`
 	FeedRefresh       time.Duration         `toml:"feed_refresh"`
+	FeedReconcile     time.Duration         `toml:"feed_reconcile"`
 	Timeout           time.Duration         `toml:"timeout"`
 	FeedLogLevel      FeedLogLevel          `tomt:"feed_log_level"`
 	PublishersTLPs    models.PublishersTLPs `toml:"publishers_tlps"`
@@ -104,6 +111,36 @@ type Sources struct {
 	AESKey            string                `toml:"aes_key"`
 	Checking          time.Duration         `toml:"checking"`
 	KeepFeedLogs      time.Duration         `toml:"keep_feed_logs"`
+	Quarantine        bool                  `toml:"quarantine"`
+	Health            Health                `toml:"health"`
+}
+
+// Health are the config options for the health scoring of the feeds.
+type Health struct {
+	Failures     int           `toml:"failures"`
+	MaxBackoff   time.Duration `toml:"max_backoff"`
+	Latency      time.Duration `toml:"latency"`
+	InvalidRatio float64       `toml:"invalid_ratio"`
+	Attention    bool          `toml:"attention"`
+}
+
+// Vault are the config options for a HashiCorp Vault compatible
+// key value store (KV version 2).
+type Vault struct {
+	Address   string        `toml:"address"`
+	Token     string        `toml:"token"`
+	Mount     string        `toml:"mount"`
+	Path      string        `toml:"path"`
+	Namespace string        `toml:"namespace"`
+	Timeout   time.Duration `toml:"timeout"`
+}
+
+// Secrets are the config options for storing the secrets
+// of the sources and the forward targets.
+type Secrets struct {
+	Provider  SecretsProvider `toml:"provider"`
+	Directory string          `toml:"directory"`
+	Vault     Vault           `toml:"vault"`
 }
 
 // ForwardTarget are the config options for the forward target.
@@ -126,10 +163,109 @@ type Forwarder struct {
 	Strategy       ForwarderStrategy `toml:"strategy"`
 }
 
+// ProvisioningTemplate is the template for the sources which are
+// automatically provisioned from aggregators.
+type ProvisioningTemplate struct {
+	Rate           float64       `toml:"rate"`
+	Slots          int           `toml:"slots"`
+	Age            time.Duration `toml:"age"`
+	IgnorePatterns []string      `toml:"ignore_patterns"`
+	TLPs           []models.TLP  `toml:"tlps"`
+	Activate       bool          `toml:"activate"`
+}
+
 // Aggregators are the config options for the aggregators.
 type Aggregators struct {
+	Timeout        time.Duration        `toml:"timeout"`
+	UpdateInterval time.Duration        `toml:"update_interval"`
+	Provisioning   ProvisioningTemplate `toml:"provisioning"`
+}
+
+// SyncPeer is another isdubad instance to synchronize with.
+type SyncPeer struct {
+	Name           string                `toml:"name"`
+	URL            string                `toml:"url"`
+	Token          string                `toml:"token"`
+	PeerToken      string                `toml:"peer_token"`
+	PublishersTLPs models.PublishersTLPs `toml:"publishers_tlps"`
+}
+
+// Sync are the config options for the synchronization between instances.
+type Sync struct {
+	Peers          []SyncPeer    `toml:"peer"`
+	UpdateInterval time.Duration `toml:"update_interval"`
 	Timeout        time.Duration `toml:"timeout"`
+	BatchSize      int           `toml:"batch_size"`
+}
+
+// SSVC are the config options for the SSVC decision models.
+type SSVC struct {
+	DefaultModel string `toml:"default_model"`
+	// Models maps the names of additional models to their files.
+	Models map[string]string `toml:"models"`
+}
+
+// EnrichmentCatalogue is a local snapshot file of an enrichment catalogue.
+// If an URL is given the file is updated from there.
+type EnrichmentCatalogue struct {
+	File string `toml:"file"`
+	URL  string `toml:"url"`
+}
+
+// Enrichment are the config options for the vulnerability enrichment data.
+type Enrichment struct {
+	CheckInterval time.Duration       `toml:"check_interval"`
+	FetchInterval time.Duration       `toml:"fetch_interval"`
+	Timeout       time.Duration       `toml:"timeout"`
+	KEV           EnrichmentCatalogue `toml:"kev"`
+	EPSS          EnrichmentCatalogue `toml:"epss"`
+	CWE           EnrichmentCatalogue `toml:"cwe"`
+}
+
+// CVSS are the config options for the indexing and scoring of CVSS vectors.
+type CVSS struct {
 	UpdateInterval time.Duration `toml:"update_interval"`
+	// Environmental are the defaults of the environmental metrics
+	// of the organisation used to re-score the vectors.
+	Environmental map[string]string `toml:"environmental"`
+}
+
+// Comments are the config options for the comments.
+type Comments struct {
+	// AttachmentsDir is the directory to store the attachments in.
+	// If empty the attachments are stored in the database.
+	AttachmentsDir    string    `toml:"attachments_dir"`
+	MaxAttachmentSize HumanSize `toml:"max_attachment_size"`
+	MaxAttachments    int       `toml:"max_attachments"`
+}
+
+// ChecklistTemplate is a named list of items to be checked
+// during the triage of an advisory.
+type ChecklistTemplate struct {
+	Name  string   `toml:"name" json:"name"`
+	Items []string `toml:"items" json:"items"`
+}
+
+// Triage are the config options for the triage data of the advisories.
+type Triage struct {
+	// DefaultChecklist is the name of the checklist template
+	// used if none is given. Defaults to the first one.
+	DefaultChecklist string              `toml:"default_checklist"`
+	Checklists       []ChecklistTemplate `toml:"checklist"`
+}
+
+// Checklist returns the checklist template with the given name.
+// An empty name selects the default template.
+func (t *Triage) Checklist(name string) *ChecklistTemplate {
+	if name == "" {
+		name = t.DefaultChecklist
+	}
+	for i := range t.Checklists {
+		if t.Checklists[i].Name == name {
+			return &t.Checklists[i]
+		}
+	}
+	return nil
 }
 
 // Client are the config options for the client.
@@ -153,8 +289,15 @@ type Config struct {
 	Sources         Sources                     `toml:"sources"`
 	RemoteValidator csaf.RemoteValidatorOptions `toml:"remote_validator"`
 	Client          Client                      `toml:"client"`
+	Secrets         Secrets                     `toml:"secrets"`
 	Forwarder       Forwarder                   `toml:"forwarder"`
 	Aggregators     Aggregators                 `toml:"aggregators"`
+	Sync            Sync                        `toml:"sync"`
+	SSVC            SSVC                        `toml:"ssvc"`
+	Enrichment      Enrichment                  `toml:"enrichment"`
+	CVSS            CVSS                        `toml:"cvss"`
+	Comments        Comments                    `toml:"comments"`
+	Triage          Triage                      `toml:"triage"`
 }
 
 func escape(s string) string {
@@ -257,6 +400,7 @@ func Load(file string) (*Config, error) {
 			BlockLoopback:         defaultBlockLoopback,
 			BlockedRanges:         nil,
 			AllowedIPs:            nil,
+			KeepTrash:             defaultKeepTrash,
 		},
 		Log: Log{
 			File:   defaultLogFile,
@@ -302,6 +446,7 @@ func Load(file string) (*Config, error) {
 			MaxRatePerSource:  defaultSourcesMaxRatePerSlot,
 			OpenPGPCaching:    defaultSourcesOpenPGPCaching,
 			FeedRefresh:       defaultSourcesFeedRefresh,
+			FeedReconcile:     defaultSourcesFeedReconcile,
 			Timeout:           defaultSourcesTimeout,
 			FeedLogLevel:      defaultSourcesFeedLogLevel,
 			FeedImporter:      defaultSourcesFeedImporter,
@@ -313,6 +458,22 @@ func Load(file string) (*Config, error) {
 			DefaultAge:        defaultSourcesAge,
 			Checking:          defaultSourcesChecking,
 			KeepFeedLogs:      defaultKeepFeedLogs,
+			Quarantine:        defaultSourcesQuarantine,
+			Health: Health{
+				Failures:     defaultHealthFailures,
+				MaxBackoff:   defaultHealthMaxBackoff,
+				Latency:      defaultHealthLatency,
+				InvalidRatio: defaultHealthInvalidRatio,
+				Attention:    defaultHealthAttention,
+			},
+		},
+		Secrets: Secrets{
+			Provider: defaultSecretsProvider,
+			Vault: Vault{
+				Mount:   defaultSecretsVaultMount,
+				Path:    defaultSecretsVaultPath,
+				Timeout: defaultSecretsVaultTimeout,
+			},
 		},
 		Forwarder: Forwarder{
 			UpdateInterval: defaultForwarderUpdateInterval,
@@ -332,6 +493,29 @@ func Load(file string) (*Config, error) {
 		Aggregators: Aggregators{
 			Timeout:        defaultAggregatorsTimeout,
 			UpdateInterval: defaultAggregatorsUpdateInterval,
+			Provisioning: ProvisioningTemplate{
+				Activate: defaultProvisioningActivate,
+			},
+		},
+		Sync: Sync{
+			UpdateInterval: defaultSyncUpdateInterval,
+			Timeout:        defaultSyncTimeout,
+			BatchSize:      defaultSyncBatchSize,
+		},
+		SSVC: SSVC{
+			DefaultModel: defaultSSVCDefaultModel,
+		},
+		Enrichment: Enrichment{
+			CheckInterval: defaultEnrichmentCheckInterval,
+			FetchInterval: defaultEnrichmentFetchInterval,
+			Timeout:       defaultEnrichmentTimeout,
+		},
+		CVSS: CVSS{
+			UpdateInterval: defaultCVSSUpdateInterval,
+		},
+		Comments: Comments{
+			MaxAttachmentSize: defaultCommentsMaxAttachmentSize,
+			MaxAttachments:    defaultCommentsMaxAttachments,
 		},
 	}
 	if file != "" {
@@ -355,7 +539,42 @@ func Load(file string) (*Config, error) {
 }
 
 func (cfg *Config) validate() error {
-	return cfg.Forwarder.validate()
+	return errors.Join(
+		cfg.General.Proxy.validate(),
+		cfg.Sources.Health.validate(),
+		cfg.Secrets.validate(),
+		cfg.Forwarder.validate(),
+		cfg.Aggregators.Provisioning.validate(&cfg.Sources),
+		cfg.Sync.validate(),
+		cfg.SSVC.validate(),
+		cfg.Enrichment.validate(),
+		cfg.CVSS.validate(),
+		cfg.Comments.validate(),
+		cfg.Triage.validate())
+}
+
+func (h *Health) validate() error {
+	if h.Failures < 1 {
+		return errors.New("sources.health.failures has to be at least 1")
+	}
+	if h.InvalidRatio < 0 || h.InvalidRatio > 1 {
+		return errors.New("sources.health.invalid_ratio has to be between 0 and 1")
+	}
+	return nil
+}
+
+func (s *Secrets) validate() error {
+	switch s.Provider {
+	case SecretsFiles:
+		if s.Directory == "" {
+			return errors.New("secrets provider 'files' needs a directory")
+		}
+	case SecretsVault:
+		if s.Vault.Address == "" {
+			return errors.New("secrets provider 'vault' needs an address")
+		}
+	}
+	return nil
 }
 
 func (f *Forwarder) validate() error {
@@ -377,6 +596,126 @@ func (f *Forwarder) validate() error {
 	return nil
 }
 
+func (pt *ProvisioningTemplate) validate(sources *Sources) error {
+	if pt.Rate < 0 ||
+		(sources.MaxRatePerSource != 0 && pt.Rate > sources.MaxRatePerSource) {
+		return fmt.Errorf("provisioning rate %g is out of range", pt.Rate)
+	}
+	if pt.Slots < 0 || pt.Slots > sources.MaxSlotsPerSource {
+		return fmt.Errorf("provisioning slots %d are out of range", pt.Slots)
+	}
+	for _, pattern := range pt.IgnorePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("provisioning ignore pattern %q is invalid: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (s *Sync) validate() error {
+	names := make(map[string]struct{}, len(s.Peers))
+	for i := range s.Peers {
+		name := s.Peers[i].Name
+		if name == "" || strings.ContainsRune(name, ':') {
+			return fmt.Errorf("sync peer name %q is empty or contains a ':'", name)
+		}
+		if _, found := names[name]; found {
+			return fmt.Errorf("sync peer name %q is not unique", name)
+		}
+		names[name] = struct{}{}
+	}
+	if s.BatchSize < 1 {
+		return errors.New("sync batch_size has to be at least 1")
+	}
+	return nil
+}
+
+func (s *SSVC) validate() error {
+	if _, found := s.Models[models.DefaultSSVCModel]; found {
+		return fmt.Errorf("ssvc model name %q is reserved for the embedded model", models.DefaultSSVCModel)
+	}
+	if _, found := s.Models[s.DefaultModel]; !found && s.DefaultModel != models.DefaultSSVCModel {
+		return fmt.Errorf("ssvc default_model %q is not configured", s.DefaultModel)
+	}
+	return nil
+}
+
+func (e *Enrichment) validate() error {
+	var errs []error
+	for _, c := range []struct {
+		name string
+		ec   *EnrichmentCatalogue
+	}{
+		{"kev", &e.KEV},
+		{"epss", &e.EPSS},
+		{"cwe", &e.CWE},
+	} {
+		name, ec := c.name, c.ec
+		if ec.URL == "" {
+			continue
+		}
+		if ec.File == "" {
+			errs = append(errs, fmt.Errorf("enrichment.%s.url needs a file to store into", name))
+		}
+		if u, err := url.Parse(ec.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+			errs = append(errs, fmt.Errorf("enrichment.%s.url %q is not a HTTP(S) URL", name, ec.URL))
+		}
+	}
+	if e.CheckInterval <= 0 {
+		errs = append(errs, errors.New("enrichment.check_interval has to be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *CVSS) validate() error {
+	var errs []error
+	for metric, value := range c.Environmental {
+		if !cvss.IsEnvironmental(metric, value) {
+			errs = append(errs, fmt.Errorf(
+				"cvss.environmental %s = %q is not a valid environmental metric", metric, value))
+		}
+	}
+	if c.UpdateInterval <= 0 {
+		errs = append(errs, errors.New("cvss.update_interval has to be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Comments) validate() error {
+	var errs []error
+	if c.MaxAttachmentSize <= 0 {
+		errs = append(errs, errors.New("comments.max_attachment_size has to be positive"))
+	}
+	if c.MaxAttachments < 0 {
+		errs = append(errs, errors.New("comments.max_attachments must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Triage) validate() error {
+	var errs []error
+	names := make(map[string]struct{}, len(t.Checklists))
+	for i := range t.Checklists {
+		cl := &t.Checklists[i]
+		if cl.Name == "" {
+			errs = append(errs, errors.New("triage.checklist needs a name"))
+			continue
+		}
+		if _, found := names[cl.Name]; found {
+			errs = append(errs, fmt.Errorf("triage.checklist name %q is not unique", cl.Name))
+		}
+		names[cl.Name] = struct{}{}
+		if len(cl.Items) == 0 {
+			errs = append(errs, fmt.Errorf("triage.checklist %q has no items", cl.Name))
+		}
+	}
+	if t.DefaultChecklist != "" && t.Checklist(t.DefaultChecklist) == nil {
+		errs = append(errs, fmt.Errorf(
+			"triage.default_checklist %q is not a configured checklist", t.DefaultChecklist))
+	}
+	return errors.Join(errs...)
+}
+
 func parsedDefaultBlockedRanges() []IPRange {
 	brs := make([]IPRange, 0, len(defaultBlockedRanges))
 	for _, cidr := range defaultBlockedRanges {
@@ -399,6 +738,20 @@ func (cfg *Config) presetEmptyDefaults() {
 	if cfg.Client.KeycloakURL == "" {
 		cfg.Client.KeycloakURL = cfg.Keycloak.URL
 	}
+	if cfg.Aggregators.Provisioning.TLPs == nil {
+		cfg.Aggregators.Provisioning.TLPs = defaultProvisioningTLPs
+	}
+	for i := range cfg.Sync.Peers {
+		if cfg.Sync.Peers[i].PublishersTLPs == nil {
+			cfg.Sync.Peers[i].PublishersTLPs = defaultSyncPublishersTLPs
+		}
+	}
+	if cfg.Triage.Checklists == nil {
+		cfg.Triage.Checklists = defaultTriageChecklists
+	}
+	if cfg.Triage.DefaultChecklist == "" && len(cfg.Triage.Checklists) > 0 {
+		cfg.Triage.DefaultChecklist = cfg.Triage.Checklists[0].Name
+	}
 }
 
 func (cfg *Config) fillFromEnv() error {
@@ -411,11 +764,19 @@ func (cfg *Config) fillFromEnv() error {
 		storeHumanSize         = store(storeHumanSize)
 		storeFeedLogLevel      = store(storeFeedLogLevel)
 		storeForwarderStrategy = store(ParseForwarderStrategy)
+		storeSecretsProvider   = store(ParseSecretsProvider)
 		storeFloat64           = store(parseFloat64)
+		storeNoProxy           = store(ParseNoProxy)
 	)
 	return storeFromEnv(
 		envStore{"ISDUBA_ADVISORY_UPLOAD_LIMIT", storeHumanSize(&cfg.General.AdvisoryUploadLimit)},
 		envStore{"ISDUBA_ANONYMOUS_EVENT_LOGGING", storeBool(&cfg.General.AnonymousEventLogging)},
+		envStore{"ISDUBA_KEEP_TRASH", storeDuration(&cfg.General.KeepTrash)},
+		envStore{"ISDUBA_PROXY_URL", storeString(&cfg.General.Proxy.URL)},
+		envStore{"ISDUBA_PROXY_USERNAME", storeString(&cfg.General.Proxy.Username)},
+		envStore{"ISDUBA_PROXY_PASSWORD", storeString(&cfg.General.Proxy.Password)},
+		envStore{"ISDUBA_PROXY_NO_PROXY", storeNoProxy(&cfg.General.Proxy.NoProxy)},
+		envStore{"ISDUBA_PROXY_REMOTE_DNS", storeBool(&cfg.General.Proxy.RemoteDNS)},
 		envStore{"ISDUBA_LOG_FILE", storeString(&cfg.Log.File)},
 		envStore{"ISDUBA_LOG_LEVEL", storeLevel(&cfg.Log.Level)},
 		envStore{"ISDUBA_LOG_JSON", storeBool(&cfg.Log.JSON)},
@@ -449,6 +810,7 @@ func (cfg *Config) fillFromEnv() error {
 		envStore{"ISDUBA_SOURCES_MAX_RATE_PER_SOURCE", storeFloat64(&cfg.Sources.MaxRatePerSource)},
 		envStore{"ISDUBA_SOURCES_OPENPGP_CACHING", storeDuration(&cfg.Sources.OpenPGPCaching)},
 		envStore{"ISDUBA_SOURCES_FEED_REFRESH", storeDuration(&cfg.Sources.FeedRefresh)},
+		envStore{"ISDUBA_SOURCES_FEED_RECONCILE", storeDuration(&cfg.Sources.FeedReconcile)},
 		envStore{"ISDUBA_SOURCES_FEED_LOG_LEVEL", storeFeedLogLevel(&cfg.Sources.FeedLogLevel)},
 		envStore{"ISDUBA_SOURCES_FEED_IMPORTER", storeString(&cfg.Sources.FeedImporter)},
 		envStore{"ISDUBA_SOURCES_DEFAULT_MESSAGE", storeString(&cfg.Sources.DefaultMessage)},
@@ -460,6 +822,12 @@ func (cfg *Config) fillFromEnv() error {
 		envStore{"ISDUBA_SOURCES_AES_KEY", storeString(&cfg.Sources.AESKey)},
 		envStore{"ISDUBA_SOURCES_CHECKING", storeDuration(&cfg.Sources.Checking)},
 		envStore{"ISDUBA_SOURCES_KEEP_FEED_LOGS", storeDuration(&cfg.Sources.KeepFeedLogs)},
+		envStore{"ISDUBA_SOURCES_QUARANTINE", storeBool(&cfg.Sources.Quarantine)},
+		envStore{"ISDUBA_SOURCES_HEALTH_FAILURES", storeInt(&cfg.Sources.Health.Failures)},
+		envStore{"ISDUBA_SOURCES_HEALTH_MAX_BACKOFF", storeDuration(&cfg.Sources.Health.MaxBackoff)},
+		envStore{"ISDUBA_SOURCES_HEALTH_LATENCY", storeDuration(&cfg.Sources.Health.Latency)},
+		envStore{"ISDUBA_SOURCES_HEALTH_INVALID_RATIO", storeFloat64(&cfg.Sources.Health.InvalidRatio)},
+		envStore{"ISDUBA_SOURCES_HEALTH_ATTENTION", storeBool(&cfg.Sources.Health.Attention)},
 		envStore{"ISDUBA_REMOTE_VALIDATOR_URL", storeString(&cfg.RemoteValidator.URL)},
 		envStore{"ISDUBA_REMOTE_VALIDATOR_CACHE", storeString(&cfg.RemoteValidator.Cache)},
 		envStore{"ISDUBA_CLIENT_KEYCLOAK_URL", storeString(&cfg.Client.KeycloakURL)},
@@ -467,9 +835,39 @@ func (cfg *Config) fillFromEnv() error {
 		envStore{"ISDUBA_CLIENT_KEYCLOAK_CLIENT_ID", storeString(&cfg.Client.KeycloakClientID)},
 		envStore{"ISDUBA_CLIENT_UPDATE_INTERVAL", storeDuration(&cfg.Client.UpdateInterval)},
 		envStore{"ISDUBA_CLIENT_IDLE_TIMEOUT", storeDuration(&cfg.Client.IdleTimeout)},
+		envStore{"ISDUBA_SECRETS_PROVIDER", storeSecretsProvider(&cfg.Secrets.Provider)},
+		envStore{"ISDUBA_SECRETS_DIRECTORY", storeString(&cfg.Secrets.Directory)},
+		envStore{"ISDUBA_SECRETS_VAULT_ADDRESS", storeString(&cfg.Secrets.Vault.Address)},
+		envStore{"ISDUBA_SECRETS_VAULT_TOKEN", storeString(&cfg.Secrets.Vault.Token)},
+		envStore{"ISDUBA_SECRETS_VAULT_MOUNT", storeString(&cfg.Secrets.Vault.Mount)},
+		envStore{"ISDUBA_SECRETS_VAULT_PATH", storeString(&cfg.Secrets.Vault.Path)},
+		envStore{"ISDUBA_SECRETS_VAULT_NAMESPACE", storeString(&cfg.Secrets.Vault.Namespace)},
+		envStore{"ISDUBA_SECRETS_VAULT_TIMEOUT", storeDuration(&cfg.Secrets.Vault.Timeout)},
 		envStore{"ISDUBA_FORWARDER_UPDATE_INTERVAL", storeDuration(&cfg.Forwarder.UpdateInterval)},
 		envStore{"ISDUBA_FORWARDER_STRATEGY", storeForwarderStrategy(&cfg.Forwarder.Strategy)},
 		envStore{"ISDUBA_AGGREGATORS_TIMEOUT", storeDuration(&cfg.Aggregators.Timeout)},
 		envStore{"ISDUBA_AGGREGATORS_UPDATE_INTERVAL", storeDuration(&cfg.Aggregators.UpdateInterval)},
+		envStore{"ISDUBA_AGGREGATORS_PROVISIONING_RATE", storeFloat64(&cfg.Aggregators.Provisioning.Rate)},
+		envStore{"ISDUBA_AGGREGATORS_PROVISIONING_SLOTS", storeInt(&cfg.Aggregators.Provisioning.Slots)},
+		envStore{"ISDUBA_AGGREGATORS_PROVISIONING_AGE", storeDuration(&cfg.Aggregators.Provisioning.Age)},
+		envStore{"ISDUBA_AGGREGATORS_PROVISIONING_ACTIVATE", storeBool(&cfg.Aggregators.Provisioning.Activate)},
+		envStore{"ISDUBA_SYNC_UPDATE_INTERVAL", storeDuration(&cfg.Sync.UpdateInterval)},
+		envStore{"ISDUBA_SYNC_TIMEOUT", storeDuration(&cfg.Sync.Timeout)},
+		envStore{"ISDUBA_SYNC_BATCH_SIZE", storeInt(&cfg.Sync.BatchSize)},
+		envStore{"ISDUBA_SSVC_DEFAULT_MODEL", storeString(&cfg.SSVC.DefaultModel)},
+		envStore{"ISDUBA_ENRICHMENT_CHECK_INTERVAL", storeDuration(&cfg.Enrichment.CheckInterval)},
+		envStore{"ISDUBA_ENRICHMENT_FETCH_INTERVAL", storeDuration(&cfg.Enrichment.FetchInterval)},
+		envStore{"ISDUBA_ENRICHMENT_TIMEOUT", storeDuration(&cfg.Enrichment.Timeout)},
+		envStore{"ISDUBA_ENRICHMENT_KEV_FILE", storeString(&cfg.Enrichment.KEV.File)},
+		envStore{"ISDUBA_ENRICHMENT_KEV_URL", storeString(&cfg.Enrichment.KEV.URL)},
+		envStore{"ISDUBA_ENRICHMENT_EPSS_FILE", storeString(&cfg.Enrichment.EPSS.File)},
+		envStore{"ISDUBA_ENRICHMENT_EPSS_URL", storeString(&cfg.Enrichment.EPSS.URL)},
+		envStore{"ISDUBA_ENRICHMENT_CWE_FILE", storeString(&cfg.Enrichment.CWE.File)},
+		envStore{"ISDUBA_ENRICHMENT_CWE_URL", storeString(&cfg.Enrichment.CWE.URL)},
+		envStore{"ISDUBA_CVSS_UPDATE_INTERVAL", storeDuration(&cfg.CVSS.UpdateInterval)},
+		envStore{"ISDUBA_COMMENTS_ATTACHMENTS_DIR", storeString(&cfg.Comments.AttachmentsDir)},
+		envStore{"ISDUBA_COMMENTS_MAX_ATTACHMENT_SIZE", storeHumanSize(&cfg.Comments.MaxAttachmentSize)},
+		envStore{"ISDUBA_COMMENTS_MAX_ATTACHMENTS", storeInt(&cfg.Comments.MaxAttachments)},
+		envStore{"ISDUBA_TRIAGE_DEFAULT_CHECKLIST", storeString(&cfg.Triage.DefaultChecklist)},
 	)
 }
@@ -21,6 +21,7 @@ const DefaultConfigFile = "isduba.toml"
 const (
 	defaultAdvisoryUploadLimit   = 512 * 1024 * 1024
 	defaultAnonymousEventLogging = false
+	defaultKeepTrash             = 31 * 24 * time.Hour
 )
 
 var (
@@ -82,6 +83,10 @@ var (
 	defaultPublishersTLPs = models.PublishersTLPs{
 		"*": []models.TLP{models.TLPWhite},
 	}
+	defaultSyncPublishersTLPs = models.PublishersTLPs{
+		"*": []models.TLP{models.TLPWhite},
+	}
+	defaultProvisioningTLPs      = []models.TLP{models.TLPWhite}
 	defaultSourcesPublishersTLPs = models.PublishersTLPs{
 		"*": []models.TLP{
 			models.TLPWhite,
@@ -104,6 +109,7 @@ const (
 	defaultSourcesMaxRatePerSlot    = 0
 	defaultSourcesOpenPGPCaching    = 24 * time.Hour
 	defaultSourcesFeedRefresh       = 15 * time.Minute
+	defaultSourcesFeedReconcile     = 24 * time.Hour
 	defaultSourcesTimeout           = 30 * time.Second
 	defaultSourcesFeedLogLevel      = InfoFeedLogLevel
 	defaultSourcesFeedImporter      = "feedimporter"
@@ -114,9 +120,25 @@ const (
 	defaultSourcesSignatureCheck = true
 	defaultSourcesAge            = 17520 * time.Hour
 	defaultSourcesChecking       = 2 * time.Hour
+	defaultSourcesQuarantine     = true
 	defaultKeepFeedLogs          = 3 * 31 * 24 * time.Hour
 )
 
+const (
+	defaultHealthFailures     = 3
+	defaultHealthMaxBackoff   = 24 * time.Hour
+	defaultHealthLatency      = 10 * time.Second
+	defaultHealthInvalidRatio = 0.5
+	defaultHealthAttention    = false
+)
+
+const (
+	defaultSecretsProvider     = SecretsDatabase
+	defaultSecretsVaultMount   = "secret"
+	defaultSecretsVaultPath    = "isduba"
+	defaultSecretsVaultTimeout = 30 * time.Second
+)
+
 const (
 	defaultForwarderUpdateInterval = 5 * time.Minute
 	defaultForwarderStratgy        = ForwarderStrategyAll
@@ -139,4 +161,35 @@ const (
 const (
 	defaultAggregatorsTimeout        = 30 * time.Second
 	defaultAggregatorsUpdateInterval = 1 * time.Hour
+	defaultProvisioningActivate      = true
+)
+
+const (
+	defaultSyncUpdateInterval = 5 * time.Minute
+	defaultSyncTimeout        = 30 * time.Second
+	defaultSyncBatchSize      = 500
+)
+
+const defaultSSVCDefaultModel = models.DefaultSSVCModel
+
+const (
+	defaultEnrichmentCheckInterval = 5 * time.Minute
+	defaultEnrichmentFetchInterval = 24 * time.Hour
+	defaultEnrichmentTimeout       = 5 * time.Minute
 )
+
+const defaultCVSSUpdateInterval = time.Minute
+
+const (
+	defaultCommentsMaxAttachmentSize = 10 * 1024 * 1024
+	defaultCommentsMaxAttachments    = 10
+)
+
+var defaultTriageChecklists = []ChecklistTemplate{{
+	Name: "default",
+	Items: []string{
+		"Checked affected products",
+		"Informed asset owners",
+		"Checked mitigations and remediations",
+	},
+}}
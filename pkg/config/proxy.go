@@ -0,0 +1,189 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DirectProxy is the proxy URL to bypass all proxies.
+const DirectProxy = "direct"
+
+// Proxy are the config options of a proxy for the outgoing requests.
+// Without an URL the proxy is taken from the environment
+// (HTTPS_PROXY, HTTP_PROXY and NO_PROXY).
+// RemoteDNS leaves the host names of the targets which cannot be
+// resolved locally to the proxy.
+type Proxy struct {
+	URL       string   `toml:"url"`
+	Username  string   `toml:"username"`
+	Password  string   `toml:"password"`
+	NoProxy   []string `toml:"no_proxy"`
+	RemoteDNS bool     `toml:"remote_dns"`
+}
+
+// ParseProxyURL parses an URL of a HTTP CONNECT or SOCKS5 proxy.
+func ParseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("proxy URL has no host")
+	}
+	return u, nil
+}
+
+// ParseNoProxy parses a comma separated list of hosts to bypass the proxy.
+func ParseNoProxy(s string) ([]string, error) {
+	var noProxy []string
+	for entry := range strings.SplitSeq(s, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			noProxy = append(noProxy, entry)
+		}
+	}
+	return noProxy, nil
+}
+
+func (p *Proxy) validate() error {
+	var errs []error
+	if p.URL != "" && p.URL != DirectProxy {
+		if _, err := ParseProxyURL(p.URL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, entry := range p.NoProxy {
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				errs = append(errs, fmt.Errorf("invalid no_proxy entry %q: %w", entry, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bypass checks if the host is matched by the no_proxy list.
+// Entries are "*", domains matching their sub domains, IPs and CIDR ranges.
+func (p *Proxy) bypass(host string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, entry := range p.NoProxy {
+		switch {
+		case entry == "*":
+			return true
+		case strings.Contains(entry, "/"):
+			if _, ipNet, err := net.ParseCIDR(entry); err == nil && ip != nil && ipNet.Contains(ip) {
+				return true
+			}
+		case ip != nil:
+			if other := net.ParseIP(entry); other != nil && other.Equal(ip) {
+				return true
+			}
+		default:
+			domain := strings.TrimPrefix(strings.ToLower(entry), ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// proxyFunc returns the function to select the proxy of a request.
+func (p *Proxy) proxyFunc() func(*http.Request) (*url.URL, error) {
+	switch p.URL {
+	case "":
+		return http.ProxyFromEnvironment
+	case DirectProxy:
+		return func(*http.Request) (*url.URL, error) { return nil, nil }
+	}
+	u, err := ParseProxyURL(p.URL)
+	if err == nil && p.Username != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if err != nil {
+			return nil, err
+		}
+		if p.bypass(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return u, nil
+	}
+}
+
+// proxyAddress returns the address the transport dials to reach the proxy.
+func proxyAddress(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// checkTarget checks if a target reached over a proxy is allowed.
+// The dialing control only sees the address of the proxy in this case.
+// Host names which cannot be resolved locally are only left to the
+// proxy if remoteDNS is set.
+func (g *General) checkTarget(ctx context.Context, target *url.URL, remoteDNS bool) error {
+	if len(g.AllowedPorts) > 0 {
+		port := target.Port()
+		if port == "" {
+			if target.Scheme == "https" {
+				port = "443"
+			} else {
+				port = "80"
+			}
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("invalid port: %q", port)
+		}
+		if !g.allowedPort(p) {
+			return fmt.Errorf("port %d is not an allowed port", p)
+		}
+	}
+	host := target.Hostname()
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		resolved, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+		if err != nil {
+			if remoteDNS {
+				return nil
+			}
+			return fmt.Errorf("cannot resolve %q to check it: %w", host, err)
+		}
+		ips = resolved
+	}
+	for _, ip := range ips {
+		if g.blockedIP(ip) {
+			return errors.New("accessing address is not allowed")
+		}
+	}
+	return nil
+}
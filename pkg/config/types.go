@@ -33,6 +33,19 @@ const (
 	ForwarderStrategyNewAndMajor
 )
 
+// SecretsProvider is the backend storing the secrets of
+// the sources and the forward targets.
+type SecretsProvider int
+
+const (
+	// SecretsDatabase stores the secrets AES encrypted in the database.
+	SecretsDatabase SecretsProvider = iota
+	// SecretsFiles stores the secrets as files in a directory.
+	SecretsFiles
+	// SecretsVault stores the secrets in a HashiCorp Vault compatible KV store.
+	SecretsVault
+)
+
 const (
 	// DebugFeedLogLevel represents the debug log level in feeds.
 	DebugFeedLogLevel FeedLogLevel = iota
@@ -173,3 +186,46 @@ func (fs *ForwarderStrategy) UnmarshalText(b []byte) error {
 	*fs = x
 	return nil
 }
+
+// String implements [fmt.Stringer].
+func (sp SecretsProvider) String() string {
+	switch sp {
+	case SecretsDatabase:
+		return "database"
+	case SecretsFiles:
+		return "files"
+	case SecretsVault:
+		return "vault"
+	default:
+		return fmt.Sprintf("unknown secrets provider %d", sp)
+	}
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (sp SecretsProvider) MarshalText() ([]byte, error) {
+	return []byte(sp.String()), nil
+}
+
+// ParseSecretsProvider parses the secrets provider.
+func ParseSecretsProvider(s string) (SecretsProvider, error) {
+	switch strings.ToLower(s) {
+	case "database":
+		return SecretsDatabase, nil
+	case "files":
+		return SecretsFiles, nil
+	case "vault":
+		return SecretsVault, nil
+	default:
+		return 0, fmt.Errorf("unknown secrets provider %q", s)
+	}
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (sp *SecretsProvider) UnmarshalText(b []byte) error {
+	x, err := ParseSecretsProvider(string(b))
+	if err != nil {
+		return err
+	}
+	*sp = x
+	return nil
+}
@@ -0,0 +1,105 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package csafmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gocsaf/csaf/v3/csaf"
+	"github.com/gocsaf/csaf/v3/util"
+)
+
+// Document is a CSAF document served by the provider.
+type Document struct {
+	// Data is the raw content of the document.
+	Data    []byte
+	Summary *csaf.AdvisorySummary
+}
+
+// NewDocument creates a document from its raw content.
+func NewDocument(data []byte) (*Document, error) {
+	var doc any
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	summary, err := csaf.NewAdvisorySummary(util.NewPathEval(), doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSAF document: %w", err)
+	}
+	return &Document{Data: data, Summary: summary}, nil
+}
+
+// Filename returns the file name of the document derived from its tracking id.
+func (d *Document) Filename() string {
+	return util.CleanFileName(d.Summary.ID)
+}
+
+// TLP returns the lower case TLP label of the document.
+func (d *Document) TLP() string {
+	if d.Summary.TLPLabel == "" {
+		return "unlabeled"
+	}
+	return strings.ToLower(d.Summary.TLPLabel)
+}
+
+// Updated returns the time of the last update of the document.
+func (d *Document) Updated() time.Time {
+	return d.Summary.CurrentReleaseDate.UTC()
+}
+
+// path returns the path of the document relative to the TLP folder.
+func (d *Document) path() string {
+	return fmt.Sprintf("%d/%s", d.Summary.InitialReleaseDate.Year(), d.Filename())
+}
+
+// LoadDocuments loads the CSAF documents from a directory and its
+// sub directories. Only the newest version of a document is kept.
+func LoadDocuments(dir string) ([]*Document, error) {
+	byID := map[string]*Document{}
+	var order []string
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || !strings.HasSuffix(strings.ToLower(path), ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := NewDocument(data)
+		if err != nil {
+			return fmt.Errorf("loading %q failed: %w", path, err)
+		}
+		id := doc.Summary.ID
+		switch have, ok := byID[id]; {
+		case !ok:
+			order = append(order, id)
+			byID[id] = doc
+		case doc.Updated().After(have.Updated()):
+			byID[id] = doc
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	docs := make([]*Document, 0, len(order))
+	for _, id := range order {
+		docs = append(docs, byID[id])
+	}
+	return docs, nil
+}
@@ -0,0 +1,68 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package csafmock
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// Faults are the errors injected by the provider. The patterns
+// are matched against the paths of the requests. Nil patterns
+// match nothing.
+type Faults struct {
+	// BadHashes selects the documents served with wrong hashes.
+	BadHashes *regexp.Regexp
+	// BadSignatures selects the documents served with wrong signatures.
+	BadSignatures *regexp.Regexp
+	// Slow selects the requests which are answered after Delay.
+	Slow  *regexp.Regexp
+	Delay time.Duration
+	// Errors selects the requests which fail with ErrorStatus.
+	// ErrorRate is the probability of a failure between 0 and 1.
+	Errors      *regexp.Regexp
+	ErrorRate   float64
+	ErrorStatus int
+}
+
+func matches(re *regexp.Regexp, path string) bool {
+	return re != nil && re.MatchString(path)
+}
+
+// badHash tells if the hash of the document at path should be wrong.
+func (f *Faults) badHash(path string) bool { return matches(f.BadHashes, path) }
+
+// badSignature tells if the signature of the document at path should be wrong.
+func (f *Faults) badSignature(path string) bool { return matches(f.BadSignatures, path) }
+
+// intercept applies the slow responses and the errors to a request.
+// It returns true if the request is answered already.
+func (f *Faults) intercept(w http.ResponseWriter, r *http.Request) bool {
+	path := r.URL.Path
+	if matches(f.Slow, path) && f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	if matches(f.Errors, path) && rand.Float64() < f.ErrorRate {
+		status := f.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, http.StatusText(status), status)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,409 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package csafmock implements a CSAF trusted provider and a CSAF lister
+// serving a given set of documents. It is intended for development
+// and for end-to-end tests without network access.
+package csafmock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+	"github.com/gocsaf/csaf/v3/csaf"
+)
+
+const (
+	// ProviderMetadataPath is the path of the provider-metadata.json.
+	ProviderMetadataPath = "/.well-known/csaf/provider-metadata.json"
+	// AggregatorPath is the path of the aggregator.json.
+	AggregatorPath = "/.well-known/csaf-aggregator/aggregator.json"
+
+	wellKnown = "/.well-known/csaf"
+)
+
+// servedDocument is a document with its hashes and signatures.
+type servedDocument struct {
+	*Document
+	sha256, sha512, signature          []byte
+	badSHA256, badSHA512, badSignature []byte
+}
+
+// Provider is a CSAF trusted provider. It offers a ROLIE feed and a
+// directory based feed per TLP label and lists itself in an aggregator.
+// The URLs are derived from the requests so the provider can be
+// served under any address.
+type Provider struct {
+	// Faults are the errors injected into the responses.
+	// They should not be changed while serving.
+	Faults Faults
+
+	docs        map[string]*servedDocument
+	tlps        []string
+	publisher   *csaf.Publisher
+	fingerprint string
+	publicKey   string
+	updated     time.Time
+	mux         *http.ServeMux
+}
+
+// NewProvider creates a provider serving the given documents. The
+// documents are signed with the given unlocked OpenPGP key. If the
+// key is nil a new one is generated.
+func NewProvider(docs []*Document, key *crypto.Key) (*Provider, error) {
+	if key == nil {
+		var err error
+		if key, err = crypto.GenerateKey("csafmock", "csafmock@example.com", "x25519", 0); err != nil {
+			return nil, fmt.Errorf("generating OpenPGP key failed: %w", err)
+		}
+	}
+	locked, err := key.IsLocked()
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, errors.New("OpenPGP key is locked")
+	}
+	ring, err := crypto.NewKeyRing(key)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := key.GetArmoredPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	p := &Provider{
+		docs:        make(map[string]*servedDocument, len(docs)),
+		fingerprint: strings.ToUpper(key.GetFingerprint()),
+		publicKey:   publicKey,
+		updated:     time.Now().UTC().Truncate(time.Second),
+		publisher:   defaultPublisher(),
+	}
+	for i, doc := range docs {
+		if i == 0 && doc.Summary.Publisher != nil {
+			p.publisher = doc.Summary.Publisher
+		}
+		sd, err := serve(doc, ring)
+		if err != nil {
+			return nil, err
+		}
+		p.docs[doc.TLP()+"/"+doc.path()] = sd
+		if !slices.Contains(p.tlps, doc.TLP()) {
+			p.tlps = append(p.tlps, doc.TLP())
+		}
+	}
+	slices.Sort(p.tlps)
+
+	p.mux = http.NewServeMux()
+	p.mux.HandleFunc("GET /.well-known/security.txt", p.securityTxt)
+	p.mux.HandleFunc("GET "+ProviderMetadataPath, p.providerMetadata)
+	p.mux.HandleFunc("GET "+AggregatorPath, p.aggregator)
+	p.mux.HandleFunc("GET "+wellKnown+"/openpgp/{file}", p.openPGPKey)
+	p.mux.HandleFunc("GET "+wellKnown+"/{tlp}/{file}", p.index)
+	p.mux.HandleFunc("GET "+wellKnown+"/{tlp}/{year}/{file}", p.document)
+	return p, nil
+}
+
+func defaultPublisher() *csaf.Publisher {
+	category := csaf.CSAFCategoryOther
+	name, namespace := "csafmock", "https://example.com"
+	return &csaf.Publisher{Category: &category, Name: &name, Namespace: &namespace}
+}
+
+// serve calculates the hashes and the signatures of a document.
+// The bad ones are calculated for slightly modified content.
+func serve(doc *Document, ring *crypto.KeyRing) (*servedDocument, error) {
+	sd := &servedDocument{Document: doc}
+	filename := doc.Filename()
+	modified := append(bytes.Clone(doc.Data), '\n')
+	hashes := func(data []byte) ([]byte, []byte) {
+		s256, s512 := sha256.Sum256(data), sha512.Sum512(data)
+		return fmt.Appendf(nil, "%x  %s\n", s256, filename),
+			fmt.Appendf(nil, "%x  %s\n", s512, filename)
+	}
+	sign := func(data []byte) ([]byte, error) {
+		sig, err := ring.SignDetached(crypto.NewPlainMessage(data))
+		if err != nil {
+			return nil, fmt.Errorf("signing %q failed: %w", filename, err)
+		}
+		armored, err := sig.GetArmored()
+		return []byte(armored), err
+	}
+	sd.sha256, sd.sha512 = hashes(doc.Data)
+	sd.badSHA256, sd.badSHA512 = hashes(modified)
+	var err error
+	if sd.signature, err = sign(doc.Data); err != nil {
+		return nil, err
+	}
+	if sd.badSignature, err = sign(modified); err != nil {
+		return nil, err
+	}
+	return sd, nil
+}
+
+// Fingerprint returns the fingerprint of the OpenPGP key.
+func (p *Provider) Fingerprint() string { return p.fingerprint }
+
+// PublicKey returns the armored public OpenPGP key.
+func (p *Provider) PublicKey() string { return p.publicKey }
+
+// ServeHTTP implements [http.Handler].
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.Faults.intercept(w, r) {
+		return
+	}
+	p.mux.ServeHTTP(w, r)
+}
+
+// prefix returns the URL of the CSAF folder of the provider.
+func prefix(r *http.Request) string {
+	scheme := "https"
+	if r.TLS == nil {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Host + wellKnown
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func (p *Provider) securityTxt(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "CSAF: %s/provider-metadata.json\n", prefix(r))
+}
+
+func (p *Provider) providerMetadata(w http.ResponseWriter, r *http.Request) {
+	pre := prefix(r)
+	pmd := csaf.NewProviderMetadata(pre + "/provider-metadata.json")
+	pmd.SetLastUpdated(p.updated)
+	pmd.Publisher = p.publisher
+	pmd.SetPGP(p.fingerprint, pre+"/openpgp/"+p.fingerprint+".asc")
+	feeds := make([]csaf.Feed, 0, len(p.tlps))
+	for _, tlp := range p.tlps {
+		label := csaf.TLPLabel(strings.ToUpper(tlp))
+		url := csaf.JSONURL(pre + "/" + tlp + "/csaf-feed-tlp-" + tlp + ".json")
+		feeds = append(feeds, csaf.Feed{
+			Summary:  "TLP:" + string(label) + " advisories",
+			TLPLabel: &label,
+			URL:      &url,
+		})
+	}
+	if len(feeds) > 0 {
+		pmd.Distributions = append(pmd.Distributions, csaf.Distribution{
+			Rolie: &csaf.ROLIE{Feeds: feeds},
+		})
+	}
+	for _, tlp := range p.tlps {
+		pmd.AddDirectoryDistribution(pre + "/" + tlp + "/")
+	}
+	writeJSON(w, pmd)
+}
+
+func (p *Provider) aggregator(w http.ResponseWriter, r *http.Request) {
+	var (
+		category     = csaf.AggregatorLister
+		version      = csaf.AggregatorVersion20
+		role         = csaf.MetadataRoleTrustedProvider
+		base         = strings.TrimSuffix(prefix(r), wellKnown)
+		canonicalURL = csaf.AggregatorURL(base + AggregatorPath)
+		pmdURL       = csaf.ProviderURL(base + ProviderMetadataPath)
+		updated      = csaf.TimeStamp(p.updated)
+	)
+	writeJSON(w, &csaf.Aggregator{
+		Aggregator: &csaf.AggregatorInfo{
+			Category:  &category,
+			Name:      "csafmock",
+			Namespace: base,
+		},
+		Version:      &version,
+		CanonicalURL: &canonicalURL,
+		CSAFProviders: []*csaf.AggregatorCSAFProvider{{
+			Metadata: &csaf.AggregatorCSAFProviderMetadata{
+				LastUpdated: &updated,
+				Publisher:   p.publisher,
+				Role:        &role,
+				URL:         &pmdURL,
+			},
+		}},
+		LastUpdated: &updated,
+	})
+}
+
+func (p *Provider) openPGPKey(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("file") != p.fingerprint+".asc" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pgp-keys")
+	fmt.Fprint(w, p.publicKey)
+}
+
+// documents returns the documents with a given TLP newest first.
+func (p *Provider) documents(tlp string) []*servedDocument {
+	var docs []*servedDocument
+	for _, doc := range p.docs {
+		if doc.TLP() == tlp {
+			docs = append(docs, doc)
+		}
+	}
+	slices.SortFunc(docs, func(a, b *servedDocument) int {
+		if c := b.Updated().Compare(a.Updated()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.path(), b.path())
+	})
+	return docs
+}
+
+// index serves the ROLIE feed, the changes.csv and the index.txt of a TLP.
+func (p *Provider) index(w http.ResponseWriter, r *http.Request) {
+	tlp, file := r.PathValue("tlp"), r.PathValue("file")
+	if !slices.Contains(p.tlps, tlp) {
+		http.NotFound(w, r)
+		return
+	}
+	docs := p.documents(tlp)
+	var newest time.Time
+	if len(docs) > 0 {
+		newest = docs[0].Updated()
+	}
+	var (
+		content     bytes.Buffer
+		contentType = "text/plain"
+	)
+	switch file {
+	case "csaf-feed-tlp-" + tlp + ".json":
+		p.rolieFeed(w, r, tlp, docs, newest)
+		return
+	case "changes.csv":
+		contentType = "text/csv"
+		c := csv.NewWriter(&content)
+		for _, doc := range docs {
+			c.Write([]string{doc.path(), doc.Updated().Format(time.RFC3339)})
+		}
+		c.Flush()
+	case "index.txt":
+		for _, doc := range docs {
+			fmt.Fprintln(&content, doc.path())
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	// Support conditional and range requests.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Etag", fmt.Sprintf(`"%x"`, sha256.Sum256(content.Bytes())))
+	http.ServeContent(w, r, file, newest, bytes.NewReader(content.Bytes()))
+}
+
+func (p *Provider) rolieFeed(
+	w http.ResponseWriter,
+	r *http.Request,
+	tlp string,
+	docs []*servedDocument,
+	newest time.Time,
+) {
+	pre := prefix(r)
+	label := strings.ToUpper(tlp)
+	feedID := "csaf-feed-tlp-" + tlp
+	rolie := &csaf.ROLIEFeed{
+		Feed: csaf.FeedData{
+			ID:    feedID,
+			Title: "CSAF feed (TLP:" + label + ")",
+			Link: []csaf.Link{{
+				Rel:  "self",
+				HRef: pre + "/" + tlp + "/" + feedID + ".json",
+			}},
+			Category: []csaf.ROLIECategory{{
+				Scheme: "urn:ietf:params:rolie:category:information-type",
+				Term:   "csaf",
+			}},
+			Updated: csaf.TimeStamp(newest),
+			Entry:   make([]*csaf.Entry, 0, len(docs)),
+		},
+	}
+	for _, doc := range docs {
+		url := pre + "/" + tlp + "/" + doc.path()
+		entry := &csaf.Entry{
+			ID:        doc.Summary.ID,
+			Titel:     doc.Summary.Title,
+			Published: csaf.TimeStamp(doc.Summary.InitialReleaseDate.UTC()),
+			Updated:   csaf.TimeStamp(doc.Updated()),
+			Link: []csaf.Link{
+				{Rel: "self", HRef: url},
+				{Rel: "hash", HRef: url + ".sha256"},
+				{Rel: "hash", HRef: url + ".sha512"},
+				{Rel: "signature", HRef: url + ".asc"},
+			},
+			Format: csaf.Format{
+				Schema:  "https://docs.oasis-open.org/csaf/csaf/v2.0/csaf_json_schema.json",
+				Version: "2.0",
+			},
+			Content: csaf.Content{Type: "application/json", Src: url},
+		}
+		if doc.Summary.Summary != "" {
+			entry.Summary = &csaf.Summary{Content: doc.Summary.Summary}
+		}
+		rolie.Feed.Entry = append(rolie.Feed.Entry, entry)
+	}
+	writeJSON(w, rolie)
+}
+
+// document serves a document, its hashes and its signature.
+func (p *Provider) document(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	name, ext := file, ""
+	for _, suffix := range []string{".sha256", ".sha512", ".asc"} {
+		if base, ok := strings.CutSuffix(file, suffix); ok {
+			name, ext = base, suffix
+			break
+		}
+	}
+	doc := p.docs[r.PathValue("tlp")+"/"+r.PathValue("year")+"/"+name]
+	if doc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	docPath := strings.TrimSuffix(r.URL.Path, ext)
+	var content []byte
+	switch ext {
+	case "":
+		w.Header().Set("Content-Type", "application/json")
+		content = doc.Data
+	case ".sha256":
+		content = doc.sha256
+		if p.Faults.badHash(docPath) {
+			content = doc.badSHA256
+		}
+	case ".sha512":
+		content = doc.sha512
+		if p.Faults.badHash(docPath) {
+			content = doc.badSHA512
+		}
+	case ".asc":
+		content = doc.signature
+		if p.Faults.badSignature(docPath) {
+			content = doc.badSignature
+		}
+	}
+	http.ServeContent(w, r, file, doc.Updated(), bytes.NewReader(content))
+}
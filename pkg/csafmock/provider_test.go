@@ -0,0 +1,218 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package csafmock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+	"github.com/gocsaf/csaf/v3/csaf"
+	"github.com/gocsaf/csaf/v3/util"
+)
+
+func newTestServer(t *testing.T) (*Provider, *httptest.Server) {
+	t.Helper()
+	docs, err := LoadDocuments("../../docs/example-advisories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("have %d documents want 2", len(docs))
+	}
+	p, err := NewProvider(docs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewTLSServer(p)
+	t.Cleanup(server.Close)
+	return p, server
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, []byte) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, data
+}
+
+func loadFeed(t *testing.T, client *http.Client, url string) *csaf.ROLIEFeed {
+	t.Helper()
+	status, data := get(t, client, url)
+	if status != http.StatusOK {
+		t.Fatalf("loading ROLIE feed failed: %d", status)
+	}
+	feed, err := csaf.LoadROLIEFeed(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return feed
+}
+
+// verify checks the hash and the signature of a ROLIE entry.
+func verify(t *testing.T, p *Provider, client *http.Client, entry *csaf.Entry) (bool, bool) {
+	t.Helper()
+	links := map[string]string{}
+	for _, link := range entry.Link {
+		if _, ok := links[link.Rel]; !ok {
+			links[link.Rel] = link.HRef
+		}
+	}
+	_, doc := get(t, client, links["self"])
+	_, hash := get(t, client, links["hash"])
+	remote, err := util.HashFromReader(bytes.NewReader(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := sha256.Sum256(doc)
+
+	_, armored := get(t, client, links["signature"])
+	signature, err := crypto.NewPGPSignatureFromArmored(string(armored))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.NewKeyFromArmored(p.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := crypto.NewKeyRing(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := ring.VerifyDetached(crypto.NewPlainMessage(doc), signature, crypto.GetUnixTime()) == nil
+	return bytes.Equal(remote, local[:]), signed
+}
+
+func TestProviderMetadata(t *testing.T) {
+	p, server := newTestServer(t)
+	loader := csaf.NewProviderMetadataLoader(server.Client())
+	lpmd := loader.Load(server.URL + ProviderMetadataPath)
+	if !lpmd.Valid() {
+		t.Fatalf("invalid provider metadata: %v", lpmd.Messages)
+	}
+	var pmd csaf.ProviderMetadata
+	if err := util.ReMarshalJSON(&pmd, lpmd.Document); err != nil {
+		t.Fatal(err)
+	}
+	if len(pmd.PGPKeys) != 1 || string(pmd.PGPKeys[0].Fingerprint) != p.Fingerprint() {
+		t.Errorf("unexpected OpenPGP keys: %v", pmd.PGPKeys)
+	}
+	var rolie, directory int
+	for _, dist := range pmd.Distributions {
+		if dist.Rolie != nil {
+			rolie += len(dist.Rolie.Feeds)
+		}
+		if dist.DirectoryURL != "" {
+			directory++
+		}
+	}
+	if rolie != 1 || directory != 1 {
+		t.Errorf("have %d ROLIE and %d directory feeds want 1 each", rolie, directory)
+	}
+	status, key := get(t, server.Client(), *pmd.PGPKeys[0].URL)
+	if status != http.StatusOK || string(key) != p.PublicKey() {
+		t.Errorf("loading OpenPGP key failed: %d", status)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	p, server := newTestServer(t)
+	client := server.Client()
+	feed := loadFeed(t, client, server.URL+wellKnown+"/white/csaf-feed-tlp-white.json")
+	if n := feed.CountEntries(); n != 2 {
+		t.Fatalf("have %d entries want 2", n)
+	}
+	for _, entry := range feed.Feed.Entry {
+		if hashOK, signatureOK := verify(t, p, client, entry); !hashOK || !signatureOK {
+			t.Errorf("%s: hash %t signature %t", entry.ID, hashOK, signatureOK)
+		}
+	}
+	status, changes := get(t, client, server.URL+wellKnown+"/white/changes.csv")
+	if status != http.StatusOK {
+		t.Fatalf("loading changes.csv failed: %d", status)
+	}
+	lines := strings.Split(strings.TrimSpace(string(changes)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "2024/avendor-advisory-0004.json,") {
+		t.Errorf("unexpected changes.csv: %q", changes)
+	}
+	status, index := get(t, client, server.URL+wellKnown+"/white/index.txt")
+	if status != http.StatusOK || strings.Count(string(index), "\n") != 2 {
+		t.Errorf("unexpected index.txt: %d %q", status, index)
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	_, server := newTestServer(t)
+	status, data := get(t, server.Client(), server.URL+AggregatorPath)
+	if status != http.StatusOK {
+		t.Fatalf("loading aggregator failed: %d", status)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := csaf.ValidateAggregator(doc)
+	if err != nil || len(msgs) > 0 {
+		t.Fatalf("invalid aggregator: %v %v", err, msgs)
+	}
+	var agg csaf.Aggregator
+	if err := json.Unmarshal(data, &agg); err != nil {
+		t.Fatal(err)
+	}
+	if len(agg.CSAFProviders) != 1 ||
+		string(*agg.CSAFProviders[0].Metadata.URL) != server.URL+ProviderMetadataPath {
+		t.Errorf("unexpected providers: %v", agg.CSAFProviders)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	p, server := newTestServer(t)
+	client := server.Client()
+	feed := loadFeed(t, client, server.URL+wellKnown+"/white/csaf-feed-tlp-white.json")
+
+	p.Faults.BadHashes = regexp.MustCompile(`0004\.json$`)
+	p.Faults.BadSignatures = regexp.MustCompile(`0005\.json$`)
+	for _, entry := range feed.Feed.Entry {
+		hashOK, signatureOK := verify(t, p, client, entry)
+		bad0004 := strings.HasSuffix(entry.ID, "0004")
+		if hashOK == bad0004 || signatureOK != bad0004 {
+			t.Errorf("%s: hash %t signature %t", entry.ID, hashOK, signatureOK)
+		}
+	}
+
+	p.Faults = Faults{Errors: regexp.MustCompile(`changes\.csv$`), ErrorRate: 1}
+	if status, _ := get(t, client, server.URL+wellKnown+"/white/changes.csv"); status != http.StatusServiceUnavailable {
+		t.Errorf("have status %d want %d", status, http.StatusServiceUnavailable)
+	}
+	if status, _ := get(t, client, server.URL+wellKnown+"/white/index.txt"); status != http.StatusOK {
+		t.Errorf("have status %d want %d", status, http.StatusOK)
+	}
+
+	p.Faults = Faults{Slow: regexp.MustCompile(`index\.txt$`), Delay: 50 * time.Millisecond}
+	start := time.Now()
+	get(t, client, server.URL+wellKnown+"/white/index.txt")
+	if elapsed := time.Since(start); elapsed < p.Faults.Delay {
+		t.Errorf("response after %v is too fast", elapsed)
+	}
+}
@@ -0,0 +1,257 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package cvss implements the parsing and the base and environmental
+// scoring of CVSS v2, v3.x and v4 vectors.
+package cvss
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The supported CVSS versions.
+const (
+	Version20 = "2.0"
+	Version30 = "3.0"
+	Version31 = "3.1"
+	Version40 = "4.0"
+)
+
+// metric defines a metric of a CVSS version.
+type metric struct {
+	name          string
+	values        []string
+	required      bool
+	environmental bool
+}
+
+// metrics are the metrics of the CVSS versions in the order of their specifications.
+var metrics = map[string][]metric{
+	Version20: {
+		{"AV", []string{"L", "A", "N"}, true, false},
+		{"AC", []string{"H", "M", "L"}, true, false},
+		{"Au", []string{"M", "S", "N"}, true, false},
+		{"C", []string{"N", "P", "C"}, true, false},
+		{"I", []string{"N", "P", "C"}, true, false},
+		{"A", []string{"N", "P", "C"}, true, false},
+		{"E", []string{"U", "POC", "F", "H", "ND"}, false, false},
+		{"RL", []string{"OF", "TF", "W", "U", "ND"}, false, false},
+		{"RC", []string{"UC", "UR", "C", "ND"}, false, false},
+		{"CDP", []string{"N", "L", "LM", "MH", "H", "ND"}, false, true},
+		{"TD", []string{"N", "L", "M", "H", "ND"}, false, true},
+		{"CR", []string{"L", "M", "H", "ND"}, false, true},
+		{"IR", []string{"L", "M", "H", "ND"}, false, true},
+		{"AR", []string{"L", "M", "H", "ND"}, false, true},
+	},
+	Version30: metrics3,
+	Version31: metrics3,
+	Version40: {
+		{"AV", []string{"N", "A", "L", "P"}, true, false},
+		{"AC", []string{"L", "H"}, true, false},
+		{"AT", []string{"N", "P"}, true, false},
+		{"PR", []string{"N", "L", "H"}, true, false},
+		{"UI", []string{"N", "P", "A"}, true, false},
+		{"VC", []string{"H", "L", "N"}, true, false},
+		{"VI", []string{"H", "L", "N"}, true, false},
+		{"VA", []string{"H", "L", "N"}, true, false},
+		{"SC", []string{"H", "L", "N"}, true, false},
+		{"SI", []string{"H", "L", "N"}, true, false},
+		{"SA", []string{"H", "L", "N"}, true, false},
+		{"E", []string{"X", "A", "P", "U"}, false, false},
+		{"CR", []string{"X", "H", "M", "L"}, false, true},
+		{"IR", []string{"X", "H", "M", "L"}, false, true},
+		{"AR", []string{"X", "H", "M", "L"}, false, true},
+		{"MAV", []string{"X", "N", "A", "L", "P"}, false, true},
+		{"MAC", []string{"X", "L", "H"}, false, true},
+		{"MAT", []string{"X", "N", "P"}, false, true},
+		{"MPR", []string{"X", "N", "L", "H"}, false, true},
+		{"MUI", []string{"X", "N", "P", "A"}, false, true},
+		{"MVC", []string{"X", "H", "L", "N"}, false, true},
+		{"MVI", []string{"X", "H", "L", "N"}, false, true},
+		{"MVA", []string{"X", "H", "L", "N"}, false, true},
+		{"MSC", []string{"X", "H", "L", "N"}, false, true},
+		{"MSI", []string{"X", "S", "H", "L", "N"}, false, true},
+		{"MSA", []string{"X", "S", "H", "L", "N"}, false, true},
+		{"S", []string{"X", "N", "P"}, false, false},
+		{"AU", []string{"X", "N", "Y"}, false, false},
+		{"R", []string{"X", "A", "U", "I"}, false, false},
+		{"V", []string{"X", "D", "C"}, false, false},
+		{"RE", []string{"X", "L", "M", "H"}, false, false},
+		{"U", []string{"X", "Clear", "Green", "Amber", "Red"}, false, false},
+	},
+}
+
+// metrics3 are the metrics of CVSS v3.0 and v3.1.
+var metrics3 = []metric{
+	{"AV", []string{"N", "A", "L", "P"}, true, false},
+	{"AC", []string{"L", "H"}, true, false},
+	{"PR", []string{"N", "L", "H"}, true, false},
+	{"UI", []string{"N", "R"}, true, false},
+	{"S", []string{"U", "C"}, true, false},
+	{"C", []string{"H", "L", "N"}, true, false},
+	{"I", []string{"H", "L", "N"}, true, false},
+	{"A", []string{"H", "L", "N"}, true, false},
+	{"E", []string{"X", "H", "F", "P", "U"}, false, false},
+	{"RL", []string{"X", "U", "W", "T", "O"}, false, false},
+	{"RC", []string{"X", "C", "R", "U"}, false, false},
+	{"CR", []string{"X", "H", "M", "L"}, false, true},
+	{"IR", []string{"X", "H", "M", "L"}, false, true},
+	{"AR", []string{"X", "H", "M", "L"}, false, true},
+	{"MAV", []string{"X", "N", "A", "L", "P"}, false, true},
+	{"MAC", []string{"X", "L", "H"}, false, true},
+	{"MPR", []string{"X", "N", "L", "H"}, false, true},
+	{"MUI", []string{"X", "N", "R"}, false, true},
+	{"MS", []string{"X", "U", "C"}, false, true},
+	{"MC", []string{"X", "H", "L", "N"}, false, true},
+	{"MI", []string{"X", "H", "L", "N"}, false, true},
+	{"MA", []string{"X", "H", "L", "N"}, false, true},
+}
+
+// Vector is a parsed CVSS vector.
+type Vector struct {
+	// Version is the CVSS version of the vector.
+	Version string
+	metrics map[string]string
+}
+
+// findMetric looks up the definition of a metric of a CVSS version.
+func findMetric(version, name string) *metric {
+	defs := metrics[version]
+	if idx := slices.IndexFunc(defs, func(m metric) bool { return m.name == name }); idx >= 0 {
+		return &defs[idx]
+	}
+	return nil
+}
+
+// Parse parses a CVSS vector. Vectors without a "CVSS:" prefix
+// are considered to be CVSS v2 vectors.
+func Parse(s string) (*Vector, error) {
+	s = strings.TrimSpace(s)
+	version := Version20
+	if rest, ok := strings.CutPrefix(s, "CVSS:"); ok {
+		if version, s, ok = strings.Cut(rest, "/"); !ok {
+			return nil, errors.New("missing metrics")
+		}
+		if _, ok := metrics[version]; !ok || version == Version20 {
+			return nil, fmt.Errorf("unsupported CVSS version %q", version)
+		}
+	} else if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = s[1 : len(s)-1]
+	}
+	v := &Vector{Version: version, metrics: map[string]string{}}
+	for part := range strings.SplitSeq(s, "/") {
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid metric %q", part)
+		}
+		m := findMetric(version, name)
+		if m == nil {
+			return nil, fmt.Errorf("unknown metric %q", name)
+		}
+		if !slices.Contains(m.values, value) {
+			return nil, fmt.Errorf("invalid value %q of metric %q", value, name)
+		}
+		if _, dup := v.metrics[name]; dup {
+			return nil, fmt.Errorf("duplicate metric %q", name)
+		}
+		v.metrics[name] = value
+	}
+	for _, m := range metrics[version] {
+		if _, ok := v.metrics[m.name]; m.required && !ok {
+			return nil, fmt.Errorf("missing metric %q", m.name)
+		}
+	}
+	return v, nil
+}
+
+// Metric returns the value of a metric or an empty string if it is not set.
+func (v *Vector) Metric(name string) string {
+	return v.metrics[name]
+}
+
+// String implements [fmt.Stringer].
+func (v *Vector) String() string {
+	var b strings.Builder
+	if v.Version != Version20 {
+		b.WriteString("CVSS:" + v.Version)
+	}
+	for _, m := range metrics[v.Version] {
+		if value, ok := v.metrics[m.name]; ok {
+			if b.Len() > 0 {
+				b.WriteByte('/')
+			}
+			b.WriteString(m.name + ":" + value)
+		}
+	}
+	return b.String()
+}
+
+// IsEnvironmental tells if the given value of an environmental
+// metric is valid in at least one of the CVSS versions.
+func IsEnvironmental(name, value string) bool {
+	for version := range metrics {
+		if m := findMetric(version, name); m != nil && m.environmental && slices.Contains(m.values, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// unset tells if a metric value is not defined.
+func unset(value string) bool {
+	return value == "" || value == "X" || value == "ND"
+}
+
+// WithDefaults returns a copy of the vector where the environmental
+// metrics not defined in the vector are set to the given defaults.
+// Defaults not valid for the CVSS version of the vector are ignored.
+func (v *Vector) WithDefaults(defaults map[string]string) *Vector {
+	nv := &Vector{Version: v.Version, metrics: make(map[string]string, len(v.metrics))}
+	for name, value := range v.metrics {
+		nv.metrics[name] = value
+	}
+	for name, value := range defaults {
+		if m := findMetric(v.Version, name); m != nil && m.environmental &&
+			slices.Contains(m.values, value) && unset(nv.metrics[name]) {
+			nv.metrics[name] = value
+		}
+	}
+	return nv
+}
+
+// BaseScore returns the base score of the vector.
+// For CVSS v4 vectors this is the CVSS-B score.
+func (v *Vector) BaseScore() (float64, bool) {
+	switch v.Version {
+	case Version20:
+		return v.base2(), true
+	case Version30, Version31:
+		return v.base3(), true
+	case Version40:
+		return v.score4(false), true
+	}
+	return 0, false
+}
+
+// EnvironmentalScore returns the environmental score of the vector
+// with the given defaults of the environmental metrics applied.
+// For CVSS v4 vectors this is the CVSS-BTE score.
+func (v *Vector) EnvironmentalScore(defaults map[string]string) (float64, bool) {
+	switch v = v.WithDefaults(defaults); v.Version {
+	case Version20:
+		return v.environmental2(), true
+	case Version30, Version31:
+		return v.environmental3(), true
+	case Version40:
+		return v.score4(true), true
+	}
+	return 0, false
+}
@@ -0,0 +1,95 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package cvss
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	for _, input := range []struct {
+		vector  string
+		version string
+		valid   bool
+	}{
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", Version20, true},
+		{"(AV:N/AC:L/Au:N/C:P/I:P/A:P)", Version20, true},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", Version31, true},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P/CR:H", Version30, true},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:A", Version40, true},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H", "", false},
+		{"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "", false},
+		{"CVSS:3.1/AV:N/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "", false},
+		{"CVSS:3.2/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "", false},
+		{"CVSS:4.0/AV:N/AC:L/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", "", false},
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P/XX:Y", "", false},
+	} {
+		v, err := Parse(input.vector)
+		if (err == nil) != input.valid {
+			t.Errorf("%q: have error %v want valid %t", input.vector, err, input.valid)
+			continue
+		}
+		if err == nil && v.Version != input.version {
+			t.Errorf("%q: have version %q want %q", input.vector, v.Version, input.version)
+		}
+	}
+}
+
+func TestScores(t *testing.T) {
+	for _, input := range []struct {
+		vector        string
+		defaults      map[string]string
+		base          float64
+		environmental float64
+	}{
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", nil, 7.5, 7.5},
+		{"AV:N/AC:M/Au:N/C:N/I:P/A:N", nil, 4.3, 4.3},
+		{"AV:N/AC:L/Au:N/C:N/I:N/A:C/E:F/RL:OF/RC:C/CDP:H/TD:H/CR:M/IR:M/AR:H", nil, 7.8, 9.2},
+		{"AV:N/AC:L/Au:N/C:C/I:C/A:C/E:F/RL:OF/RC:C",
+			map[string]string{"CDP": "H", "TD": "H", "CR": "M", "IR": "M", "AR": "L"}, 10.0, 9.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", nil, 9.8, 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", nil, 10.0, 10.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", nil, 6.5, 6.5},
+		{"CVSS:3.0/AV:L/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H", nil, 7.8, 7.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			map[string]string{"CR": "L", "IR": "L", "AR": "L"}, 9.8, 8.0},
+		// Metrics of the vector take precedence over the defaults.
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/CR:H/IR:H/AR:H",
+			map[string]string{"CR": "L", "IR": "L", "AR": "L", "CDP": "H"}, 9.8, 9.8},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", nil, 10.0, 10.0},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", nil, 0.0, 0.0},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", nil, 9.3, 9.3},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:H/SI:H/SA:H", nil, 7.9, 7.9},
+		{"CVSS:4.0/AV:P/AC:H/AT:P/PR:H/UI:A/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N", nil, 1.0, 1.0},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:P/VC:N/VI:H/VA:H/SC:N/SI:L/SA:L", nil, 5.2, 5.2},
+		// The threat metrics only count in the environmental score.
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H/E:U", nil, 10.0, 9.1},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H/MVI:L/MSA:S", nil, 10.0, 9.8},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:P/VC:N/VI:H/VA:H/SC:N/SI:L/SA:L/E:P/CR:H/IR:M/AR:H/" +
+			"MAV:A/MAT:P/MPR:N/MVI:H/MVA:N/MSI:H/MSA:N/S:N/V:C/U:Amber", nil, 5.2, 4.7},
+		{"CVSS:4.0/AV:N/AC:H/AT:N/PR:H/UI:N/VC:N/VI:N/VA:H/SC:H/SI:H/SA:H/CR:L/IR:L/AR:L", nil, 7.2, 5.8},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			map[string]string{"CR": "L", "IR": "L", "AR": "L"}, 9.3, 8.9},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/CR:H",
+			map[string]string{"CR": "L", "IR": "L", "AR": "L", "MAV": "L", "CDP": "H"}, 9.3, 8.4},
+		// Modifying all impacts to none leaves no risk.
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			map[string]string{"MVC": "N", "MVI": "N", "MVA": "N"}, 9.3, 0.0},
+	} {
+		v, err := Parse(input.vector)
+		if err != nil {
+			t.Errorf("%q: %v", input.vector, err)
+			continue
+		}
+		if base, ok := v.BaseScore(); !ok || base != input.base {
+			t.Errorf("%q: have base score %.1f want %.1f", input.vector, base, input.base)
+		}
+		if env, ok := v.EnvironmentalScore(input.defaults); !ok || env != input.environmental {
+			t.Errorf("%q: have environmental score %.1f want %.1f", input.vector, env, input.environmental)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package cvss
+
+import "math"
+
+// weights maps metric values to their numerical weights.
+type weights map[string]float64
+
+// weight returns the weight of a metric value.
+// Undefined values have the weight of the given fallback.
+func (w weights) weight(value, fallback string) float64 {
+	if unset(value) {
+		value = fallback
+	}
+	return w[value]
+}
+
+// CVSS v2 weights.
+var (
+	av2  = weights{"L": 0.395, "A": 0.646, "N": 1}
+	ac2  = weights{"H": 0.35, "M": 0.61, "L": 0.71}
+	au2  = weights{"M": 0.45, "S": 0.56, "N": 0.704}
+	cia2 = weights{"N": 0, "P": 0.275, "C": 0.660}
+	e2   = weights{"U": 0.85, "POC": 0.9, "F": 0.95, "H": 1, "ND": 1}
+	rl2  = weights{"OF": 0.87, "TF": 0.9, "W": 0.95, "U": 1, "ND": 1}
+	rc2  = weights{"UC": 0.9, "UR": 0.95, "C": 1, "ND": 1}
+	cdp2 = weights{"N": 0, "L": 0.1, "LM": 0.3, "MH": 0.4, "H": 0.5, "ND": 0}
+	td2  = weights{"N": 0, "L": 0.25, "M": 0.75, "H": 1, "ND": 1}
+	req2 = weights{"L": 0.5, "M": 1, "H": 1.51, "ND": 1}
+)
+
+// CVSS v3.x weights.
+var (
+	av3         = weights{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}
+	ac3         = weights{"L": 0.77, "H": 0.44}
+	prUnchanged = weights{"N": 0.85, "L": 0.62, "H": 0.27}
+	prChanged   = weights{"N": 0.85, "L": 0.68, "H": 0.5}
+	ui3         = weights{"N": 0.85, "R": 0.62}
+	cia3        = weights{"H": 0.56, "L": 0.22, "N": 0}
+	e3          = weights{"X": 1, "H": 1, "F": 0.97, "P": 0.94, "U": 0.91}
+	rl3         = weights{"X": 1, "U": 1, "W": 0.97, "T": 0.96, "O": 0.95}
+	rc3         = weights{"X": 1, "C": 1, "R": 0.96, "U": 0.92}
+	req3        = weights{"X": 1, "H": 1.5, "M": 1, "L": 0.5}
+)
+
+// round1 rounds to one decimal as specified by CVSS v2.
+func round1(x float64) float64 {
+	return math.Round(x*10) / 10
+}
+
+// roundUp rounds up to one decimal as specified by CVSS v3.1.
+// It avoids the floating point artifacts of the CVSS v3.0 definition
+// and is used for both versions.
+func roundUp(x float64) float64 {
+	i := int64(math.Round(x * 100_000))
+	if i%10_000 == 0 {
+		return float64(i) / 100_000
+	}
+	return float64(i/10_000+1) / 10
+}
+
+// score2 computes a CVSS v2 base score from the impact.
+func (v *Vector) score2(impact float64) float64 {
+	if impact == 0 {
+		return 0
+	}
+	exploitability := 20 * av2[v.metrics["AV"]] * ac2[v.metrics["AC"]] * au2[v.metrics["Au"]]
+	return round1((0.6*impact + 0.4*exploitability - 1.5) * 1.176)
+}
+
+// temporal2 returns the temporal factor of a CVSS v2 vector.
+func (v *Vector) temporal2() float64 {
+	return e2.weight(v.metrics["E"], "ND") *
+		rl2.weight(v.metrics["RL"], "ND") *
+		rc2.weight(v.metrics["RC"], "ND")
+}
+
+func (v *Vector) base2() float64 {
+	c, i, a := cia2[v.metrics["C"]], cia2[v.metrics["I"]], cia2[v.metrics["A"]]
+	return v.score2(10.41 * (1 - (1-c)*(1-i)*(1-a)))
+}
+
+func (v *Vector) environmental2() float64 {
+	c := cia2[v.metrics["C"]] * req2.weight(v.metrics["CR"], "ND")
+	i := cia2[v.metrics["I"]] * req2.weight(v.metrics["IR"], "ND")
+	a := cia2[v.metrics["A"]] * req2.weight(v.metrics["AR"], "ND")
+	impact := min(10, 10.41*(1-(1-c)*(1-i)*(1-a)))
+	temporal := round1(v.score2(impact) * v.temporal2())
+	cdp := cdp2.weight(v.metrics["CDP"], "ND")
+	td := td2.weight(v.metrics["TD"], "ND")
+	return round1((temporal + (10-temporal)*cdp) * td)
+}
+
+// modified returns the value of the modified metric
+// if it is defined or the value of the base metric.
+func (v *Vector) modified(name string) string {
+	if value := v.metrics["M"+name]; !unset(value) {
+		return value
+	}
+	return v.metrics[name]
+}
+
+// score3 computes a CVSS v3.x score from the impact and exploitability.
+func score3(impact, exploitability float64, changed bool) float64 {
+	switch {
+	case impact <= 0:
+		return 0
+	case changed:
+		return roundUp(min(1.08*(impact+exploitability), 10))
+	default:
+		return roundUp(min(impact+exploitability, 10))
+	}
+}
+
+// exploitability3 computes the CVSS v3.x exploitability of the given metrics.
+func exploitability3(av, ac, pr, ui string, changed bool) float64 {
+	prs := prUnchanged
+	if changed {
+		prs = prChanged
+	}
+	return 8.22 * av3[av] * ac3[ac] * prs[pr] * ui3[ui]
+}
+
+func (v *Vector) base3() float64 {
+	c, i, a := cia3[v.metrics["C"]], cia3[v.metrics["I"]], cia3[v.metrics["A"]]
+	iss := 1 - (1-c)*(1-i)*(1-a)
+	changed := v.metrics["S"] == "C"
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	return score3(impact,
+		exploitability3(v.metrics["AV"], v.metrics["AC"], v.metrics["PR"], v.metrics["UI"], changed),
+		changed)
+}
+
+func (v *Vector) environmental3() float64 {
+	c := cia3[v.modified("C")] * req3.weight(v.metrics["CR"], "X")
+	i := cia3[v.modified("I")] * req3.weight(v.metrics["IR"], "X")
+	a := cia3[v.modified("A")] * req3.weight(v.metrics["AR"], "X")
+	miss := min(1-(1-c)*(1-i)*(1-a), 0.915)
+	changed := v.modified("S") == "C"
+	impact := 6.42 * miss
+	if changed {
+		if v.Version == Version30 {
+			impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss-0.02, 15)
+		} else {
+			impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss*0.9731-0.02, 13)
+		}
+	}
+	score := score3(impact,
+		exploitability3(v.modified("AV"), v.modified("AC"), v.modified("PR"), v.modified("UI"), changed),
+		changed)
+	return roundUp(score *
+		e3.weight(v.metrics["E"], "X") *
+		rl3.weight(v.metrics["RL"], "X") *
+		rc3.weight(v.metrics["RC"], "X"))
+}
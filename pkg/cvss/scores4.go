@@ -0,0 +1,321 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package cvss
+
+import (
+	"math"
+	"strings"
+)
+
+// The CVSS v4 scoring follows the reference implementation of the
+// calculator of FIRST (https://github.com/FIRSTdotorg/cvss-v4-calculator).
+// The scores of the vectors are interpolated between the scores of
+// the macro vectors they belong to and the next lower ones.
+
+// macroVectors4 are the scores of the CVSS v4 macro vectors.
+// The keys are the levels of the equivalence classes EQ1 to EQ6.
+var macroVectors4 = map[string]float64{
+	"000000": 10, "000001": 9.9, "000010": 9.8, "000011": 9.5, "000020": 9.5, "000021": 9.2,
+	"000100": 10, "000101": 9.6, "000110": 9.3, "000111": 8.7, "000120": 9.1, "000121": 8.1,
+	"000200": 9.3, "000201": 9.0, "000210": 8.9, "000211": 8.0, "000220": 8.1, "000221": 6.8,
+	"001000": 9.8, "001001": 9.5, "001010": 9.5, "001011": 9.2, "001020": 9.0, "001021": 8.4,
+	"001100": 9.3, "001101": 9.2, "001110": 8.9, "001111": 8.1, "001120": 8.1, "001121": 6.5,
+	"001200": 8.8, "001201": 8.0, "001210": 7.8, "001211": 7.0, "001220": 6.9, "001221": 4.8,
+	"002001": 9.2, "002011": 8.2, "002021": 7.2, "002101": 7.9, "002111": 6.9, "002121": 5.0,
+	"002201": 6.9, "002211": 5.5, "002221": 2.7, "010000": 9.9, "010001": 9.7, "010010": 9.5,
+	"010011": 9.2, "010020": 9.2, "010021": 8.5, "010100": 9.5, "010101": 9.1, "010110": 9.0,
+	"010111": 8.3, "010120": 8.4, "010121": 7.1, "010200": 9.2, "010201": 8.1, "010210": 8.2,
+	"010211": 7.1, "010220": 7.2, "010221": 5.3, "011000": 9.5, "011001": 9.3, "011010": 9.2,
+	"011011": 8.5, "011020": 8.5, "011021": 7.3, "011100": 9.2, "011101": 8.2, "011110": 8.0,
+	"011111": 7.2, "011120": 7.0, "011121": 5.9, "011200": 8.4, "011201": 7.0, "011210": 7.1,
+	"011211": 5.2, "011220": 5.0, "011221": 3.0, "012001": 8.6, "012011": 7.5, "012021": 5.2,
+	"012101": 7.1, "012111": 5.2, "012121": 2.9, "012201": 6.3, "012211": 2.9, "012221": 1.7,
+	"100000": 9.8, "100001": 9.5, "100010": 9.4, "100011": 8.7, "100020": 9.1, "100021": 8.1,
+	"100100": 9.4, "100101": 8.9, "100110": 8.6, "100111": 7.4, "100120": 7.7, "100121": 6.4,
+	"100200": 8.7, "100201": 7.5, "100210": 7.4, "100211": 6.3, "100220": 6.3, "100221": 4.9,
+	"101000": 9.4, "101001": 8.9, "101010": 8.8, "101011": 7.7, "101020": 7.6, "101021": 6.7,
+	"101100": 8.6, "101101": 7.6, "101110": 7.4, "101111": 5.8, "101120": 5.9, "101121": 5.0,
+	"101200": 7.2, "101201": 5.7, "101210": 5.7, "101211": 5.2, "101220": 5.2, "101221": 2.5,
+	"102001": 8.3, "102011": 7.0, "102021": 5.4, "102101": 6.5, "102111": 5.8, "102121": 2.6,
+	"102201": 5.3, "102211": 2.1, "102221": 1.3, "110000": 9.5, "110001": 9.0, "110010": 8.8,
+	"110011": 7.6, "110020": 7.6, "110021": 7.0, "110100": 9.0, "110101": 7.7, "110110": 7.5,
+	"110111": 6.2, "110120": 6.1, "110121": 5.3, "110200": 7.7, "110201": 6.6, "110210": 6.8,
+	"110211": 5.9, "110220": 5.2, "110221": 3.0, "111000": 8.9, "111001": 7.8, "111010": 7.6,
+	"111011": 6.7, "111020": 6.2, "111021": 5.8, "111100": 7.4, "111101": 5.9, "111110": 5.7,
+	"111111": 5.7, "111120": 4.7, "111121": 2.3, "111200": 6.1, "111201": 5.2, "111210": 5.7,
+	"111211": 2.9, "111220": 2.4, "111221": 1.6, "112001": 7.1, "112011": 5.9, "112021": 3.0,
+	"112101": 5.8, "112111": 2.6, "112121": 1.5, "112201": 2.3, "112211": 1.3, "112221": 0.6,
+	"200000": 9.3, "200001": 8.7, "200010": 8.6, "200011": 7.2, "200020": 7.5, "200021": 5.8,
+	"200100": 8.6, "200101": 7.4, "200110": 7.4, "200111": 6.1, "200120": 5.6, "200121": 3.4,
+	"200200": 7.0, "200201": 5.4, "200210": 5.2, "200211": 4.0, "200220": 4.0, "200221": 2.2,
+	"201000": 8.5, "201001": 7.5, "201010": 7.4, "201011": 5.5, "201020": 6.2, "201021": 5.1,
+	"201100": 7.2, "201101": 5.7, "201110": 5.5, "201111": 4.1, "201120": 4.6, "201121": 1.9,
+	"201200": 5.3, "201201": 3.6, "201210": 3.4, "201211": 1.9, "201220": 1.9, "201221": 0.8,
+	"202001": 6.4, "202011": 5.1, "202021": 2.0, "202101": 4.7, "202111": 2.1, "202121": 1.1,
+	"202201": 2.4, "202211": 0.9, "202221": 0.4, "210000": 8.8, "210001": 7.5, "210010": 7.3,
+	"210011": 5.3, "210020": 6.0, "210021": 5.0, "210100": 7.3, "210101": 5.5, "210110": 5.9,
+	"210111": 4.0, "210120": 4.1, "210121": 2.0, "210200": 5.4, "210201": 4.3, "210210": 4.5,
+	"210211": 2.2, "210220": 2.0, "210221": 1.1, "211000": 7.5, "211001": 5.5, "211010": 5.8,
+	"211011": 4.5, "211020": 4.0, "211021": 2.1, "211100": 6.1, "211101": 5.1, "211110": 4.8,
+	"211111": 1.8, "211120": 2.0, "211121": 0.9, "211200": 4.6, "211201": 1.8, "211210": 1.7,
+	"211211": 0.7, "211220": 0.8, "211221": 0.2, "212001": 5.3, "212011": 2.4, "212021": 1.4,
+	"212101": 2.4, "212111": 1.2, "212121": 0.5, "212201": 1.0, "212211": 0.3, "212221": 0.1,
+}
+
+// levels4 are the severity distances of the metric values in steps of 0.1.
+var levels4 = map[string]map[string]int{
+	"AV": {"N": 0, "A": 1, "L": 2, "P": 3},
+	"PR": {"N": 0, "L": 1, "H": 2},
+	"UI": {"N": 0, "P": 1, "A": 2},
+	"AC": {"L": 0, "H": 1},
+	"AT": {"N": 0, "P": 1},
+	"VC": {"H": 0, "L": 1, "N": 2},
+	"VI": {"H": 0, "L": 1, "N": 2},
+	"VA": {"H": 0, "L": 1, "N": 2},
+	"SC": {"H": 1, "L": 2, "N": 3},
+	"SI": {"S": 0, "H": 1, "L": 2, "N": 3},
+	"SA": {"S": 0, "H": 1, "L": 2, "N": 3},
+	"CR": {"H": 0, "M": 1, "L": 2},
+	"IR": {"H": 0, "M": 1, "L": 2},
+	"AR": {"H": 0, "M": 1, "L": 2},
+}
+
+// The highest severity vectors of the levels of the
+// equivalence classes. EQ3 and EQ6 are combined.
+var (
+	highestEQ1 = [][]string{
+		{"AV:N/PR:N/UI:N"},
+		{"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"},
+		{"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"},
+	}
+	highestEQ2 = [][]string{
+		{"AC:L/AT:N"},
+		{"AC:H/AT:N", "AC:L/AT:P"},
+	}
+	highestEQ3EQ6 = [][][]string{
+		{
+			{"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"},
+		},
+		{
+			{"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"},
+			{
+				"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:L/VI:H/VA:H/CR:H/IR:M/AR:M",
+				"VC:H/VI:L/VA:H/CR:M/IR:H/AR:M", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H",
+				"VC:L/VI:L/VA:H/CR:H/IR:H/AR:M",
+			},
+		},
+		{
+			nil,
+			{"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"},
+		},
+	}
+	highestEQ4 = [][]string{
+		{"SC:H/SI:S/SA:S"},
+		{"SC:H/SI:H/SA:H"},
+		{"SC:L/SI:L/SA:L"},
+	}
+)
+
+// The maximal severity distances within the levels
+// of the equivalence classes in steps of 0.1.
+var (
+	depthEQ1    = []int{1, 4, 5}
+	depthEQ2    = []int{1, 2}
+	depthEQ3EQ6 = [][]int{{7, 6}, {8, 8}, {0, 10}}
+	depthEQ4    = []int{6, 5, 4}
+)
+
+// effective4 returns the value of a CVSS v4 metric used for scoring.
+// Modified metrics override the base metrics and undefined
+// requirements and exploit maturity are assumed to be the worst case.
+func (v *Vector) effective4(name string, environmental bool) string {
+	if !environmental {
+		switch name {
+		case "CR", "IR", "AR":
+			return "H"
+		case "E":
+			return "A"
+		}
+		return v.metrics[name]
+	}
+	switch value := v.metrics[name]; name {
+	case "CR", "IR", "AR":
+		if unset(value) {
+			return "H"
+		}
+		return value
+	case "E":
+		if unset(value) {
+			return "A"
+		}
+		return value
+	}
+	if value := v.metrics["M"+name]; !unset(value) {
+		return value
+	}
+	return v.metrics[name]
+}
+
+// macroVector4 returns the levels of the equivalence classes EQ1 to EQ6.
+func macroVector4(m func(string) string, msi, msa string) [6]int {
+	var eqs [6]int
+	av, pr, ui := m("AV"), m("PR"), m("UI")
+	switch {
+	case av == "N" && pr == "N" && ui == "N":
+		eqs[0] = 0
+	case (av == "N" || pr == "N" || ui == "N") && av != "P":
+		eqs[0] = 1
+	default:
+		eqs[0] = 2
+	}
+	if m("AC") != "L" || m("AT") != "N" {
+		eqs[1] = 1
+	}
+	vc, vi, va := m("VC"), m("VI"), m("VA")
+	switch {
+	case vc == "H" && vi == "H":
+		eqs[2] = 0
+	case vc == "H" || vi == "H" || va == "H":
+		eqs[2] = 1
+	default:
+		eqs[2] = 2
+	}
+	switch {
+	case msi == "S" || msa == "S":
+		eqs[3] = 0
+	case m("SC") == "H" || m("SI") == "H" || m("SA") == "H":
+		eqs[3] = 1
+	default:
+		eqs[3] = 2
+	}
+	switch m("E") {
+	case "P":
+		eqs[4] = 1
+	case "U":
+		eqs[4] = 2
+	}
+	if !(m("CR") == "H" && vc == "H") &&
+		!(m("IR") == "H" && vi == "H") &&
+		!(m("AR") == "H" && va == "H") {
+		eqs[5] = 1
+	}
+	return eqs
+}
+
+// macroVectorKey returns the key of the macro vector in [macroVectors4].
+func macroVectorKey(eqs [6]int) string {
+	var b [6]byte
+	for i, eq := range eqs {
+		b[i] = byte('0' + eq)
+	}
+	return string(b[:])
+}
+
+// score4 computes the score of a CVSS v4 vector. Without environmental
+// the threat and environmental metrics are ignored.
+func (v *Vector) score4(environmental bool) float64 {
+	m := func(name string) string { return v.effective4(name, environmental) }
+
+	// No impact on the vulnerable and the subsequent systems.
+	if m("VC") == "N" && m("VI") == "N" && m("VA") == "N" &&
+		m("SC") == "N" && m("SI") == "N" && m("SA") == "N" {
+		return 0
+	}
+
+	var msi, msa string
+	if environmental {
+		msi, msa = v.metrics["MSI"], v.metrics["MSA"]
+	}
+	eqs := macroVector4(m, msi, msa)
+	value := macroVectors4[macroVectorKey(eqs)]
+
+	// The scores of the next lower macro vectors.
+	// They are NaN if there is no such macro vector.
+	lower := func(eq, diff int) float64 {
+		next := eqs
+		next[eq] += diff
+		if score, ok := macroVectors4[macroVectorKey(next)]; ok {
+			return score
+		}
+		return math.NaN()
+	}
+	var lowerEQ3EQ6 float64
+	switch eq3, eq6 := eqs[2], eqs[5]; {
+	case eq3 == 0 && eq6 == 0:
+		// Both directions are possible. Take the higher one.
+		if left, right := lower(5, 1), lower(2, 1); left > right {
+			lowerEQ3EQ6 = left
+		} else {
+			lowerEQ3EQ6 = right
+		}
+	case eq6 == 0:
+		lowerEQ3EQ6 = lower(5, 1)
+	case eq3 < 2:
+		lowerEQ3EQ6 = lower(2, 1)
+	default:
+		lowerEQ3EQ6 = math.NaN()
+	}
+	lowers := [5]float64{lower(0, 1), lower(1, 1), lowerEQ3EQ6, lower(3, 1), lower(4, 1)}
+
+	// The severity distances to a highest severity vector of the macro vector.
+	var distances [5]int
+	distance := func(highest string) (int, bool) {
+		sum := 0
+		for metric := range strings.SplitSeq(highest, "/") {
+			name, value, _ := strings.Cut(metric, ":")
+			d := levels4[name][m(name)] - levels4[name][value]
+			if d < 0 {
+				return 0, false
+			}
+			sum += d
+		}
+		return sum, true
+	}
+found:
+	for _, eq1 := range highestEQ1[eqs[0]] {
+		for _, eq2 := range highestEQ2[eqs[1]] {
+			for _, eq3eq6 := range highestEQ3EQ6[eqs[2]][eqs[5]] {
+				for _, eq4 := range highestEQ4[eqs[3]] {
+					d1, ok1 := distance(eq1)
+					d2, ok2 := distance(eq2)
+					d3, ok3 := distance(eq3eq6)
+					d4, ok4 := distance(eq4)
+					if ok1 && ok2 && ok3 && ok4 {
+						distances = [5]int{d1, d2, d3, d4, 0}
+						break found
+					}
+				}
+			}
+		}
+	}
+
+	depths := [5]int{
+		depthEQ1[eqs[0]],
+		depthEQ2[eqs[1]],
+		depthEQ3EQ6[eqs[2]][eqs[5]],
+		depthEQ4[eqs[3]],
+		1, // EQ5 only consists of E.
+	}
+
+	// The mean of the proportional distances to the next lower macro vectors.
+	var sum float64
+	var n int
+	for i, score := range lowers {
+		if math.IsNaN(score) {
+			continue
+		}
+		n++
+		sum += (value - score) * float64(distances[i]) / float64(depths[i])
+	}
+	if n > 0 {
+		value -= sum / float64(n)
+	}
+	return round1(min(max(value, 0), 10))
+}
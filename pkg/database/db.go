@@ -11,6 +11,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -61,3 +62,51 @@ func (db *DB) Run(
 		return fn(timeoutCtx, conn)
 	})
 }
+
+// serverLock is the advisory lock held by running servers.
+const serverLock = 0x15d0ba
+
+var (
+	// ErrServerRunning is returned by LockOffline if a server is running.
+	ErrServerRunning = errors.New("server is running")
+	// ErrOffline is returned by LockServer during offline changes.
+	ErrOffline = errors.New("database is locked for offline changes")
+)
+
+// LockServer marks the database as used by a running server.
+// Several servers can hold the lock at the same time.
+// It returns ErrOffline while offline changes are made.
+// The returned function releases the lock.
+func (db *DB) LockServer(ctx context.Context) (func(), error) {
+	return db.lock(ctx, `SELECT pg_try_advisory_lock_shared($1)`, ErrOffline)
+}
+
+// LockOffline ensures that no server is running while changes
+// are made which a running server would not notice.
+// It returns ErrServerRunning if a server holds its lock.
+// The returned function releases the lock.
+func (db *DB) LockOffline(ctx context.Context) (func(), error) {
+	return db.lock(ctx, `SELECT pg_try_advisory_lock($1)`, ErrServerRunning)
+}
+
+// lock takes the advisory server lock on a connection
+// which is kept out of the pool till the lock is released.
+// If the lock is not available the given error is returned.
+func (db *DB) lock(ctx context.Context, sql string, errLocked error) (func(), error) {
+	pconn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn := pconn.Hijack()
+	var locked bool
+	if err := conn.QueryRow(ctx, sql, serverLock).Scan(&locked); err != nil {
+		conn.Close(ctx)
+		return nil, fmt.Errorf("taking server lock failed: %w", err)
+	}
+	if !locked {
+		conn.Close(ctx)
+		return nil, errLocked
+	}
+	// Closing the connection releases the lock.
+	return func() { conn.Close(context.Background()) }, nil
+}
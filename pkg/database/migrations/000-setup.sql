@@ -41,16 +41,43 @@ CREATE FUNCTION revision_history_length(jsonb) RETURNS int AS $$
     SELECT jsonb_array_length(jsonb_path_query($1, '$.document.tracking.revision_history'))
 $$ LANGUAGE SQL IMMUTABLE;
 
+-- CSAF 2.0 stores the CVSS scores in the scores
+-- and CSAF 2.1 in the metrics of the vulnerabilities.
 CREATE FUNCTION max_cvss2_score(jsonb) RETURNS float AS $$
-    SELECT max(a::float) FROM
-        jsonb_path_query(
-            $1, '$.vulnerabilities[*].scores[*].cvss_v2.baseScore') a
+    SELECT max(a::float) FROM (
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].scores[*].cvss_v2.baseScore')
+        UNION ALL
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.cvss_v2.baseScore')) AS s(a)
 $$ LANGUAGE SQL IMMUTABLE;
 
 CREATE FUNCTION max_cvss3_score(jsonb) RETURNS float AS $$
+    SELECT max(a::float) FROM (
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].scores[*].cvss_v3.baseScore')
+        UNION ALL
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.cvss_v3.baseScore')) AS s(a)
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE FUNCTION max_cvss4_score(jsonb) RETURNS float AS $$
     SELECT max(a::float) FROM
         jsonb_path_query(
-            $1, '$.vulnerabilities[*].scores[*].cvss_v3.baseScore') a
+            $1, '$.vulnerabilities[*].metrics[*].content.cvss_v4.baseScore') a
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- The EPSS content of the CSAF 2.1 metrics stores the numbers as strings.
+CREATE FUNCTION max_epss_probability(jsonb) RETURNS float AS $$
+    SELECT max((a #>> '{}')::float) FROM
+        jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.epss.probability') a
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE FUNCTION max_epss_percentile(jsonb) RETURNS float AS $$
+    SELECT max((a #>> '{}')::float) FROM
+        jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.epss.percentile') a
 $$ LANGUAGE SQL IMMUTABLE;
 
 CREATE FUNCTION first_four_cves(jsonb) RETURNS jsonb AS $$
@@ -94,9 +121,16 @@ CREATE TABLE documents (
                 GENERATED ALWAYS AS (max_cvss2_score(document)) STORED,
     cvss_v3_score float
                 GENERATED ALWAYS AS (max_cvss3_score(document)) STORED,
+    cvss_v4_score float
+                GENERATED ALWAYS AS (max_cvss4_score(document)) STORED,
     critical    float
                 GENERATED ALWAYS AS (
-                    coalesce(max_cvss3_score(document), max_cvss2_score(document))) STORED,
+                    coalesce(max_cvss4_score(document), max_cvss3_score(document),
+                             max_cvss2_score(document))) STORED,
+    csaf_epss   float
+                GENERATED ALWAYS AS (max_epss_probability(document)) STORED,
+    csaf_epss_percentile float
+                GENERATED ALWAYS AS (max_epss_percentile(document)) STORED,
     four_cves   jsonb
                 GENERATED ALWAYS AS (first_four_cves(document)) STORED,
     -- The data
@@ -104,6 +138,8 @@ CREATE TABLE documents (
     original    bytea COMPRESSION lz4 NOT NULL,
     signature   bytea COMPRESSION lz4,
     filename    varchar,
+    -- Documents with a deleted time stamp are in the trash bin.
+    deleted     timestamptz,
 
     UNIQUE (advisories_id, version, rev_history_length, tracking_status)
 );
@@ -152,23 +188,39 @@ CREATE FUNCTION update_advisory() RETURNS trigger AS $$
     END;
 $$ LANGUAGE plpgsql;
 
--- delete_advisory tries to re-establish an advisory after the last head was deleted.
-CREATE FUNCTION delete_advisory() RETURNS trigger AS $$
+-- elect_latest makes the newest document of an advisory
+-- which is not in the trash bin the latest one.
+CREATE FUNCTION elect_latest(adv_id int) RETURNS void AS $$
     DECLARE
         lead_id int;
+    BEGIN
+        SELECT id
+            INTO lead_id
+            FROM documents
+            WHERE advisories_id = adv_id AND deleted IS NULL
+            ORDER BY current_release_date DESC, rev_history_length DESC
+            LIMIT 1;
+        -- Drop the old lead first to not violate only_one_latest_constraint.
+        UPDATE documents SET latest = FALSE
+            WHERE advisories_id = adv_id AND latest AND id IS DISTINCT FROM lead_id;
+        IF lead_id IS NOT NULL THEN
+            UPDATE documents SET latest = TRUE
+                WHERE id = lead_id AND latest IS NOT TRUE;
+        END IF;
+    END;
+$$ LANGUAGE plpgsql;
+
+-- delete_advisory re-establishes the latest document after a document
+-- was purged and removes the advisory if there are no documents left.
+CREATE FUNCTION delete_advisory() RETURNS trigger AS $$
     BEGIN
         -- Update is only needed if deleted one was latest.
         IF OLD.latest THEN
-            SELECT id
-                INTO lead_id
-                FROM documents
-                WHERE advisories_id = OLD.id
-                ORDER BY current_release_date DESC, rev_history_length DESC;
-            IF FOUND THEN
-                UPDATE documents SET latest = TRUE WHERE id = lead_id;
-            ELSE -- No documents for advisory -> Delete advisory.
-                DELETE FROM advisories WHERE id = OLD.id;
-            END IF;
+            PERFORM elect_latest(OLD.advisories_id);
+        END IF;
+        -- Documents in the trash bin keep the advisory alive.
+        IF NOT EXISTS (SELECT 1 FROM documents WHERE advisories_id = OLD.advisories_id) THEN
+            DELETE FROM advisories WHERE id = OLD.advisories_id;
         END IF;
         RETURN NULL;
     END;
@@ -182,11 +234,13 @@ CREATE TRIGGER insert_document
 CREATE TRIGGER delete_document AFTER DELETE ON documents
     FOR EACH ROW EXECUTE FUNCTION delete_advisory();
 
+CREATE INDEX documents_deleted_idx ON documents(deleted) WHERE deleted IS NOT NULL;
 CREATE INDEX current_release_date_idx ON documents (current_release_date);
 CREATE INDEX initial_release_date_idx ON documents (initial_release_date);
 
 CREATE INDEX documents_cvss2_idx ON documents(coalesce(cvss_v2_score, '0'::double precision) DESC);
 CREATE INDEX documents_cvss3_idx ON documents(coalesce(cvss_v3_score, '0'::double precision) DESC);
+CREATE INDEX documents_cvss4_idx ON documents(coalesce(cvss_v4_score, '0'::double precision) DESC);
 CREATE INDEX documents_critical_idx ON documents(coalesce(critical, '0'::double precision) DESC);
 
 CREATE TABLE unique_texts (
@@ -215,11 +269,15 @@ CREATE TABLE comments (
     documents_id int NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
     time         timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
     commentator  varchar NOT NULL,
-    message      varchar(10000)
+    message      varchar(10000),
+    -- Replies refer to the comment they answer.
+    -- They are kept as top-level comments if the parent is purged.
+    parent_id    int REFERENCES comments(id) ON DELETE SET NULL
 );
 
 CREATE INDEX ON comments(documents_id);
 CREATE INDEX ON comments USING gin(message gin_trgm_ops);
+CREATE INDEX ON comments(parent_id);
 
 -- Trigger functions to update cached comment count per advisory.
 CREATE FUNCTION incr_comments() RETURNS trigger AS $$
@@ -256,11 +314,182 @@ CREATE TRIGGER decrement_comments
     ON comments
     FOR EACH ROW EXECUTE FUNCTION decr_comments();
 
+-- trash_document re-establishes the latest document and the cached
+-- comment count of an advisory if a document is moved to or
+-- restored from the trash bin.
+CREATE FUNCTION trash_document() RETURNS trigger AS $$
+    DECLARE
+        num_comments int;
+    BEGIN
+        PERFORM elect_latest(NEW.advisories_id);
+        SELECT count(*) INTO num_comments FROM comments WHERE documents_id = NEW.id;
+        IF NEW.deleted IS NOT NULL THEN
+            num_comments = -num_comments;
+        END IF;
+        UPDATE advisories
+            SET comments = greatest(0, comments + num_comments)
+            WHERE id = NEW.advisories_id;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trash_document
+    AFTER UPDATE OF deleted
+    ON documents
+    FOR EACH ROW
+    WHEN ((OLD.deleted IS NULL) <> (NEW.deleted IS NULL))
+    EXECUTE FUNCTION trash_document();
+
+-- comment_mentions are the users mentioned as @user in the comments.
+CREATE TABLE comment_mentions (
+    comments_id int     NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    mentioned   varchar NOT NULL,
+    PRIMARY KEY (comments_id, mentioned)
+);
+
+CREATE INDEX ON comment_mentions(mentioned);
+
+CREATE FUNCTION extract_mentions() RETURNS trigger AS $$
+    BEGIN
+        DELETE FROM comment_mentions WHERE comments_id = NEW.id;
+        INSERT INTO comment_mentions (comments_id, mentioned)
+            SELECT DISTINCT NEW.id, m[1]
+            FROM regexp_matches(coalesce(NEW.message, ''),
+                '(?:^|[^[:alnum:]_])@([[:alnum:]_]+(?:[.-][[:alnum:]_]+)*)', 'g') AS m;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER insert_comment_mentions
+    AFTER INSERT
+    ON comments
+    FOR EACH ROW EXECUTE FUNCTION extract_mentions();
+
+CREATE TRIGGER update_comment_mentions
+    AFTER UPDATE OF message
+    ON comments
+    FOR EACH ROW EXECUTE FUNCTION extract_mentions();
+
+-- comment_reactions are the reactions of the users to the comments.
+CREATE TABLE comment_reactions (
+    comments_id int         NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    actor       varchar     NOT NULL,
+    reaction    varchar(32) NOT NULL,
+    time        timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comments_id, actor, reaction)
+);
+
+-- comment_attachments are the files attached to the comments.
+-- The content is stored either in data or in a file
+-- named by path in the configured attachments directory.
+CREATE TABLE comment_attachments (
+    id           int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    comments_id  int         NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    filename     varchar     NOT NULL,
+    content_type varchar     NOT NULL,
+    size         bigint      NOT NULL,
+    uploader     varchar,
+    time         timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data         bytea,
+    path         varchar,
+    CHECK ((data IS NULL) <> (path IS NULL))
+);
+
+CREATE INDEX ON comment_attachments(comments_id);
+
+-- removed_attachments are the files of deleted attachments
+-- which have to be removed from the attachments directory.
+CREATE TABLE removed_attachments (
+    path varchar PRIMARY KEY
+);
+
+CREATE FUNCTION remove_attachment() RETURNS trigger AS $$
+    BEGIN
+        IF OLD.path IS NOT NULL THEN
+            INSERT INTO removed_attachments (path) VALUES (OLD.path)
+                ON CONFLICT DO NOTHING;
+        END IF;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER delete_comment_attachment
+    AFTER DELETE
+    ON comment_attachments
+    FOR EACH ROW EXECUTE FUNCTION remove_attachment();
+
+-- advisory_checklists are the triage checklists of the advisories.
+-- The items are copied from the configured templates.
+CREATE TABLE advisory_checklists (
+    advisories_id int         NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    num           int         NOT NULL,
+    item          varchar     NOT NULL,
+    done          boolean     NOT NULL DEFAULT FALSE,
+    changed_by    varchar,
+    changed       timestamptz,
+    PRIMARY KEY (advisories_id, num)
+);
+
+-- advisory_notes are internal notes on the advisories
+-- independent of the versions of their documents.
+CREATE TABLE advisory_notes (
+    id            int             PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    advisories_id int             NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    author        varchar,
+    time          timestamptz     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    changed       timestamptz,
+    note          varchar(10000)  NOT NULL
+);
+
+CREATE INDEX ON advisory_notes(advisories_id);
+
+-- tags are the admin managed definitions of the tags
+-- the advisories can be labeled with.
+CREATE TABLE tags (
+    name        varchar(64) PRIMARY KEY,
+    colour      varchar(7),
+    description varchar
+);
+
+-- advisory_tags are the tags the advisories are labeled with.
+CREATE TABLE advisory_tags (
+    advisories_id int         NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    tag           varchar(64) NOT NULL REFERENCES tags(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (advisories_id, tag)
+);
+
+CREATE INDEX ON advisory_tags(tag);
+
+-- vulnerability_cases group advisories about the same vulnerability.
+-- The shared_* flags control which parts of the workflow are
+-- propagated to all advisories of a case.
+CREATE TABLE vulnerability_cases (
+    id              int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    name            varchar     NOT NULL,
+    created         timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    shared_state    boolean     NOT NULL DEFAULT FALSE,
+    shared_ssvc     boolean     NOT NULL DEFAULT FALSE,
+    shared_comments boolean     NOT NULL DEFAULT FALSE
+);
+
+-- vulnerability_case_advisories are the advisories of the cases.
+-- An advisory belongs to at most one case.
+CREATE TABLE vulnerability_case_advisories (
+    cases_id      int NOT NULL REFERENCES vulnerability_cases(id) ON DELETE CASCADE,
+    advisories_id int NOT NULL UNIQUE REFERENCES advisories(id) ON DELETE CASCADE,
+    PRIMARY KEY (cases_id, advisories_id)
+);
+
 CREATE TYPE events AS ENUM (
     'import_document', 'delete_document',
+    'restore_document', 'purge_document',
     'state_change',
     'add_sscv', 'change_sscv', 'delete_sscv',
-    'add_comment', 'change_comment', 'delete_comment'
+    'add_comment', 'change_comment', 'delete_comment',
+    'add_tag', 'remove_tag',
+    'create_source', 'deactivate_source',
+    'release_document',
+    'activate_source'
 );
 
 CREATE TABLE events_log (
@@ -269,10 +498,22 @@ CREATE TABLE events_log (
     time         timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
     actor        varchar,
     documents_id int REFERENCES documents(id) ON DELETE SET NULL,
-    comments_id  int REFERENCES comments(id) ON DELETE SET NULL
+    comments_id  int REFERENCES comments(id) ON DELETE SET NULL,
+    -- The transaction and the id of an event are used as
+    -- position in the changes feed of the peers.
+    -- The ids are handed out before commit so they are not enough.
+    id           bigint GENERATED BY DEFAULT AS IDENTITY,
+    xact         xid8   NOT NULL DEFAULT pg_current_xact_id(),
+    -- origin is the name of the peer a synchronized change came from.
+    -- It is NULL for local changes.
+    origin       varchar,
+    -- tag is the tag added to or removed from an advisory.
+    tag          varchar(64)
 );
 
 CREATE INDEX events_log_time_idx ON events_log(time);
+CREATE INDEX events_log_xact_id_idx ON events_log(xact, id);
+CREATE UNIQUE INDEX events_log_id_idx ON events_log(id);
 CREATE INDEX ON events_log(documents_id);
 
 -- Trigger to update cached recent value of advisory.
@@ -297,7 +538,7 @@ CREATE TRIGGER update_recent
 -- user defined stored queries
 --
 CREATE TYPE stored_queries_kind AS ENUM (
-    'documents', 'advisories', 'events'
+    'documents', 'advisories', 'events', 'cases'
 );
 
 CREATE TYPE stored_queries_roles AS ENUM (
@@ -332,6 +573,10 @@ CREATE TABLE default_query_exclusion (
 ---
 --- sources
 ---
+CREATE TYPE source_auth_modes AS ENUM (
+    'none', 'bearer', 'oauth2'
+);
+
 CREATE TABLE sources (
     id                     int     PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
     name                   varchar NOT NULL UNIQUE,
@@ -346,17 +591,40 @@ CREATE TABLE sources (
     age                    interval,
     ignore_patterns        text[],
     client_cert_public     bytea,
-    client_cert_private    bytea,
-    client_cert_passphrase bytea,
     checksum               bytea,
     checksum_ack           timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP - '1 second'::interval,
     checksum_updated       timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- schedule is a cron expression or a list of time windows.
+    schedule               varchar,
+    -- auth is the mode the source authenticates with. The bearer token
+    -- and the OAuth2 client secret are kept by the secrets provider.
+    auth                   source_auth_modes NOT NULL DEFAULT 'none',
+    oauth2_token_url       varchar,
+    oauth2_client_id       varchar,
+    oauth2_scopes          text[],
+    proxy                  varchar,
     CHECK(name <> ''),
+    CHECK(proxy <> ''),
     CHECK(url <> ''),
     CHECK(rate IS NULL OR rate > 0.0),
     CHECK(slots IS NULL OR slots >= 1)
 );
 
+-- source_pmds stores the versions of the provider metadata of the sources.
+CREATE TABLE source_pmds (
+    id         int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    sources_id int         NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fetched    timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- checksum is the SHA256 sum of the fetched document.
+    checksum   bytea       NOT NULL,
+    document   jsonb       NOT NULL
+);
+
+CREATE INDEX source_pmds_sources_id_idx ON source_pmds(sources_id, id);
+
+-- sources_id is the source an event is about.
+ALTER TABLE events_log ADD COLUMN sources_id int REFERENCES sources(id) ON DELETE SET NULL;
+
 CREATE TYPE feed_logs_level AS ENUM (
     'debug', 'info', 'warn', 'error');
 
@@ -367,6 +635,7 @@ CREATE TABLE feeds (
     url        varchar         NOT NULL,
     rolie      bool            NOT NULL DEFAULT FALSE,
     log_lvl    feed_logs_level NOT NULL DEFAULT 'info',
+    schedule   varchar,
     CHECK(label <> ''),
     CHECK(url <> ''),
     UNIQUE(label, sources_id)
@@ -404,6 +673,108 @@ CREATE TABLE downloads (
 
 CREATE INDEX ON downloads (time);
 
+CREATE TYPE quarantine_state AS ENUM (
+    'pending', 'released', 'rejected'
+);
+
+-- quarantine keeps the downloads which failed the checks in strict mode.
+CREATE TABLE quarantine (
+    id               int              PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    feeds_id         int              REFERENCES feeds(id) ON DELETE SET NULL,
+    url              varchar          NOT NULL,
+    time             timestamptz      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- checksum is the SHA256 sum of the original document.
+    checksum         bytea            NOT NULL,
+    original         bytea            NOT NULL,
+    signature        bytea,
+    filename         varchar,
+    publisher        varchar,
+    tracking_id      varchar,
+    version          varchar,
+    tlp              varchar,
+    filename_failed  bool             NOT NULL DEFAULT FALSE,
+    schema_failed    bool             NOT NULL DEFAULT FALSE,
+    remote_failed    bool             NOT NULL DEFAULT FALSE,
+    checksum_failed  bool             NOT NULL DEFAULT FALSE,
+    signature_failed bool             NOT NULL DEFAULT FALSE,
+    state            quarantine_state NOT NULL DEFAULT 'pending',
+    decided          timestamptz,
+    actor            varchar,
+    documents_id     int              REFERENCES documents(id) ON DELETE SET NULL,
+    UNIQUE (url, checksum)
+);
+
+CREATE INDEX quarantine_state_idx ON quarantine(state);
+
+CREATE TYPE download_state AS ENUM (
+    'waiting', 'running', 'failed'
+);
+
+-- download_queue keeps the pending downloads of the feeds over restarts.
+CREATE TABLE download_queue (
+    feeds_id  int            NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    url       varchar        NOT NULL,
+    updated   timestamptz    NOT NULL,
+    hash      varchar,
+    signature varchar,
+    state     download_state NOT NULL DEFAULT 'waiting',
+    retries   int            NOT NULL DEFAULT 0,
+    message   varchar,
+    time      timestamptz    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(feeds_id, url)
+);
+
+CREATE TYPE source_key_state AS ENUM (
+    'pending', 'trusted', 'rejected'
+);
+
+-- source_keys are the OpenPGP keys pinned for the sources.
+CREATE TABLE source_keys (
+    sources_id  int              NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fingerprint varchar          NOT NULL,
+    -- key is the armored public key.
+    key         text             NOT NULL,
+    state       source_key_state NOT NULL DEFAULT 'pending',
+    -- advertised tells if the key is listed in the current PMD.
+    advertised  boolean          NOT NULL DEFAULT FALSE,
+    -- uploaded tells if the key was added manually.
+    uploaded    boolean          NOT NULL DEFAULT FALSE,
+    expires     timestamptz,
+    first_seen  timestamptz      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided     timestamptz,
+    actor       varchar,
+    -- used counts the documents whose signatures were verified with the key.
+    used        bigint           NOT NULL DEFAULT 0,
+    last_used   timestamptz,
+    PRIMARY KEY(sources_id, fingerprint)
+);
+
+CREATE TYPE source_key_events AS ENUM (
+    'pinned', 'added', 'removed', 'trusted', 'rejected', 'uploaded', 'deleted'
+);
+
+-- source_keys_log records the changes of the OpenPGP keys of the sources.
+CREATE TABLE source_keys_log (
+    id          int               PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    sources_id  int               NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fingerprint varchar           NOT NULL,
+    event       source_key_events NOT NULL,
+    time        timestamptz       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor       varchar
+);
+
+CREATE INDEX source_keys_log_sources_id_idx ON source_keys_log(sources_id, time);
+
+-- secrets are the credentials of the sources and the forward targets
+-- if they are stored in the database. The values are encrypted
+-- with the AES key of the server.
+CREATE TABLE secrets (
+    name    varchar     PRIMARY KEY,
+    value   bytea       NOT NULL,
+    updated timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK(name <> '')
+);
+
 -- Track CVEs for documents.
 CREATE TABLE unique_cves (
     id  int PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
@@ -416,6 +787,99 @@ CREATE TABLE documents_cves (
     UNIQUE(documents_id, cve_id)
 );
 
+-- kev are the entries of the CISA catalogue of known exploited vulnerabilities.
+CREATE TABLE kev (
+    cve        text    PRIMARY KEY,
+    vendor     text,
+    product    text,
+    name       text,
+    date_added date,
+    due_date   date,
+    ransomware boolean NOT NULL DEFAULT FALSE
+);
+
+-- epss are the exploit prediction scores of the CVEs.
+CREATE TABLE epss (
+    cve        text  PRIMARY KEY,
+    epss       float NOT NULL,
+    percentile float NOT NULL
+);
+
+-- cwes are the entries of the CWE list.
+CREATE TABLE cwes (
+    id          int  PRIMARY KEY,
+    name        text NOT NULL,
+    description text
+);
+
+-- enrichment_imports records the last import of the enrichment catalogues.
+CREATE TABLE enrichment_imports (
+    catalogue varchar     PRIMARY KEY,
+    modified  timestamptz NOT NULL,
+    imported  timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    entries   int         NOT NULL
+);
+
+-- documents_cvss are the parsed CVSS vectors of the vulnerabilities of the documents.
+CREATE TABLE documents_cvss (
+    documents_id        int     NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    vulnerability       int     NOT NULL, -- index in the vulnerabilities of the document
+    cve                 text,
+    version             varchar NOT NULL,
+    vector              text    NOT NULL,
+    base_score          float,
+    environmental_score float,
+    -- The metrics. Which of them are set depends on the CVSS version.
+    av varchar,
+    ac varchar,
+    at varchar,
+    pr varchar,
+    ui varchar,
+    au varchar,
+    s  varchar,
+    c  varchar,
+    i  varchar,
+    a  varchar,
+    vc varchar,
+    vi varchar,
+    va varchar,
+    sc varchar,
+    si varchar,
+    sa varchar,
+    e  varchar,
+    PRIMARY KEY (documents_id, vulnerability, vector)
+);
+
+-- cvss_pending are the documents whose CVSS vectors have to be (re-)indexed.
+CREATE TABLE cvss_pending (
+    documents_id int         PRIMARY KEY REFERENCES documents(id) ON DELETE CASCADE,
+    queued       timestamptz NOT NULL DEFAULT clock_timestamp()
+);
+
+-- cvss_environment are the environmental defaults the vectors were scored with.
+CREATE TABLE cvss_environment (
+    metrics jsonb NOT NULL
+);
+
+CREATE FUNCTION queue_cvss() RETURNS TRIGGER AS $$
+    BEGIN
+        INSERT INTO cvss_pending (documents_id) VALUES (NEW.id)
+            ON CONFLICT (documents_id) DO UPDATE SET queued = clock_timestamp();
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER queue_cvss_trigger_insert AFTER INSERT
+    ON documents
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_cvss();
+
+CREATE TRIGGER queue_cvss_trigger_update AFTER UPDATE
+    ON documents
+    FOR EACH ROW
+    WHEN (NEW.document <> OLD.document)
+    EXECUTE FUNCTION queue_cvss();
+
 CREATE FUNCTION extract_cves() RETURNS TRIGGER AS $$
     BEGIN
         DELETE FROM documents_cves WHERE documents_id = NEW.id;
@@ -465,6 +929,9 @@ CREATE TABLE ssvc_history (
     change_number bigint      NOT NULL,
     documents_id  integer     NOT NULL                            REFERENCES documents(id) ON DELETE CASCADE,
     ssvc          text,
+    origin        varchar,
+    -- model is the name of the SSVC decision model of the vector.
+    model         varchar,
 
     PRIMARY KEY (documents_id, change_number)
 );
@@ -505,7 +972,8 @@ BEGIN
         time,
         actor,
         documents_id,
-        comments_id
+        comments_id,
+        origin
     )
     VALUES (
         v_event,
@@ -514,7 +982,8 @@ BEGIN
         NEW.changedate,
         NEW.actor,
         NEW.documents_id,
-        NULL
+        NULL,
+        NEW.origin
     );
     RETURN NEW;
 END;
@@ -544,6 +1013,17 @@ BEFORE INSERT ON ssvc_history
 FOR EACH ROW
 EXECUTE FUNCTION generate_ssvc_change_number();
 
+-- ssvc_suggestions are the SSVC vectors proposed from the data
+-- of the documents. They are kept apart from the confirmed
+-- SSVC vectors in ssvc_history.
+CREATE TABLE ssvc_suggestions (
+    documents_id integer     PRIMARY KEY REFERENCES documents(id) ON DELETE CASCADE,
+    model        varchar     NOT NULL,
+    ssvc         text        NOT NULL,
+    rationale    jsonb       NOT NULL,
+    created      timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 
 --
 -- forwarded documents
@@ -569,6 +1049,9 @@ CREATE INDEX ON forwarders_queue(state) WHERE state = 'pending';
 --
 -- aggregators
 --
+CREATE TYPE aggregator_provisioning AS ENUM (
+    'off', 'review', 'auto');
+
 CREATE TABLE aggregators (
     id     int        PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
     active bool       NOT NULL DEFAULT FALSE,
@@ -577,9 +1060,59 @@ CREATE TABLE aggregators (
     checksum          bytea,
     checksum_ack      timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP - '1 second'::interval,
     checksum_updated  timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    provisioning      aggregator_provisioning NOT NULL DEFAULT 'off',
+    -- listed are the URLs listed at the last provisioning.
+    listed            varchar[],
     CHECK(url LIKE '%/aggregator.json')
 );
 
+CREATE TYPE aggregator_action AS ENUM (
+    'create_source', 'deactivate_source', 'activate_source');
+
+CREATE TYPE aggregator_action_state AS ENUM (
+    'pending', 'applied', 'rejected');
+
+-- aggregator_actions records the source provisioning done
+-- for the aggregators and holds the actions awaiting review.
+CREATE TABLE aggregator_actions (
+    id             int                     PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    aggregators_id int                     NOT NULL REFERENCES aggregators(id) ON DELETE CASCADE,
+    action         aggregator_action       NOT NULL,
+    url            varchar                 NOT NULL,
+    sources_id     int                     REFERENCES sources(id) ON DELETE SET NULL,
+    state          aggregator_action_state NOT NULL DEFAULT 'pending',
+    time           timestamptz             NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided        timestamptz,
+    actor          varchar,
+    message        varchar
+);
+
+CREATE UNIQUE INDEX aggregator_actions_pending_idx
+    ON aggregator_actions(aggregators_id, action, url) WHERE state = 'pending';
+
+--
+-- peer synchronization
+--
+CREATE TABLE sync_peers (
+    id         int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    name       varchar     NOT NULL UNIQUE,
+    -- last_event is the id of the last event fetched from the peer.
+    last_event bigint      NOT NULL DEFAULT 0,
+    -- last_xact is the transaction of the last event fetched from the peer.
+    last_xact  bigint      NOT NULL DEFAULT 0,
+    last_sync  timestamptz,
+    last_error varchar,
+    CHECK(name <> '')
+);
+
+-- sync_comments maps the comments of the peers to the local ones.
+CREATE TABLE sync_comments (
+    sync_peers_id int NOT NULL REFERENCES sync_peers(id) ON DELETE CASCADE,
+    remote_id     int NOT NULL,
+    comments_id   int NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    PRIMARY KEY (sync_peers_id, remote_id)
+);
+
 --
 -- permissions
 --
@@ -589,20 +1122,47 @@ GRANT INSERT, DELETE, SELECT, UPDATE ON documents               TO {{ .User | sa
 GRANT INSERT, DELETE, SELECT, UPDATE ON documents_texts         TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON unique_texts            TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON comments                TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON comment_mentions        TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON comment_reactions       TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON comment_attachments     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON removed_attachments     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON advisory_checklists     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON advisory_notes          TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON advisory_tags           TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON tags                    TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON vulnerability_cases     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON vulnerability_case_advisories TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON events_log              TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON stored_queries          TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON default_query_exclusion TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON sources                 TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON feeds                   TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON source_pmds             TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON changes                 TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON feed_logs               TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON downloads               TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON quarantine              TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON download_queue          TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON source_keys             TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON source_keys_log         TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON secrets                 TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON unique_cves             TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON documents_cves          TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON kev                     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON epss                    TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON cwes                    TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON enrichment_imports      TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON documents_cvss          TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON cvss_pending            TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON cvss_environment        TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON forwarders              TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON forwarders_queue        TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON aggregators             TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON aggregator_actions      TO {{ .User | sanitize }};
 GRANT INSERT, DELETE, SELECT, UPDATE ON ssvc_history            TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON ssvc_suggestions        TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON sync_peers              TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON sync_comments           TO {{ .User | sanitize }};
 --
 -- default queries
 --
@@ -0,0 +1,79 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- Documents with a deleted time stamp are in the trash bin.
+ALTER TABLE documents ADD COLUMN deleted timestamptz;
+
+CREATE INDEX documents_deleted_idx ON documents(deleted) WHERE deleted IS NOT NULL;
+
+ALTER TYPE events ADD VALUE 'restore_document' AFTER 'delete_document';
+ALTER TYPE events ADD VALUE 'purge_document' AFTER 'restore_document';
+
+-- elect_latest makes the newest document of an advisory
+-- which is not in the trash bin the latest one.
+CREATE FUNCTION elect_latest(adv_id int) RETURNS void AS $$
+    DECLARE
+        lead_id int;
+    BEGIN
+        SELECT id
+            INTO lead_id
+            FROM documents
+            WHERE advisories_id = adv_id AND deleted IS NULL
+            ORDER BY current_release_date DESC, rev_history_length DESC
+            LIMIT 1;
+        -- Drop the old lead first to not violate only_one_latest_constraint.
+        UPDATE documents SET latest = FALSE
+            WHERE advisories_id = adv_id AND latest AND id IS DISTINCT FROM lead_id;
+        IF lead_id IS NOT NULL THEN
+            UPDATE documents SET latest = TRUE
+                WHERE id = lead_id AND latest IS NOT TRUE;
+        END IF;
+    END;
+$$ LANGUAGE plpgsql;
+
+-- delete_advisory re-establishes the latest document after a document
+-- was purged and removes the advisory if there are no documents left.
+CREATE OR REPLACE FUNCTION delete_advisory() RETURNS trigger AS $$
+    BEGIN
+        -- Update is only needed if deleted one was latest.
+        IF OLD.latest THEN
+            PERFORM elect_latest(OLD.advisories_id);
+        END IF;
+        -- Documents in the trash bin keep the advisory alive.
+        IF NOT EXISTS (SELECT 1 FROM documents WHERE advisories_id = OLD.advisories_id) THEN
+            DELETE FROM advisories WHERE id = OLD.advisories_id;
+        END IF;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+-- trash_document re-establishes the latest document and the cached
+-- comment count of an advisory if a document is moved to or
+-- restored from the trash bin.
+CREATE FUNCTION trash_document() RETURNS trigger AS $$
+    DECLARE
+        num_comments int;
+    BEGIN
+        PERFORM elect_latest(NEW.advisories_id);
+        SELECT count(*) INTO num_comments FROM comments WHERE documents_id = NEW.id;
+        IF NEW.deleted IS NOT NULL THEN
+            num_comments = -num_comments;
+        END IF;
+        UPDATE advisories
+            SET comments = greatest(0, comments + num_comments)
+            WHERE id = NEW.advisories_id;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trash_document
+    AFTER UPDATE OF deleted
+    ON documents
+    FOR EACH ROW
+    WHEN ((OLD.deleted IS NULL) <> (NEW.deleted IS NULL))
+    EXECUTE FUNCTION trash_document();
@@ -0,0 +1,93 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- The id of an event is used as position in the changes feed of the peers.
+ALTER TABLE events_log ADD COLUMN id bigint GENERATED BY DEFAULT AS IDENTITY;
+CREATE UNIQUE INDEX events_log_id_idx ON events_log(id);
+
+-- origin is the name of the peer a synchronized change came from.
+-- It is NULL for local changes.
+ALTER TABLE events_log ADD COLUMN origin varchar;
+ALTER TABLE ssvc_history ADD COLUMN origin varchar;
+
+CREATE OR REPLACE FUNCTION log_ssvc_history_to_events()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_prev_ssvc text;
+    v_event     events;
+BEGIN
+    -- Find the most recent previous SSVC value for this document
+    SELECT ssvc
+      INTO v_prev_ssvc
+      FROM ssvc_history
+     WHERE documents_id = NEW.documents_id
+       AND change_number < NEW.change_number
+     ORDER BY change_number DESC
+     LIMIT 1;
+
+    -- Determine event based on the change
+    IF v_prev_ssvc IS NULL THEN
+        -- No previous value existed: add
+        v_event := 'add_sscv';
+        -- Not possible yet, but future-proofing:
+    ELSIF NEW.ssvc IS NULL THEN
+        -- Had value before, now null: delete
+        v_event := 'delete_sscv';
+    ELSE
+        -- Only a change: change
+        v_event := 'change_sscv';
+    END IF;
+
+    INSERT INTO events_log (
+        event,
+        state,
+        time,
+        actor,
+        documents_id,
+        comments_id,
+        origin
+    )
+    VALUES (
+        v_event,
+        (SELECT ads.state FROM advisories ads JOIN documents docs
+        ON docs.advisories_id = ads.id WHERE docs.id = NEW.documents_id),
+        NEW.changedate,
+        NEW.actor,
+        NEW.documents_id,
+        NULL,
+        NEW.origin
+    );
+    RETURN NEW;
+END;
+$$;
+
+--
+-- peer synchronization
+--
+CREATE TABLE sync_peers (
+    id         int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    name       varchar     NOT NULL UNIQUE,
+    -- last_event is the id of the last event fetched from the peer.
+    last_event bigint      NOT NULL DEFAULT 0,
+    last_sync  timestamptz,
+    last_error varchar,
+    CHECK(name <> '')
+);
+
+-- sync_comments maps the comments of the peers to the local ones.
+CREATE TABLE sync_comments (
+    sync_peers_id int NOT NULL REFERENCES sync_peers(id) ON DELETE CASCADE,
+    remote_id     int NOT NULL,
+    comments_id   int NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    PRIMARY KEY (sync_peers_id, remote_id)
+);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON sync_peers    TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON sync_comments TO {{ .User | sanitize }};
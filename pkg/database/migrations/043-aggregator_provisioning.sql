@@ -0,0 +1,39 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+CREATE TYPE aggregator_provisioning AS ENUM (
+    'off', 'review', 'auto');
+
+ALTER TABLE aggregators
+    ADD COLUMN provisioning aggregator_provisioning NOT NULL DEFAULT 'off';
+
+CREATE TYPE aggregator_action AS ENUM (
+    'create_source', 'deactivate_source');
+
+CREATE TYPE aggregator_action_state AS ENUM (
+    'pending', 'applied', 'rejected');
+
+-- aggregator_actions records the source provisioning done
+-- for the aggregators and holds the actions awaiting review.
+CREATE TABLE aggregator_actions (
+    id             int                     PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    aggregators_id int                     NOT NULL REFERENCES aggregators(id) ON DELETE CASCADE,
+    action         aggregator_action       NOT NULL,
+    url            varchar                 NOT NULL,
+    sources_id     int                     REFERENCES sources(id) ON DELETE SET NULL,
+    state          aggregator_action_state NOT NULL DEFAULT 'pending',
+    time           timestamptz             NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided        timestamptz,
+    actor          varchar,
+    message        varchar
+);
+
+CREATE UNIQUE INDEX aggregator_actions_pending_idx
+    ON aggregator_actions(aggregators_id, action, url) WHERE state = 'pending';
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON aggregator_actions TO {{ .User | sanitize }};
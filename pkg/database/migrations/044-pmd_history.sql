@@ -0,0 +1,21 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- source_pmds stores the versions of the provider metadata of the sources.
+CREATE TABLE source_pmds (
+    id         int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    sources_id int         NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fetched    timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- checksum is the SHA256 sum of the fetched document.
+    checksum   bytea       NOT NULL,
+    document   jsonb       NOT NULL
+);
+
+CREATE INDEX source_pmds_sources_id_idx ON source_pmds(sources_id, id);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON source_pmds TO {{ .User | sanitize }};
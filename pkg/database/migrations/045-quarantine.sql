@@ -0,0 +1,42 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+CREATE TYPE quarantine_state AS ENUM (
+    'pending', 'released', 'rejected'
+);
+
+-- quarantine keeps the downloads which failed the checks in strict mode.
+CREATE TABLE quarantine (
+    id               int              PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    feeds_id         int              REFERENCES feeds(id) ON DELETE SET NULL,
+    url              varchar          NOT NULL,
+    time             timestamptz      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- checksum is the SHA256 sum of the original document.
+    checksum         bytea            NOT NULL,
+    original         bytea            NOT NULL,
+    signature        bytea,
+    filename         varchar,
+    publisher        varchar,
+    tracking_id      varchar,
+    version          varchar,
+    tlp              varchar,
+    filename_failed  bool             NOT NULL DEFAULT FALSE,
+    schema_failed    bool             NOT NULL DEFAULT FALSE,
+    remote_failed    bool             NOT NULL DEFAULT FALSE,
+    checksum_failed  bool             NOT NULL DEFAULT FALSE,
+    signature_failed bool             NOT NULL DEFAULT FALSE,
+    state            quarantine_state NOT NULL DEFAULT 'pending',
+    decided          timestamptz,
+    actor            varchar,
+    documents_id     int              REFERENCES documents(id) ON DELETE SET NULL,
+    UNIQUE (url, checksum)
+);
+
+CREATE INDEX quarantine_state_idx ON quarantine(state);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON quarantine TO {{ .User | sanitize }};
@@ -0,0 +1,27 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+CREATE TYPE download_state AS ENUM (
+    'waiting', 'running', 'failed'
+);
+
+-- download_queue keeps the pending downloads of the feeds over restarts.
+CREATE TABLE download_queue (
+    feeds_id  int            NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    url       varchar        NOT NULL,
+    updated   timestamptz    NOT NULL,
+    hash      varchar,
+    signature varchar,
+    state     download_state NOT NULL DEFAULT 'waiting',
+    retries   int            NOT NULL DEFAULT 0,
+    message   varchar,
+    time      timestamptz    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(feeds_id, url)
+);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON download_queue TO {{ .User | sanitize }};
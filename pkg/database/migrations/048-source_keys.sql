@@ -0,0 +1,51 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+CREATE TYPE source_key_state AS ENUM (
+    'pending', 'trusted', 'rejected'
+);
+
+-- source_keys are the OpenPGP keys pinned for the sources.
+CREATE TABLE source_keys (
+    sources_id  int              NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fingerprint varchar          NOT NULL,
+    -- key is the armored public key.
+    key         text             NOT NULL,
+    state       source_key_state NOT NULL DEFAULT 'pending',
+    -- advertised tells if the key is listed in the current PMD.
+    advertised  boolean          NOT NULL DEFAULT FALSE,
+    -- uploaded tells if the key was added manually.
+    uploaded    boolean          NOT NULL DEFAULT FALSE,
+    expires     timestamptz,
+    first_seen  timestamptz      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided     timestamptz,
+    actor       varchar,
+    -- used counts the documents whose signatures were verified with the key.
+    used        bigint           NOT NULL DEFAULT 0,
+    last_used   timestamptz,
+    PRIMARY KEY(sources_id, fingerprint)
+);
+
+CREATE TYPE source_key_events AS ENUM (
+    'pinned', 'added', 'removed', 'trusted', 'rejected', 'uploaded', 'deleted'
+);
+
+-- source_keys_log records the changes of the OpenPGP keys of the sources.
+CREATE TABLE source_keys_log (
+    id          int               PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    sources_id  int               NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fingerprint varchar           NOT NULL,
+    event       source_key_events NOT NULL,
+    time        timestamptz       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor       varchar
+);
+
+CREATE INDEX source_keys_log_sources_id_idx ON source_keys_log(sources_id, time);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON source_keys     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON source_keys_log TO {{ .User | sanitize }};
@@ -0,0 +1,31 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- secrets are the credentials of the sources and the forward targets
+-- if they are stored in the database. The values are encrypted
+-- with the AES key of the server.
+CREATE TABLE secrets (
+    name    varchar     PRIMARY KEY,
+    value   bytea       NOT NULL,
+    updated timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK(name <> '')
+);
+
+-- The credentials are encrypted with the same key so they can be moved as they are.
+INSERT INTO secrets (name, value)
+    SELECT 'sources/' || id || '/client_cert_private', client_cert_private
+    FROM sources WHERE client_cert_private IS NOT NULL;
+
+INSERT INTO secrets (name, value)
+    SELECT 'sources/' || id || '/client_cert_passphrase', client_cert_passphrase
+    FROM sources WHERE client_cert_passphrase IS NOT NULL;
+
+ALTER TABLE sources DROP COLUMN client_cert_private;
+ALTER TABLE sources DROP COLUMN client_cert_passphrase;
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON secrets TO {{ .User | sanitize }};
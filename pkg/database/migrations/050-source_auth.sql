@@ -0,0 +1,18 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+CREATE TYPE source_auth_modes AS ENUM (
+    'none', 'bearer', 'oauth2'
+);
+
+-- auth is the mode the source authenticates with. The bearer token
+-- and the OAuth2 client secret are kept by the secrets provider.
+ALTER TABLE sources ADD COLUMN auth             source_auth_modes NOT NULL DEFAULT 'none';
+ALTER TABLE sources ADD COLUMN oauth2_token_url varchar;
+ALTER TABLE sources ADD COLUMN oauth2_client_id varchar;
+ALTER TABLE sources ADD COLUMN oauth2_scopes    text[];
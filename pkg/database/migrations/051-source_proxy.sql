@@ -0,0 +1,12 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- proxy is the URL of the proxy of the source or 'direct' to bypass
+-- the proxies. NULL uses the globally configured proxy.
+-- The credentials of the proxy are kept by the secrets provider.
+ALTER TABLE sources ADD COLUMN proxy varchar CHECK(proxy <> '');
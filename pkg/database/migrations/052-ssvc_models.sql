@@ -0,0 +1,13 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- model is the name of the SSVC decision model the vector was made with.
+-- All vectors so far were validated against the embedded CISA Coordinator model.
+ALTER TABLE ssvc_history ADD COLUMN model varchar;
+
+UPDATE ssvc_history SET model = 'CISA-Coordinator' WHERE ssvc IS NOT NULL;
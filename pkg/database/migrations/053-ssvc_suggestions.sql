@@ -0,0 +1,20 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- ssvc_suggestions are the SSVC vectors proposed from the data
+-- of the documents. They are kept apart from the confirmed
+-- SSVC vectors in ssvc_history.
+CREATE TABLE ssvc_suggestions (
+    documents_id integer     PRIMARY KEY REFERENCES documents(id) ON DELETE CASCADE,
+    model        varchar     NOT NULL,
+    ssvc         text        NOT NULL,
+    rationale    jsonb       NOT NULL,
+    created      timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON ssvc_suggestions TO {{ .User | sanitize }};
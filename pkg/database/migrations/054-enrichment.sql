@@ -0,0 +1,45 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- kev are the entries of the CISA catalogue of known exploited vulnerabilities.
+CREATE TABLE kev (
+    cve        text    PRIMARY KEY,
+    vendor     text,
+    product    text,
+    name       text,
+    date_added date,
+    due_date   date,
+    ransomware boolean NOT NULL DEFAULT FALSE
+);
+
+-- epss are the exploit prediction scores of the CVEs.
+CREATE TABLE epss (
+    cve        text  PRIMARY KEY,
+    epss       float NOT NULL,
+    percentile float NOT NULL
+);
+
+-- cwes are the entries of the CWE list.
+CREATE TABLE cwes (
+    id          int  PRIMARY KEY,
+    name        text NOT NULL,
+    description text
+);
+
+-- enrichment_imports records the last import of the enrichment catalogues.
+CREATE TABLE enrichment_imports (
+    catalogue varchar     PRIMARY KEY,
+    modified  timestamptz NOT NULL,
+    imported  timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    entries   int         NOT NULL
+);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON kev                TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON epss               TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON cwes               TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON enrichment_imports TO {{ .User | sanitize }};
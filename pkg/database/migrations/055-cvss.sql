@@ -0,0 +1,90 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+CREATE FUNCTION max_cvss4_score(jsonb) RETURNS float AS $$
+    SELECT max(a::float) FROM
+        jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.cvss_v4.baseScore') a
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE documents ADD COLUMN cvss_v4_score float
+    GENERATED ALWAYS AS (max_cvss4_score(document)) STORED;
+
+-- Make critical prefer the CVSS v4 scores.
+ALTER TABLE documents DROP COLUMN critical;
+ALTER TABLE documents ADD COLUMN critical float GENERATED ALWAYS AS (
+    coalesce(max_cvss4_score(document), max_cvss3_score(document), max_cvss2_score(document))) STORED;
+
+CREATE INDEX documents_cvss4_idx ON documents(coalesce(cvss_v4_score, '0'::double precision) DESC);
+CREATE INDEX documents_critical_idx ON documents(coalesce(critical, '0'::double precision) DESC);
+
+-- documents_cvss are the parsed CVSS vectors of the vulnerabilities of the documents.
+CREATE TABLE documents_cvss (
+    documents_id        int     NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    vulnerability       int     NOT NULL, -- index in the vulnerabilities of the document
+    cve                 text,
+    version             varchar NOT NULL,
+    vector              text    NOT NULL,
+    base_score          float,
+    environmental_score float,
+    -- The metrics. Which of them are set depends on the CVSS version.
+    av varchar,
+    ac varchar,
+    at varchar,
+    pr varchar,
+    ui varchar,
+    au varchar,
+    s  varchar,
+    c  varchar,
+    i  varchar,
+    a  varchar,
+    vc varchar,
+    vi varchar,
+    va varchar,
+    sc varchar,
+    si varchar,
+    sa varchar,
+    e  varchar,
+    PRIMARY KEY (documents_id, vulnerability, vector)
+);
+
+-- cvss_pending are the documents whose CVSS vectors have to be (re-)indexed.
+CREATE TABLE cvss_pending (
+    documents_id int         PRIMARY KEY REFERENCES documents(id) ON DELETE CASCADE,
+    queued       timestamptz NOT NULL DEFAULT clock_timestamp()
+);
+
+-- cvss_environment are the environmental defaults the vectors were scored with.
+CREATE TABLE cvss_environment (
+    metrics jsonb NOT NULL
+);
+
+CREATE FUNCTION queue_cvss() RETURNS TRIGGER AS $$
+    BEGIN
+        INSERT INTO cvss_pending (documents_id) VALUES (NEW.id)
+            ON CONFLICT (documents_id) DO UPDATE SET queued = clock_timestamp();
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER queue_cvss_trigger_insert AFTER INSERT
+    ON documents
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_cvss();
+
+CREATE TRIGGER queue_cvss_trigger_update AFTER UPDATE
+    ON documents
+    FOR EACH ROW
+    WHEN (NEW.document <> OLD.document)
+    EXECUTE FUNCTION queue_cvss();
+
+INSERT INTO cvss_pending (documents_id) SELECT id FROM documents;
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON documents_cvss   TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON cvss_pending     TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON cvss_environment TO {{ .User | sanitize }};
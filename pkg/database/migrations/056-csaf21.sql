@@ -0,0 +1,47 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- CSAF 2.1 stores the CVSS scores in the metrics of the vulnerabilities.
+-- The stored columns need no re-computation as CSAF 2.1 documents
+-- were not accepted before.
+CREATE OR REPLACE FUNCTION max_cvss2_score(jsonb) RETURNS float AS $$
+    SELECT max(a::float) FROM (
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].scores[*].cvss_v2.baseScore')
+        UNION ALL
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.cvss_v2.baseScore')) AS s(a)
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION max_cvss3_score(jsonb) RETURNS float AS $$
+    SELECT max(a::float) FROM (
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].scores[*].cvss_v3.baseScore')
+        UNION ALL
+        SELECT jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.cvss_v3.baseScore')) AS s(a)
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- The EPSS content of the CSAF 2.1 metrics stores the numbers as strings.
+CREATE FUNCTION max_epss_probability(jsonb) RETURNS float AS $$
+    SELECT max((a #>> '{}')::float) FROM
+        jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.epss.probability') a
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE FUNCTION max_epss_percentile(jsonb) RETURNS float AS $$
+    SELECT max((a #>> '{}')::float) FROM
+        jsonb_path_query(
+            $1, '$.vulnerabilities[*].metrics[*].content.epss.percentile') a
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE documents
+    ADD COLUMN csaf_epss float
+        GENERATED ALWAYS AS (max_epss_probability(document)) STORED,
+    ADD COLUMN csaf_epss_percentile float
+        GENERATED ALWAYS AS (max_epss_percentile(document)) STORED;
@@ -0,0 +1,101 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- Replies refer to the comment they answer.
+-- They are kept as top-level comments if the parent is purged.
+ALTER TABLE comments ADD COLUMN parent_id int REFERENCES comments(id) ON DELETE SET NULL;
+
+CREATE INDEX ON comments(parent_id);
+
+-- comment_mentions are the users mentioned as @user in the comments.
+CREATE TABLE comment_mentions (
+    comments_id int     NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    mentioned   varchar NOT NULL,
+    PRIMARY KEY (comments_id, mentioned)
+);
+
+CREATE INDEX ON comment_mentions(mentioned);
+
+CREATE FUNCTION extract_mentions() RETURNS trigger AS $$
+    BEGIN
+        DELETE FROM comment_mentions WHERE comments_id = NEW.id;
+        INSERT INTO comment_mentions (comments_id, mentioned)
+            SELECT DISTINCT NEW.id, m[1]
+            FROM regexp_matches(coalesce(NEW.message, ''),
+                '(?:^|[^[:alnum:]_])@([[:alnum:]_]+(?:[.-][[:alnum:]_]+)*)', 'g') AS m;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER insert_comment_mentions
+    AFTER INSERT
+    ON comments
+    FOR EACH ROW EXECUTE FUNCTION extract_mentions();
+
+CREATE TRIGGER update_comment_mentions
+    AFTER UPDATE OF message
+    ON comments
+    FOR EACH ROW EXECUTE FUNCTION extract_mentions();
+
+INSERT INTO comment_mentions (comments_id, mentioned)
+    SELECT DISTINCT id, m[1]
+    FROM comments, regexp_matches(coalesce(message, ''),
+        '(?:^|[^[:alnum:]_])@([[:alnum:]_]+(?:[.-][[:alnum:]_]+)*)', 'g') AS m;
+
+-- comment_reactions are the reactions of the users to the comments.
+CREATE TABLE comment_reactions (
+    comments_id int         NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    actor       varchar     NOT NULL,
+    reaction    varchar(32) NOT NULL,
+    time        timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comments_id, actor, reaction)
+);
+
+-- comment_attachments are the files attached to the comments.
+-- The content is stored either in data or in a file
+-- named by path in the configured attachments directory.
+CREATE TABLE comment_attachments (
+    id           int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    comments_id  int         NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    filename     varchar     NOT NULL,
+    content_type varchar     NOT NULL,
+    size         bigint      NOT NULL,
+    uploader     varchar,
+    time         timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data         bytea,
+    path         varchar,
+    CHECK ((data IS NULL) <> (path IS NULL))
+);
+
+CREATE INDEX ON comment_attachments(comments_id);
+
+-- removed_attachments are the files of deleted attachments
+-- which have to be removed from the attachments directory.
+CREATE TABLE removed_attachments (
+    path varchar PRIMARY KEY
+);
+
+CREATE FUNCTION remove_attachment() RETURNS trigger AS $$
+    BEGIN
+        IF OLD.path IS NOT NULL THEN
+            INSERT INTO removed_attachments (path) VALUES (OLD.path)
+                ON CONFLICT DO NOTHING;
+        END IF;
+        RETURN NULL;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER delete_comment_attachment
+    AFTER DELETE
+    ON comment_attachments
+    FOR EACH ROW EXECUTE FUNCTION remove_attachment();
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON comment_mentions    TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON comment_reactions   TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON comment_attachments TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON removed_attachments TO {{ .User | sanitize }};
@@ -0,0 +1,45 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- advisory_checklists are the triage checklists of the advisories.
+-- The items are copied from the configured templates.
+CREATE TABLE advisory_checklists (
+    advisories_id int         NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    num           int         NOT NULL,
+    item          varchar     NOT NULL,
+    done          boolean     NOT NULL DEFAULT FALSE,
+    changed_by    varchar,
+    changed       timestamptz,
+    PRIMARY KEY (advisories_id, num)
+);
+
+-- advisory_notes are internal notes on the advisories
+-- independent of the versions of their documents.
+CREATE TABLE advisory_notes (
+    id            int             PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    advisories_id int             NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    author        varchar,
+    time          timestamptz     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    changed       timestamptz,
+    note          varchar(10000)  NOT NULL
+);
+
+CREATE INDEX ON advisory_notes(advisories_id);
+
+-- advisory_tags are the tags the advisories are labeled with.
+CREATE TABLE advisory_tags (
+    advisories_id int         NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    tag           varchar(64) NOT NULL,
+    PRIMARY KEY (advisories_id, tag)
+);
+
+CREATE INDEX ON advisory_tags(tag);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON advisory_checklists TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON advisory_notes      TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON advisory_tags       TO {{ .User | sanitize }};
@@ -0,0 +1,29 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- tags are the admin managed definitions of the tags
+-- the advisories can be labeled with.
+CREATE TABLE tags (
+    name        varchar(64) PRIMARY KEY,
+    colour      varchar(7),
+    description varchar
+);
+
+-- Define the tags already in use.
+INSERT INTO tags (name) SELECT DISTINCT tag FROM advisory_tags;
+
+ALTER TABLE advisory_tags ADD FOREIGN KEY (tag)
+    REFERENCES tags(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TYPE events ADD VALUE 'add_tag';
+ALTER TYPE events ADD VALUE 'remove_tag' AFTER 'add_tag';
+
+-- tag is the tag added to or removed from an advisory.
+ALTER TABLE events_log ADD COLUMN tag varchar(64);
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON tags TO {{ .User | sanitize }};
@@ -0,0 +1,32 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- vulnerability_cases group advisories about the same vulnerability.
+-- The shared_* flags control which parts of the workflow are
+-- propagated to all advisories of a case.
+CREATE TABLE vulnerability_cases (
+    id              int         PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    name            varchar     NOT NULL,
+    created         timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    shared_state    boolean     NOT NULL DEFAULT FALSE,
+    shared_ssvc     boolean     NOT NULL DEFAULT FALSE,
+    shared_comments boolean     NOT NULL DEFAULT FALSE
+);
+
+-- vulnerability_case_advisories are the advisories of the cases.
+-- An advisory belongs to at most one case.
+CREATE TABLE vulnerability_case_advisories (
+    cases_id      int NOT NULL REFERENCES vulnerability_cases(id) ON DELETE CASCADE,
+    advisories_id int NOT NULL UNIQUE REFERENCES advisories(id) ON DELETE CASCADE,
+    PRIMARY KEY (cases_id, advisories_id)
+);
+
+ALTER TYPE stored_queries_kind ADD VALUE 'cases';
+
+GRANT INSERT, DELETE, SELECT, UPDATE ON vulnerability_cases           TO {{ .User | sanitize }};
+GRANT INSERT, DELETE, SELECT, UPDATE ON vulnerability_case_advisories TO {{ .User | sanitize }};
@@ -0,0 +1,22 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- xact is the transaction which logged the event. The ids of the events
+-- are handed out before commit so a late committing transaction may become
+-- visible with a lower id. Together with the id the transaction gives a
+-- position in the changes feed of the peers which is safe against this.
+-- The existing events are all committed and keep their order by id.
+ALTER TABLE events_log ADD COLUMN xact xid8;
+UPDATE events_log SET xact = '0';
+ALTER TABLE events_log ALTER COLUMN xact SET DEFAULT pg_current_xact_id();
+ALTER TABLE events_log ALTER COLUMN xact SET NOT NULL;
+
+CREATE INDEX events_log_xact_id_idx ON events_log(xact, id);
+
+-- last_xact is the transaction of the last event fetched from the peer.
+ALTER TABLE sync_peers ADD COLUMN last_xact bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- The provisioning of sources from the aggregators is logged as events.
+ALTER TYPE events ADD VALUE 'create_source';
+ALTER TYPE events ADD VALUE 'deactivate_source';
+
+-- sources_id is the source an event is about.
+ALTER TABLE events_log ADD COLUMN sources_id int REFERENCES sources(id) ON DELETE SET NULL;
@@ -0,0 +1,12 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- CVSS v4 vectors are scored now. Re-index the documents having them.
+INSERT INTO cvss_pending (documents_id)
+    SELECT DISTINCT documents_id FROM documents_cvss WHERE version = '4.0'
+    ON CONFLICT (documents_id) DO UPDATE SET queued = clock_timestamp();
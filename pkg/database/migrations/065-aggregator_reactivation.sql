@@ -0,0 +1,15 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+-- Sources deactivated as their providers were not listed by an
+-- aggregator any more are reactivated when they are listed again.
+ALTER TYPE aggregator_action ADD VALUE 'activate_source';
+ALTER TYPE events ADD VALUE 'activate_source';
+
+-- listed are the URLs listed by an aggregator at its last provisioning.
+ALTER TABLE aggregators ADD COLUMN listed varchar[];
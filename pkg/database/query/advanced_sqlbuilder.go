@@ -38,8 +38,10 @@ type statementMode interface {
 	searchWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
 	mentionedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
 	involvedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
+	taggedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
 	ilikePNameWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
 	ilikePIDWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
+	cvssMetricWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder)
 	order(sb *AdvancedSQLBuilder, b *strings.Builder, name string)
 }
 
@@ -63,7 +65,7 @@ func (classicMode) projectionCommon(
 		b.WriteString(versionsCount + `AS versions`)
 	case "comments":
 		switch sb.mode() {
-		case AdvisoryMode:
+		case AdvisoryMode, CaseMode:
 			b.WriteString(name)
 		case DocumentMode:
 			b.WriteString(commentsCountDocuments + `AS comments`)
@@ -89,6 +91,15 @@ func (cm classicMode) projection(sb *AdvancedSQLBuilder, b *strings.Builder, nam
 		b.WriteString(name)
 	case "ssvc":
 		b.WriteString("ssvc_current.ssvc AS ssvc")
+	case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+		b.WriteString(derivedColumns[name])
+		b.WriteString(` AS `)
+		b.WriteString(name)
+	case "case_id", "case_name", "case_advisories",
+		"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+		b.WriteString(caseColumns[name])
+		b.WriteString(` AS `)
+		b.WriteString(name)
 	default:
 		cm.projectionCommon(sb, b, name,
 			versionsCountClassic, commentsCountDocumentsClassic)
@@ -121,9 +132,17 @@ func (classicMode) from(sb *AdvancedSQLBuilder, b *strings.Builder) {
 	case AdvisoryMode, DocumentMode:
 		b.WriteString(`documents ` +
 			`JOIN advisories ON ` +
-			`advisories.id = documents.advisories_id`)
+			`advisories.id = documents.advisories_id ` +
+			`AND documents.deleted IS NULL`)
+	case CaseMode:
+		b.WriteString(`documents ` +
+			`JOIN advisories ON ` +
+			`advisories.id = documents.advisories_id ` +
+			`AND documents.deleted IS NULL` +
+			casesJoin)
 	case EventMode:
 		b.WriteString(`events_log JOIN documents ON events_log.documents_id = documents.id ` +
+			`AND documents.deleted IS NULL ` +
 			`JOIN advisories ON advisories.id = documents.advisories_id ` +
 			`LEFT JOIN (SELECT message, id FROM comments) AS comment ON events_log.comments_id = comment.id`)
 	}
@@ -199,7 +218,7 @@ func (sb *AdvancedSQLBuilder) createAliasedSearches(b *strings.Builder) {
 
 func (cteMode) from(sb *AdvancedSQLBuilder, b *strings.Builder) {
 	switch sb.mode() {
-	case AdvisoryMode, DocumentMode:
+	case AdvisoryMode, DocumentMode, CaseMode:
 		b.WriteString(`docads`)
 	case EventMode:
 		b.WriteString(`events_log JOIN docads ON events_log.documents_id = docads.id ` +
@@ -223,7 +242,7 @@ func (classicMode) accessWhereCommon(
 		b.WriteString(versionsCount)
 	case "comments":
 		switch sb.mode() {
-		case AdvisoryMode:
+		case AdvisoryMode, CaseMode:
 			b.WriteString(column)
 		case DocumentMode:
 			b.WriteString(commentsCountDocuments)
@@ -247,6 +266,11 @@ func (cm classicMode) accessWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Bu
 		b.WriteString(column)
 	case "ssvc":
 		b.WriteString("ssvc_current.ssvc")
+	case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+		b.WriteString(derivedColumns[column])
+	case "case_id", "case_name", "case_advisories",
+		"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+		b.WriteString(caseColumns[column])
 	default:
 		cm.accessWhereCommon(sb, e, b,
 			versionsCountClassic, commentsCountDocumentsClassic)
@@ -273,27 +297,36 @@ func (classicMode) searchWhere(_ *AdvancedSQLBuilder, _ *Expr, b *strings.Builde
 	b.WriteString("TRUE")
 }
 
+// mentionedUser returns the placeholder of the user mentioned in the comments.
+func mentionedUser(sb *AdvancedSQLBuilder, e *Expr) int {
+	return sb.replacementIndex(strings.TrimPrefix(e.stringValue, "@")) + 1
+}
+
 func (classicMode) mentionedWhereCommon(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
 	switch sb.mode() {
 	case EventMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments WHERE message ILIKE $%d "+
-			"AND comments.id = events_log.comments_id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"WHERE comment_mentions.mentioned = $%d "+
+			"AND comment_mentions.comments_id = events_log.comments_id)",
+			mentionedUser(sb, e))
 	}
 }
 
 func (cm classicMode) mentionedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
 	switch sb.mode() {
-	case AdvisoryMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments "+
+	case AdvisoryMode, CaseMode:
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"JOIN comments ON comment_mentions.comments_id = comments.id "+
 			"JOIN documents docs ON comments.documents_id = docs.id "+
-			"WHERE message ILIKE $%d "+
+			"WHERE comment_mentions.mentioned = $%d "+
 			"AND docs.advisories_id = documents.advisories_id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+			mentionedUser(sb, e))
 	case DocumentMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments WHERE message ILIKE $%d "+
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"JOIN comments ON comment_mentions.comments_id = comments.id "+
+			"WHERE comment_mentions.mentioned = $%d "+
 			"AND comments.documents_id = documents.id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+			mentionedUser(sb, e))
 	default:
 		cm.mentionedWhereCommon(sb, e, b)
 	}
@@ -301,16 +334,19 @@ func (cm classicMode) mentionedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings
 
 func (cm cteMode) mentionedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
 	switch sb.mode() {
-	case AdvisoryMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments "+
-			"JOIN docads ON comments.documents_id = docads.id "+
-			"WHERE message ILIKE $%d "+
-			"AND docads.advisories_id = docads.advisories_id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+	case AdvisoryMode, CaseMode:
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"JOIN comments ON comment_mentions.comments_id = comments.id "+
+			"JOIN documents docs ON comments.documents_id = docs.id "+
+			"WHERE comment_mentions.mentioned = $%d "+
+			"AND docs.advisories_id = docads.advisories_id)",
+			mentionedUser(sb, e))
 	case DocumentMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments WHERE message ILIKE $%d "+
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"JOIN comments ON comment_mentions.comments_id = comments.id "+
+			"WHERE comment_mentions.mentioned = $%d "+
 			"AND comments.documents_id = docads.id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+			mentionedUser(sb, e))
 	default:
 		cm.mentionedWhereCommon(sb, e, b)
 	}
@@ -318,7 +354,7 @@ func (cm cteMode) mentionedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Bui
 
 func (classicMode) involvedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
 	switch sb.mode() {
-	case AdvisoryMode, EventMode:
+	case AdvisoryMode, EventMode, CaseMode:
 		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM events_log JOIN documents docs "+
 			"ON events_log.documents_id = docs.id "+
 			"WHERE actor = $%d "+
@@ -333,7 +369,7 @@ func (classicMode) involvedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Bui
 
 func (cteMode) involvedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
 	switch sb.mode() {
-	case AdvisoryMode, EventMode:
+	case AdvisoryMode, EventMode, CaseMode:
 		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM events_log JOIN documents docads "+
 			"ON events_log.documents_id = docads.id "+
 			"WHERE actor = $%d)",
@@ -345,6 +381,22 @@ func (cteMode) involvedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder
 	}
 }
 
+// taggedWhereCommon writes an EXISTS clause checking if the
+// advisory with the given id column is labeled with the tag.
+func taggedWhereCommon(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder, id string) {
+	fmt.Fprintf(b, "EXISTS(SELECT 1 FROM advisory_tags WHERE tag = $%d "+
+		"AND advisory_tags.advisories_id = "+id+")",
+		sb.replacementIndex(e.stringValue)+1)
+}
+
+func (classicMode) taggedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
+	taggedWhereCommon(sb, e, b, "documents.advisories_id")
+}
+
+func (cteMode) taggedWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
+	taggedWhereCommon(sb, e, b, "docads.advisories_id")
+}
+
 func (cm classicMode) ilikePNameWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
 	b.WriteString(`EXISTS (` +
 		`WITH product_names AS (SELECT jsonb_path_query(` +
@@ -407,9 +459,28 @@ func (cm cteMode) ilikePIDWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Buil
 	b.WriteString(ilikeSuffix + `)`)
 }
 
+// cvssMetricWhereCommon writes an EXISTS clause checking the
+// CVSS metric of the document with the given id column.
+func cvssMetricWhereCommon(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder, sm statementMode, id string) {
+	b.WriteString(`EXISTS(SELECT 1 FROM documents_cvss WHERE ` +
+		`documents_cvss.documents_id = ` + id + ` AND ` +
+		`documents_cvss.` + e.stringValue + ` = `)
+	sb.whereRecurse(e.children[0], b, sm)
+	b.WriteByte(')')
+}
+
+func (cm classicMode) cvssMetricWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
+	cvssMetricWhereCommon(sb, e, b, cm, "documents.id")
+}
+
+func (cm cteMode) cvssMetricWhere(sb *AdvancedSQLBuilder, e *Expr, b *strings.Builder) {
+	cvssMetricWhereCommon(sb, e, b, cm, "docads.id")
+}
+
 func (classicMode) orderCommon(b *strings.Builder, name string) {
 	switch name {
-	case "cvss_v2_score", "cvss_v3_score", "critical":
+	case "cvss_v2_score", "cvss_v3_score", "cvss_v4_score", "critical",
+		"epss", "epss_percentile", "cvss_environmental_score":
 		b.WriteString("COALESCE(")
 		b.WriteString(name)
 		b.WriteString(",0)")
@@ -429,6 +500,11 @@ func (cm classicMode) order(_ *AdvancedSQLBuilder, b *strings.Builder, name stri
 		b.WriteString(name)
 	case "ssvc":
 		b.WriteString("ssvc_current.ssvc")
+	case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+		b.WriteString(derivedOrder(derivedColumns[name], name))
+	case "case_id", "case_name", "case_advisories",
+		"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+		b.WriteString(caseColumns[name])
 	default:
 		cm.orderCommon(b, name)
 	}
@@ -669,12 +745,16 @@ func (sb *AdvancedSQLBuilder) whereRecurse(e *Expr, b *strings.Builder, sm state
 		sm.mentionedWhere(sb, e, b)
 	case involved:
 		sm.involvedWhere(sb, e, b)
+	case tagged:
+		sm.taggedWhere(sb, e, b)
 	case ilike:
 		sb.ilikeWhere(e, b, sm)
 	case ilikePName:
 		sm.ilikePNameWhere(sb, e, b)
 	case ilikePID:
 		sm.ilikePIDWhere(sb, e, b)
+	case cvssMetric:
+		sm.cvssMetricWhere(sb, e, b)
 	case now:
 		sb.nowWhere(b)
 	case add:
@@ -751,12 +831,25 @@ func (sb *AdvancedSQLBuilder) prefixCTE(b *strings.Builder) {
 				`SELECT ssvc FROM ssvc_history ` +
 				`WHERE documents_id = documents.id ` +
 				`ORDER BY changedate DESC, change_number DESC LIMIT 1)`)
+		case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+			b.WriteString(derivedColumns[field])
+			b.WriteString(` AS `)
+			b.WriteString(field)
+		case "case_id", "case_name", "case_advisories",
+			"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+			b.WriteString(caseColumns[field])
+			b.WriteString(` AS `)
+			b.WriteString(field)
 		default:
 			b.WriteString(field)
 		}
 	}
 	b.WriteString(` FROM documents JOIN advisories` +
-		` ON documents.advisories_id = advisories.id)`)
+		` ON documents.advisories_id = advisories.id`)
+	if sb.mode() == CaseMode {
+		b.WriteString(casesJoin)
+	}
+	b.WriteString(` WHERE documents.deleted IS NULL)`)
 }
 
 // CreateQuery creates an SQL statement to query the documents
@@ -35,9 +35,11 @@ const (
 	search
 	mentioned
 	involved
+	tagged
 	ilike
 	ilikePName
 	ilikePID
+	cvssMetric
 	now
 	add
 	sub
@@ -176,10 +178,14 @@ func (et exprType) String() string {
 		return "mentioned"
 	case involved:
 		return "involved"
+	case tagged:
+		return "tagged"
 	case ilike:
 		return "ilike"
 	case ilikePID:
 		return "ilikepid"
+	case cvssMetric:
+		return "cvss"
 	case now:
 		return "now"
 	case add:
@@ -32,6 +32,8 @@ const (
 	AdvisoryMode
 	// EventMode operates on events.
 	EventMode
+	// CaseMode operates on the advisories of the vulnerability cases.
+	CaseMode
 )
 
 // Parser helps parsing database queries,
@@ -68,6 +70,8 @@ const (
 	eventsLogTable
 	// commentsTable means that the comments table is needed.
 	commentsTable
+	// casesTable means that the vulnerability cases tables are needed.
+	casesTable
 )
 
 var columnSourceTables = [...]string{
@@ -78,6 +82,7 @@ var columnSourceTables = [...]string{
 	ssvcHistoryTable: "ssvc_history",
 	eventsLogTable:   "events_log",
 	commentsTable:    "comments",
+	casesTable:       "vulnerability_cases",
 }
 
 type documentColumn struct {
@@ -111,7 +116,14 @@ var documentColumns = []documentColumn{
 	{"ssvc", stringType, docAdvEvtModes, false, ssvcHistoryTable},
 	{"cvss_v2_score", floatType, docAdvEvtModes, false, documentsTable},
 	{"cvss_v3_score", floatType, docAdvEvtModes, false, documentsTable},
+	{"cvss_v4_score", floatType, docAdvEvtModes, false, documentsTable},
+	{"cvss_environmental_score", floatType, docAdvEvtModes, false, documentsTable},
 	{"critical", floatType, docAdvEvtModes, false, documentsTable},
+	{"kev", boolType, docAdvEvtModes, false, documentsTable},
+	{"epss", floatType, docAdvEvtModes, false, documentsTable},
+	{"epss_percentile", floatType, docAdvEvtModes, false, documentsTable},
+	{"checklist_done", boolType, docAdvEvtModes, false, documentsTable},
+	{"tags", stringType, docAdvEvtModes, false, documentsTable},
 	{"four_cves", stringType, docAdvEvtModes, true, documentsTable},
 	{"comments", intType, docAdvEvtModes, false, documentsTable},
 	{"tracking_status", statusType, docAdvEvtModes, false, documentsTable},
@@ -127,6 +139,13 @@ var documentColumns = []documentColumn{
 	{"actor", stringType, evtsModes, false, eventsLogTable},
 	{"comments_id", intType, evtsModes, false, commentsTable},
 	{"message", stringType, evtsModes, false, commentsTable},
+	// Cases only
+	{"case_id", intType, caseModes, false, casesTable},
+	{"case_name", stringType, caseModes, false, casesTable},
+	{"case_advisories", intType, caseModes, false, casesTable},
+	{"case_shared_state", boolType, caseModes, false, casesTable},
+	{"case_shared_ssvc", boolType, caseModes, false, casesTable},
+	{"case_shared_comments", boolType, caseModes, false, casesTable},
 }
 
 var (
@@ -152,6 +171,7 @@ var (
 		"ilike":      (*Parser).pushILike,
 		"ilikepname": pushTypedILike(ilikePName),
 		"ilikepid":   pushTypedILike(ilikePID),
+		"cvss":       (*Parser).pushCVSS,
 		"now":        (*Parser).pushNow,
 		"duration":   (*Parser).pushDuration,
 		"+":          curry3((*Parser).pushBinary, add),
@@ -161,6 +181,8 @@ var (
 		"me":         (*Parser).pushMe,
 		"mentioned":  (*Parser).pushMentioned,
 		"involved":   (*Parser).pushInvolved,
+		"tagged":     (*Parser).pushTagged,
+		"tag":        (*Parser).pushTagged,
 		"search":     (*Parser).pushSearch,
 		"as":         (*Parser).pushAs,
 	}
@@ -169,6 +191,7 @@ var (
 		DocumentMode: buildActions(DocumentMode),
 		AdvisoryMode: buildActions(AdvisoryMode),
 		EventMode:    buildActions(EventMode),
+		CaseMode:     buildActions(CaseMode),
 	}
 )
 
@@ -201,9 +224,11 @@ var binaryCompatMatrix = map[binaryCompat]valueType{
 }
 
 var (
-	docAdvEvtModes = []ParserMode{DocumentMode, AdvisoryMode, EventMode}
-	advModes       = []ParserMode{AdvisoryMode}
+	// The case mode works on advisories, too.
+	docAdvEvtModes = []ParserMode{DocumentMode, AdvisoryMode, EventMode, CaseMode}
+	advModes       = []ParserMode{AdvisoryMode, CaseMode}
 	evtsModes      = []ParserMode{EventMode}
+	caseModes      = []ParserMode{CaseMode}
 )
 
 func curry3[A, B, C any](fn func(A, B, C), c C) func(A, B) {
@@ -219,6 +244,8 @@ func (pm *ParserMode) UnmarshalText(text []byte) error {
 		*pm = DocumentMode
 	case "events":
 		*pm = EventMode
+	case "cases":
+		*pm = CaseMode
 	default:
 		return fmt.Errorf("unknown parser mode %q", s)
 	}
@@ -234,6 +261,8 @@ func (pm ParserMode) MarshalText() ([]byte, error) {
 		return []byte("documents"), nil
 	case EventMode:
 		return []byte("events"), nil
+	case CaseMode:
+		return []byte("cases"), nil
 	default:
 		return nil, fmt.Errorf("unknown parser mode %d", pm)
 	}
@@ -248,6 +277,8 @@ func (pm ParserMode) String() string {
 		return "advisories"
 	case EventMode:
 		return "events"
+	case CaseMode:
+		return "cases"
 	default:
 		return fmt.Sprintf("Unknown parser mode: %d", pm)
 	}
@@ -519,6 +550,17 @@ func (p *Parser) pushSearch(st *stack) {
 	})
 }
 
+// advisoryIDAccess is a hidden access to the advisory id of the documents.
+// It is the child of expressions referring to the advisories to make
+// the column available in the common table expressions.
+func advisoryIDAccess() *Expr {
+	return &Expr{
+		exprType:    access,
+		valueType:   intType,
+		stringValue: "advisories_id",
+	}
+}
+
 func (p *Parser) pushMentioned(st *stack) {
 	term := st.pop()
 	term.checkValueType(stringType)
@@ -528,6 +570,7 @@ func (p *Parser) pushMentioned(st *stack) {
 		exprType:    mentioned,
 		valueType:   boolType,
 		stringValue: term.stringValue,
+		children:    []*Expr{advisoryIDAccess()},
 	})
 }
 
@@ -542,6 +585,18 @@ func (p *Parser) pushInvolved(st *stack) {
 	})
 }
 
+func (p *Parser) pushTagged(st *stack) {
+	term := st.pop()
+	term.checkValueType(stringType)
+	p.UsedSources.add(documentsTable)
+	st.push(&Expr{
+		exprType:    tagged,
+		valueType:   boolType,
+		stringValue: term.stringValue,
+		children:    []*Expr{advisoryIDAccess()},
+	})
+}
+
 func (p *Parser) pushILike(st *stack) {
 	needle := st.pop()
 	haystack := st.pop()
@@ -568,6 +623,34 @@ func pushTypedILike(typ exprType) func(*Parser, *stack) {
 	}
 }
 
+// cvssMetricColumns maps the CVSS metrics to the columns
+// of the documents_cvss table.
+var cvssMetricColumns = map[string]string{
+	"AV": "av", "AC": "ac", "AT": "at", "PR": "pr", "UI": "ui", "AU": "au",
+	"S": "s", "C": "c", "I": "i", "A": "a",
+	"VC": "vc", "VI": "vi", "VA": "va", "SC": "sc", "SI": "si", "SA": "sa",
+	"E": "e",
+}
+
+func (p *Parser) pushCVSS(st *stack) {
+	value := st.pop()
+	metric := st.pop()
+	value.checkValueType(stringType)
+	metric.checkValueType(stringType)
+	metric.checkExprType(cnst)
+	column, ok := cvssMetricColumns[strings.ToUpper(metric.stringValue)]
+	if !ok {
+		panic(parseError(fmt.Sprintf("unknown CVSS metric %q", metric.stringValue)))
+	}
+	p.UsedSources.add(documentsTable)
+	st.push(&Expr{
+		exprType:    cvssMetric,
+		valueType:   boolType,
+		stringValue: column,
+		children:    []*Expr{value},
+	})
+}
+
 func (*Parser) pushNow(st *stack) {
 	st.push(&Expr{
 		exprType:  now,
@@ -760,9 +843,14 @@ func parseWorkflow(s string) string {
 
 var validEvents = []string{
 	"import_document", "delete_document",
+	"restore_document", "purge_document",
 	"state_change",
 	"add_sscv", "change_sscv", "delete_sscv",
 	"add_comment", "change_comment", "delete_comment",
+	"add_tag", "remove_tag",
+	"create_source", "deactivate_source",
+	"release_document",
+	"activate_source",
 }
 
 func parseEvents(s string) string {
@@ -81,7 +81,7 @@ func (sb *SQLBuilder) searchWhere(e *Expr, b *strings.Builder) {
 		sb.Aliases[e.alias] = `txt`
 	} else {
 		switch sb.Mode {
-		case AdvisoryMode, DocumentMode:
+		case AdvisoryMode, DocumentMode, CaseMode:
 			fmt.Fprintf(b, "EXISTS(SELECT 1 FROM documents_texts "+
 				"JOIN unique_texts ON unique_texts.id = documents_texts.txt_id "+
 				"WHERE txt ILIKE $%d "+
@@ -111,27 +111,32 @@ func (sb *SQLBuilder) searchWhere(e *Expr, b *strings.Builder) {
 }
 
 func (sb *SQLBuilder) mentionedWhere(e *Expr, b *strings.Builder) {
+	mentioned := sb.replacementIndex(strings.TrimPrefix(e.stringValue, "@")) + 1
 	switch sb.Mode {
-	case AdvisoryMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments "+
+	case AdvisoryMode, CaseMode:
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"JOIN comments ON comment_mentions.comments_id = comments.id "+
 			"JOIN documents docs ON comments.documents_id = docs.id "+
-			"WHERE message ILIKE $%d "+
+			"WHERE comment_mentions.mentioned = $%d "+
 			"AND docs.advisories_id = documents.advisories_id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+			mentioned)
 	case DocumentMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments WHERE message ILIKE $%d "+
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"JOIN comments ON comment_mentions.comments_id = comments.id "+
+			"WHERE comment_mentions.mentioned = $%d "+
 			"AND comments.documents_id = documents.id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+			mentioned)
 	case EventMode:
-		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comments WHERE message ILIKE $%d "+
-			"AND comments.id = events_log.comments_id)",
-			sb.replacementIndex(LikeEscape(e.stringValue))+1)
+		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM comment_mentions "+
+			"WHERE comment_mentions.mentioned = $%d "+
+			"AND comment_mentions.comments_id = events_log.comments_id)",
+			mentioned)
 	}
 }
 
 func (sb *SQLBuilder) involvedWhere(e *Expr, b *strings.Builder) {
 	switch sb.Mode {
-	case AdvisoryMode, EventMode:
+	case AdvisoryMode, EventMode, CaseMode:
 		fmt.Fprintf(b, "EXISTS(SELECT 1 FROM events_log JOIN documents docs "+
 			"ON events_log.documents_id = docs.id "+
 			"WHERE actor = $%d "+
@@ -144,6 +149,12 @@ func (sb *SQLBuilder) involvedWhere(e *Expr, b *strings.Builder) {
 	}
 }
 
+func (sb *SQLBuilder) taggedWhere(e *Expr, b *strings.Builder) {
+	fmt.Fprintf(b, "EXISTS(SELECT 1 FROM advisory_tags WHERE tag = $%d "+
+		"AND advisory_tags.advisories_id = documents.advisories_id)",
+		sb.replacementIndex(e.stringValue)+1)
+}
+
 func (sb *SQLBuilder) castWhere(e *Expr, b *strings.Builder) {
 	b.WriteString("CAST(")
 	sb.whereRecurse(e.children[0], b)
@@ -225,7 +236,7 @@ func (sb *SQLBuilder) notWhere(e *Expr, b *strings.Builder) {
 
 const (
 	versionsCountClassic = `(SELECT count(*) FROM documents WHERE ` +
-		`documents.advisories_id = advisories.id)`
+		`documents.advisories_id = advisories.id AND documents.deleted IS NULL)`
 	commentsCountDocumentsClassic = `(SELECT count(*) FROM comments WHERE ` +
 		`comments.documents_id = documents.id)`
 	versionsCountCTE          = `(SELECT count(*) FROM docads)`
@@ -235,6 +246,61 @@ const (
 		`comments.documents_id = documents_id)`
 )
 
+// cvesOfDocument joins the CVEs of a document to the enrichment data.
+const cvesOfDocument = `FROM documents_cves ` +
+	`JOIN unique_cves ON documents_cves.cve_id = unique_cves.id `
+
+// derivedColumns are the columns derived from the
+// enrichment data of the CVEs and the indexed CVSS vectors of a document
+// and from the checklist and the tags of its advisory.
+// The EPSS data of the catalogue is preferred over the one stated
+// in the metrics of CSAF 2.1 documents.
+var derivedColumns = map[string]string{
+	"kev": `EXISTS(SELECT 1 ` + cvesOfDocument +
+		`JOIN kev ON kev.cve = unique_cves.cve ` +
+		`WHERE documents_cves.documents_id = documents.id)`,
+	"epss": `coalesce((SELECT max(epss.epss) ` + cvesOfDocument +
+		`JOIN epss ON epss.cve = unique_cves.cve ` +
+		`WHERE documents_cves.documents_id = documents.id), documents.csaf_epss)`,
+	"epss_percentile": `coalesce((SELECT max(epss.percentile) ` + cvesOfDocument +
+		`JOIN epss ON epss.cve = unique_cves.cve ` +
+		`WHERE documents_cves.documents_id = documents.id), documents.csaf_epss_percentile)`,
+	"cvss_environmental_score": `(SELECT max(documents_cvss.environmental_score) ` +
+		`FROM documents_cvss WHERE documents_cvss.documents_id = documents.id)`,
+	"checklist_done": `(EXISTS(SELECT 1 FROM advisory_checklists ` +
+		`WHERE advisory_checklists.advisories_id = documents.advisories_id) ` +
+		`AND NOT EXISTS(SELECT 1 FROM advisory_checklists ` +
+		`WHERE advisory_checklists.advisories_id = documents.advisories_id AND NOT done))`,
+	"tags": `array_to_string(ARRAY(SELECT tag FROM advisory_tags ` +
+		`WHERE advisory_tags.advisories_id = documents.advisories_id ORDER BY tag), ',')`,
+}
+
+// casesJoin joins the advisories with their vulnerability cases.
+const casesJoin = ` JOIN vulnerability_case_advisories ` +
+	`ON vulnerability_case_advisories.advisories_id = advisories.id ` +
+	`JOIN vulnerability_cases ` +
+	`ON vulnerability_cases.id = vulnerability_case_advisories.cases_id`
+
+// caseColumns are the columns of the vulnerability cases.
+var caseColumns = map[string]string{
+	"case_id":   `vulnerability_cases.id`,
+	"case_name": `vulnerability_cases.name`,
+	"case_advisories": `(SELECT count(*) FROM vulnerability_case_advisories vca ` +
+		`WHERE vca.cases_id = vulnerability_cases.id)`,
+	"case_shared_state":    `vulnerability_cases.shared_state`,
+	"case_shared_ssvc":     `vulnerability_cases.shared_ssvc`,
+	"case_shared_comments": `vulnerability_cases.shared_comments`,
+}
+
+// derivedOrder returns the ORDER BY term of a derived column.
+func derivedOrder(expr, name string) string {
+	switch name {
+	case "kev", "checklist_done", "tags":
+		return expr
+	}
+	return "COALESCE(" + expr + ",0)"
+}
+
 func (sb *SQLBuilder) accessWhere(e *Expr, b *strings.Builder) {
 	switch column := e.stringValue; column {
 	case "id":
@@ -247,7 +313,7 @@ func (sb *SQLBuilder) accessWhere(e *Expr, b *strings.Builder) {
 		b.WriteString(versionsCountClassic)
 	case "comments":
 		switch sb.Mode {
-		case AdvisoryMode:
+		case AdvisoryMode, CaseMode:
 			b.WriteString(column)
 		case DocumentMode:
 			b.WriteString(commentsCountDocumentsClassic)
@@ -258,6 +324,11 @@ func (sb *SQLBuilder) accessWhere(e *Expr, b *strings.Builder) {
 		b.WriteString("events_log.state")
 	case "ssvc":
 		b.WriteString("ssvc_current.ssvc")
+	case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+		b.WriteString(derivedColumns[column])
+	case "case_id", "case_name", "case_advisories",
+		"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+		b.WriteString(caseColumns[column])
 	default:
 		b.WriteString(column)
 	}
@@ -328,6 +399,14 @@ func (sb *SQLBuilder) ilikePIDWhere(e *Expr, b *strings.Builder) {
 	*/
 }
 
+func (sb *SQLBuilder) cvssMetricWhere(e *Expr, b *strings.Builder) {
+	b.WriteString(`EXISTS(SELECT 1 FROM documents_cvss WHERE ` +
+		`documents_cvss.documents_id = documents.id AND ` +
+		`documents_cvss.` + e.stringValue + ` = `)
+	sb.whereRecurse(e.children[0], b)
+	b.WriteByte(')')
+}
+
 func (sb *SQLBuilder) whereRecurse(e *Expr, b *strings.Builder) {
 	b.WriteByte('(')
 	switch e.exprType {
@@ -361,12 +440,16 @@ func (sb *SQLBuilder) whereRecurse(e *Expr, b *strings.Builder) {
 		sb.mentionedWhere(e, b)
 	case involved:
 		sb.involvedWhere(e, b)
+	case tagged:
+		sb.taggedWhere(e, b)
 	case ilike:
 		sb.ilikeWhere(e, b)
 	case ilikePName:
 		sb.ilikePNameWhere(e, b)
 	case ilikePID:
 		sb.ilikePIDWhere(e, b)
+	case cvssMetric:
+		sb.cvssMetricWhere(e, b)
 	case now:
 		sb.nowWhere(e, b)
 	case add:
@@ -399,9 +482,17 @@ func (sb *SQLBuilder) createFrom(b *strings.Builder) {
 	case AdvisoryMode, DocumentMode:
 		b.WriteString(`documents ` +
 			`JOIN advisories ON ` +
-			`advisories.id = documents.advisories_id`)
+			`advisories.id = documents.advisories_id ` +
+			`AND documents.deleted IS NULL`)
+	case CaseMode:
+		b.WriteString(`documents ` +
+			`JOIN advisories ON ` +
+			`advisories.id = documents.advisories_id ` +
+			`AND documents.deleted IS NULL` +
+			casesJoin)
 	case EventMode:
 		b.WriteString(`events_log JOIN documents ON events_log.documents_id = documents.id ` +
+			`AND documents.deleted IS NULL ` +
 			`JOIN advisories ON advisories.id = documents.advisories_id ` +
 			`LEFT JOIN (SELECT message, id FROM comments) AS comment ON events_log.comments_id = comment.id`)
 	}
@@ -449,12 +540,17 @@ func (sb *SQLBuilder) CreateOrder(fields []string) (string, error) {
 		case "tracking_id", "publisher", "id":
 			b.WriteString("advisories.")
 			b.WriteString(field)
-		case "cvss_v2_score", "cvss_v3_score", "critical":
+		case "cvss_v2_score", "cvss_v3_score", "cvss_v4_score", "critical":
 			b.WriteString("COALESCE(")
 			b.WriteString(field)
 			b.WriteString(",0)")
 		case "ssvc":
 			b.WriteString("ssvc_current.ssvc")
+		case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+			b.WriteString(derivedOrder(derivedColumns[field], field))
+		case "case_id", "case_name", "case_advisories",
+			"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+			b.WriteString(caseColumns[field])
 		case "version":
 			// TODO: This is not optimal (SemVer).
 			b.WriteString(
@@ -546,9 +642,18 @@ func (sb *SQLBuilder) projectionsWithCasts(b *strings.Builder, proj []string) {
 			b.WriteString(versionsCountClassic + `AS versions`)
 		case "ssvc":
 			b.WriteString("ssvc_current.ssvc AS ssvc")
+		case "kev", "epss", "epss_percentile", "cvss_environmental_score", "checklist_done", "tags":
+			b.WriteString(derivedColumns[p])
+			b.WriteString(` AS `)
+			b.WriteString(p)
+		case "case_id", "case_name", "case_advisories",
+			"case_shared_state", "case_shared_ssvc", "case_shared_comments":
+			b.WriteString(caseColumns[p])
+			b.WriteString(` AS `)
+			b.WriteString(p)
 		case "comments":
 			switch sb.Mode {
-			case AdvisoryMode:
+			case AdvisoryMode, CaseMode:
 				b.WriteString(p)
 			case DocumentMode:
 				b.WriteString(commentsCountDocumentsClassic + `AS comments`)
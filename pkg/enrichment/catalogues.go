@@ -0,0 +1,162 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package enrichment
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseKEV parses the CISA KEV catalogue in JSON format.
+func parseKEV(r io.Reader) ([][]any, error) {
+	var catalogue struct {
+		Vulnerabilities []struct {
+			CVEID                      string `json:"cveID"`
+			VendorProject              string `json:"vendorProject"`
+			Product                    string `json:"product"`
+			VulnerabilityName          string `json:"vulnerabilityName"`
+			DateAdded                  string `json:"dateAdded"`
+			DueDate                    string `json:"dueDate"`
+			KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.NewDecoder(r).Decode(&catalogue); err != nil {
+		return nil, err
+	}
+	date := func(s string) *time.Time {
+		if t, err := time.Parse(time.DateOnly, s); err == nil {
+			return &t
+		}
+		return nil
+	}
+	seen := map[string]struct{}{}
+	rows := make([][]any, 0, len(catalogue.Vulnerabilities))
+	for _, v := range catalogue.Vulnerabilities {
+		if _, dup := seen[v.CVEID]; dup || v.CVEID == "" {
+			continue
+		}
+		seen[v.CVEID] = struct{}{}
+		rows = append(rows, []any{
+			v.CVEID,
+			v.VendorProject,
+			v.Product,
+			v.VulnerabilityName,
+			date(v.DateAdded),
+			date(v.DueDate),
+			strings.EqualFold(v.KnownRansomwareCampaignUse, "Known"),
+		})
+	}
+	return rows, nil
+}
+
+// csvColumns reads the header of a CSV file and
+// returns the indices of the requested columns.
+func csvColumns(r *csv.Reader, names ...string) ([]int, error) {
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header failed: %w", err)
+	}
+	indices := make([]int, len(names))
+nextName:
+	for i, name := range names {
+		for j, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				indices[i] = j
+				continue nextName
+			}
+		}
+		return nil, fmt.Errorf("missing column %q", name)
+	}
+	return indices, nil
+}
+
+// field returns the field at the given index or an empty string.
+func field(record []string, idx int) string {
+	if idx < len(record) {
+		return strings.TrimSpace(record[idx])
+	}
+	return ""
+}
+
+// parseEPSS parses the EPSS scores in CSV format.
+func parseEPSS(r io.Reader) ([][]any, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cols, err := csvColumns(cr, "cve", "epss", "percentile")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{}
+	var rows [][]any
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		cve := field(record, cols[0])
+		if _, dup := seen[cve]; dup || cve == "" {
+			continue
+		}
+		seen[cve] = struct{}{}
+		epss, err := strconv.ParseFloat(field(record, cols[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EPSS score of %q: %w", cve, err)
+		}
+		percentile, err := strconv.ParseFloat(field(record, cols[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EPSS percentile of %q: %w", cve, err)
+		}
+		rows = append(rows, []any{cve, epss, percentile})
+	}
+}
+
+// parseCWE parses the CWE list in the CSV format published by MITRE.
+func parseCWE(r io.Reader) ([][]any, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cols, err := csvColumns(cr, "CWE-ID", "Name", "Description")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[int]struct{}{}
+	var rows [][]any
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(field(record, cols[0]), "CWE-"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CWE id %q: %w", field(record, cols[0]), err)
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		var description *string
+		if d := field(record, cols[2]); d != "" {
+			description = &d
+		}
+		rows = append(rows, []any{id, field(record, cols[1]), description})
+	}
+}
@@ -0,0 +1,49 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package enrichment
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseCatalogues(t *testing.T) {
+	for _, input := range []struct {
+		name  string
+		parse func(string) (int, error)
+		data  string
+		want  int
+	}{
+		{"kev", countRows(parseKEV), `{"vulnerabilities": [
+			{"cveID": "CVE-2021-44228", "dateAdded": "2021-12-10", "knownRansomwareCampaignUse": "Known"},
+			{"cveID": "CVE-2021-44228"}]}`, 1},
+		{"epss", countRows(parseEPSS), "#model_version:v2023.03.01,score_date:2024-03-13T00:00:00+0000\n" +
+			"cve,epss,percentile\nCVE-1999-0001,0.01,0.8\nCVE-1999-0002,0.5,0.99\n", 2},
+		{"cwes", countRows(parseCWE), "CWE-ID,Name,Weakness Abstraction,Status,Description,\n" +
+			`79,"Improper Neutralization of Input During Web Page Generation",Base,Stable,"XSS",` + "\n", 1},
+	} {
+		n, err := input.parse(input.data)
+		if err != nil {
+			t.Errorf("%s: %v", input.name, err)
+		} else if n != input.want {
+			t.Errorf("%s: have %d rows want %d", input.name, n, input.want)
+		}
+	}
+	if _, err := parseEPSS(strings.NewReader("cve,epss\nCVE-1999-0001,0.01\n")); err == nil {
+		t.Error("expected EPSS without percentile to fail")
+	}
+}
+
+func countRows(parse func(io.Reader) ([][]any, error)) func(string) (int, error) {
+	return func(data string) (int, error) {
+		rows, err := parse(strings.NewReader(data))
+		return len(rows), err
+	}
+}
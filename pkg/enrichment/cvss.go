@@ -0,0 +1,283 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/cvss"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+)
+
+// cvssBatchSize is the number of documents indexed in one transaction.
+const cvssBatchSize = 100
+
+// cvssMetrics are the metrics stored in the columns of the documents_cvss table.
+var cvssMetrics = []string{
+	"AV", "AC", "AT", "PR", "UI", "Au", "S", "C", "I", "A",
+	"VC", "VI", "VA", "SC", "SI", "SA", "E",
+}
+
+// cvssColumns are the columns of the documents_cvss table.
+var cvssColumns = func() []string {
+	columns := []string{
+		"documents_id", "vulnerability", "cve", "version", "vector",
+		"base_score", "environmental_score",
+	}
+	for _, metric := range cvssMetrics {
+		columns = append(columns, strings.ToLower(metric))
+	}
+	return columns
+}()
+
+// cvssScore is a CVSS score of a CSAF document.
+type cvssScore struct {
+	VectorString string   `json:"vectorString"`
+	BaseScore    *float64 `json:"baseScore"`
+}
+
+// cvssScores are the CVSS scores of a CSAF score or metric content.
+type cvssScores struct {
+	CVSS2 *cvssScore `json:"cvss_v2"`
+	CVSS3 *cvssScore `json:"cvss_v3"`
+	CVSS4 *cvssScore `json:"cvss_v4"`
+}
+
+// cvssVulnerability is the part of a CSAF vulnerability holding the CVSS scores.
+// CSAF 2.0 stores them in the scores and CSAF 2.1 in the metrics.
+type cvssVulnerability struct {
+	CVE     string       `json:"cve"`
+	Scores  []cvssScores `json:"scores"`
+	Metrics []struct {
+		Content cvssScores `json:"content"`
+	} `json:"metrics"`
+}
+
+// all returns the CVSS scores of the vulnerability.
+func (cv *cvssVulnerability) all() []*cvssScore {
+	var scores []*cvssScore
+	for _, s := range cv.Scores {
+		scores = append(scores, s.CVSS2, s.CVSS3, s.CVSS4)
+	}
+	for _, m := range cv.Metrics {
+		scores = append(scores, m.Content.CVSS2, m.Content.CVSS3, m.Content.CVSS4)
+	}
+	return scores
+}
+
+// CVSSIndexer stores the CVSS vectors of the documents in
+// normalised form and scores them with the environmental
+// defaults of the organisation.
+type CVSSIndexer struct {
+	cfg *config.CVSS
+	db  *database.DB
+}
+
+// NewCVSSIndexer returns a new CVSS indexer.
+func NewCVSSIndexer(cfg *config.Config, db *database.DB) *CVSSIndexer {
+	return &CVSSIndexer{cfg: &cfg.CVSS, db: db}
+}
+
+// Run runs the indexer. To be used in a Go routine.
+func (ci *CVSSIndexer) Run(ctx context.Context) {
+	if err := ci.checkEnvironment(ctx); err != nil {
+		slog.Error("checking CVSS environment failed", "err", err)
+	}
+	ticker := time.NewTicker(ci.cfg.UpdateInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := ci.index(ctx)
+			if err != nil {
+				slog.Error("indexing CVSS vectors failed", "err", err)
+			}
+			if err != nil || n < cvssBatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkEnvironment queues all documents for re-indexing if the
+// environmental defaults differ from the ones the vectors were scored with.
+func (ci *CVSSIndexer) checkEnvironment(ctx context.Context) error {
+	const (
+		selectSQL = `SELECT metrics FROM cvss_environment`
+		deleteSQL = `DELETE FROM cvss_environment`
+		insertSQL = `INSERT INTO cvss_environment (metrics) VALUES ($1)`
+		queueSQL  = `INSERT INTO cvss_pending (documents_id) SELECT id FROM documents ` +
+			`ON CONFLICT (documents_id) DO UPDATE SET queued = clock_timestamp()`
+	)
+	return ci.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			var metrics map[string]string
+			if err := tx.QueryRow(rctx, selectSQL).Scan(&metrics); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			if maps.Equal(metrics, ci.cfg.Environmental) {
+				return nil
+			}
+			environmental := ci.cfg.Environmental
+			if environmental == nil {
+				environmental = map[string]string{}
+			}
+			if _, err := tx.Exec(rctx, deleteSQL); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, insertSQL, environmental); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, queueSQL); err != nil {
+				return err
+			}
+			slog.Info("CVSS environment changed, re-scoring all documents")
+			return tx.Commit(rctx)
+		}, 0,
+	)
+}
+
+// rows returns the rows of the documents_cvss table for the vulnerabilities of a document.
+func (ci *CVSSIndexer) rows(documentID int64, vulns []cvssVulnerability) [][]any {
+	type key struct {
+		vulnerability int
+		vector        string
+	}
+	seen := map[key]struct{}{}
+	var rows [][]any
+	for i := range vulns {
+		vuln := &vulns[i]
+		var cve *string
+		if vuln.CVE != "" {
+			cve = &vuln.CVE
+		}
+		for _, score := range vuln.all() {
+			if score == nil || score.VectorString == "" {
+				continue
+			}
+			v, err := cvss.Parse(score.VectorString)
+			if err != nil {
+				slog.Warn("invalid CVSS vector",
+					"document", documentID, "vector", score.VectorString, "err", err)
+				continue
+			}
+			vector := v.String()
+			if _, dup := seen[key{i, vector}]; dup {
+				continue
+			}
+			seen[key{i, vector}] = struct{}{}
+			// Prefer the computed scores over the stated ones.
+			baseScore := score.BaseScore
+			if base, ok := v.BaseScore(); ok {
+				baseScore = &base
+			}
+			var envScore *float64
+			if env, ok := v.EnvironmentalScore(ci.cfg.Environmental); ok {
+				envScore = &env
+			}
+			row := []any{documentID, i, cve, v.Version, vector, baseScore, envScore}
+			for _, metric := range cvssMetrics {
+				var value *string
+				// S is the safety in CVSS v4 and not the scope.
+				if m := v.Metric(metric); m != "" && (metric != "S" || v.Version != cvss.Version40) {
+					value = &m
+				}
+				row = append(row, value)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// index indexes a batch of the queued documents
+// and returns the number of indexed documents.
+func (ci *CVSSIndexer) index(ctx context.Context) (int, error) {
+	const (
+		selectSQL = `SELECT cvss_pending.documents_id, cvss_pending.queued, ` +
+			`documents.document -> 'vulnerabilities' ` +
+			`FROM cvss_pending JOIN documents ON cvss_pending.documents_id = documents.id ` +
+			`ORDER BY cvss_pending.queued LIMIT $1`
+		deleteSQL  = `DELETE FROM documents_cvss WHERE documents_id = $1`
+		dequeueSQL = `DELETE FROM cvss_pending WHERE documents_id = $1 AND queued = $2`
+	)
+	type pending struct {
+		id     int64
+		queued time.Time
+		vulns  []byte
+	}
+	var n int
+	err := ci.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, selectSQL, cvssBatchSize)
+			pendings, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (pending, error) {
+				var p pending
+				err := row.Scan(&p.id, &p.queued, &p.vulns)
+				return p, err
+			})
+			if err != nil {
+				return err
+			}
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			var cvssRows [][]any
+			for _, p := range pendings {
+				var vulns []cvssVulnerability
+				if len(p.vulns) > 0 {
+					if err := json.Unmarshal(p.vulns, &vulns); err != nil {
+						slog.Warn("cannot extract CVSS vectors", "document", p.id, "err", err)
+						vulns = nil
+					}
+				}
+				cvssRows = append(cvssRows, ci.rows(p.id, vulns)...)
+				if _, err := tx.Exec(rctx, deleteSQL, p.id); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(rctx, dequeueSQL, p.id, p.queued); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.CopyFrom(
+				rctx, pgx.Identifier{"documents_cvss"}, cvssColumns, pgx.CopyFromRows(cvssRows),
+			); err != nil {
+				return err
+			}
+			if err := tx.Commit(rctx); err != nil {
+				return err
+			}
+			n = len(pendings)
+			return nil
+		}, 0,
+	)
+	return n, err
+}
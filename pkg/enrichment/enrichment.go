@@ -0,0 +1,235 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package enrichment implements the import of local snapshots of
+// vulnerability enrichment catalogues like the CISA KEV catalogue,
+// the EPSS scores and the CWE list as well as the indexing and
+// re-scoring of the CVSS vectors of the documents.
+package enrichment
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+)
+
+// catalogue describes how an enrichment catalogue is parsed and stored.
+type catalogue struct {
+	name    string
+	columns []string
+	parse   func(io.Reader) ([][]any, error)
+	config  func(*config.Enrichment) *config.EnrichmentCatalogue
+}
+
+// catalogues are the supported enrichment catalogues.
+// Their names are the names of the tables they are stored in.
+var catalogues = []catalogue{{
+	name:    "kev",
+	columns: []string{"cve", "vendor", "product", "name", "date_added", "due_date", "ransomware"},
+	parse:   parseKEV,
+	config:  func(cfg *config.Enrichment) *config.EnrichmentCatalogue { return &cfg.KEV },
+}, {
+	name:    "epss",
+	columns: []string{"cve", "epss", "percentile"},
+	parse:   parseEPSS,
+	config:  func(cfg *config.Enrichment) *config.EnrichmentCatalogue { return &cfg.EPSS },
+}, {
+	name:    "cwes",
+	columns: []string{"id", "name", "description"},
+	parse:   parseCWE,
+	config:  func(cfg *config.Enrichment) *config.EnrichmentCatalogue { return &cfg.CWE },
+}}
+
+// Importer keeps the enrichment tables in sync with the snapshot files.
+// Files dropped in place are imported as well as files fetched from the
+// configured URLs.
+type Importer struct {
+	cfg     *config.Enrichment
+	db      *database.DB
+	client  *http.Client
+	fetched map[string]time.Time
+}
+
+// NewImporter returns a new importer.
+func NewImporter(cfg *config.Config, db *database.DB) *Importer {
+	return &Importer{
+		cfg: &cfg.Enrichment,
+		db:  db,
+		client: &http.Client{
+			Timeout:   cfg.Enrichment.Timeout,
+			Transport: cfg.General.Transport(),
+		},
+		fetched: map[string]time.Time{},
+	}
+}
+
+// Run runs the importer. To be used in a Go routine.
+func (im *Importer) Run(ctx context.Context) {
+	ticker := time.NewTicker(im.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		for i := range catalogues {
+			im.update(ctx, &catalogues[i])
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update fetches the file of a catalogue if it is due
+// and imports it if it was modified since the last import.
+func (im *Importer) update(ctx context.Context, c *catalogue) {
+	ec := c.config(im.cfg)
+	if ec.File == "" {
+		return
+	}
+	if ec.URL != "" && time.Since(im.fetched[c.name]) >= im.cfg.FetchInterval {
+		if err := im.fetch(ctx, ec); err != nil {
+			slog.Error("fetching enrichment catalogue failed",
+				"catalogue", c.name, "url", ec.URL, "err", err)
+		} else {
+			im.fetched[c.name] = time.Now()
+		}
+	}
+	fi, err := os.Stat(ec.File)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Error("checking enrichment catalogue failed",
+				"catalogue", c.name, "file", ec.File, "err", err)
+		}
+		return
+	}
+	// The database stores the time in microseconds.
+	modified := fi.ModTime().UTC().Truncate(time.Microsecond)
+	if err := im.importFile(ctx, c, ec.File, modified); err != nil {
+		slog.Error("importing enrichment catalogue failed",
+			"catalogue", c.name, "file", ec.File, "err", err)
+	}
+}
+
+// fetch downloads the catalogue into its file.
+// The file is replaced atomically to not import half written files.
+func (im *Importer) fetch(ctx context.Context, ec *config.EnrichmentCatalogue) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ec.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(ec.File), filepath.Base(ec.File)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), ec.File)
+}
+
+// parseFile parses a catalogue file which may be gzip compressed.
+func parseFile(file string, parse func(io.Reader) ([][]any, error)) ([][]any, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	if magic, err := r.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return parse(gz)
+	}
+	return parse(r)
+}
+
+// importFile replaces the content of the table of the catalogue
+// with the file if it was modified since the last import.
+func (im *Importer) importFile(
+	ctx context.Context,
+	c *catalogue,
+	file string,
+	modified time.Time,
+) error {
+	const (
+		lastSQL   = `SELECT modified FROM enrichment_imports WHERE catalogue = $1`
+		recordSQL = `INSERT INTO enrichment_imports (catalogue, modified, entries) ` +
+			`VALUES ($1, $2, $3) ` +
+			`ON CONFLICT (catalogue) DO UPDATE SET ` +
+			`modified = $2, imported = current_timestamp, entries = $3`
+	)
+	return im.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var last time.Time
+			switch err := conn.QueryRow(rctx, lastSQL, c.name).Scan(&last); {
+			case errors.Is(err, pgx.ErrNoRows):
+			case err != nil:
+				return err
+			case last.Equal(modified):
+				return nil
+			}
+			rows, err := parseFile(file, c.parse)
+			if err != nil {
+				return fmt.Errorf("parsing failed: %w", err)
+			}
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			if _, err := tx.Exec(rctx, `DELETE FROM `+c.name); err != nil {
+				return err
+			}
+			n, err := tx.CopyFrom(rctx, pgx.Identifier{c.name}, c.columns, pgx.CopyFromRows(rows))
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, recordSQL, c.name, modified, n); err != nil {
+				return err
+			}
+			if err := tx.Commit(rctx); err != nil {
+				return err
+			}
+			slog.Info("imported enrichment catalogue", "catalogue", c.name, "entries", n)
+			return nil
+		}, 0,
+	)
+}
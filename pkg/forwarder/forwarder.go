@@ -22,6 +22,7 @@ import (
 
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -40,23 +41,82 @@ type forwarder struct {
 	headers     http.Header
 }
 
-func newForwarder(
+// targetSecret returns the name of a secret of a forward target.
+func targetSecret(name, kind string) string {
+	return "forwarder/" + name + "/" + kind
+}
+
+// loadClientCert loads the client certificate of a forward target.
+// If it is not configured it is looked up in the secrets provider.
+func loadClientCert(
+	ctx context.Context,
 	cfg *config.ForwardTarget,
-	externalURL *url.URL,
-	db *database.DB,
-) (*forwarder, error) {
-	// Init http clients
-	var tlsConfig tls.Config
+	provider secrets.Provider,
+) (*tls.Certificate, error) {
 	if cfg.ClientPrivateCert != "" && cfg.ClientPublicCert != "" {
 		clientCert, err := tls.LoadX509KeyPair(
 			cfg.ClientPublicCert,
 			cfg.ClientPrivateCert)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"cannot load client cert for forward target %q: %w",
-				cfg.URL, err)
+			return nil, err
 		}
-		tlsConfig.Certificates = []tls.Certificate{clientCert}
+		return &clientCert, nil
+	}
+	if cfg.Name == "" || provider == nil {
+		return nil, nil
+	}
+	private, err := secrets.LoadOptional(ctx, provider, targetSecret(cfg.Name, "private_cert"))
+	if err != nil {
+		return nil, err
+	}
+	public, err := secrets.LoadOptional(ctx, provider, targetSecret(cfg.Name, "public_cert"))
+	if err != nil {
+		return nil, err
+	}
+	if private == nil || public == nil {
+		return nil, nil
+	}
+	clientCert, err := tls.X509KeyPair(public, private)
+	if err != nil {
+		return nil, err
+	}
+	return &clientCert, nil
+}
+
+// loadHeaders loads the headers of a forward target.
+// If they are not configured they are looked up in the secrets provider.
+func loadHeaders(
+	ctx context.Context,
+	cfg *config.ForwardTarget,
+	provider secrets.Provider,
+) ([]string, error) {
+	if len(cfg.Header) > 0 || cfg.Name == "" || provider == nil {
+		return cfg.Header, nil
+	}
+	data, err := secrets.LoadOptional(ctx, provider, targetSecret(cfg.Name, "headers"))
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n"), nil
+}
+
+func newForwarder(
+	ctx context.Context,
+	cfg *config.ForwardTarget,
+	externalURL *url.URL,
+	db *database.DB,
+	provider secrets.Provider,
+) (*forwarder, error) {
+	// Init http clients
+	var tlsConfig tls.Config
+	clientCert, err := loadClientCert(ctx, cfg, provider)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot load client cert for forward target %q: %w",
+			cfg.URL, err)
+	}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
 	}
 	client := &http.Client{
 		Timeout: cfg.Timeout,
@@ -64,8 +124,14 @@ func newForwarder(
 			TLSClientConfig: &tlsConfig,
 		},
 	}
-	headers := make(http.Header, len(cfg.Header))
-	for _, header := range cfg.Header {
+	lines, err := loadHeaders(ctx, cfg, provider)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot load headers for forward target %q: %w",
+			cfg.URL, err)
+	}
+	headers := make(http.Header, len(lines))
+	for _, header := range lines {
 		if k, v, ok := strings.Cut(header, ":"); ok {
 			headers.Add(k, v)
 			continue
@@ -162,7 +228,8 @@ func (f *forwarder) forwardDocument(ctx context.Context, docID int64) error {
 		` JOIN downloads ON documents.id = downloads.documents_id` +
 		` JOIN advisories ON documents.advisories_id = advisories.id ` +
 		`WHERE` +
-		` documents.id = $1`
+		` documents.id = $1 AND` +
+		` documents.deleted IS NULL`
 	var (
 		doc              []byte
 		filename         *string
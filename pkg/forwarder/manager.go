@@ -26,6 +26,7 @@ import (
 
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
 )
 
 // Manager forwards documents to specified targets.
@@ -77,8 +78,10 @@ func parseTrackingStatus(s *string) trackingStatus {
 
 // NewManager creates a new forward manager.
 func NewManager(
+	ctx context.Context,
 	cfg *config.Config,
 	db *database.DB,
+	provider secrets.Provider,
 ) (*Manager, error) {
 	// TODO: Move this parsing to config.
 	var extURL *url.URL
@@ -93,7 +96,7 @@ func NewManager(
 	forwarders := make([]*forwarder, 0, len(fwdCfg.Targets))
 	for i := range fwdCfg.Targets {
 		tcfg := &fwdCfg.Targets[i]
-		forwarder, err := newForwarder(tcfg, extURL, db)
+		forwarder, err := newForwarder(ctx, tcfg, extURL, db, provider)
 		if err != nil {
 			return nil,
 				fmt.Errorf("create automatic forwarder for %q failed: %w",
@@ -364,7 +367,8 @@ func loadVersionInfos(
 		` initial_release_date ` +
 		`FROM documents ` +
 		`WHERE` +
-		` advisories_id = $1`
+		` advisories_id = $1 AND` +
+		` deleted IS NULL`
 	rows, _ := conn.Query(ctx, versionSQL, advisoryID)
 	vs, err := pgx.CollectRows(
 		rows,
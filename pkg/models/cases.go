@@ -0,0 +1,173 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrPeerForbidden is returned if a change shared in a case
+// is not allowed for one of the other advisories of the case.
+var ErrPeerForbidden = errors.New("change of advisory in case not allowed")
+
+// AdvisoryCVE is a CVE mentioned by the latest document of an advisory.
+type AdvisoryCVE struct {
+	AdvisoryID int64
+	CVE        string
+}
+
+// VulnerabilityCluster are advisories transitively sharing CVEs.
+type VulnerabilityCluster struct {
+	Advisories []int64
+	CVEs       []string
+}
+
+// VulnerabilityCase groups advisories about the same vulnerability.
+type VulnerabilityCase struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Created        time.Time       `json:"created"`
+	SharedState    bool            `json:"shared_state"`
+	SharedSSVC     bool            `json:"shared_ssvc"`
+	SharedComments bool            `json:"shared_comments"`
+	Advisories     []AdvisoryState `json:"advisories"`
+}
+
+// CasePeer is another advisory of a case which follows a shared change.
+type CasePeer struct {
+	AdvisoryID int64
+	DocumentID int64
+	Publisher  string
+	TLP        TLP
+	State      Workflow
+}
+
+// visible returns [ErrPeerForbidden] if one of the peers
+// is not allowed with the TLPs.
+func visible(peers []CasePeer, tlps PublishersTLPs) error {
+	if len(tlps) == 0 {
+		return nil
+	}
+	for i := range peers {
+		if !tlps.Allowed(peers[i].Publisher, peers[i].TLP) {
+			return ErrPeerForbidden
+		}
+	}
+	return nil
+}
+
+// StatePeers returns the peers which follow a change of the workflow
+// state to the given state. Peers already in this state or without a
+// transition to it are skipped. [ErrPeerForbidden] is returned if a
+// peer is not allowed with the TLPs or if none of the roles
+// of the transition of a peer is granted.
+func StatePeers(
+	peers []CasePeer,
+	state Workflow,
+	tlps PublishersTLPs,
+	hasAnyRole func(...WorkflowRole) bool,
+) ([]CasePeer, error) {
+	if err := visible(peers, tlps); err != nil {
+		return nil, err
+	}
+	var following []CasePeer
+	for _, peer := range peers {
+		if peer.State == state {
+			continue
+		}
+		roles := peer.State.TransitionsRoles(state)
+		if len(roles) == 0 {
+			continue
+		}
+		if !hasAnyRole(roles...) {
+			return nil, ErrPeerForbidden
+		}
+		following = append(following, peer)
+	}
+	return following, nil
+}
+
+// SSVCPeers returns the peers which follow a change of the SSVC.
+// [ErrPeerForbidden] is returned if a peer is not allowed with the TLPs.
+func SSVCPeers(peers []CasePeer, tlps PublishersTLPs) ([]CasePeer, error) {
+	if err := visible(peers, tlps); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// ClusterByCVEs groups the advisories into clusters of advisories
+// transitively sharing CVEs. Advisories which do not share a CVE
+// with another advisory are left out. The advisories and the CVEs
+// of a cluster are sorted and the clusters are ordered by their
+// first advisory.
+func ClusterByCVEs(pairs []AdvisoryCVE) []VulnerabilityCluster {
+	parents := map[int64]int64{}
+	var find func(int64) int64
+	find = func(id int64) int64 {
+		parent, ok := parents[id]
+		if !ok {
+			parents[id] = id
+			return id
+		}
+		if parent == id {
+			return id
+		}
+		root := find(parent)
+		parents[id] = root
+		return root
+	}
+	union := func(a, b int64) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parents[max(ra, rb)] = min(ra, rb)
+		}
+	}
+
+	first := map[string]int64{}
+	for _, p := range pairs {
+		if other, ok := first[p.CVE]; ok {
+			union(other, p.AdvisoryID)
+		} else {
+			first[p.CVE] = p.AdvisoryID
+			find(p.AdvisoryID)
+		}
+	}
+
+	byRoot := map[int64]*VulnerabilityCluster{}
+	for id := range parents {
+		root := find(id)
+		cluster := byRoot[root]
+		if cluster == nil {
+			cluster = new(VulnerabilityCluster)
+			byRoot[root] = cluster
+		}
+		cluster.Advisories = append(cluster.Advisories, id)
+	}
+	for cve, id := range first {
+		cluster := byRoot[find(id)]
+		cluster.CVEs = append(cluster.CVEs, cve)
+	}
+
+	clusters := make([]VulnerabilityCluster, 0, len(byRoot))
+	for _, cluster := range byRoot {
+		if len(cluster.Advisories) < 2 {
+			continue
+		}
+		slices.Sort(cluster.Advisories)
+		slices.Sort(cluster.CVEs)
+		clusters = append(clusters, *cluster)
+	}
+	slices.SortFunc(clusters, func(a, b VulnerabilityCluster) int {
+		return cmp.Compare(a.Advisories[0], b.Advisories[0])
+	})
+	return clusters
+}
@@ -0,0 +1,125 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestClusterByCVEs(t *testing.T) {
+	for _, x := range []struct {
+		name     string
+		pairs    []AdvisoryCVE
+		expected []VulnerabilityCluster
+	}{
+		{"empty", nil, []VulnerabilityCluster{}},
+		{
+			"no shared CVEs",
+			[]AdvisoryCVE{{1, "CVE-1"}, {2, "CVE-2"}},
+			[]VulnerabilityCluster{},
+		}, {
+			"shared CVE",
+			[]AdvisoryCVE{{2, "CVE-1"}, {1, "CVE-1"}, {1, "CVE-2"}, {3, "CVE-3"}},
+			[]VulnerabilityCluster{{[]int64{1, 2}, []string{"CVE-1", "CVE-2"}}},
+		}, {
+			"transitive",
+			[]AdvisoryCVE{
+				{5, "CVE-1"}, {4, "CVE-1"}, {4, "CVE-2"}, {3, "CVE-2"},
+				{1, "CVE-9"}, {2, "CVE-9"},
+			},
+			[]VulnerabilityCluster{
+				{[]int64{1, 2}, []string{"CVE-9"}},
+				{[]int64{3, 4, 5}, []string{"CVE-1", "CVE-2"}},
+			},
+		},
+	} {
+		if got := ClusterByCVEs(x.pairs); !reflect.DeepEqual(got, x.expected) {
+			t.Errorf("%s: expected %v got %v", x.name, x.expected, got)
+		}
+	}
+}
+
+func TestStatePeers(t *testing.T) {
+	tlps := PublishersTLPs{"*": {TLPWhite, TLPGreen}}
+	editor := func(roles ...WorkflowRole) bool { return slices.Contains(roles, Editor) }
+	peer := func(id int64, tlp TLP, state Workflow) CasePeer {
+		return CasePeer{AdvisoryID: id, DocumentID: 10 * id, Publisher: "p", TLP: tlp, State: state}
+	}
+	for _, x := range []struct {
+		name     string
+		peers    []CasePeer
+		state    Workflow
+		expected []int64
+		err      error
+	}{
+		{"no peers", nil, ReviewWorkflow, nil, nil},
+		{
+			"follow",
+			[]CasePeer{peer(1, TLPWhite, AssessingWorkflow), peer(2, TLPGreen, AssessingWorkflow)},
+			ReviewWorkflow, []int64{1, 2}, nil,
+		}, {
+			"skip same and without transition",
+			[]CasePeer{
+				peer(1, TLPWhite, ReviewWorkflow),
+				peer(2, TLPWhite, NewWorkflow),
+				peer(3, TLPWhite, AssessingWorkflow),
+			},
+			ReviewWorkflow, []int64{3}, nil,
+		}, {
+			"hidden by TLP",
+			[]CasePeer{peer(1, TLPWhite, AssessingWorkflow), peer(2, TLPRed, AssessingWorkflow)},
+			ReviewWorkflow, nil, ErrPeerForbidden,
+		}, {
+			"role missing",
+			[]CasePeer{peer(1, TLPWhite, AssessingWorkflow), peer(2, TLPWhite, ReviewWorkflow)},
+			ArchivedWorkflow, nil, ErrPeerForbidden,
+		},
+	} {
+		following, err := StatePeers(x.peers, x.state, tlps, editor)
+		if !errors.Is(err, x.err) {
+			t.Errorf("%s: expected error %v got %v", x.name, x.err, err)
+			continue
+		}
+		var ids []int64
+		for _, p := range following {
+			ids = append(ids, p.AdvisoryID)
+		}
+		if !slices.Equal(ids, x.expected) {
+			t.Errorf("%s: expected %v got %v", x.name, x.expected, ids)
+		}
+	}
+}
+
+func TestSSVCPeers(t *testing.T) {
+	peers := []CasePeer{
+		{AdvisoryID: 1, Publisher: "a", TLP: TLPWhite},
+		{AdvisoryID: 2, Publisher: "b", TLP: TLPAmber},
+	}
+	for _, x := range []struct {
+		name string
+		tlps PublishersTLPs
+		err  error
+	}{
+		{"unrestricted", nil, nil},
+		{"allowed", PublishersTLPs{"a": {TLPWhite}, "b": {TLPAmber}}, nil},
+		{"hidden by TLP", PublishersTLPs{"a": {TLPWhite, TLPAmber}}, ErrPeerForbidden},
+	} {
+		following, err := SSVCPeers(peers, x.tlps)
+		if !errors.Is(err, x.err) {
+			t.Errorf("%s: expected error %v got %v", x.name, x.err, err)
+			continue
+		}
+		if err == nil && len(following) != len(peers) {
+			t.Errorf("%s: expected %d peers got %d", x.name, len(peers), len(following))
+		}
+	}
+}
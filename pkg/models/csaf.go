@@ -0,0 +1,83 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"bytes"
+	_ "embed" // Used for embedding.
+	"errors"
+	"sync"
+
+	"github.com/gocsaf/csaf/v3/csaf"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// The supported CSAF versions.
+const (
+	CSAFVersion20 = "2.0"
+	CSAFVersion21 = "2.1"
+)
+
+// csaf21Schema checks the core structure of CSAF 2.1 documents.
+//
+//go:embed csaf_2.1_schema.json
+var csaf21Schema []byte
+
+const csaf21SchemaURL = "https://isduba.github.io/schema/csaf_2.1_core.json"
+
+// compiledCSAF21Schema is the compiled CSAF 2.1 schema.
+var compiledCSAF21Schema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(csaf21Schema))
+	if err != nil {
+		return nil, err
+	}
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	if err := c.AddResource(csaf21SchemaURL, doc); err != nil {
+		return nil, err
+	}
+	return c.Compile(csaf21SchemaURL)
+})
+
+// CSAFVersion returns the CSAF version of a decoded document.
+func CSAFVersion(document any) string {
+	if doc, ok := document.(map[string]any); ok {
+		if meta, ok := doc["document"].(map[string]any); ok {
+			if version, ok := meta["csaf_version"].(string); ok {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// ValidateCSAF validates a decoded document against
+// the JSON schema of its CSAF version.
+// Documents of unknown versions are checked against CSAF 2.0.
+func ValidateCSAF(document any) ([]string, error) {
+	if CSAFVersion(document) != CSAFVersion21 {
+		return csaf.ValidateCSAF(document)
+	}
+	schema, err := compiledCSAF21Schema()
+	if err != nil {
+		return nil, err
+	}
+	err = schema.Validate(document)
+	var valErr *jsonschema.ValidationError
+	if !errors.As(err, &valErr) {
+		return nil, err
+	}
+	var msgs []string
+	for _, e := range valErr.BasicOutput().Errors {
+		if e.Error != nil {
+			msgs = append(msgs, e.InstanceLocation+": "+e.Error.String())
+		}
+	}
+	return msgs, nil
+}
@@ -13,13 +13,21 @@ type Event string
 
 // Documents to export
 const (
-	ImportDocumentEvent Event = "import_document" // ImportDocumentEvent represents a document import.
-	DeleteDocumentEvent Event = "delete_document" // DeleteDocumentEvent represents a document deletion.
-	StateChangeEvent    Event = "state_change"    // StateChangeEvent represents changing the advisory state.
-	AddSSVCEvent        Event = "add_sscv"        // AddSSVCEvent represents the addtion of a SSVC score.
-	ChangeSSVCEvent     Event = "change_sscv"     // ChangeSSVCEvent represents the change of a SSVC score.
-	DeleteSSVCEvent     Event = "delete_sscv"     // DeleteSSVCEvent represents the deletion of a SSVC score.
-	AddCommentEvent     Event = "add_comment"     // AddCommentEvent represents the addition of a comment.
-	ChangeCommentEvent  Event = "change_comment"  // ChangeCommentEvent represents the change of a comment.
-	DeleteCommentEvent  Event = "delete_comment"  // DeleteCommentEvent represents the deletion of a comment.
+	ImportDocumentEvent   Event = "import_document"   // ImportDocumentEvent represents a document import.
+	DeleteDocumentEvent   Event = "delete_document"   // DeleteDocumentEvent represents moving a document to the trash bin.
+	RestoreDocumentEvent  Event = "restore_document"  // RestoreDocumentEvent represents restoring a document from the trash bin.
+	PurgeDocumentEvent    Event = "purge_document"    // PurgeDocumentEvent represents the permanent deletion of a document.
+	StateChangeEvent      Event = "state_change"      // StateChangeEvent represents changing the advisory state.
+	AddSSVCEvent          Event = "add_sscv"          // AddSSVCEvent represents the addtion of a SSVC score.
+	ChangeSSVCEvent       Event = "change_sscv"       // ChangeSSVCEvent represents the change of a SSVC score.
+	DeleteSSVCEvent       Event = "delete_sscv"       // DeleteSSVCEvent represents the deletion of a SSVC score.
+	AddCommentEvent       Event = "add_comment"       // AddCommentEvent represents the addition of a comment.
+	ChangeCommentEvent    Event = "change_comment"    // ChangeCommentEvent represents the change of a comment.
+	DeleteCommentEvent    Event = "delete_comment"    // DeleteCommentEvent represents the deletion of a comment.
+	AddTagEvent           Event = "add_tag"           // AddTagEvent represents labeling an advisory with a tag.
+	RemoveTagEvent        Event = "remove_tag"        // RemoveTagEvent represents removing a tag from an advisory.
+	CreateSourceEvent     Event = "create_source"     // CreateSourceEvent represents provisioning a source from an aggregator.
+	DeactivateSourceEvent Event = "deactivate_source" // DeactivateSourceEvent represents deactivating a provisioned source.
+	ReleaseDocumentEvent  Event = "release_document"  // ReleaseDocumentEvent represents releasing a document from the quarantine.
+	ActivateSourceEvent   Event = "activate_source"   // ActivateSourceEvent represents reactivating a provisioned source.
 )
@@ -20,16 +20,17 @@ import (
 	"sync"
 	"unicode"
 
-	"github.com/gocsaf/csaf/v3/csaf"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
 	// ErrAlreadyInDatabase is returned from ImportDocument if the
 	// advisory is already in the database.
 	ErrAlreadyInDatabase = errors.New("already in database")
+	// ErrInTrash is returned from ImportDocument along with
+	// ErrAlreadyInDatabase if the document is in the trash bin.
+	ErrInTrash = errors.New("in trash bin")
 	// ErrNotAllowed is returned from ImportDocument if the
 	// TLP restrictions are not met.
 	ErrNotAllowed = errors.New("not allowed")
@@ -113,6 +114,21 @@ func keepByValues(values []string) replacer {
 	}
 }
 
+// keepInMetrics keeps the strings in the contents of the CSAF 2.1
+// metrics which are excluded by their keys or values.
+func keepInMetrics(keys, values []string) replacer {
+	return func(ks []string, v string) (any, bool) {
+		if len(ks) <= len(metricsContent) || !slices.Equal(ks[:len(metricsContent)], metricsContent) {
+			return v, false
+		}
+		if _, found := slices.BinarySearch(keys, ks[len(ks)-1]); found {
+			return v, true
+		}
+		_, found := slices.BinarySearch(values, v)
+		return v, found
+	}
+}
+
 func replaceByIndex(index func(string) int) replacer {
 	return func(_ []string, v string) (any, bool) {
 		return index(v), true
@@ -244,6 +260,36 @@ var (
 		"REQUIRED",
 		"CRITICAL",
 	})
+	// metricsContent is the path of the contents of the CSAF 2.1 metrics.
+	metricsContent = []string{"vulnerabilities", "metrics", "content"}
+	// metricsExcludeKeys are the keys of the EPSS and SSVC contents.
+	metricsExcludeKeys = sorted([]string{
+		"probability",
+		"percentile",
+		"timestamp",
+		"schemaVersion",
+		"namespace",
+		"key",
+	})
+	// metricsExcludeValues are the values of the CVSS v4 contents.
+	metricsExcludeValues = sorted([]string{
+		"ACTIVE",
+		"PASSIVE",
+		"PRESENT",
+		"ATTACKED",
+		"POC",
+		"UNREPORTED",
+		"SAFETY",
+		"NEGLIGIBLE",
+		"NO",
+		"YES",
+		"AUTOMATIC",
+		"USER",
+		"IRRECOVERABLE",
+		"DIFFUSE",
+		"CONCENTRATED",
+		"MODERATE",
+	})
 )
 
 // DocumentStoreChainFunc is a function which is called after the document
@@ -280,10 +326,17 @@ func StoreFilename(filename string) DocumentStoreChainFunc {
 	}
 }
 
+// TxBeginner starts the transaction to import a document in.
+// Besides database connections this is implemented by transactions
+// which start a nested transaction based on a savepoint.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 // ImportDocument imports a given advisory into the database.
 func ImportDocument(
 	ctx context.Context,
-	conn *pgxpool.Conn,
+	conn TxBeginner,
 	r io.Reader,
 	actor *string,
 	pstlps PublishersTLPs,
@@ -298,7 +351,7 @@ func ImportDocument(
 		return 0, err
 	}
 
-	msgs, err := csaf.ValidateCSAF(document)
+	msgs, err := ValidateCSAF(document)
 	if err != nil {
 		return 0, fmt.Errorf("schema validation failed: %w", err)
 	}
@@ -311,7 +364,7 @@ func ImportDocument(
 // ImportDocumentData imports a given advisory into the database.
 func ImportDocumentData(
 	ctx context.Context,
-	conn *pgxpool.Conn,
+	conn TxBeginner,
 	document any,
 	raw []byte,
 	actor *string,
@@ -342,6 +395,7 @@ func ImportDocumentData(
 			keepAndIndexSuffix(idxer.index, "vulnerabilities", "cve"),
 			keepByKeys(excludeKeys),
 			keepByValues(excludeValues),
+			keepInMetrics(metricsExcludeKeys, metricsExcludeValues),
 			replaceByIndex(idxer.index),
 		)...))
 
@@ -393,7 +447,14 @@ func ImportDocumentData(
 		queryText            = `SELECT id FROM unique_texts WHERE txt = $1`
 		insertText           = `INSERT INTO unique_texts (txt) VALUES ($1) RETURNING id`
 		insertDocText        = `INSERT INTO documents_texts (documents_id, num, txt_id) VALUES ($1, $2, $3)`
-		loadTexts            = `SELECT u.id, txt FROM documents d JOIN documents_texts t ` +
+		// Same key as the unique constraint of the documents.
+		queryTrashed = `SELECT EXISTS(SELECT 1 FROM documents ` +
+			`WHERE advisories_id = $1 AND deleted IS NOT NULL ` +
+			`AND (version, rev_history_length, tracking_status) IS NOT DISTINCT FROM (` +
+			`$2::jsonb #>> '{document,tracking,version}', ` +
+			`revision_history_length($2::jsonb), ` +
+			`text_to_status($2::jsonb #>> '{document,tracking,status}')))`
+		loadTexts = `SELECT u.id, txt FROM documents d JOIN documents_texts t ` +
 			`ON d.id = t.documents_id JOIN unique_texts u ` +
 			`ON t.txt_id = u.id ` +
 			`WHERE d.advisories_id = $1`
@@ -438,6 +499,11 @@ func ImportDocumentData(
 			if _, err2 := tx.Exec(ctx, rollbackSavepointDoc); err2 != nil {
 				return 0, errors.Join(ErrAlreadyInDatabase, err2)
 			}
+			// Documents in the trash bin have to be restored instead.
+			var trashed bool
+			if err2 := tx.QueryRow(ctx, queryTrashed, advisoryID, document).Scan(&trashed); err2 != nil {
+				return 0, errors.Join(ErrAlreadyInDatabase, err2)
+			}
 			if inTx != nil {
 				if err2 := inTx(ctx, tx, 0, true); err2 != nil {
 					return 0, errors.Join(ErrAlreadyInDatabase, err2)
@@ -446,6 +512,9 @@ func ImportDocumentData(
 					return 0, errors.Join(ErrAlreadyInDatabase, err2)
 				}
 			}
+			if trashed {
+				return 0, errors.Join(ErrAlreadyInDatabase, ErrInTrash)
+			}
 			return 0, ErrAlreadyInDatabase
 		}
 		return 0, fmt.Errorf("inserting document failed: %w", err)
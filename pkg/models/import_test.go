@@ -0,0 +1,56 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKeepInMetrics(t *testing.T) {
+	const doc = `{
+  "document": {
+    "notes": [{"category": "summary", "text": "YES", "title": "namespace"}],
+    "tracking": {"generator": {"engine": {"name": "USER"}}}
+  },
+  "vulnerabilities": [{
+    "notes": [{"category": "details", "text": "MODERATE"}],
+    "metrics": [{
+      "content": {
+        "cvss_v4": {"attackRequirements": "PRESENT", "safety": "NEGLIGIBLE"},
+        "epss": {"probability": "0.4", "timestamp": "2026-01-01T00:00:00Z"},
+        "ssvc_v1": {"selections": [{"namespace": "ssvc", "key": "E", "values": ["active"]}]}
+      }
+    }]
+  }]
+}`
+	var document any
+	if err := json.Unmarshal([]byte(doc), &document); err != nil {
+		t.Fatal(err)
+	}
+	idxer := newIndexer[string]()
+	transformJSON(document, chainReplacers(
+		keepByKeys(excludeKeys),
+		keepByValues(excludeValues),
+		keepInMetrics(metricsExcludeKeys, metricsExcludeValues),
+		replaceByIndex(idxer.index),
+	))
+	// The strings outside the metrics stay searchable.
+	for _, text := range []string{"YES", "namespace", "USER", "MODERATE", "active"} {
+		if _, found := idxer.indexToElements[text]; !found {
+			t.Errorf("%q is not indexed", text)
+		}
+	}
+	// The enumerations in the metrics are kept in place.
+	for _, text := range []string{"PRESENT", "NEGLIGIBLE", "0.4", "ssvc", "E", "2026-01-01T00:00:00Z"} {
+		if _, found := idxer.indexToElements[text]; found {
+			t.Errorf("%q is indexed", text)
+		}
+	}
+}
@@ -3,8 +3,8 @@
 //
 // SPDX-License-Identifier: Apache-2.0
 //
-// SPDX-FileCopyrightText: 2024 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
-// Software-Engineering: 2024 Intevation GmbH <https://intevation.de>
+// SPDX-FileCopyrightText: 2024, 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2024, 2026 Intevation GmbH <https://intevation.de>
 
 package models
 
@@ -13,6 +13,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,14 +24,6 @@ import (
 //go:embed CISA-Coordinator.json
 var cisaCoordinator []byte
 
-type ssvcDecision struct {
-	Exploitation     string `json:"Exploitation"`
-	Automatable      string `json:"Automatable"`
-	TechnicalImpact  string `json:"Technical Impact"`
-	MissionWellBeing string `json:"Mission & Well-being"`
-	Decision         string `json:"Decision"`
-}
-
 type ssvcDecisionPointOptionChildCombination struct {
 	ChildLabel        string   `json:"child_label"`
 	ChildKey          string   `json:"child_key"`
@@ -56,13 +51,18 @@ type ssvcDecisionPoint struct {
 }
 
 type ssvc struct {
+	Title          string              `json:"title"`
+	Version        string              `json:"version"`
 	DecisionPoints []ssvcDecisionPoint `json:"decision_points"`
-	DecisionTable  []ssvcDecision      `json:"decisions_table"`
+	// DecisionTable maps the labels of the decision points
+	// to the labels of the chosen options.
+	DecisionTable []map[string]string `json:"decisions_table"`
 }
 
 // SSVCHistoryEntry represents a singular ssvc change event
 type SSVCHistoryEntry struct {
 	SSVC             *string   `json:"ssvc"`
+	Model            *string   `json:"model,omitempty"`
 	ChangeDate       time.Time `json:"changedate"`
 	ChangeNumber     int64     `json:"change_number"`
 	Actor            *string   `json:"actor,omitempty"`
@@ -78,7 +78,38 @@ type SSVCChange struct {
 
 // SSVCResponse represents a singular SSVC
 type SSVCResponse struct {
-	SSVC *string `json:"ssvc,omitempty"`
+	SSVC  *string `json:"ssvc,omitempty"`
+	Model *string `json:"model,omitempty"`
+}
+
+// DefaultSSVCModel is the name of the embedded CISA Coordinator model.
+const DefaultSSVCModel = "CISA-Coordinator"
+
+// SSVCModel is a SSVC decision model.
+type SSVCModel struct {
+	ssvc
+	name string
+	data []byte
+	// outcome is the decision point decided by the decision table.
+	outcome *ssvcDecisionPoint
+	// columns are the other decision points of the decision table.
+	columns []*ssvcDecisionPoint
+	// parents maps the labels of the children to their complex decision points.
+	parents map[string]*ssvcDecisionPoint
+}
+
+// SSVCModelInfo describes a SSVC decision model.
+type SSVCModelInfo struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version,omitempty"`
+	Default bool   `json:"default"`
+}
+
+// SSVCModels are the SSVC decision models to validate vectors against.
+type SSVCModels struct {
+	models       map[string]*SSVCModel
+	defaultModel string
 }
 
 func (dp *ssvcDecisionPoint) findOption(option string) *ssvcDecisionPointOption {
@@ -91,7 +122,17 @@ func (dp *ssvcDecisionPoint) findOption(option string) *ssvcDecisionPointOption
 	return nil
 }
 
-func (s ssvc) findDecisionPointByKey(key string) *ssvcDecisionPoint {
+func (dp *ssvcDecisionPoint) findOptionByLabel(label string) *ssvcDecisionPointOption {
+	for i := range dp.Options {
+		opt := &dp.Options[i]
+		if opt.Label == label {
+			return opt
+		}
+	}
+	return nil
+}
+
+func (s *ssvc) findDecisionPointByKey(key string) *ssvcDecisionPoint {
 	for i := range s.DecisionPoints {
 		dp := &s.DecisionPoints[i]
 		if dp.Key == key {
@@ -101,93 +142,319 @@ func (s ssvc) findDecisionPointByKey(key string) *ssvcDecisionPoint {
 	return nil
 }
 
-func (s *ssvc) validateVector(vector string) error {
+func (s *ssvc) findDecisionPointByLabel(label string) *ssvcDecisionPoint {
+	for i := range s.DecisionPoints {
+		dp := &s.DecisionPoints[i]
+		if dp.Label == label {
+			return dp
+		}
+	}
+	return nil
+}
+
+// parseSSVCModel parses a SSVC decision model and checks its consistency.
+// The last decision point is the outcome of the decision table.
+func parseSSVCModel(name string, data []byte) (*SSVCModel, error) {
+	m := &SSVCModel{name: name, data: data}
+	if err := json.Unmarshal(data, &m.ssvc); err != nil {
+		return nil, err
+	}
+	if len(m.DecisionPoints) == 0 {
+		return nil, errors.New("model has no decision points")
+	}
+	if len(m.DecisionTable) == 0 {
+		return nil, errors.New("model has no decision table")
+	}
+	labels := map[string]struct{}{}
+	keys := map[string]struct{}{}
+	for i := range m.DecisionPoints {
+		dp := &m.DecisionPoints[i]
+		if dp.Key == "" || strings.ContainsAny(dp.Key, ":/") {
+			return nil, fmt.Errorf("decision point %q has an invalid key %q", dp.Label, dp.Key)
+		}
+		if _, dup := labels[dp.Label]; dup {
+			return nil, fmt.Errorf("decision point label %q is not unique", dp.Label)
+		}
+		if _, dup := keys[dp.Key]; dup {
+			return nil, fmt.Errorf("decision point key %q is not unique", dp.Key)
+		}
+		labels[dp.Label], keys[dp.Key] = struct{}{}, struct{}{}
+	}
+	m.outcome = &m.DecisionPoints[len(m.DecisionPoints)-1]
+	for i := range m.DecisionPoints[:len(m.DecisionPoints)-1] {
+		if dp := &m.DecisionPoints[i]; m.DecisionTable[0][dp.Label] != "" {
+			m.columns = append(m.columns, dp)
+		}
+	}
+	for i, row := range m.DecisionTable {
+		if len(row) != len(m.columns)+1 {
+			return nil, fmt.Errorf("row %d of decision table has unexpected columns", i+1)
+		}
+		for _, dp := range append(m.columns, m.outcome) {
+			if dp.findOptionByLabel(row[dp.Label]) == nil {
+				return nil, fmt.Errorf("row %d of decision table has no valid option for %q", i+1, dp.Label)
+			}
+		}
+	}
+	m.parents = map[string]*ssvcDecisionPoint{}
+	for i := range m.DecisionPoints {
+		dp := &m.DecisionPoints[i]
+		for _, child := range dp.Children {
+			if m.findDecisionPointByLabel(child.Label) == nil {
+				return nil, fmt.Errorf("child %q of %q is not a decision point", child.Label, dp.Label)
+			}
+			if _, dup := m.parents[child.Label]; dup {
+				return nil, fmt.Errorf("decision point %q has more than one parent", child.Label)
+			}
+			m.parents[child.Label] = dp
+		}
+	}
+	return m, nil
+}
+
+// Name returns the name of the model.
+func (m *SSVCModel) Name() string { return m.name }
+
+// Data returns the JSON document of the model.
+func (m *SSVCModel) Data() []byte { return m.data }
+
+// group returns the complex decision point the given one belongs to.
+func (m *SSVCModel) group(dp *ssvcDecisionPoint) *ssvcDecisionPoint {
+	if parent := m.parents[dp.Label]; parent != nil {
+		return parent
+	}
+	if len(dp.Children) > 0 {
+		return dp
+	}
+	return nil
+}
+
+// checkOrder checks that the decision points which are not children
+// appear in the order of the model and that the children of a
+// complex decision point are grouped together with it.
+func (m *SSVCModel) checkOrder(order []*ssvcDecisionPoint) error {
+	last := -1
+	for i, dp := range order {
+		if group := m.group(dp); group != nil && i > 0 && m.group(order[i-1]) != group &&
+			slices.ContainsFunc(order[:i], func(o *ssvcDecisionPoint) bool { return m.group(o) == group }) {
+			return fmt.Errorf("decision points of %q are not grouped together", group.Label)
+		}
+		if m.parents[dp.Label] != nil {
+			continue
+		}
+		idx := slices.IndexFunc(m.DecisionPoints, func(o ssvcDecisionPoint) bool { return o.Key == dp.Key })
+		if idx < last {
+			return fmt.Errorf("invalid order of decision points. %q at point %d", dp.Label, i)
+		}
+		last = idx
+	}
+	return nil
+}
+
+// derive returns the option of a complex decision point matching the
+// chosen options of its children. It returns nil if not all children are chosen.
+func (dp *ssvcDecisionPoint) derive(chosen map[string]*ssvcDecisionPointOption) (*ssvcDecisionPointOption, error) {
+	for _, child := range dp.Children {
+		if chosen[child.Label] == nil {
+			return nil, nil
+		}
+	}
+	matches := func(combination []ssvcDecisionPointOptionChildCombination) bool {
+		for _, c := range combination {
+			if opt := chosen[c.ChildLabel]; opt == nil || !slices.Contains(c.ChildOptionLabels, opt.Label) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range dp.Options {
+		if slices.ContainsFunc(dp.Options[i].ChildCombinations, matches) {
+			return &dp.Options[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no option of %q matches the options of its children", dp.Label)
+}
+
+// Complete validates the given vector against the model. Complex decision
+// points are derived from their children and the decision is computed
+// from the decision table. Given values contradicting them are rejected.
+// It returns the completed vector.
+func (m *SSVCModel) Complete(vector string) (string, error) {
 	parts := strings.Split(vector, "/")
-	// Parts:
-	// 1: SSVCv2
-	// 2: Exploitation
-	// 3: Automatable
-	// 4: Technical Impact
-	// The order between Mission & Well Being and the optional
-	// Decision Points is not set. Having the mandatory in the middle
-	// makes little sense, but wouldn't necessarily be incorrect.
-	// ToDo: Evaluate whether that should be allowed. No for now.
-	// 5/6/7: Mission Prevalence (Optional, must appear alongside Public Well-Being Impact)
-	// 5/6/7: Public Well-Being Impact (Optional, must appear alongside Mission Prevalence)
-	// 5/7: Mission & Well Being (Optional in theory as long as the other optional decions are set. We require it.)
-	// 6/8: Decision
-	// 7/9: Date
-	// 8/10: End after last /
-	if len(parts) < 8 || len(parts) > 10 {
-		return errors.New("vector has invalid length")
+	// Parts: 'SSVCv2', the decisions, the timestamp and the end after the last '/'.
+	if len(parts) < 4 || len(parts) > len(m.DecisionPoints)+3 {
+		return "", errors.New("vector has invalid length")
 	}
 	if parts[0] != "SSVCv2" {
-		return errors.New("vector does not start with 'SSVCv2'")
+		return "", errors.New("vector does not start with 'SSVCv2'")
 	}
 	if parts[len(parts)-1] != "" {
-		return errors.New("vector is not terminated with '/'")
+		return "", errors.New("vector is not terminated with '/'")
 	}
 
 	const timestampFormat = "2006-01-02T15:04:05Z"
 	ts := parts[len(parts)-2]
 	if _, err := time.Parse(timestampFormat, ts); err != nil {
-		return fmt.Errorf("vector timestamp is invalid: %v", err)
+		return "", fmt.Errorf("vector timestamp is invalid: %v", err)
 	}
 	decisions := parts[1 : len(parts)-2]
-	uniqueKeys := map[string]struct{}{}
+	order := make([]*ssvcDecisionPoint, 0, len(m.DecisionPoints))
+	chosen := make(map[string]*ssvcDecisionPointOption, len(m.DecisionPoints))
 
-	for part, decision := range decisions {
+	for _, decision := range decisions {
 		key, option, ok := strings.Cut(decision, ":")
 		if !ok {
-			return fmt.Errorf("decision %q has no ':'", decision)
-		}
-		if _, ok = uniqueKeys[key]; ok {
-			return fmt.Errorf("decision about %q was defined multiple times", key)
+			return "", fmt.Errorf("decision %q has no ':'", decision)
 		}
-		uniqueKeys[key] = struct{}{}
-		dp := s.findDecisionPointByKey(key)
+		dp := m.findDecisionPointByKey(key)
 		if dp == nil {
-			return fmt.Errorf("no decision point with key %q found", key)
+			return "", fmt.Errorf("no decision point with key %q found", key)
 		}
-		if !dp.checkValidOrder(part) {
-			return fmt.Errorf("invalid order of decision points. %q at point %d", dp.Label, part)
+		if chosen[dp.Label] != nil {
+			return "", fmt.Errorf("decision about %q was defined multiple times", key)
 		}
-		if dp.findOption(option) == nil {
-			return fmt.Errorf("decision point %q has no option %q", dp.Key, option)
+		opt := dp.findOption(option)
+		if opt == nil {
+			return "", fmt.Errorf("decision point %q has no option %q", dp.Key, option)
 		}
+		order = append(order, dp)
+		chosen[dp.Label] = opt
 	}
-	return nil
+	if err := m.checkOrder(order); err != nil {
+		return "", err
+	}
+
+	// Derive the complex decision points from their children.
+	for i := range m.DecisionPoints {
+		dp := &m.DecisionPoints[i]
+		if len(dp.Children) == 0 {
+			continue
+		}
+		opt, err := dp.derive(chosen)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case opt == nil:
+		case chosen[dp.Label] == nil:
+			// Insert behind the last child.
+			pos := 0
+			for j, o := range order {
+				if m.parents[o.Label] == dp {
+					pos = j + 1
+				}
+			}
+			order = slices.Insert(order, pos, dp)
+			chosen[dp.Label] = opt
+		case chosen[dp.Label] != opt:
+			return "", fmt.Errorf("option %q of %q contradicts its children", chosen[dp.Label].Key, dp.Label)
+		}
+	}
+
+	// Compute the decision from the decision table.
+	for _, dp := range m.columns {
+		if chosen[dp.Label] == nil {
+			return "", fmt.Errorf("decision point %q is missing", dp.Label)
+		}
+	}
+	idx := slices.IndexFunc(m.DecisionTable, func(row map[string]string) bool {
+		for _, dp := range m.columns {
+			if row[dp.Label] != chosen[dp.Label].Label {
+				return false
+			}
+		}
+		return true
+	})
+	if idx < 0 {
+		return "", errors.New("decision table has no matching decision")
+	}
+	decision := m.outcome.findOptionByLabel(m.DecisionTable[idx][m.outcome.Label])
+	switch have := chosen[m.outcome.Label]; {
+	case have == nil:
+		order = append(order, m.outcome)
+		chosen[m.outcome.Label] = decision
+	case have != decision:
+		return "", fmt.Errorf("decision %q contradicts the decision table: expected %q", have.Key, decision.Key)
+	}
+
+	var b strings.Builder
+	b.WriteString("SSVCv2/")
+	for _, dp := range order {
+		b.WriteString(dp.Key)
+		b.WriteByte(':')
+		b.WriteString(chosen[dp.Label].Key)
+		b.WriteByte('/')
+	}
+	b.WriteString(ts)
+	b.WriteByte('/')
+	return b.String(), nil
+}
+
+// Validate checks if the given vector is valid for the model.
+func (m *SSVCModel) Validate(vector string) error {
+	_, err := m.Complete(vector)
+	return err
 }
 
-func (dp *ssvcDecisionPoint) checkValidOrder(part int) bool {
-	switch dp.Label {
-	case "Exploitation":
-		return part == 0
-	case "Automatable":
-		return part == 1
-	case "Technical Impact":
-		return part == 2
-	case "Mission & Well-being":
-		return part == 3 || part == 5
-	case "Mission Prevalence":
-		return 2 < part && 6 > part
-	case "Public Well-being Impact":
-		return 2 < part && 6 > part
-	case "Decision":
-		return part == 4 || part == 6
-	}
-	return true
-}
-
-var parsedSSVCv2 = sync.OnceValue(func() *ssvc {
-	s := new(ssvc)
-	if err := json.Unmarshal(cisaCoordinator, s); err != nil {
+var parsedSSVCv2 = sync.OnceValue(func() *SSVCModel {
+	m, err := parseSSVCModel(DefaultSSVCModel, cisaCoordinator)
+	if err != nil {
 		panic(fmt.Sprintf("cannot parse 'CISA-coordinator.json': %v", err))
 	}
-	return s
+	return m
 })
 
-// ValidateSSVCv2Vector checks if the given SSVCv2 vector is valid.
+// ValidateSSVCv2Vector checks if the given SSVCv2 vector is valid
+// for the embedded CISA Coordinator model.
 func ValidateSSVCv2Vector(vector string) error {
-	return parsedSSVCv2().validateVector(vector)
+	return parsedSSVCv2().Validate(vector)
+}
+
+// LoadSSVCModels loads the SSVC decision models from the given files.
+// The embedded CISA Coordinator model is always available.
+func LoadSSVCModels(defaultModel string, files map[string]string) (*SSVCModels, error) {
+	sm := &SSVCModels{
+		models:       map[string]*SSVCModel{DefaultSSVCModel: parsedSSVCv2()},
+		defaultModel: defaultModel,
+	}
+	for name, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("loading SSVC model %q failed: %w", name, err)
+		}
+		m, err := parseSSVCModel(name, data)
+		if err != nil {
+			return nil, fmt.Errorf("SSVC model %q in %q is invalid: %w", name, file, err)
+		}
+		sm.models[name] = m
+	}
+	if sm.models[defaultModel] == nil {
+		return nil, fmt.Errorf("default SSVC model %q is not loaded", defaultModel)
+	}
+	return sm, nil
+}
+
+// Model returns the model with the given name.
+// An empty name selects the default model.
+// It returns nil if there is no such model.
+func (sm *SSVCModels) Model(name string) *SSVCModel {
+	if name == "" {
+		name = sm.defaultModel
+	}
+	return sm.models[name]
+}
+
+// Infos returns the descriptions of the models ordered by name.
+func (sm *SSVCModels) Infos() []SSVCModelInfo {
+	infos := make([]SSVCModelInfo, 0, len(sm.models))
+	for _, name := range slices.Sorted(maps.Keys(sm.models)) {
+		m := sm.models[name]
+		infos = append(infos, SSVCModelInfo{
+			Name:    name,
+			Title:   m.Title,
+			Version: m.Version,
+			Default: name == sm.defaultModel,
+		})
+	}
+	return infos
 }
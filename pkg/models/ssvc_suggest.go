@@ -0,0 +1,403 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SSVCRationale explains a suggested decision.
+type SSVCRationale struct {
+	DecisionPoint string `json:"decision_point"`
+	Key           string `json:"key"`
+	Option        string `json:"option"`
+	Reason        string `json:"reason"`
+}
+
+// SSVCSuggestion is a SSVC vector proposed from the data of a document.
+// The decision points which cannot be derived are left out.
+type SSVCSuggestion struct {
+	Model     string          `json:"model"`
+	SSVC      string          `json:"ssvc"`
+	Rationale []SSVCRationale `json:"rationale"`
+	Created   time.Time       `json:"created"`
+}
+
+// KEV is a list of known exploited vulnerabilities.
+type KEV map[string]struct{}
+
+// Contains tells if the given CVE is known to be exploited.
+func (kev KEV) Contains(cve string) bool {
+	_, ok := kev[cve]
+	return ok
+}
+
+// SSVCVulnerability is the part of a CSAF 2.0 or 2.1 vulnerability
+// the SSVC suggestions are derived from.
+type SSVCVulnerability struct {
+	CVE     string           `json:"cve"`
+	Threats []ssvcThreat     `json:"threats"`
+	Scores  []ssvcCVSS       `json:"scores"`
+	Metrics []ssvcCSAFMetric `json:"metrics"`
+}
+
+// ssvcThreat is a threat of a vulnerability.
+type ssvcThreat struct {
+	Category string `json:"category"`
+	Details  string `json:"details"`
+}
+
+// ssvcCVSSVector is a CVSS score of a vulnerability.
+type ssvcCVSSVector struct {
+	VectorString string `json:"vectorString"`
+}
+
+// ssvcCVSS are the CVSS scores of a CSAF 2.0 score or a CSAF 2.1 metric content.
+type ssvcCVSS struct {
+	CVSS2 *ssvcCVSSVector `json:"cvss_v2"`
+	CVSS3 *ssvcCVSSVector `json:"cvss_v3"`
+	CVSS4 *ssvcCVSSVector `json:"cvss_v4"`
+}
+
+// ssvcCSAFMetric is a metric of a CSAF 2.1 vulnerability.
+type ssvcCSAFMetric struct {
+	Content struct {
+		ssvcCVSS
+		SSVC *struct {
+			Selections []SSVCSelection `json:"selections"`
+		} `json:"ssvc_v2"`
+	} `json:"content"`
+}
+
+// SSVCSelection is a decision point stated in the
+// SSVC content of the metrics of a CSAF 2.1 vulnerability.
+type SSVCSelection struct {
+	Key    string               `json:"key"`
+	Name   string               `json:"name"`
+	Values []SSVCSelectionValue `json:"values"`
+}
+
+// SSVCSelectionValue is a selected option of a decision point.
+type SSVCSelectionValue struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// Values given as plain strings are taken as names.
+func (sv *SSVCSelectionValue) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*sv = SSVCSelectionValue{Name: name}
+		return nil
+	}
+	type value SSVCSelectionValue
+	return json.Unmarshal(data, (*value)(sv))
+}
+
+// suggestion is an option suggested for a decision point.
+type suggestion struct {
+	option string
+	reason string
+}
+
+// exploitationRanks orders the options of the exploitation decision point.
+var exploitationRanks = map[string]int{"none": 0, "poc": 1, "active": 2}
+
+// exploitTerms are the word sequences naming an exploitation option.
+var exploitTerms = []struct {
+	option string
+	words  []string
+}{
+	{"active", []string{"active"}},
+	{"active", []string{"actively"}},
+	{"active", []string{"exploited"}},
+	{"active", []string{"exploitation", "detected"}},
+	{"active", []string{"in", "the", "wild"}},
+	{"poc", []string{"poc"}},
+	{"poc", []string{"proof", "of", "concept"}},
+	{"poc", []string{"exploit", "code"}},
+	{"poc", []string{"publicly", "available"}},
+	{"none", []string{"none"}},
+}
+
+// negations are the words negating the statement of a clause.
+// The contractions are split at the apostrophe by the tokenizer.
+var negations = map[string]bool{
+	"no": true, "not": true, "none": true, "never": true, "nor": true,
+	"without": true, "isn": true, "aren": true, "wasn": true, "weren": true,
+	"hasn": true, "haven": true, "hadn": true, "doesn": true, "don": true,
+	"didn": true,
+}
+
+// clauses splits a text into clauses of lower case words.
+// Besides the punctuation "but" and "however" start a new clause.
+func clauses(text string) [][]string {
+	var result [][]string
+	for _, part := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return strings.ContainsRune(".,;!?", r)
+	}) {
+		var clause []string
+		for _, word := range strings.FieldsFunc(part, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if word == "but" || word == "however" {
+				result = append(result, clause)
+				clause = nil
+				continue
+			}
+			clause = append(clause, word)
+		}
+		result = append(result, clause)
+	}
+	return result
+}
+
+// exploitStatus classifies the details of an exploit status threat.
+// A negated clause like "No active exploitation is known" or
+// "Exploited: No" counts as no exploitation.
+func exploitStatus(details string) string {
+	status := ""
+	for _, clause := range clauses(details) {
+		option := ""
+		for i := range clause {
+			for _, term := range exploitTerms {
+				if slices.Equal(clause[i:min(i+len(term.words), len(clause))], term.words) &&
+					(option == "" || exploitationRanks[term.option] > exploitationRanks[option]) {
+					option = term.option
+				}
+			}
+		}
+		if slices.ContainsFunc(clause, func(w string) bool { return negations[w] }) {
+			option = "none"
+		}
+		if option != "" && (status == "" || exploitationRanks[option] > exploitationRanks[status]) {
+			status = option
+		}
+	}
+	return status
+}
+
+// suggestExploitation derives the exploitation from the exploit status
+// threats of the vulnerabilities and the list of known exploited vulnerabilities.
+func suggestExploitation(vulns []SSVCVulnerability, kev KEV) *suggestion {
+	for _, v := range vulns {
+		if v.CVE != "" && kev.Contains(v.CVE) {
+			return &suggestion{"active", fmt.Sprintf("%s is listed in the KEV catalogue", v.CVE)}
+		}
+	}
+	best := &suggestion{"none", "no exploitation is reported in the document or the KEV catalogue"}
+	for _, v := range vulns {
+		for _, t := range v.Threats {
+			if t.Category != "exploit_status" || t.Details == "" {
+				continue
+			}
+			if status := exploitStatus(t.Details); status != "" &&
+				exploitationRanks[status] >= exploitationRanks[best.option] {
+				best = &suggestion{status, fmt.Sprintf("exploit status: %q", t.Details)}
+			}
+		}
+	}
+	return best
+}
+
+// vector returns the vector of the newest CVSS version of the scores.
+func (sc *ssvcCVSS) vector() string {
+	for _, v := range []*ssvcCVSSVector{sc.CVSS4, sc.CVSS3, sc.CVSS2} {
+		if v != nil && v.VectorString != "" {
+			return v.VectorString
+		}
+	}
+	return ""
+}
+
+// cvssVectors returns the CVSS vectors of the vulnerabilities.
+// Newer CVSS versions are preferred over older ones of the same score or metric.
+func cvssVectors(vulns []SSVCVulnerability) []string {
+	var vectors []string
+	for _, v := range vulns {
+		for i := range v.Scores {
+			if vector := v.Scores[i].vector(); vector != "" {
+				vectors = append(vectors, vector)
+			}
+		}
+		for i := range v.Metrics {
+			if vector := v.Metrics[i].Content.vector(); vector != "" {
+				vectors = append(vectors, vector)
+			}
+		}
+	}
+	return vectors
+}
+
+// cvssMetrics splits a CVSS vector into its metrics.
+func cvssMetrics(vector string) map[string]string {
+	metrics := map[string]string{}
+	for part := range strings.SplitSeq(vector, "/") {
+		if metric, value, ok := strings.Cut(part, ":"); ok {
+			metrics[metric] = value
+		}
+	}
+	return metrics
+}
+
+// isCVSS3 tells if the metrics are from a CVSS v3 vector.
+func isCVSS3(metrics map[string]string) bool {
+	return strings.HasPrefix(metrics["CVSS"], "3")
+}
+
+// isCVSS4 tells if the metrics are from a CVSS v4 vector.
+func isCVSS4(metrics map[string]string) bool {
+	return strings.HasPrefix(metrics["CVSS"], "4")
+}
+
+// suggestAutomatable considers a vulnerability automatable if it is
+// reachable over the network without complexity, privileges and user interaction.
+func suggestAutomatable(vectors []string) *suggestion {
+	if len(vectors) == 0 {
+		return nil
+	}
+	for _, vector := range vectors {
+		m := cvssMetrics(vector)
+		var noInteraction bool
+		switch {
+		case isCVSS4(m):
+			noInteraction = m["AT"] == "N" && m["PR"] == "N" && m["UI"] == "N"
+		case isCVSS3(m):
+			noInteraction = m["PR"] == "N" && m["UI"] == "N"
+		default:
+			noInteraction = m["Au"] == "N"
+		}
+		if m["AV"] == "N" && m["AC"] == "L" && noInteraction {
+			return &suggestion{"yes", fmt.Sprintf("CVSS vector %s is remotely exploitable without interaction", vector)}
+		}
+	}
+	return &suggestion{"no", fmt.Sprintf("no CVSS vector of %s is remotely exploitable without interaction",
+		strings.Join(vectors, ", "))}
+}
+
+// suggestTechnicalImpact considers the technical impact total if
+// confidentiality, integrity and availability are fully compromised.
+func suggestTechnicalImpact(vectors []string) *suggestion {
+	if len(vectors) == 0 {
+		return nil
+	}
+	for _, vector := range vectors {
+		m := cvssMetrics(vector)
+		full, c, i, a := "C", m["C"], m["I"], m["A"]
+		switch {
+		case isCVSS4(m):
+			full, c, i, a = "H", m["VC"], m["VI"], m["VA"]
+		case isCVSS3(m):
+			full = "H"
+		}
+		if c == full && i == full && a == full {
+			return &suggestion{"total", fmt.Sprintf("CVSS vector %s has full impact", vector)}
+		}
+	}
+	return &suggestion{"partial", fmt.Sprintf("no CVSS vector of %s has full impact",
+		strings.Join(vectors, ", "))}
+}
+
+// findDecisionPoint returns the decision point of the model
+// with the given name or key.
+func (m *SSVCModel) findDecisionPoint(name, key string) *ssvcDecisionPoint {
+	for i := range m.DecisionPoints {
+		if dp := &m.DecisionPoints[i]; strings.EqualFold(dp.Label, name) {
+			return dp
+		}
+	}
+	for i := range m.DecisionPoints {
+		if dp := &m.DecisionPoints[i]; dp.Key == key {
+			return dp
+		}
+	}
+	return nil
+}
+
+// stated returns the options of the SSVC selections stated
+// in the metrics of the vulnerabilities.
+// The first selection of a decision point wins.
+func (m *SSVCModel) stated(vulns []SSVCVulnerability) map[string]*suggestion {
+	stated := map[string]*suggestion{}
+	for _, v := range vulns {
+		for i := range v.Metrics {
+			ssvc := v.Metrics[i].Content.SSVC
+			if ssvc == nil {
+				continue
+			}
+			for _, sel := range ssvc.Selections {
+				dp := m.findDecisionPoint(sel.Name, sel.Key)
+				if dp == nil || stated[dp.Label] != nil || len(sel.Values) != 1 {
+					continue
+				}
+				value := sel.Values[0]
+				opt := dp.findOption(value.Key)
+				for j := range dp.Options {
+					if strings.EqualFold(dp.Options[j].Label, value.Name) {
+						opt = &dp.Options[j]
+						break
+					}
+				}
+				if opt != nil {
+					stated[dp.Label] = &suggestion{opt.Label, "stated in the SSVC metrics of the document"}
+				}
+			}
+		}
+	}
+	return stated
+}
+
+// Suggest proposes the decision points of the model which can be derived
+// from the vulnerabilities of a document and the known exploited vulnerabilities.
+// SSVC selections stated in the document take precedence over derived ones.
+// If all needed decision points are derived the decision is computed, too.
+func (m *SSVCModel) Suggest(vulns []SSVCVulnerability, kev KEV, now time.Time) *SSVCSuggestion {
+	vectors := cvssVectors(vulns)
+	suggestions := map[string]*suggestion{
+		"Exploitation":     suggestExploitation(vulns, kev),
+		"Automatable":      suggestAutomatable(vectors),
+		"Technical Impact": suggestTechnicalImpact(vectors),
+	}
+	maps.Copy(suggestions, m.stated(vulns))
+	now = now.UTC()
+	s := &SSVCSuggestion{Model: m.name, Created: now, Rationale: []SSVCRationale{}}
+	var b strings.Builder
+	b.WriteString("SSVCv2/")
+	for i := range m.DecisionPoints {
+		dp := &m.DecisionPoints[i]
+		sug := suggestions[dp.Label]
+		if sug == nil {
+			continue
+		}
+		opt := dp.findOptionByLabel(sug.option)
+		if opt == nil {
+			continue
+		}
+		b.WriteString(dp.Key + ":" + opt.Key + "/")
+		s.Rationale = append(s.Rationale, SSVCRationale{
+			DecisionPoint: dp.Label,
+			Key:           dp.Key,
+			Option:        opt.Label,
+			Reason:        sug.reason,
+		})
+	}
+	b.WriteString(now.Format("2006-01-02T15:04:05Z") + "/")
+	s.SSVC = b.String()
+	if complete, err := m.Complete(s.SSVC); err == nil {
+		s.SSVC = complete
+	}
+	return s
+}
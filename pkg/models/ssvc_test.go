@@ -8,7 +8,11 @@
 
 package models
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
 
 func TestSSVC(t *testing.T) {
 	for _, input := range []struct {
@@ -27,6 +31,11 @@ func TestSSVC(t *testing.T) {
 		{"SSVCv2/N/A:N/T:P/M:L/D:T/2024-03-13T10:34:39Z/", false},
 		{"SSVCv2/💩:N/A:N/T:P/M:L/D:T/2024-03-13T10:34:39Z/", false},
 		{"SSVCv2/E:N/A:N/T:P/M:💩/D:T/2024-03-13T10:34:39Z/", false},
+		{"SSVCv2/E:N/A:N/T:P/M:L/2024-03-13T10:34:39Z/", true},
+		{"SSVCv2/E:N/A:N/T:P/M:L/D:C/2024-03-13T10:34:39Z/", false},
+		{"SSVCv2/E:N/A:N/T:P/P:M/M:L/B:M/D:T/2024-03-13T10:33:45Z/", true},
+		{"SSVCv2/E:N/A:N/P:M/T:P/B:M/M:L/D:T/2024-03-13T10:33:45Z/", false},
+		{"SSVCv2/E:N/A:N/T:P/P:E/B:I/M:L/D:T/2024-03-13T10:33:45Z/", false},
 	} {
 		err := ValidateSSVCv2Vector(input.vector)
 		if err != nil && input.pass {
@@ -37,3 +46,101 @@ func TestSSVC(t *testing.T) {
 		}
 	}
 }
+
+func TestSSVCComplete(t *testing.T) {
+	m := parsedSSVCv2()
+	for _, input := range []struct {
+		vector string
+		want   string
+	}{
+		{"SSVCv2/E:N/A:N/T:P/M:L/2024-03-13T10:34:39Z/",
+			"SSVCv2/E:N/A:N/T:P/M:L/D:T/2024-03-13T10:34:39Z/"},
+		{"SSVCv2/E:A/A:Y/T:T/P:E/B:I/2024-03-13T10:34:39Z/",
+			"SSVCv2/E:A/A:Y/T:T/P:E/B:I/M:H/D:C/2024-03-13T10:34:39Z/"},
+	} {
+		have, err := m.Complete(input.vector)
+		if err != nil {
+			t.Errorf("%q failed to complete: %v", input.vector, err)
+		} else if have != input.want {
+			t.Errorf("%q: have %q want %q", input.vector, have, input.want)
+		}
+	}
+}
+
+func TestSSVCSuggest(t *testing.T) {
+	var vulns []SSVCVulnerability
+	if err := json.Unmarshal([]byte(`[{
+		"cve": "CVE-2024-0001",
+		"threats": [{"category": "exploit_status", "details": "Proof of concept is publicly available"}],
+		"scores": [{"cvss_v3": {"version": "3.1",
+			"vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			"baseScore": 9.8, "baseSeverity": "CRITICAL"}, "products": ["p1"]}]
+	}]`), &vulns); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 13, 10, 34, 39, 0, time.UTC)
+	m := parsedSSVCv2()
+	for _, input := range []struct {
+		kev  KEV
+		want string
+	}{
+		{KEV{}, "SSVCv2/E:P/A:Y/T:T/2024-03-13T10:34:39Z/"},
+		{KEV{"CVE-2024-0001": {}}, "SSVCv2/E:A/A:Y/T:T/2024-03-13T10:34:39Z/"},
+	} {
+		s := m.Suggest(vulns, input.kev, now)
+		if s.SSVC != input.want {
+			t.Errorf("have %q want %q", s.SSVC, input.want)
+		}
+		if len(s.Rationale) != 3 {
+			t.Errorf("have %d rationales want 3", len(s.Rationale))
+		}
+	}
+}
+
+func TestSSVCSuggestCSAF21(t *testing.T) {
+	var vulns []SSVCVulnerability
+	if err := json.Unmarshal([]byte(`[{
+		"cve": "CVE-2024-0002",
+		"metrics": [{"content": {
+			"cvss_v4": {"version": "4.0",
+				"vectorString": "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:L/VA:N/SC:N/SI:N/SA:N",
+				"baseScore": 8.8},
+			"ssvc_v2": {"schemaVersion": "2.0.0", "timestamp": "2024-03-13T10:34:39Z",
+				"selections": [{"namespace": "ssvc", "key": "E", "name": "Exploitation",
+					"version": "1.1.0", "values": [{"key": "A", "name": "Active"}]}]}
+		}, "products": ["p1"]}]
+	}]`), &vulns); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 13, 10, 34, 39, 0, time.UTC)
+	s := parsedSSVCv2().Suggest(vulns, KEV{}, now)
+	if want := "SSVCv2/E:A/A:Y/T:P/2024-03-13T10:34:39Z/"; s.SSVC != want {
+		t.Errorf("have %q want %q", s.SSVC, want)
+	}
+}
+
+func TestExploitStatus(t *testing.T) {
+	for _, input := range []struct {
+		details string
+		want    string
+	}{
+		{"No active exploitation is known", "none"},
+		{"No known public exploitation specifically targeting this vulnerability has been reported to CISA.", "none"},
+		{"The Cisco PSIRT is not aware of any public announcements or malicious use of the vulnerability.", "none"},
+		{"Publicly Disclosed:No;Exploited:No;Latest Software Release:Exploitation Less Likely", "none"},
+		{"Exploit code is not publicly available.", "none"},
+		{"None", "none"},
+		{"Publicly Disclosed:No;Exploited:Yes;Latest Software Release:Exploitation Detected", "active"},
+		{"The Cisco PSIRT is aware of attempted exploitation of this vulnerability in the wild.", "active"},
+		{"Not actively exploited, but a proof-of-concept exploit is publicly available.", "poc"},
+		{"There is no PoC, however the vulnerability is actively exploited.", "active"},
+		{"Proof of concept is publicly available", "poc"},
+		// Substrings of other words do not count.
+		{"Exploitation requires a pocket device in close proximity to the victim.", ""},
+		{"An attacker must be authenticated.", ""},
+	} {
+		if have := exploitStatus(input.details); have != input.want {
+			t.Errorf("%q: have %q want %q", input.details, have, input.want)
+		}
+	}
+}
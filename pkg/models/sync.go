@@ -0,0 +1,39 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package models
+
+import "time"
+
+// SyncEvents are the events which are exchanged between peers.
+var SyncEvents = []Event{
+	StateChangeEvent,
+	AddSSVCEvent,
+	ChangeSSVCEvent,
+	AddCommentEvent,
+	ChangeCommentEvent,
+}
+
+// SyncChange is an entry in the changes feed offered to peers.
+// The transaction and the id of the change are the position in the feed.
+type SyncChange struct {
+	ID         int64     `json:"id"`
+	Xact       int64     `json:"xact"`
+	Event      Event     `json:"event"`
+	Time       time.Time `json:"time"`
+	Actor      *string   `json:"actor,omitempty"`
+	Publisher  string    `json:"publisher"`
+	TrackingID string    `json:"tracking_id"`
+	Version    string    `json:"version"`
+	State      *Workflow `json:"state,omitempty"`
+	SSVC       *string   `json:"ssvc,omitempty"`
+	SSVCModel  *string   `json:"ssvc_model,omitempty"`
+	CommentID  *int64    `json:"comment_id,omitempty"`
+	ParentID   *int64    `json:"parent_id,omitempty"`
+	Message    *string   `json:"message,omitempty"`
+}
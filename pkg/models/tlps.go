@@ -11,12 +11,13 @@ package models
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/ISDuBA/ISDuBA/pkg/database/query"
 )
 
 type (
-	// TLP represents a Traffic Light Protocol 1 value.
+	// TLP represents a Traffic Light Protocol 1 or 2 value.
 	TLP string
 	// Publisher represents the publisher name.
 	Publisher string
@@ -30,13 +31,18 @@ const (
 	TLPGreen TLP = "GREEN" // TLPGreen represents TLP:GREEN
 	TLPAmber TLP = "AMBER" // TLPAmber represents TLP:AMBER
 	TLPRed   TLP = "RED"   // TLPRed   represents TLP:RED
+
+	TLPClear       TLP = "CLEAR"        // TLPClear       represents TLP:CLEAR
+	TLPAmberStrict TLP = "AMBER+STRICT" // TLPAmberStrict represents TLP:AMBER+STRICT
 )
 
 // UnmarshalText implements [encoding.TextUnmarshaler].
+// The labels are accepted case-insensitive with an optional "TLP:" prefix.
 func (tlp *TLP) UnmarshalText(text []byte) error {
-	s := TLP(text)
+	s := TLP(strings.ToUpper(strings.TrimSpace(string(text))))
+	s = TLP(strings.TrimPrefix(string(s), "TLP:"))
 	switch s {
-	case TLPWhite, TLPGreen, TLPAmber, TLPRed:
+	case TLPWhite, TLPGreen, TLPAmber, TLPRed, TLPClear, TLPAmberStrict:
 		*tlp = s
 		return nil
 	default:
@@ -44,13 +50,65 @@ func (tlp *TLP) UnmarshalText(text []byte) error {
 	}
 }
 
+// tier returns the TLP 1 label with the same meaning.
+// TLP:CLEAR is TLP:WHITE while TLP:AMBER+STRICT has no TLP 1 counterpart.
+func (tlp TLP) tier() TLP {
+	if tlp == TLPClear {
+		return TLPWhite
+	}
+	return tlp
+}
+
+// Equivalent checks if two labels have the same meaning
+// in the TLP 1 and TLP 2.0 vocabularies.
+func (tlp TLP) Equivalent(other TLP) bool {
+	return tlp.tier() == other.tier()
+}
+
+// equivalents returns the labels of both vocabularies with the same meaning.
+func (tlp TLP) equivalents() []TLP {
+	if tlp.tier() == TLPWhite {
+		return []TLP{TLPWhite, TLPClear}
+	}
+	return []TLP{tlp}
+}
+
+// ContainsTLP checks if a list of TLPs contains a label equivalent to tlp.
+func ContainsTLP(tlps []TLP, tlp TLP) bool {
+	return slices.ContainsFunc(tlps, tlp.Equivalent)
+}
+
+// MergeTLPs appends the TLPs of other not covered by tlps yet.
+func MergeTLPs(tlps, other []TLP) []TLP {
+	for _, tlp := range other {
+		if !ContainsTLP(tlps, tlp) {
+			tlps = append(tlps, tlp)
+		}
+	}
+	return tlps
+}
+
+// expand returns the labels of both vocabularies matching the given TLPs.
+func expand(tlps []TLP) []TLP {
+	var labels []TLP
+	for _, tlp := range tlps {
+		for _, label := range tlp.equivalents() {
+			if !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
 // Allowed checks if a pair of publisher/tlp is allowed.
+// Labels of the TLP 1 and TLP 2.0 vocabularies are matched by their meaning.
 func (ptlps PublishersTLPs) Allowed(publisher string, tlp TLP) bool {
 	if p, ok := ptlps[Publisher(publisher)]; ok {
-		return slices.Contains(p, tlp)
+		return ContainsTLP(p, tlp)
 	}
 	wildcard, ok := ptlps["*"]
-	return ok && slices.Contains(wildcard, tlp)
+	return ok && ContainsTLP(wildcard, tlp)
 }
 
 // or transforms a slice of string kinds into list of or-ed string field accesses.
@@ -72,7 +130,7 @@ func (ptlps PublishersTLPs) AsExpr() *query.Expr {
 }
 
 // AsExprPublisher returns the list of TLP rules as an expression tree with a given
-// publisher field name.
+// publisher field name. The TLPs are matched in both vocabularies.
 func (ptlps PublishersTLPs) AsExprPublisher(publisher string) *query.Expr {
 	// Make build process deterministic.
 	pubs := make([]Publisher, 0, len(ptlps))
@@ -85,7 +143,7 @@ func (ptlps PublishersTLPs) AsExprPublisher(publisher string) *query.Expr {
 
 	var root *query.Expr
 	for _, pub := range pubs {
-		ts := or("tlp", ptlps[pub])
+		ts := or("tlp", expand(ptlps[pub]))
 		if ts == nil {
 			// List is empty.
 			continue
@@ -102,7 +160,7 @@ func (ptlps PublishersTLPs) AsExprPublisher(publisher string) *query.Expr {
 
 	// Do we have a wildcard?
 	if tlps, ok := ptlps["*"]; ok {
-		if ts := or("tlp", tlps); ts != nil {
+		if ts := or("tlp", expand(tlps)); ts != nil {
 			// If we have other publishers,
 			// don't apply wildcard in these cases.
 			if len(pubs) > 0 {
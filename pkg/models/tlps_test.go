@@ -24,32 +24,40 @@ func TestAsConditions(t *testing.T) {
 	}{
 		{
 			`{"*": [ "WHITE", "GREEN" ]}`,
-			`(((((tlp)=($1)))OR(((tlp)=($2)))))`,
-			[]any{"WHITE", "GREEN"},
+			`(((((((tlp)=($1)))OR(((tlp)=($2)))))OR(((tlp)=($3)))))`,
+			[]any{"WHITE", "CLEAR", "GREEN"},
 		}, {
 			`{}`,
 			`(FALSE)`,
 			[]any{},
 		}, {
 			`{"A": [ "WHITE", "GREEN" ]}`,
-			`(((((advisories.publisher)=($1)))AND(((((tlp)=($2)))OR(((tlp)=($3)))))))`,
-			[]any{"A", "WHITE", "GREEN"},
+			`(((((advisories.publisher)=($1)))AND(((((((tlp)=($2)))OR(((tlp)=($3)))))OR(((tlp)=($4)))))))`,
+			[]any{"A", "WHITE", "CLEAR", "GREEN"},
 		}, {
 			`{"A": [ "AMBER", "RED" ], "*": ["WHITE"]}`,
-			`(((((((advisories.publisher)=($1)))AND(((((tlp)=($2)))OR(((tlp)=($3)))))))OR(((((tlp)=($4)))AND((NOT (((advisories.publisher)=($1)))))))))`,
-			[]any{"A", "AMBER", "RED", "WHITE"},
+			`(((((((advisories.publisher)=($1)))AND(((((tlp)=($2)))OR(((tlp)=($3)))))))OR(((((((tlp)=($4)))OR(((tlp)=($5)))))AND((NOT (((advisories.publisher)=($1)))))))))`,
+			[]any{"A", "AMBER", "RED", "WHITE", "CLEAR"},
 		}, {
 			`{"A": [ "AMBER", "RED" ], "*": ["WHITE", "GREEN"]}`,
-			`(((((((advisories.publisher)=($1)))AND(((((tlp)=($2)))OR(((tlp)=($3)))))))OR(((((((tlp)=($4)))OR(((tlp)=($5)))))AND((NOT (((advisories.publisher)=($1)))))))))`,
-			[]any{"A", "AMBER", "RED", "WHITE", "GREEN"},
+			`(((((((advisories.publisher)=($1)))AND(((((tlp)=($2)))OR(((tlp)=($3)))))))OR(((((((((tlp)=($4)))OR(((tlp)=($5)))))OR(((tlp)=($6)))))AND((NOT (((advisories.publisher)=($1)))))))))`,
+			[]any{"A", "AMBER", "RED", "WHITE", "CLEAR", "GREEN"},
 		}, {
 			`{"A": [ "AMBER" ], "B": ["RED"], "*": ["WHITE"]}`,
-			`(((((((((advisories.publisher)=($1)))AND(((tlp)=($2)))))OR(((((advisories.publisher)=($3)))AND(((tlp)=($4)))))))OR(((((tlp)=($5)))AND((NOT (((((advisories.publisher)=($1)))OR(((advisories.publisher)=($3)))))))))))`,
-			[]any{"A", "AMBER", "B", "RED", "WHITE"},
+			`(((((((((advisories.publisher)=($1)))AND(((tlp)=($2)))))OR(((((advisories.publisher)=($3)))AND(((tlp)=($4)))))))OR(((((((tlp)=($5)))OR(((tlp)=($6)))))AND((NOT (((((advisories.publisher)=($1)))OR(((advisories.publisher)=($3)))))))))))`,
+			[]any{"A", "AMBER", "B", "RED", "WHITE", "CLEAR"},
+		}, {
+			`{"*": [ "CLEAR", "AMBER+STRICT" ]}`,
+			`(((((((tlp)=($1)))OR(((tlp)=($2)))))OR(((tlp)=($3)))))`,
+			[]any{"WHITE", "CLEAR", "AMBER+STRICT"},
+		}, {
+			`{"*": [ "WHITE", "CLEAR" ]}`,
+			`(((((tlp)=($1)))OR(((tlp)=($2)))))`,
+			[]any{"WHITE", "CLEAR"},
 		}, {
 			`{"*": ["WHITE"], "A": [ "AMBER" ], "B": ["RED"]}`,
-			`(((((((((advisories.publisher)=($1)))AND(((tlp)=($2)))))OR(((((advisories.publisher)=($3)))AND(((tlp)=($4)))))))OR(((((tlp)=($5)))AND((NOT (((((advisories.publisher)=($1)))OR(((advisories.publisher)=($3)))))))))))`,
-			[]any{"A", "AMBER", "B", "RED", "WHITE"},
+			`(((((((((advisories.publisher)=($1)))AND(((tlp)=($2)))))OR(((((advisories.publisher)=($3)))AND(((tlp)=($4)))))))OR(((((((tlp)=($5)))OR(((tlp)=($6)))))AND((NOT (((((advisories.publisher)=($1)))OR(((advisories.publisher)=($3)))))))))))`,
+			[]any{"A", "AMBER", "B", "RED", "WHITE", "CLEAR"},
 		},
 	} {
 		var ptlps PublishersTLPs
@@ -78,6 +86,10 @@ func TestUnmarshalText(t *testing.T) {
 		{"GREEN", TLPGreen, false},
 		{"AMBER", TLPAmber, false},
 		{"RED", TLPRed, false},
+		{"CLEAR", TLPClear, false},
+		{"AMBER+STRICT", TLPAmberStrict, false},
+		{"TLP:CLEAR", TLPClear, false},
+		{"amber+strict", TLPAmberStrict, false},
 		{"HEARD", "", true},
 	} {
 		var have TLP
@@ -92,3 +104,47 @@ func TestUnmarshalText(t *testing.T) {
 		}
 	}
 }
+
+func TestAllowedMixedVocabularies(t *testing.T) {
+	ptlps := PublishersTLPs{
+		"A": {TLPClear, TLPAmber},
+		"*": {TLPWhite, TLPAmberStrict},
+	}
+	for _, x := range []struct {
+		publisher string
+		tlp       TLP
+		allowed   bool
+	}{
+		{"A", TLPWhite, true},
+		{"A", TLPClear, true},
+		{"A", TLPAmber, true},
+		{"A", TLPAmberStrict, false},
+		{"A", TLPRed, false},
+		{"B", TLPWhite, true},
+		{"B", TLPClear, true},
+		{"B", TLPAmberStrict, true},
+		{"B", TLPAmber, false},
+		{"B", TLPGreen, false},
+	} {
+		if have := ptlps.Allowed(x.publisher, x.tlp); have != x.allowed {
+			t.Errorf("%s/%s: have %t expected %t", x.publisher, x.tlp, have, x.allowed)
+		}
+	}
+}
+
+func TestMergeTLPs(t *testing.T) {
+	for _, x := range []struct {
+		tlps     []TLP
+		other    []TLP
+		expected []TLP
+	}{
+		{nil, []TLP{TLPWhite, TLPGreen}, []TLP{TLPWhite, TLPGreen}},
+		{[]TLP{TLPWhite}, []TLP{TLPClear, TLPGreen}, []TLP{TLPWhite, TLPGreen}},
+		{[]TLP{TLPClear}, []TLP{TLPWhite}, []TLP{TLPClear}},
+		{[]TLP{TLPAmber}, []TLP{TLPAmberStrict}, []TLP{TLPAmber, TLPAmberStrict}},
+	} {
+		if have := MergeTLPs(x.tlps, x.other); !slices.Equal(have, x.expected) {
+			t.Errorf("%q + %q: have %q expected %q", x.tlps, x.other, have, x.expected)
+		}
+	}
+}
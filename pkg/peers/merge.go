@@ -0,0 +1,234 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package peers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// merger merges the changes of a peer into the local database.
+// Conflicts are resolved per field by taking the latest change.
+type merger struct {
+	conn      *pgxpool.Conn
+	ssvc      *models.SSVCModels
+	origin    string
+	peerID    int64
+	lastXact  int64
+	lastEvent int64
+}
+
+// actor returns the actor of a change prefixed with the name of the peer.
+func (m *merger) actor(sc *models.SyncChange) string {
+	if sc.Actor == nil {
+		return m.origin + ":"
+	}
+	return m.origin + ":" + *sc.Actor
+}
+
+// merge applies a change and advances the position in the
+// feed of the peer in one transaction.
+func (m *merger) merge(ctx context.Context, sc *models.SyncChange) error {
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	const findSQL = `SELECT documents.id, advisories.id ` +
+		`FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE advisories.publisher = $1 AND advisories.tracking_id = $2 ` +
+		`AND documents.version = $3 AND documents.deleted IS NULL ` +
+		`ORDER BY documents.rev_history_length DESC LIMIT 1`
+
+	var docID, advID int64
+	switch err := tx.QueryRow(ctx, findSQL, sc.Publisher, sc.TrackingID, sc.Version).Scan(
+		&docID, &advID,
+	); {
+	case errors.Is(err, pgx.ErrNoRows):
+		slog.Debug("sync: document not found",
+			"peer", m.origin,
+			"publisher", sc.Publisher,
+			"tracking_id", sc.TrackingID,
+			"version", sc.Version)
+	case err != nil:
+		return err
+	default:
+		switch sc.Event {
+		case models.StateChangeEvent:
+			err = m.mergeState(ctx, tx, sc, docID, advID)
+		case models.AddSSVCEvent, models.ChangeSSVCEvent:
+			err = m.mergeSSVC(ctx, tx, sc, docID)
+		case models.AddCommentEvent, models.ChangeCommentEvent:
+			err = m.mergeComment(ctx, tx, sc, docID)
+		default:
+			slog.Debug("sync: ignoring event", "peer", m.origin, "event", sc.Event)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	const advanceSQL = `UPDATE sync_peers SET (last_xact, last_event) = ($1, $2) WHERE id = $3`
+	if _, err := tx.Exec(ctx, advanceSQL, sc.Xact, sc.ID, m.peerID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	m.lastXact, m.lastEvent = sc.Xact, sc.ID
+	return nil
+}
+
+// newer checks if the change is newer than the latest local one.
+func newer(ctx context.Context, tx pgx.Tx, sc *models.SyncChange, latestSQL string, args ...any) (bool, error) {
+	var latest *time.Time
+	if err := tx.QueryRow(ctx, latestSQL, args...).Scan(&latest); err != nil {
+		return false, err
+	}
+	return lastWriterWins(sc.Time, latest), nil
+}
+
+// lastWriterWins checks if a change at the given time replaces
+// the latest local change. On a tie the local change is kept.
+func lastWriterWins(change time.Time, latest *time.Time) bool {
+	return latest == nil || change.After(*latest)
+}
+
+func (m *merger) mergeState(
+	ctx context.Context,
+	tx pgx.Tx,
+	sc *models.SyncChange,
+	docID, advID int64,
+) error {
+	// Skip broken changes instead of blocking the feed.
+	if sc.State == nil || !sc.State.Valid() {
+		slog.Warn("sync: invalid state", "peer", m.origin, "id", sc.ID)
+		return nil
+	}
+	const latestSQL = `SELECT max(time) FROM events_log ` +
+		`JOIN documents ON events_log.documents_id = documents.id ` +
+		`WHERE event = 'state_change' AND documents.advisories_id = $1`
+	if ok, err := newer(ctx, tx, sc, latestSQL, advID); err != nil || !ok {
+		return err
+	}
+	const (
+		updateSQL = `UPDATE advisories SET state = $1::workflow WHERE id = $2`
+		eventSQL  = `INSERT INTO events_log (event, state, time, actor, documents_id, origin) ` +
+			`VALUES ('state_change', $1::workflow, $2, $3, $4, $5)`
+	)
+	state := string(*sc.State)
+	if _, err := tx.Exec(ctx, updateSQL, state, advID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, eventSQL, state, sc.Time, m.actor(sc), docID, m.origin)
+	return err
+}
+
+func (m *merger) mergeSSVC(
+	ctx context.Context,
+	tx pgx.Tx,
+	sc *models.SyncChange,
+	docID int64,
+) error {
+	if sc.SSVC == nil {
+		return nil
+	}
+	// Peers of older versions only know the CISA Coordinator model.
+	name := models.DefaultSSVCModel
+	if sc.SSVCModel != nil {
+		name = *sc.SSVCModel
+	}
+	model := m.ssvc.Model(name)
+	if model == nil {
+		slog.Warn("sync: unknown SSVC model", "peer", m.origin, "id", sc.ID, "model", name)
+		return nil
+	}
+	if err := model.Validate(*sc.SSVC); err != nil {
+		slog.Warn("sync: invalid SSVC", "peer", m.origin, "id", sc.ID, "error", err)
+		return nil
+	}
+	const latestSQL = `SELECT max(changedate) FROM ssvc_history WHERE documents_id = $1`
+	if ok, err := newer(ctx, tx, sc, latestSQL, docID); err != nil || !ok {
+		return err
+	}
+	// The event is logged by a trigger.
+	const insertSQL = `INSERT INTO ssvc_history ` +
+		`(actor, changedate, documents_id, ssvc, model, origin) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.Exec(ctx, insertSQL, m.actor(sc), sc.Time, docID, *sc.SSVC, name, m.origin)
+	return err
+}
+
+func (m *merger) mergeComment(
+	ctx context.Context,
+	tx pgx.Tx,
+	sc *models.SyncChange,
+	docID int64,
+) error {
+	// Deleted comments have no message any more.
+	if sc.CommentID == nil || sc.Message == nil {
+		return nil
+	}
+	const (
+		mappedSQL = `SELECT comments_id FROM sync_comments ` +
+			`WHERE sync_peers_id = $1 AND remote_id = $2`
+		latestSQL = `SELECT max(time) FROM events_log ` +
+			`WHERE comments_id = $1 AND event IN ('add_comment', 'change_comment')`
+		// Replies to comments unknown here become top level comments.
+		insertSQL = `INSERT INTO comments (documents_id, time, commentator, message, parent_id) ` +
+			`VALUES ($1, $2, $3, $4, (SELECT comments_id FROM sync_comments ` +
+			`WHERE sync_peers_id = $5 AND remote_id = $6)) RETURNING id`
+		mapSQL = `INSERT INTO sync_comments (sync_peers_id, remote_id, comments_id) ` +
+			`VALUES ($1, $2, $3)`
+		updateSQL = `UPDATE comments SET message = $1 WHERE id = $2`
+		eventSQL  = `INSERT INTO events_log ` +
+			`(event, state, time, actor, documents_id, comments_id, origin) ` +
+			`VALUES ($1::events, ` +
+			`(SELECT state FROM advisories ads JOIN documents docs ` +
+			`ON ads.id = docs.advisories_id WHERE docs.id = $4), ` +
+			`$2, $3, $4, $5, $6)`
+	)
+	var commentID int64
+	event := models.ChangeCommentEvent
+	switch err := tx.QueryRow(ctx, mappedSQL, m.peerID, *sc.CommentID).Scan(&commentID); {
+	case errors.Is(err, pgx.ErrNoRows):
+		// The message is the current one so a change of an
+		// unknown comment can be taken as an addition.
+		if err := tx.QueryRow(
+			ctx, insertSQL, docID, sc.Time, m.actor(sc), *sc.Message, m.peerID, sc.ParentID,
+		).Scan(&commentID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, mapSQL, m.peerID, *sc.CommentID, commentID); err != nil {
+			return err
+		}
+		event = models.AddCommentEvent
+	case err != nil:
+		return err
+	default:
+		ok, err := newer(ctx, tx, sc, latestSQL, commentID)
+		if err != nil || !ok {
+			return err
+		}
+		if _, err := tx.Exec(ctx, updateSQL, *sc.Message, commentID); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(ctx, eventSQL,
+		string(event), sc.Time, m.actor(sc), docID, commentID, m.origin)
+	return err
+}
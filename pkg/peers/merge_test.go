@@ -0,0 +1,34 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package peers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastWriterWins(t *testing.T) {
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, x := range []struct {
+		name     string
+		change   time.Time
+		latest   *time.Time
+		expected bool
+	}{
+		{"no local change", local, nil, true},
+		{"remote newer", local.Add(time.Second), &local, true},
+		{"remote older", local.Add(-time.Second), &local, false},
+		{"tie", local, &local, false},
+		{"other time zone", local.In(time.FixedZone("CET", 3600)).Add(time.Nanosecond), &local, true},
+	} {
+		if got := lastWriterWins(x.change, x.latest); got != x.expected {
+			t.Errorf("%s: expected %t got %t", x.name, x.expected, got)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package peers implements the synchronization of the
+// assessments between isdubad instances.
+package peers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// Syncer pulls the changes feeds of the peers and
+// merges them into the local database.
+type Syncer struct {
+	cfg    *config.Sync
+	db     *database.DB
+	ssvc   *models.SSVCModels
+	client *http.Client
+}
+
+// NewSyncer returns a new syncer.
+func NewSyncer(cfg *config.Sync, db *database.DB, ssvc *models.SSVCModels) *Syncer {
+	return &Syncer{
+		cfg:    cfg,
+		db:     db,
+		ssvc:   ssvc,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Run runs the syncer. To be used in a Go routine.
+func (s *Syncer) Run(ctx context.Context) {
+	var pulled []*config.SyncPeer
+	for i := range s.cfg.Peers {
+		peer := &s.cfg.Peers[i]
+		if peer.URL == "" {
+			continue
+		}
+		if err := s.createPeer(ctx, peer.Name); err != nil {
+			slog.Error("sync", "peer", peer.Name, "error", err)
+			continue
+		}
+		pulled = append(pulled, peer)
+	}
+	// Nothing to pull.
+	if len(pulled) == 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.UpdateInterval)
+	defer ticker.Stop()
+	for {
+		for _, peer := range pulled {
+			if err := s.pull(ctx, peer); err != nil {
+				slog.Error("sync", "peer", peer.Name, "error", err)
+				s.storeError(ctx, peer.Name, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// createPeer ensures the existence of a peer in the peer lookup table.
+func (s *Syncer) createPeer(ctx context.Context, name string) error {
+	const insertPeerSQL = `` +
+		`INSERT INTO sync_peers (name) VALUES ($1) ` +
+		`ON CONFLICT (name) DO NOTHING`
+	if err := s.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			_, err := conn.Exec(rctx, insertPeerSQL, name)
+			return err
+		}, 0,
+	); err != nil {
+		return fmt.Errorf("inserting peer %q failed: %w", name, err)
+	}
+	return nil
+}
+
+// storeError records the last error of a peer.
+func (s *Syncer) storeError(ctx context.Context, name string, err error) {
+	const errorSQL = `UPDATE sync_peers SET last_error = $1 WHERE name = $2`
+	if err := s.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			_, err := conn.Exec(rctx, errorSQL, err.Error(), name)
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("storing sync error failed", "peer", name, "error", err)
+	}
+}
+
+// pull fetches the changes of a peer batch by batch and merges them.
+func (s *Syncer) pull(ctx context.Context, peer *config.SyncPeer) error {
+	const (
+		lastEventSQL = `SELECT id, last_xact, last_event FROM sync_peers WHERE name = $1`
+		syncedSQL    = `UPDATE sync_peers ` +
+			`SET last_sync = current_timestamp, last_error = NULL ` +
+			`WHERE id = $1`
+	)
+	return s.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			m := merger{conn: conn, ssvc: s.ssvc, origin: peer.Name}
+			if err := conn.QueryRow(rctx, lastEventSQL, peer.Name).Scan(
+				&m.peerID, &m.lastXact, &m.lastEvent,
+			); err != nil {
+				return err
+			}
+			if err := pullBatches(
+				func() ([]models.SyncChange, error) {
+					return s.fetch(rctx, peer, m.lastXact, m.lastEvent)
+				},
+				func(sc *models.SyncChange) error { return m.merge(rctx, sc) },
+			); err != nil {
+				return err
+			}
+			_, err := conn.Exec(rctx, syncedSQL, m.peerID)
+			return err
+		}, 0,
+	)
+}
+
+// pullBatches merges the batches of changes until an empty batch is fetched.
+// The size of the batches is not relied on as the peer may limit
+// it to a smaller size than requested.
+func pullBatches(
+	fetch func() ([]models.SyncChange, error),
+	merge func(*models.SyncChange) error,
+) error {
+	for {
+		changes, err := fetch()
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		for i := range changes {
+			if err := merge(&changes[i]); err != nil {
+				return fmt.Errorf("merging change %d failed: %w", changes[i].ID, err)
+			}
+		}
+	}
+}
+
+// fetch loads a batch of changes from a peer
+// following the given position in its feed.
+func (s *Syncer) fetch(
+	ctx context.Context,
+	peer *config.SyncPeer,
+	sinceXact, since int64,
+) ([]models.SyncChange, error) {
+	u, err := url.Parse(peer.URL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("since_xact", strconv.FormatInt(sinceXact, 10))
+	q.Set("since", strconv.FormatInt(since, 10))
+	q.Set("limit", strconv.Itoa(s.cfg.BatchSize))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if peer.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+peer.Token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching changes failed: %s", resp.Status)
+	}
+	var changes []models.SyncChange
+	if err := json.NewDecoder(resp.Body).Decode(&changes); err != nil {
+		return nil, fmt.Errorf("decoding changes failed: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,60 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package peers
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+func TestPullBatches(t *testing.T) {
+	// The peer clamps the batches to two changes.
+	const clamped = 2
+	feed := make([]models.SyncChange, 5)
+	for i := range feed {
+		feed[i].ID = int64(i + 1)
+	}
+	var (
+		position int
+		merged   []int64
+	)
+	fetch := func() ([]models.SyncChange, error) {
+		return feed[position:min(position+clamped, len(feed))], nil
+	}
+	merge := func(sc *models.SyncChange) error {
+		merged = append(merged, sc.ID)
+		position++
+		return nil
+	}
+	if err := pullBatches(fetch, merge); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int64{1, 2, 3, 4, 5}; !slices.Equal(merged, expected) {
+		t.Errorf("expected %v got %v", expected, merged)
+	}
+
+	// A failing merge stops the pull.
+	errMerge := errors.New("merge failed")
+	position, merged = 0, nil
+	failing := func(sc *models.SyncChange) error {
+		if sc.ID == 3 {
+			return errMerge
+		}
+		return merge(sc)
+	}
+	if err := pullBatches(fetch, failing); !errors.Is(err, errMerge) {
+		t.Errorf("expected %v got %v", errMerge, err)
+	}
+	if expected := []int64{1, 2}; !slices.Equal(merged, expected) {
+		t.Errorf("expected %v got %v", expected, merged)
+	}
+}
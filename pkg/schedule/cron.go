@@ -0,0 +1,170 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the supported shorthands of cron expressions.
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// cronField is the set of allowed values of a field as a bit set.
+type cronField uint64
+
+// cron is a classic cron expression with the fields
+// minute, hour, day of month, month and day of week.
+type cron struct {
+	expr                          string
+	minute, hour, dom, month, dow cronField
+	domRestricted, dowRestricted  bool
+}
+
+// cronBounds are the minimal and maximal values of the fields.
+var cronBounds = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are Sunday
+}
+
+func (cf cronField) has(v int) bool { return cf&(1<<v) != 0 }
+
+// parseCronField parses a field of a cron expression. Supported are
+// "*", single values, ranges "a-b", steps "*/n" or "a-b/n" and lists thereof.
+func parseCronField(s string, lo, hi int) (cronField, error) {
+	var field cronField
+	for part := range strings.SplitSeq(s, ",") {
+		rng, stepS, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepS); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepS)
+			}
+		}
+		from, to := lo, hi
+		if rng != "*" {
+			fromS, toS, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(fromS); err != nil {
+				return 0, fmt.Errorf("invalid value %q", fromS)
+			}
+			switch {
+			case isRange:
+				if to, err = strconv.Atoi(toS); err != nil {
+					return 0, fmt.Errorf("invalid value %q", toS)
+				}
+			case !hasStep:
+				to = from
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			field |= 1 << v
+		}
+	}
+	return field, nil
+}
+
+func parseCron(s string) (*cron, error) {
+	expr := s
+	if desc, ok := cronDescriptors[strings.ToLower(s)]; ok {
+		expr = desc
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron expression %q needs 5 fields", s)
+	}
+	var fields [5]cronField
+	for i, part := range parts {
+		var err error
+		if fields[i], err = parseCronField(part, cronBounds[i][0], cronBounds[i][1]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", s, err)
+		}
+	}
+	// Sunday may be given as 7.
+	if fields[4].has(7) {
+		fields[4] |= 1
+	}
+	c := &cron{
+		expr:          s,
+		minute:        fields[0],
+		hour:          fields[1],
+		dom:           fields[2],
+		month:         fields[3],
+		dow:           fields[4],
+		domRestricted: parts[2] != "*",
+		dowRestricted: parts[4] != "*",
+	}
+	if c.Start(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", s)
+	}
+	return c, nil
+}
+
+// String implements [fmt.Stringer].
+func (c *cron) String() string { return c.expr }
+
+// matchesDay tells if the day of t matches. As in classic cron
+// a day matches either field if both are restricted.
+func (c *cron) matchesDay(t time.Time) bool {
+	dom, dow := c.dom.has(t.Day()), c.dow.has(int(t.Weekday()))
+	if c.domRestricted && c.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Start implements [Schedule].
+func (c *cron) Start(t time.Time) time.Time {
+	loc := t.Location()
+	next := t.Truncate(time.Minute)
+	if next.Before(t) {
+		next = next.Add(time.Minute)
+	}
+	// Stop searching for impossible dates like the 31st of February.
+	for limit := next.AddDate(5, 0, 0); next.Before(limit); {
+		switch {
+		case !c.month.has(int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+		case !c.hour.has(next.Hour()):
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+		case !c.minute.has(next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// Next implements [Schedule].
+func (c *cron) Next(t time.Time, _ time.Duration) time.Time {
+	return c.Start(t.Truncate(time.Minute).Add(time.Minute))
+}
+
+// Allows implements [Schedule]. Downloads are not restricted by cron expressions.
+func (c *cron) Allows(time.Time) bool { return true }
@@ -0,0 +1,53 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package schedule implements the schedules of the feed fetching.
+// A schedule is either a cron expression or a list of time windows.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule tells when feeds are fetched.
+type Schedule interface {
+	fmt.Stringer
+	// Start returns the time of the first fetch at or after t.
+	Start(t time.Time) time.Time
+	// Next returns the time of the fetch following a fetch at t.
+	// interval is the regular time between two fetches.
+	Next(t time.Time, interval time.Duration) time.Time
+	// Allows tells if downloads are allowed at t.
+	Allows(t time.Time) bool
+}
+
+// Parse parses a schedule. Time windows look like "22:00-06:00"
+// and may be separated by commas. Everything else is parsed
+// as a cron expression.
+func Parse(s string) (Schedule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty schedule")
+	}
+	if isWindows(s) {
+		return parseWindows(s)
+	}
+	return parseCron(s)
+}
+
+// String returns the textual representation of a schedule
+// which may be nil.
+func String(s Schedule) *string {
+	if s == nil {
+		return nil
+	}
+	str := s.String()
+	return &str
+}
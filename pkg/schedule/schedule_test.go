@@ -0,0 +1,118 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		expr  string
+		valid bool
+	}{
+		{"*/15 * * * *", true},
+		{"0 2 * * 1-5", true},
+		{"30 3 1,15 * *", true},
+		{"0 0 * * 7", true},
+		{"@weekly", true},
+		{"22:00-06:00", true},
+		{"01:00-03:00, 12:00-13:00", true},
+		{"18:00-24:00", true},
+		{"00:00-24:00", true},
+		{"", false},
+		{"* * * *", false},
+		{"60 * * * *", false},
+		{"0 0 31 2 *", false},
+		{"*/0 * * * *", false},
+		{"10:00-10:00", false},
+		{"10:00-25:00", false},
+		{"01:00-03:00, noon", false},
+	} {
+		_, err := Parse(tc.expr)
+		if valid := err == nil; valid != tc.valid {
+			t.Errorf("%q: have valid %t want %t (%v)", tc.expr, valid, tc.valid, err)
+		}
+	}
+}
+
+func TestCron(t *testing.T) {
+	for _, tc := range []struct {
+		expr      string
+		from      string
+		start     string
+		afterNext string
+	}{
+		{"*/15 * * * *", "2026-03-02 10:07", "2026-03-02 10:15", "2026-03-02 10:30"},
+		{"0 2 * * 1-5", "2026-03-06 03:00", "2026-03-09 02:00", "2026-03-10 02:00"},
+		{"0 0 1,15 * 0", "2026-03-02 00:01", "2026-03-08 00:00", "2026-03-15 00:00"},
+		{"@monthly", "2026-12-24 12:00", "2027-01-01 00:00", "2027-02-01 00:00"},
+	} {
+		s, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.expr, err)
+		}
+		start := s.Start(at(tc.from))
+		if want := at(tc.start); !start.Equal(want) {
+			t.Errorf("%q: start have %v want %v", tc.expr, start, want)
+		}
+		if next, want := s.Next(start, time.Hour), at(tc.afterNext); !next.Equal(want) {
+			t.Errorf("%q: next have %v want %v", tc.expr, next, want)
+		}
+		if !s.Allows(at(tc.from)) {
+			t.Errorf("%q: cron expressions should not restrict downloads", tc.expr)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	s, err := Parse("22:00-06:00, 12:00-13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		t      string
+		allows bool
+		start  string
+	}{
+		{"2026-03-02 23:30", true, "2026-03-02 23:30"},
+		{"2026-03-02 05:59", true, "2026-03-02 05:59"},
+		{"2026-03-02 06:00", false, "2026-03-02 12:00"},
+		{"2026-03-02 13:00", false, "2026-03-02 22:00"},
+	} {
+		if allows := s.Allows(at(tc.t)); allows != tc.allows {
+			t.Errorf("%s: have allows %t want %t", tc.t, allows, tc.allows)
+		}
+		if start, want := s.Start(at(tc.t)), at(tc.start); !start.Equal(want) {
+			t.Errorf("%s: have start %v want %v", tc.t, start, want)
+		}
+	}
+	if next, want := s.Next(at("2026-03-02 05:50"), 15*time.Minute), at("2026-03-02 12:00"); !next.Equal(want) {
+		t.Errorf("have next %v want %v", next, want)
+	}
+
+	allDay, err := Parse("00:00-24:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []string{"2026-03-02 00:00", "2026-03-02 12:00", "2026-03-02 23:59"} {
+		if !allDay.Allows(at(tc)) {
+			t.Errorf("%s: all day window does not allow", tc)
+		}
+	}
+}
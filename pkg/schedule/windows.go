@@ -0,0 +1,107 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var windowRe = regexp.MustCompile(`^(\d{1,2}):(\d{2})\s*-\s*(\d{1,2}):(\d{2})$`)
+
+// window is a daily time window in minutes of the day.
+// Windows with from after to span midnight.
+type window struct {
+	from, to int
+}
+
+// windows are daily time windows in which fetching is allowed.
+type windows struct {
+	expr    string
+	windows []window
+}
+
+func isWindows(s string) bool {
+	first, _, _ := strings.Cut(s, ",")
+	return windowRe.MatchString(strings.TrimSpace(first))
+}
+
+func parseWindows(s string) (*windows, error) {
+	ws := &windows{expr: s}
+	for part := range strings.SplitSeq(s, ",") {
+		m := windowRe.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return nil, fmt.Errorf("invalid time window %q", part)
+		}
+		var values [4]int
+		for i := range values {
+			values[i], _ = strconv.Atoi(m[i+1])
+		}
+		// 24:00 is allowed as the end of a day.
+		if values[0] > 23 || values[1] > 59 || values[2] > 24 || values[3] > 59 ||
+			(values[2] == 24 && values[3] != 0) {
+			return nil, fmt.Errorf("invalid time in window %q", part)
+		}
+		w := window{from: values[0]*60 + values[1], to: values[2]*60 + values[3]}
+		// Compare with the unreduced end so 00:00-24:00 covers the whole day.
+		if w.from == w.to {
+			return nil, fmt.Errorf("empty time window %q", part)
+		}
+		ws.windows = append(ws.windows, w)
+	}
+	return ws, nil
+}
+
+// String implements [fmt.Stringer].
+func (ws *windows) String() string { return ws.expr }
+
+// contains tells if a minute of the day is inside the window.
+func (w window) contains(minute int) bool {
+	if w.from < w.to {
+		return w.from <= minute && minute < w.to
+	}
+	return minute >= w.from || minute < w.to
+}
+
+// Allows implements [Schedule].
+func (ws *windows) Allows(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range ws.windows {
+		if w.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start implements [Schedule].
+func (ws *windows) Start(t time.Time) time.Time {
+	if ws.Allows(t) {
+		return t
+	}
+	var next time.Time
+	for _, w := range ws.windows {
+		start := time.Date(t.Year(), t.Month(), t.Day(), w.from/60, w.from%60, 0, 0, t.Location())
+		if !start.After(t) {
+			start = start.AddDate(0, 0, 1)
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// Next implements [Schedule].
+func (ws *windows) Next(t time.Time, interval time.Duration) time.Time {
+	return ws.Start(t.Add(interval))
+}
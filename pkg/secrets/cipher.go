@@ -6,10 +6,11 @@
 // SPDX-FileCopyrightText: 2024 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
 // Software-Engineering: 2024 Intevation GmbH <https://intevation.de>
 
-package sources
+package secrets
 
 import (
 	"bufio"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -31,23 +32,76 @@ Store this under
 in your configuration or in a separate file referencing it with
   [sources]
   aes_key = "@/path/to/file"
+or in the configured external secrets provider with
+  [sources]
+  aes_key = "secret:<name>"
 or the encrypted database fields will not be accessible any more 
 on next boot. See documentation for details.
 ================================================================
 `
 
-func createCipherKey(cfg *config.Config) ([]byte, error) {
+// secretKeyPrefix marks an AES key which is stored in an external provider.
+const secretKeyPrefix = "secret:"
+
+// CipherKey returns the AES key of the configuration. If no key is
+// configured a new one is generated and written to STDOUT.
+// A key in the form "secret:<name>" is loaded from the external
+// provider of the configuration.
+func CipherKey(ctx context.Context, cfg *config.Config) ([]byte, error) {
 	aesKey := cfg.Sources.AESKey
+	if aesKey == "" {
+		// No key given -> Create new one and write to STDOUT.
+		key, err := NewCipherKey()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf(writeKeyMsg, hex.EncodeToString(key))
+		return key, nil
+	}
+	key, err := loadCipherKey(ctx, cfg, aesKey)
+	if err != nil {
+		return nil, fmt.Errorf("sources.aes_key is invalid: %w", err)
+	}
+	return key, nil
+}
+
+// NewCipherKey generates a new random AES key.
+func NewCipherKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("cannot read random key: %w", err)
+	}
+	return key, nil
+}
+
+// loadCipherKey parses the key or loads it from the external provider.
+func loadCipherKey(ctx context.Context, cfg *config.Config, aesKey string) ([]byte, error) {
+	name, ok := strings.CutPrefix(aesKey, secretKeyPrefix)
+	if !ok {
+		return ParseCipherKey(aesKey)
+	}
+	ext, err := external(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if ext == nil {
+		return nil, errors.New("key in secrets provider needs an external provider")
+	}
+	data, err := ext.Load(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("loading key %q failed: %w", name, err)
+	}
+	return ParseCipherKey(strings.TrimSpace(string(data)))
+}
+
+// ParseCipherKey parses an AES key given as a hex string or
+// as a reference to a file containing it in the form "@/path/to/file".
+func ParseCipherKey(aesKey string) ([]byte, error) {
 	var err error
 	var key []byte
 	switch {
 	case aesKey == "":
-		// No key given -> Create new one and write to STDOUT.
-		key = make([]byte, 32)
-		if _, err := rand.Read(key); err != nil {
-			return nil, fmt.Errorf("cannot read random key: %w", err)
-		}
-		fmt.Printf(writeKeyMsg, hex.EncodeToString(key))
+		return nil, errors.New("key is empty")
 	case aesKey[0] == '@':
 		fname := aesKey[1:]
 		if key, err = loadKeyFromFile(fname); err != nil {
@@ -55,7 +109,7 @@ func createCipherKey(cfg *config.Config) ([]byte, error) {
 		}
 	default:
 		if key, err = hex.DecodeString(aesKey); err != nil {
-			return nil, fmt.Errorf("sources.aes_key is invalid: %w", err)
+			return nil, err
 		}
 	}
 	if len(key) < 32 {
@@ -85,20 +139,20 @@ func loadKeyFromFile(fname string) ([]byte, error) {
 	return key, sc.Err()
 }
 
-func (m *Manager) createCipher() (cipher.AEAD, error) {
-	blockCipher, err := aes.NewCipher(m.cipherKey)
+func createCipher(key []byte) (cipher.AEAD, error) {
+	blockCipher, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
 	return cipher.NewGCM(blockCipher)
 }
 
-// encrypt encrypts data with the key of the manager.
-func (m *Manager) encrypt(data []byte) ([]byte, error) {
+// Encrypt encrypts data with the given key.
+func Encrypt(key, data []byte) ([]byte, error) {
 	if data == nil {
 		return nil, nil
 	}
-	cipher, err := m.createCipher()
+	cipher, err := createCipher(key)
 	if err != nil {
 		return nil, err
 	}
@@ -110,15 +164,18 @@ func (m *Manager) encrypt(data []byte) ([]byte, error) {
 	return cipher.Seal(nonce, nonce, data, nil), nil
 }
 
-// decrypt decrypts data with the key of the manager.
-func (m *Manager) decrypt(data []byte) ([]byte, error) {
+// Decrypt decrypts data with the given key.
+func Decrypt(key, data []byte) ([]byte, error) {
 	if data == nil {
 		return nil, nil
 	}
-	cipher, err := m.createCipher()
+	cipher, err := createCipher(key)
 	if err != nil {
 		return nil, err
 	}
+	if len(data) < cipher.NonceSize() {
+		return nil, errors.New("encrypted data too short")
+	}
 	nonce, cipherText := data[:cipher.NonceSize()], data[cipher.NonceSize():]
 	return cipher.Open(nil, nonce, cipherText, nil)
 }
@@ -0,0 +1,179 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+)
+
+// Database stores the secrets AES encrypted in the database.
+type Database struct {
+	db  *database.DB
+	key []byte
+}
+
+// NewDatabase creates a provider which stores the secrets
+// in the database encrypted with the given key.
+func NewDatabase(db *database.DB, key []byte) *Database {
+	return &Database{db: db, key: key}
+}
+
+// Load implements [Provider].
+func (d *Database) Load(ctx context.Context, name string) ([]byte, error) {
+	const loadSQL = `SELECT value FROM secrets WHERE name = $1`
+	var encrypted []byte
+	if err := d.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			return conn.QueryRow(rctx, loadSQL, name).Scan(&encrypted)
+		}, 0,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return Decrypt(d.key, encrypted)
+}
+
+// Store implements [Provider].
+func (d *Database) Store(ctx context.Context, name string, value []byte) error {
+	const (
+		deleteSQL = `DELETE FROM secrets WHERE name = $1`
+		upsertSQL = `INSERT INTO secrets (name, value) VALUES ($1, $2) ` +
+			`ON CONFLICT (name) DO UPDATE SET value = $2, updated = CURRENT_TIMESTAMP`
+	)
+	var encrypted []byte
+	if value != nil {
+		var err error
+		if encrypted, err = Encrypt(d.key, value); err != nil {
+			return err
+		}
+	}
+	return d.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var err error
+			if encrypted == nil {
+				_, err = conn.Exec(rctx, deleteSQL, name)
+			} else {
+				_, err = conn.Exec(rctx, upsertSQL, name, encrypted)
+			}
+			return err
+		}, 0)
+}
+
+// Rekey re-encrypts all secrets in the database with a new key.
+// The secrets are changed in one transaction. Either all
+// or none of them are re-encrypted.
+// Other users of the database still holding the old key have
+// to be stopped before.
+func (d *Database) Rekey(ctx context.Context, key []byte) (int, error) {
+	const (
+		selectSQL = `SELECT name, value FROM secrets ORDER BY name FOR UPDATE`
+		updateSQL = `UPDATE secrets SET value = $2, updated = CURRENT_TIMESTAMP WHERE name = $1`
+	)
+	var n int
+	if err := d.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.Begin(rctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			type secret struct {
+				name  string
+				value []byte
+			}
+			rows, _ := tx.Query(rctx, selectSQL)
+			secrets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (secret, error) {
+				var s secret
+				err := row.Scan(&s.name, &s.value)
+				return s, err
+			})
+			if err != nil {
+				return fmt.Errorf("loading secrets failed: %w", err)
+			}
+			batch := &pgx.Batch{}
+			for _, s := range secrets {
+				data, err := Decrypt(d.key, s.value)
+				if err != nil {
+					return fmt.Errorf("decrypting secret %q failed: %w", s.name, err)
+				}
+				if data, err = Encrypt(key, data); err != nil {
+					return fmt.Errorf("encrypting secret %q failed: %w", s.name, err)
+				}
+				batch.Queue(updateSQL, s.name, data)
+			}
+			if err := tx.SendBatch(rctx, batch).Close(); err != nil {
+				return fmt.Errorf("storing secrets failed: %w", err)
+			}
+			n = len(secrets)
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		return 0, err
+	}
+	d.key = key
+	return n, nil
+}
+
+// moveTo moves all secrets from the database into another provider.
+func (d *Database) moveTo(ctx context.Context, p Provider) error {
+	const namesSQL = `SELECT name FROM secrets ORDER BY name`
+	var names []string
+	if err := d.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, namesSQL)
+			var err error
+			names, err = pgx.CollectRows(rows, pgx.RowTo[string])
+			return err
+		}, 0,
+	); err != nil {
+		return err
+	}
+	for _, name := range names {
+		value, err := d.Load(ctx, name)
+		if err != nil {
+			return fmt.Errorf("loading secret %q failed: %w", name, err)
+		}
+		if err := p.Store(ctx, name, value); err != nil {
+			return fmt.Errorf("storing secret %q failed: %w", name, err)
+		}
+		// Only remove it from the database if it is stored elsewhere.
+		if err := d.Store(ctx, name, nil); err != nil {
+			return fmt.Errorf("removing secret %q failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// countDatabase returns the number of secrets stored in the database.
+func countDatabase(ctx context.Context, db *database.DB) (int64, error) {
+	const countSQL = `SELECT count(*) FROM secrets`
+	var n int64
+	if err := db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			return conn.QueryRow(rctx, countSQL).Scan(&n)
+		}, 0,
+	); err != nil {
+		return 0, fmt.Errorf("counting secrets failed: %w", err)
+	}
+	return n, nil
+}
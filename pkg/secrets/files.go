@@ -0,0 +1,90 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Files stores the secrets as files in a directory.
+// A secret "sources/1/headers" is stored in the file
+// "sources/1/headers" below the directory. This allows
+// to use secrets mounted by an orchestration.
+type Files struct {
+	dir string
+}
+
+// NewFiles creates a provider which stores the secrets
+// as files in the given directory.
+func NewFiles(dir string) *Files {
+	return &Files{dir: dir}
+}
+
+// path returns the file name of a secret.
+func (f *Files) path(name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	return filepath.Join(f.dir, filepath.FromSlash(name)), nil
+}
+
+// Load implements [Provider].
+func (f *Files) Load(_ context.Context, name string) ([]byte, error) {
+	path, err := f.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Store implements [Provider].
+func (f *Files) Store(_ context.Context, name string, value []byte) error {
+	path, err := f.path(name)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file first to not leave a truncated secret behind.
+	tmp, err := os.CreateTemp(dir, ".secret-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
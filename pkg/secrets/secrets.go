@@ -0,0 +1,103 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package secrets implements the storage of the credentials
+// of the sources and the forward targets.
+//
+// The secrets are stored AES encrypted in the database, as files
+// in a directory or in a HashiCorp Vault compatible key value store.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+)
+
+// ErrNotFound is returned if a secret does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider stores secrets by their names. Names are
+// slash separated paths like "sources/1/headers".
+type Provider interface {
+	// Load returns the value of a secret.
+	// It returns ErrNotFound if there is no such secret.
+	Load(ctx context.Context, name string) ([]byte, error)
+	// Store stores the value of a secret.
+	// A nil value deletes the secret.
+	Store(ctx context.Context, name string, value []byte) error
+}
+
+// LoadOptional loads a secret. A missing secret is returned as nil.
+func LoadOptional(ctx context.Context, p Provider, name string) ([]byte, error) {
+	data, err := p.Load(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// external returns the external provider of the configuration.
+// It returns nil if the secrets are stored in the database.
+func external(cfg *config.Config) (Provider, error) {
+	switch sc := &cfg.Secrets; sc.Provider {
+	case config.SecretsDatabase:
+		return nil, nil
+	case config.SecretsFiles:
+		return NewFiles(sc.Directory), nil
+	case config.SecretsVault:
+		return NewVault(&sc.Vault)
+	default:
+		return nil, fmt.Errorf("unsupported secrets provider %q", sc.Provider)
+	}
+}
+
+// New creates the secrets provider of the configuration.
+// If an external provider is configured the secrets which
+// are still stored in the database are moved into it.
+func New(
+	ctx context.Context,
+	cfg *config.Config,
+	db *database.DB,
+) (Provider, error) {
+	ext, err := external(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating secrets provider failed: %w", err)
+	}
+	if ext == nil {
+		key, err := CipherKey(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("creating cipher failed: %w", err)
+		}
+		return NewDatabase(db, key), nil
+	}
+	n, err := countDatabase(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		if cfg.Sources.AESKey == "" {
+			return nil, fmt.Errorf(
+				"moving %d secrets from the database needs sources.aes_key", n)
+		}
+		key, err := loadCipherKey(ctx, cfg, cfg.Sources.AESKey)
+		if err != nil {
+			return nil, fmt.Errorf("sources.aes_key is invalid: %w", err)
+		}
+		if err := NewDatabase(db, key).moveTo(ctx, ext); err != nil {
+			return nil, fmt.Errorf("moving secrets to %s failed: %w", cfg.Secrets.Provider, err)
+		}
+		slog.Info("moved secrets from database",
+			"num", n, "provider", cfg.Secrets.Provider)
+	}
+	return ext, nil
+}
@@ -0,0 +1,146 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+)
+
+// testProvider stores, loads and deletes a secret.
+func testProvider(t *testing.T, p Provider) {
+	t.Helper()
+	ctx := t.Context()
+	const name = "sources/1/headers"
+	if _, err := p.Load(ctx, name); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("have %v want ErrNotFound", err)
+	}
+	value := []byte("x-api-key:secret")
+	if err := p.Store(ctx, name, value); err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.Load(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, value) {
+		t.Errorf("have %q want %q", data, value)
+	}
+	if err := p.Store(ctx, name, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Load(ctx, name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("have %v want ErrNotFound after delete", err)
+	}
+	if err := p.Store(ctx, name, nil); err != nil {
+		t.Errorf("deleting a missing secret failed: %v", err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	f := NewFiles(t.TempDir())
+	testProvider(t, f)
+	if err := f.Store(t.Context(), "../escape", []byte("x")); err == nil {
+		t.Error("storing outside of the directory succeeded")
+	}
+}
+
+// fakeVault is a minimal KV version 2 store.
+func fakeVault(t *testing.T) *httptest.Server {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		entries = map[string]json.RawMessage{}
+	)
+	const (
+		dataPrefix     = "/v1/secret/data/isduba/"
+		metadataPrefix = "/v1/secret/metadata/isduba/"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, dataPrefix):
+			data, ok := entries[strings.TrimPrefix(r.URL.Path, dataPrefix)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"data": data},
+			})
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, dataPrefix):
+			var payload struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			entries[strings.TrimPrefix(r.URL.Path, dataPrefix)] = payload.Data
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, metadataPrefix):
+			delete(entries, strings.TrimPrefix(r.URL.Path, metadataPrefix))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVault(t *testing.T) {
+	server := fakeVault(t)
+	v, err := NewVault(&config.Vault{
+		Address: server.URL,
+		Token:   "token",
+		Mount:   "secret",
+		Path:    "isduba",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProvider(t, v)
+}
+
+func TestEncrypt(t *testing.T) {
+	key, err := NewCipherKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := Encrypt(key, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Decrypt(key, encrypted)
+	if err != nil || string(data) != "passphrase" {
+		t.Errorf("have %q %v want %q", data, err, "passphrase")
+	}
+	other, err := NewCipherKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(other, encrypted); err == nil {
+		t.Error("decrypting with another key succeeded")
+	}
+}
@@ -0,0 +1,162 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+)
+
+// Vault stores the secrets in a HashiCorp Vault compatible
+// key value store (KV version 2). Every secret is an entry
+// below the configured path with the base64 encoded value
+// stored under the key "value".
+type Vault struct {
+	client    *http.Client
+	base      string
+	mount     string
+	path      string
+	token     string
+	namespace string
+}
+
+// vaultData is the payload of the entries.
+type vaultData struct {
+	Value string `json:"value"`
+}
+
+// NewVault creates a provider for a Vault compatible key value store.
+// The token may be given as a reference to a file in the form "@/path/to/file".
+func NewVault(cfg *config.Vault) (*Vault, error) {
+	token := cfg.Token
+	if fname, ok := strings.CutPrefix(token, "@"); ok {
+		data, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, fmt.Errorf("loading vault token failed: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	return &Vault{
+		client:    &http.Client{Timeout: cfg.Timeout},
+		base:      strings.TrimSuffix(cfg.Address, "/"),
+		mount:     strings.Trim(cfg.Mount, "/"),
+		path:      strings.Trim(cfg.Path, "/"),
+		token:     token,
+		namespace: cfg.Namespace,
+	}, nil
+}
+
+// url returns the URL of a secret in the given API section.
+func (v *Vault) url(section, name string) string {
+	parts := []string{v.base, "v1", v.mount, section}
+	if v.path != "" {
+		parts = append(parts, v.path)
+	}
+	for p := range strings.SplitSeq(name, "/") {
+		parts = append(parts, url.PathEscape(p))
+	}
+	return strings.Join(parts, "/")
+}
+
+// do sends a request to the store.
+func (v *Vault) do(ctx context.Context, method, url string, body any) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	if v.token != "" {
+		req.Header.Set("X-Vault-Token", v.token)
+	}
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return v.client.Do(req)
+}
+
+// Load implements [Provider].
+func (v *Vault) Load(ctx context.Context, name string) ([]byte, error) {
+	resp, err := v.do(ctx, http.MethodGet, v.url("data", name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("loading secret %q failed: %s", name, resp.Status)
+	}
+	var document struct {
+		Data struct {
+			Data *vaultData `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid response for secret %q: %w", name, err)
+	}
+	// Deleted versions have no data.
+	if document.Data.Data == nil {
+		return nil, ErrNotFound
+	}
+	return base64.StdEncoding.DecodeString(document.Data.Data.Value)
+}
+
+// Store implements [Provider].
+func (v *Vault) Store(ctx context.Context, name string, value []byte) error {
+	var (
+		resp *http.Response
+		err  error
+	)
+	if value == nil {
+		// Remove all versions of the secret.
+		resp, err = v.do(ctx, http.MethodDelete, v.url("metadata", name), nil)
+	} else {
+		payload := struct {
+			Data vaultData `json:"data"`
+		}{
+			Data: vaultData{Value: base64.StdEncoding.EncodeToString(value)},
+		}
+		resp, err = v.do(ctx, http.MethodPost, v.url("data", name), &payload)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		if value == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("storing secret %q failed: %s", name, resp.Status)
+}
@@ -0,0 +1,331 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AuthMode is the mode a source authenticates with.
+type AuthMode int
+
+const (
+	// AuthNone only uses the headers and the client certificate.
+	AuthNone AuthMode = iota
+	// AuthBearer sends a static bearer token.
+	AuthBearer
+	// AuthOAuth2 fetches short-lived tokens with the OAuth2 client credentials flow.
+	AuthOAuth2
+)
+
+// String implements [fmt.Stringer].
+func (am AuthMode) String() string {
+	switch am {
+	case AuthNone:
+		return "none"
+	case AuthBearer:
+		return "bearer"
+	case AuthOAuth2:
+		return "oauth2"
+	default:
+		return fmt.Sprintf("unknown auth mode %d", am)
+	}
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (am AuthMode) MarshalText() ([]byte, error) {
+	return []byte(am.String()), nil
+}
+
+// ParseAuthMode parses an auth mode.
+func ParseAuthMode(s string) (AuthMode, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return AuthNone, nil
+	case "bearer":
+		return AuthBearer, nil
+	case "oauth2":
+		return AuthOAuth2, nil
+	default:
+		return 0, InvalidArgumentError(fmt.Sprintf("unknown auth mode %q", s))
+	}
+}
+
+// Auth are the authentication settings of a source.
+type Auth struct {
+	Mode         AuthMode
+	TokenURL     *string
+	ClientID     *string
+	Scopes       []string
+	BearerToken  []byte
+	ClientSecret []byte
+}
+
+// ValidTokenURL checks if the given URL is usable as OAuth2 token URL.
+func ValidTokenURL(tokenURL string) error {
+	u, err := url.Parse(tokenURL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "https" && u.Scheme != "http") {
+		return InvalidArgumentError(fmt.Sprintf("invalid token URL %q", tokenURL))
+	}
+	return nil
+}
+
+// validate checks if the settings are complete for the mode.
+func (a *Auth) validate() error {
+	switch a.Mode {
+	case AuthBearer:
+		if len(a.BearerToken) == 0 {
+			return InvalidArgumentError("bearer auth needs a token")
+		}
+	case AuthOAuth2:
+		if a.TokenURL == nil || a.ClientID == nil || len(a.ClientSecret) == 0 {
+			return InvalidArgumentError("OAuth2 needs a token URL, a client id and a client secret")
+		}
+		return ValidTokenURL(*a.TokenURL)
+	}
+	return nil
+}
+
+// TokenState is the state of the OAuth2 access token of a source.
+type TokenState struct {
+	// Fetched is the time the last token was fetched.
+	Fetched *time.Time `json:"fetched,omitempty"`
+	// Expires is the expiry of the current token.
+	Expires *time.Time `json:"expires,omitempty"`
+	// Error is the error of the last failed attempt to fetch a token.
+	Error *string `json:"error,omitempty"`
+}
+
+// tokenSource fetches the OAuth2 access tokens of a source.
+// The tokens are refreshed automatically when they expire.
+type tokenSource struct {
+	mu      sync.Mutex
+	ts      oauth2.TokenSource
+	current *oauth2.Token
+	fetched time.Time
+	err     error
+}
+
+// token returns a valid token and records the state.
+func (ts *tokenSource) token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	tok, err := ts.ts.Token()
+	if err != nil {
+		ts.err = err
+		return nil, fmt.Errorf("fetching OAuth2 token failed: %w", err)
+	}
+	if tok != ts.current {
+		ts.current = tok
+		ts.fetched = time.Now().UTC()
+	}
+	ts.err = nil
+	return tok, nil
+}
+
+// state returns the state of the tokens.
+func (ts *tokenSource) state() *TokenState {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	var st TokenState
+	if ts.current != nil {
+		fetched := ts.fetched
+		st.Fetched = &fetched
+		if !ts.current.Expiry.IsZero() {
+			expires := ts.current.Expiry.UTC()
+			st.Expires = &expires
+		}
+	}
+	if ts.err != nil {
+		msg := ts.err.Error()
+		st.Error = &msg
+	}
+	return &st
+}
+
+// newTokenSource creates the token source of the source. The tokens are
+// fetched with the TLS settings and the client certificate of the source.
+func (s *source) newTokenSource(m *Manager) *tokenSource {
+	var tokenURL, clientID string
+	if s.oauth2TokenURL != nil {
+		tokenURL = *s.oauth2TokenURL
+	}
+	if s.oauth2ClientID != nil {
+		clientID = *s.oauth2ClientID
+	}
+	cfg := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: string(s.oauth2ClientSecret),
+		TokenURL:     tokenURL,
+		Scopes:       slices.Clone(s.oauth2Scopes),
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.httpClient(m))
+	return &tokenSource{ts: cfg.TokenSource(ctx)}
+}
+
+// resetAuth drops the tokens fetched with outdated settings.
+func (s *source) resetAuth() {
+	s.tokens = nil
+}
+
+// authorize adds the credentials of a static bearer token to the request.
+// In OAuth2 mode it returns the token source to fetch the token from.
+// This has to be called in the manager.
+func (s *source) authorize(m *Manager, req *http.Request) *tokenSource {
+	switch s.authMode {
+	case AuthBearer:
+		if len(s.bearerToken) > 0 {
+			req.Header.Set("Authorization", "Bearer "+string(s.bearerToken))
+		}
+	case AuthOAuth2:
+		if s.tokens == nil {
+			s.tokens = s.newTokenSource(m)
+		}
+		return s.tokens
+	}
+	return nil
+}
+
+// tokenState returns the state of the OAuth2 tokens if the source uses them.
+func (s *source) tokenState() *TokenState {
+	if s.authMode != AuthOAuth2 {
+		return nil
+	}
+	if s.tokens == nil {
+		return &TokenState{}
+	}
+	return s.tokens.state()
+}
+
+// pendingAuth returns the auth settings of the source with the
+// requested updates applied.
+func (su *SourceUpdater) pendingAuth() *Auth {
+	if su.auth == nil {
+		s := su.updatable
+		su.auth = &Auth{
+			Mode:         s.authMode,
+			TokenURL:     s.oauth2TokenURL,
+			ClientID:     s.oauth2ClientID,
+			Scopes:       s.oauth2Scopes,
+			BearerToken:  s.bearerToken,
+			ClientSecret: s.oauth2ClientSecret,
+		}
+	}
+	return su.auth
+}
+
+// validateAuth checks if the auth settings are still complete
+// after the requested updates.
+func (su *SourceUpdater) validateAuth() error {
+	if su.auth == nil {
+		return nil
+	}
+	return su.auth.validate()
+}
+
+// UpdateAuthMode requests an update of the auth mode.
+func (su *SourceUpdater) UpdateAuthMode(mode AuthMode) error {
+	if mode == su.updatable.authMode {
+		return nil
+	}
+	su.pendingAuth().Mode = mode
+	su.addChange(func(s *source) {
+		s.authMode = mode
+		s.resetAuth()
+	}, "auth", mode.String())
+	return nil
+}
+
+// UpdateOAuth2TokenURL requests an update of the OAuth2 token URL.
+func (su *SourceUpdater) UpdateOAuth2TokenURL(tokenURL *string) error {
+	if equalPtr(tokenURL, su.updatable.oauth2TokenURL) {
+		return nil
+	}
+	if tokenURL != nil {
+		if err := ValidTokenURL(*tokenURL); err != nil {
+			return err
+		}
+	}
+	su.pendingAuth().TokenURL = tokenURL
+	su.addChange(func(s *source) {
+		s.oauth2TokenURL = tokenURL
+		s.resetAuth()
+	}, "oauth2_token_url", tokenURL)
+	return nil
+}
+
+// UpdateOAuth2ClientID requests an update of the OAuth2 client id.
+func (su *SourceUpdater) UpdateOAuth2ClientID(clientID *string) error {
+	if equalPtr(clientID, su.updatable.oauth2ClientID) {
+		return nil
+	}
+	su.pendingAuth().ClientID = clientID
+	su.addChange(func(s *source) {
+		s.oauth2ClientID = clientID
+		s.resetAuth()
+	}, "oauth2_client_id", clientID)
+	return nil
+}
+
+// UpdateOAuth2Scopes requests an update of the OAuth2 scopes.
+func (su *SourceUpdater) UpdateOAuth2Scopes(scopes []string) error {
+	if slices.Equal(scopes, su.updatable.oauth2Scopes) {
+		return nil
+	}
+	scopes = clone(scopes)
+	su.pendingAuth().Scopes = scopes
+	su.addChange(func(s *source) {
+		s.oauth2Scopes = scopes
+		s.resetAuth()
+	}, "oauth2_scopes", scopes)
+	return nil
+}
+
+// UpdateBearerToken requests an update of the static bearer token.
+func (su *SourceUpdater) UpdateBearerToken(token []byte) error {
+	if slices.Equal(token, su.updatable.bearerToken) {
+		return nil
+	}
+	token = clone(token)
+	su.pendingAuth().BearerToken = token
+	su.addSecret(func(s *source) { s.bearerToken = token }, bearerTokenSecret, token)
+	return nil
+}
+
+// UpdateOAuth2ClientSecret requests an update of the OAuth2 client secret.
+func (su *SourceUpdater) UpdateOAuth2ClientSecret(secret []byte) error {
+	if slices.Equal(secret, su.updatable.oauth2ClientSecret) {
+		return nil
+	}
+	secret = clone(secret)
+	su.pendingAuth().ClientSecret = secret
+	su.addSecret(func(s *source) {
+		s.oauth2ClientSecret = secret
+		s.resetAuth()
+	}, oauth2ClientSecretSecret, secret)
+	return nil
+}
+
+// equalPtr checks if two optional values are equal.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
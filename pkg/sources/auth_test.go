@@ -0,0 +1,83 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateAuthValidation(t *testing.T) {
+	tokenURL, clientID := "https://example.com/token", "isduba"
+	for _, tc := range []struct {
+		name    string
+		src     source
+		updates func(*SourceUpdater) error
+		valid   bool
+	}{{
+		name:    "bearer without token",
+		updates: func(su *SourceUpdater) error { return su.UpdateAuthMode(AuthBearer) },
+	}, {
+		name: "bearer with token",
+		updates: func(su *SourceUpdater) error {
+			return errors.Join(
+				su.UpdateAuthMode(AuthBearer),
+				su.UpdateBearerToken([]byte("secret")))
+		},
+		valid: true,
+	}, {
+		name:    "bearer with stored token",
+		src:     source{bearerToken: []byte("secret")},
+		updates: func(su *SourceUpdater) error { return su.UpdateAuthMode(AuthBearer) },
+		valid:   true,
+	}, {
+		name: "oauth2 without secret",
+		updates: func(su *SourceUpdater) error {
+			return errors.Join(
+				su.UpdateAuthMode(AuthOAuth2),
+				su.UpdateOAuth2TokenURL(&tokenURL),
+				su.UpdateOAuth2ClientID(&clientID))
+		},
+	}, {
+		name: "oauth2 complete",
+		src:  source{oauth2ClientSecret: []byte("secret")},
+		updates: func(su *SourceUpdater) error {
+			return errors.Join(
+				su.UpdateAuthMode(AuthOAuth2),
+				su.UpdateOAuth2TokenURL(&tokenURL),
+				su.UpdateOAuth2ClientID(&clientID))
+		},
+		valid: true,
+	}, {
+		name: "removing the token of bearer",
+		src:  source{authMode: AuthBearer, bearerToken: []byte("secret")},
+		updates: func(su *SourceUpdater) error {
+			return su.UpdateBearerToken(nil)
+		},
+	}, {
+		name:    "no auth changes",
+		src:     source{authMode: AuthBearer},
+		updates: func(su *SourceUpdater) error { return su.UpdateName("renamed") },
+		valid:   true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			su := SourceUpdater{updater: updater[*source]{updatable: &tc.src, manager: &Manager{}}}
+			if err := tc.updates(&su); err != nil {
+				t.Fatal(err)
+			}
+			err := su.validateAuth()
+			if tc.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tc.valid && !errors.Is(err, InvalidArgumentError("")) {
+				t.Errorf("have %v want invalid argument error", err)
+			}
+		})
+	}
+}
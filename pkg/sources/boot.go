@@ -24,10 +24,11 @@ func (m *Manager) Boot(ctx context.Context) error {
 	const (
 		sourcesSQL = `SELECT id, name, url, rate, slots, active, headers, ` +
 			`strict_mode, secure, signature_check, age, ignore_patterns, ` +
-			`client_cert_public, client_cert_private, client_cert_passphrase, ` +
-			`checksum, checksum_ack, checksum_updated ` +
+			`client_cert_public, ` +
+			`checksum, checksum_ack, checksum_updated, schedule, ` +
+			`auth::text, oauth2_token_url, oauth2_client_id, oauth2_scopes, proxy ` +
 			`FROM sources ORDER BY id`
-		feedsSQL = `SELECT id, label, sources_id, url, rolie, log_lvl::text FROM feeds`
+		feedsSQL = `SELECT id, label, sources_id, url, rolie, log_lvl::text, schedule FROM feeds`
 	)
 	if err := m.db.Run(
 		ctx,
@@ -42,18 +43,22 @@ func (m *Manager) Boot(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("querying sources failed: %w", err)
 			}
-			var bads []int64
+			// legacyHeaders are the headers still stored in the sources table.
+			legacyHeaders := map[*source][]string{}
 			m.sources, err = pgx.CollectRows(srows, func(row pgx.CollectableRow) (*source, error) {
 				var (
-					s                                       source
-					patterns                                []string
-					clientCertPrivate, clientCertPassphrase []byte
+					s        source
+					patterns []string
+					headers  []string
+					sched    *string
+					auth     string
 				)
 				if err := row.Scan(
-					&s.id, &s.name, &s.url, &s.rate, &s.slots, &s.active, &s.headers,
+					&s.id, &s.name, &s.url, &s.rate, &s.slots, &s.active, &headers,
 					&s.strictMode, &s.secure, &s.signatureCheck, &s.age, &patterns,
-					&s.clientCertPublic, &clientCertPrivate, &clientCertPassphrase,
-					&s.checksum, &s.checksumAck, &s.checksumUpdated,
+					&s.clientCertPublic,
+					&s.checksum, &s.checksumAck, &s.checksumUpdated, &sched,
+					&auth, &s.oauth2TokenURL, &s.oauth2ClientID, &s.oauth2Scopes, &s.proxy,
 				); err != nil {
 					return nil, err
 				}
@@ -62,29 +67,56 @@ func (m *Manager) Boot(ctx context.Context) error {
 					return nil, err
 				}
 				s.ignorePatterns = regexps
-
-				var bad bool
-				if s.clientCertPrivate, err = m.decrypt(clientCertPrivate); err != nil {
-					bad = true
+				if s.schedule, err = AsSchedule(sched); err != nil {
+					return nil, err
 				}
-				if s.clientCertPassphrase, err = m.decrypt(clientCertPassphrase); err != nil {
-					bad = true
+				if s.authMode, err = ParseAuthMode(auth); err != nil {
+					return nil, err
 				}
-				if !bad {
-					if err := s.updateCertificate(); err != nil {
-						bad = true
-					}
-				}
-				if bad && s.active {
-					s.status = []string{deactivatedDueToClientCertIssue}
-					s.active = false
-					bads = append(bads, s.id)
+				switch {
+				case m.plainHeaders():
+					s.headers = headers
+				case headers != nil:
+					legacyHeaders[&s] = headers
 				}
 				return &s, nil
 			})
 			if err != nil {
 				return fmt.Errorf("collecting sources failed: %w", err)
 			}
+			var bads []int64
+			for _, s := range m.sources {
+				var status string
+				if err := m.loadSecrets(rctx, s); err != nil {
+					slog.Error("loading secrets of source failed", "source", s.name, "err", err)
+					status = deactivatedDueToSecretsIssue
+				} else if err := s.updateCertificate(); err != nil {
+					status = deactivatedDueToClientCertIssue
+				}
+				if status != "" && s.active {
+					s.status = []string{status}
+					s.active = false
+					bads = append(bads, s.id)
+				}
+			}
+			// Move the headers of older versions into the secrets provider.
+			// Without a persistent key they are kept in the sources table.
+			if len(legacyHeaders) > 0 {
+				const clearHeadersSQL = `UPDATE sources SET headers = NULL WHERE id = $1`
+				batch := &pgx.Batch{}
+				for s, headers := range legacyHeaders {
+					if err := m.secrets.Store(
+						rctx, sourceSecret(s.id, headersSecret), encodeHeaders(headers),
+					); err != nil {
+						return fmt.Errorf("moving headers of source %q failed: %w", s.name, err)
+					}
+					s.headers = headers
+					batch.Queue(clearHeadersSQL, s.id)
+				}
+				if err := tx.SendBatch(rctx, batch).Close(); err != nil {
+					return fmt.Errorf("clearing headers failed: %w", err)
+				}
+			}
 			// If we have sources with bad crypto deactivate these.
 			if len(bads) > 0 {
 				const deactivateSQL = `UPDATE sources SET active = FALSE WHERE id = $1`
@@ -109,6 +141,7 @@ func (m *Manager) Boot(ctx context.Context) error {
 					sid      int64
 					raw      string
 					logLevel config.FeedLogLevel
+					sched    *string
 				)
 				if err := frows.Scan(
 					&f.id,
@@ -117,9 +150,13 @@ func (m *Manager) Boot(ctx context.Context) error {
 					&raw,
 					&f.rolie,
 					&logLevel,
+					&sched,
 				); err != nil {
 					return err
 				}
+				if f.schedule, err = AsSchedule(sched); err != nil {
+					return err
+				}
 				parsed, err := url.Parse(raw)
 				if err != nil {
 					return fmt.Errorf("invalid URL: %w", err)
@@ -138,12 +175,23 @@ func (m *Manager) Boot(ctx context.Context) error {
 			if err := frows.Err(); err != nil {
 				return fmt.Errorf("collecting feeds failed: %w", err)
 			}
+			if err := m.loadKeys(rctx, tx); err != nil {
+				return err
+			}
+			// Resume the downloads pending at shutdown.
+			if err := m.loadQueues(rctx, tx); err != nil {
+				return err
+			}
 			return tx.Commit(rctx)
 		}, 0,
 	); err != nil {
 		return err
 	}
 
+	for _, s := range m.sources {
+		m.registerProxy(s)
+	}
+
 	activeFeeds := m.numActiveFeeds()
 
 	slog.Info("number of sources", "num", len(m.sources))
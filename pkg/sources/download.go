@@ -27,7 +27,6 @@ import (
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/models"
 	"github.com/ProtonMail/gopenpgp/v2/crypto"
-	"github.com/gocsaf/csaf/v3/csaf"
 	"github.com/gocsaf/csaf/v3/util"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -78,8 +77,9 @@ func (i *inserter) sql(table string) string {
 }
 
 // download fetches the files of a document and stores
-// them into the database.
-func (l *location) download(m *Manager, f *feed) {
+// them into the database. An error is returned if the
+// download should be tried again.
+func (l *location) download(m *Manager, f *feed) error {
 
 	var (
 		strictMode     bool                     // All checks have to be fulfilled.
@@ -89,6 +89,7 @@ func (l *location) download(m *Manager, f *feed) {
 		checks         []func(*dlStatus, *feed) // List of checks to pass.
 		data           bytes.Buffer             // The raw data will be stored in the database.
 		signatureData  []byte                   // The signature will be stored in the database.
+		signedBy       string                   // Fingerprint of the key which made the signature.
 		client         *http.Client
 	)
 
@@ -180,13 +181,13 @@ func (l *location) download(m *Manager, f *feed) {
 	resp, err := f.source.httpGet(client, m, l.doc.String())
 	if err != nil {
 		f.log(m, config.ErrorFeedLogLevel, "downloading %q failed: %v", l.doc, err)
-		return
+		return err
 	}
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
 		f.log(m, config.ErrorFeedLogLevel, "downloading %q failed: %s (%d)",
 			l.doc, http.StatusText(resp.StatusCode), resp.StatusCode)
-		return
+		return fmt.Errorf("status: %s (%d)", http.StatusText(resp.StatusCode), resp.StatusCode)
 	}
 
 	// Decode document into JSON.
@@ -200,7 +201,7 @@ func (l *location) download(m *Manager, f *feed) {
 	}(); err != nil {
 		// If it is not JSON there is no way to carry on.
 		f.log(m, config.ErrorFeedLogLevel, "decoding document %q failed: %v", l.doc, err)
-		return
+		return fmt.Errorf("decoding failed: %w", err)
 	}
 
 	// Check if the tracking id matches the filename.
@@ -214,7 +215,7 @@ func (l *location) download(m *Manager, f *feed) {
 
 	// Check document against schema.
 	checks = append(checks, func(ds *dlStatus, f *feed) {
-		if errors, err := csaf.ValidateCSAF(doc); err != nil || len(errors) > 0 {
+		if errors, err := models.ValidateCSAF(doc); err != nil || len(errors) > 0 {
 			ds.set(schemaValidationFailed)
 			if err != nil {
 				f.log(m, config.ErrorFeedLogLevel,
@@ -249,8 +250,20 @@ func (l *location) download(m *Manager, f *feed) {
 	keys, err := m.openPGPKeys(f.source)
 	if err != nil {
 		f.log(m, config.ErrorFeedLogLevel, "Loading OpenPGP keys failed: %v", err)
-	} else if keys.CountEntities() > 0 {
-		// Only check signature if we have something in the key ring.
+	}
+	switch {
+	case keys == nil:
+		// Only check signature if the source has keys.
+	case keys.CountEntities() == 0:
+		// Pinning keys must not switch the checks off.
+		checks = append(checks, func(ds *dlStatus, f *feed) {
+			if signatureCheck {
+				ds.set(signatureFailed)
+				f.log(m, config.ErrorFeedLogLevel,
+					"Verifying OpenPGP signature of %q failed: no trusted key", l.doc)
+			}
+		})
+	default:
 		checks = append(checks, func(ds *dlStatus, f *feed) {
 			var sign *url.URL
 			switch {
@@ -279,6 +292,8 @@ func (l *location) download(m *Manager, f *feed) {
 						f.log(m, config.ErrorFeedLogLevel,
 							"Verifying OpenPGP signature of %q failed: %v", l.doc, err)
 					}
+				} else {
+					signedBy = signingKey(keys, pm, signature)
 				}
 			}
 		})
@@ -289,22 +304,40 @@ func (l *location) download(m *Manager, f *feed) {
 	for _, check := range checks {
 		check(&status, f)
 	}
+	l.status = status
 
-	if strictMode && status != allSucceeded {
-		// Don't import, only write the stats.
+	// Failing documents are not imported in strict mode. With the
+	// quarantine enabled they are kept there in both modes.
+	if status != allSucceeded && (strictMode || m.cfg.Sources.Quarantine) {
+		// Don't import, only write the stats and quarantine the document.
 		if err := m.db.Run(context.Background(), func(ctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(ctx)
 			var i inserter
 			status.toInserter(&i)
 			if !f.invalid.Load() {
 				i.add("feeds_id", f.id)
 			}
 			sql := i.sql("downloads")
-			_, err := conn.Exec(ctx, sql, i.values...)
-			return err
+			if _, err := tx.Exec(ctx, sql, i.values...); err != nil {
+				return err
+			}
+			if m.cfg.Sources.Quarantine {
+				store := l.storeQuarantine(f, status, data.Bytes(), signatureData, filename)
+				if err := store(ctx, tx); err != nil {
+					return err
+				}
+			}
+			return tx.Commit(ctx)
 		}, 0); err != nil {
 			f.log(m, config.ErrorFeedLogLevel, "storing stats of %q failed: %v", l.doc, err)
+		} else if m.cfg.Sources.Quarantine {
+			f.log(m, config.WarnFeedLogLevel, "document %q quarantined", l.doc)
 		}
-		return
+		return nil
 	}
 
 	// Store stats in database.
@@ -329,11 +362,22 @@ func (l *location) download(m *Manager, f *feed) {
 		if duplicate {
 			return nil
 		}
-		const insertSQL = `UPDATE documents ` +
-			`SET (signature, filename) = ($1, $2)` +
-			`WHERE id = $3`
-		_, err := tx.Exec(ctx, insertSQL, signatureData, filename, docID)
-		return err
+		const (
+			insertSQL = `UPDATE documents ` +
+				`SET (signature, filename) = ($1, $2)` +
+				`WHERE id = $3`
+			usedSQL = `UPDATE source_keys ` +
+				`SET (used, last_used) = (used + 1, current_timestamp) ` +
+				`WHERE sources_id = $1 AND fingerprint = $2`
+		)
+		if _, err := tx.Exec(ctx, insertSQL, signatureData, filename, docID); err != nil {
+			return err
+		}
+		if signedBy != "" {
+			_, err := tx.Exec(ctx, usedSQL, f.source.id, signedBy)
+			return err
+		}
+		return nil
 	}
 
 	var importer *string
@@ -351,12 +395,19 @@ func (l *location) download(m *Manager, f *feed) {
 			false)
 		return err
 	}, 0); {
+	case errors.Is(err, models.ErrInTrash):
+		f.log(m, config.InfoFeedLogLevel, "not storing %q: document is in trash bin", l.doc)
 	case errors.Is(err, models.ErrAlreadyInDatabase):
 		f.log(m, config.InfoFeedLogLevel, "not storing duplicate %q: %v", l.doc, err)
+	case errors.Is(err, models.ErrNotAllowed):
+		// Trying again would not change anything.
+		f.log(m, config.ErrorFeedLogLevel, "storing %q failed: %v", l.doc, err)
+		return nil
 	case err != nil:
 		f.log(m, config.ErrorFeedLogLevel, "storing %q failed: %v", l.doc, err)
-		return
+		return fmt.Errorf("storing failed: %w", err)
 	}
 
 	f.log(m, config.InfoFeedLogLevel, "downloading %q done", l.doc)
+	return nil
 }
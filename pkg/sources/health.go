@@ -0,0 +1,323 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/bits"
+	"time"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// healthWindow is the number of recent downloads the
+// ratio of invalid documents is calculated from.
+const healthWindow = 100
+
+// HealthStatus is the health of a feed or a source.
+type HealthStatus int
+
+const (
+	// Healthy feeds work as expected.
+	Healthy HealthStatus = iota
+	// Degraded feeds failed recently, are slow or deliver invalid documents.
+	Degraded
+	// Unhealthy feeds failed too often in a row and are backed off.
+	Unhealthy
+)
+
+// String implements [fmt.Stringer].
+func (hs HealthStatus) String() string {
+	switch hs {
+	case Healthy:
+		return "healthy"
+	case Degraded:
+		return "degraded"
+	case Unhealthy:
+		return "unhealthy"
+	default:
+		return fmt.Sprintf("unknown health status %d", hs)
+	}
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (hs HealthStatus) MarshalText() ([]byte, error) {
+	return []byte(hs.String()), nil
+}
+
+// Health is the health of a feed or a source computed from its recent outcomes.
+type Health struct {
+	Status HealthStatus `json:"status"`
+	// Score is a value between 0 (broken) and 100 (perfect).
+	Score               int        `json:"score"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LatencyMillis       int64      `json:"latency_ms"`
+	Documents           int        `json:"documents"`
+	Invalid             int        `json:"invalid"`
+	InvalidRatio        float64    `json:"invalid_ratio"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	LastError           *string    `json:"last_error,omitempty"`
+	BackoffUntil        *time.Time `json:"backoff_until,omitempty"`
+}
+
+// FeedHealth is the health of a feed.
+type FeedHealth struct {
+	ID     int64   `json:"id"`
+	Label  string  `json:"label"`
+	Health *Health `json:"health"`
+}
+
+// SourceHealth is the health of a source and its feeds.
+type SourceHealth struct {
+	ID     int64        `json:"id"`
+	Name   string       `json:"name"`
+	Active bool         `json:"active"`
+	Health *Health      `json:"health"`
+	Feeds  []FeedHealth `json:"feeds,omitempty"`
+}
+
+// feedHealth records the recent outcomes of a feed.
+type feedHealth struct {
+	failures     int
+	latency      time.Duration
+	outcomes     []bool
+	lastSuccess  time.Time
+	lastFailure  time.Time
+	lastError    string
+	backoffUntil time.Time
+}
+
+// measured records the latency of an index fetch as a moving average.
+func (fh *feedHealth) measured(latency time.Duration) {
+	if fh.latency == 0 {
+		fh.latency = latency
+	} else {
+		fh.latency = (3*fh.latency + latency) / 4
+	}
+}
+
+// checked records if a downloaded document failed the checks.
+func (fh *feedHealth) checked(invalid bool) {
+	if len(fh.outcomes) >= healthWindow {
+		fh.outcomes = fh.outcomes[1:]
+	}
+	fh.outcomes = append(fh.outcomes, invalid)
+}
+
+// invalid returns the number of recent documents and the invalid ones.
+func (fh *feedHealth) invalid() (int, int) {
+	invalid := 0
+	for _, o := range fh.outcomes {
+		if o {
+			invalid++
+		}
+	}
+	return len(fh.outcomes), invalid
+}
+
+// ratio returns the ratio of invalid documents.
+func ratio(documents, invalid int) float64 {
+	if documents == 0 {
+		return 0
+	}
+	return float64(invalid) / float64(documents)
+}
+
+// status derives the health status from the recorded outcomes.
+func (fh *feedHealth) status(cfg *config.Health) HealthStatus {
+	documents, invalid := fh.invalid()
+	switch {
+	case fh.failures >= cfg.Failures:
+		return Unhealthy
+	case fh.failures > 0,
+		ratio(documents, invalid) > cfg.InvalidRatio,
+		cfg.Latency > 0 && fh.latency > cfg.Latency:
+		return Degraded
+	default:
+		return Healthy
+	}
+}
+
+// score rates the recorded outcomes between 0 and 100.
+func (fh *feedHealth) score(cfg *config.Health) int {
+	documents, invalid := fh.invalid()
+	score := 1 - min(1, float64(fh.failures)/float64(cfg.Failures))
+	score *= 1 - ratio(documents, invalid)
+	if cfg.Latency > 0 && fh.latency > cfg.Latency {
+		score *= float64(cfg.Latency) / float64(fh.latency)
+	}
+	return int(math.Round(100 * score))
+}
+
+// optTime returns nil for the zero time.
+func optTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// info returns the health of the feed.
+func (fh *feedHealth) info(cfg *config.Health) *Health {
+	documents, invalid := fh.invalid()
+	h := &Health{
+		Status:              fh.status(cfg),
+		Score:               fh.score(cfg),
+		ConsecutiveFailures: fh.failures,
+		LatencyMillis:       fh.latency.Milliseconds(),
+		Documents:           documents,
+		Invalid:             invalid,
+		InvalidRatio:        ratio(documents, invalid),
+		LastSuccess:         optTime(fh.lastSuccess),
+		LastFailure:         optTime(fh.lastFailure),
+	}
+	if fh.failures > 0 {
+		h.LastError = &fh.lastError
+		h.BackoffUntil = optTime(fh.backoffUntil)
+	}
+	return h
+}
+
+// health returns the health of the feed.
+func (f *feed) health(m *Manager) *Health {
+	return f.recent.info(&m.cfg.Sources.Health)
+}
+
+// health returns the combined health of the active feeds of the source.
+// The source is as unhealthy as its worst feed.
+func (s *source) health(m *Manager) *Health {
+	h := &Health{Score: 100}
+	documents, invalid := 0, 0
+	for _, f := range s.feeds {
+		if f.invalid.Load() {
+			continue
+		}
+		fh := f.health(m)
+		h.Status = max(h.Status, fh.Status)
+		h.Score = min(h.Score, fh.Score)
+		h.ConsecutiveFailures = max(h.ConsecutiveFailures, fh.ConsecutiveFailures)
+		h.LatencyMillis = max(h.LatencyMillis, fh.LatencyMillis)
+		documents += fh.Documents
+		invalid += fh.Invalid
+		if fh.LastSuccess != nil && (h.LastSuccess == nil || fh.LastSuccess.After(*h.LastSuccess)) {
+			h.LastSuccess = fh.LastSuccess
+		}
+		if fh.LastFailure != nil && (h.LastFailure == nil || fh.LastFailure.After(*h.LastFailure)) {
+			h.LastFailure = fh.LastFailure
+			h.LastError = fh.LastError
+		}
+	}
+	h.Documents, h.Invalid = documents, invalid
+	h.InvalidRatio = ratio(documents, invalid)
+	return h
+}
+
+// backedOff tells if the feed is backed off at the given time.
+func (f *feed) backedOff(now time.Time) bool {
+	return now.Before(f.recent.backoffUntil)
+}
+
+// succeeded records a successful index fetch or download.
+func (f *feed) succeeded(m *Manager) {
+	if f.recent.status(&m.cfg.Sources.Health) == Unhealthy {
+		f.log(m, config.InfoFeedLogLevel, "feed is healthy again")
+	}
+	f.recent.failures = 0
+	f.recent.backoffUntil = time.Time{}
+	f.recent.lastSuccess = time.Now().UTC()
+}
+
+// backoffDuration doubles the refresh interval shift times
+// but stops at maxBackoff before the doubling overflows.
+func backoffDuration(refresh, maxBackoff time.Duration, shift int) time.Duration {
+	if refresh <= 0 || shift >= bits.Len64(uint64(maxBackoff/refresh)) {
+		return maxBackoff
+	}
+	return refresh << shift
+}
+
+// failed records a failed index fetch or download. Unhealthy feeds
+// are backed off exponentially starting with the feed refresh interval.
+func (f *feed) failed(ctx context.Context, m *Manager, err error) {
+	cfg := &m.cfg.Sources.Health
+	wasUnhealthy := f.recent.status(cfg) == Unhealthy
+	now := time.Now().UTC()
+	f.recent.failures++
+	f.recent.lastFailure = now
+	f.recent.lastError = err.Error()
+	if f.recent.failures < cfg.Failures {
+		return
+	}
+	backoff := backoffDuration(
+		m.cfg.Sources.FeedRefresh, cfg.MaxBackoff, f.recent.failures-cfg.Failures)
+	f.recent.backoffUntil = now.Add(backoff)
+	if f.nextCheck.Before(f.recent.backoffUntil) {
+		f.nextCheck = f.recent.backoffUntil
+	}
+	if wasUnhealthy {
+		return
+	}
+	f.log(m, config.WarnFeedLogLevel,
+		"feed is unhealthy after %d failures in a row: backing off", f.recent.failures)
+	if cfg.Attention {
+		m.raiseHealthAttention(ctx, f.source)
+	}
+}
+
+// raiseHealthAttention flags a source with an unhealthy feed as needing attention.
+func (m *Manager) raiseHealthAttention(ctx context.Context, s *source) {
+	batch := &pgx.Batch{}
+	apply := s.raiseAttention(batch)
+	if apply == nil {
+		return
+	}
+	if err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			return conn.SendBatch(rctx, batch).Close()
+		}, 0,
+	); err != nil {
+		slog.Error("raising attention of unhealthy source failed", "source", s.id, "err", err)
+		return
+	}
+	apply()
+}
+
+// Health returns the health of the sources and their feeds.
+func (m *Manager) Health() []SourceHealth {
+	var list []SourceHealth
+	m.inManager(func(m *Manager, _ context.Context) {
+		list = make([]SourceHealth, 0, len(m.sources))
+		for _, s := range m.sources {
+			sh := SourceHealth{
+				ID:     s.id,
+				Name:   s.name,
+				Active: s.active,
+				Health: s.health(m),
+			}
+			for _, f := range s.feeds {
+				if !f.invalid.Load() {
+					sh.Feeds = append(sh.Feeds, FeedHealth{
+						ID:     f.id,
+						Label:  f.label,
+						Health: f.health(m),
+					})
+				}
+			}
+			list = append(list, sh)
+		}
+	})
+	return list
+}
@@ -0,0 +1,86 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+)
+
+func TestFeedHealth(t *testing.T) {
+	cfg := &config.Health{
+		Failures:     3,
+		Latency:      10 * time.Second,
+		InvalidRatio: 0.5,
+	}
+	var fh feedHealth
+	if st, score := fh.status(cfg), fh.score(cfg); st != Healthy || score != 100 {
+		t.Fatalf("have %s/%d want healthy/100", st, score)
+	}
+	fh.measured(time.Second)
+	for i := range 4 {
+		fh.checked(i == 0)
+	}
+	if st, score := fh.status(cfg), fh.score(cfg); st != Healthy || score != 75 {
+		t.Errorf("have %s/%d want healthy/75", st, score)
+	}
+	fh.failures = 1
+	if st := fh.status(cfg); st != Degraded {
+		t.Errorf("have %s want degraded after a failure", st)
+	}
+	fh.failures = 3
+	if st, score := fh.status(cfg), fh.score(cfg); st != Unhealthy || score != 0 {
+		t.Errorf("have %s/%d want unhealthy/0", st, score)
+	}
+	fh.failures = 0
+	fh.latency = 20 * time.Second
+	if st, score := fh.status(cfg), fh.score(cfg); st != Degraded || score != 38 {
+		t.Errorf("have %s/%d want degraded/38 when slow", st, score)
+	}
+	for range healthWindow {
+		fh.checked(false)
+	}
+	if documents, invalid := fh.invalid(); documents != healthWindow || invalid != 0 {
+		t.Errorf("have %d/%d want %d/0 in window", documents, invalid, healthWindow)
+	}
+}
+
+func TestBackoffDuration(t *testing.T) {
+	const (
+		refresh    = 15 * time.Minute
+		maxBackoff = 24 * time.Hour
+	)
+	for _, tc := range []struct {
+		shift int
+		want  time.Duration
+	}{
+		{0, refresh},
+		{1, 2 * refresh},
+		{6, 64 * refresh},
+		{7, maxBackoff},
+		{24, maxBackoff},
+		{63, maxBackoff},
+		{1000, maxBackoff},
+	} {
+		if have := backoffDuration(refresh, maxBackoff, tc.shift); have != tc.want {
+			t.Errorf("shift %d: have %v want %v", tc.shift, have, tc.want)
+		}
+	}
+	// Many failures in a row must not back off into the past.
+	for shift := range 10_000 {
+		if have := backoffDuration(refresh, maxBackoff, shift); have <= 0 || have > maxBackoff {
+			t.Fatalf("shift %d: have %v", shift, have)
+		}
+	}
+	if have := backoffDuration(2*maxBackoff, maxBackoff, 0); have != maxBackoff {
+		t.Errorf("have %v want %v if refresh exceeds the maximum", have, maxBackoff)
+	}
+}
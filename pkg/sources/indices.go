@@ -9,22 +9,52 @@
 package sources
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gocsaf/csaf/v3/csaf"
 )
 
+// changesChunkSize is the size of the beginning of the changes.csv
+// which is requested first when looking for new entries.
+const changesChunkSize = 16 * 1024
+
 type feedIndex struct {
 	base           *url.URL
 	age            *time.Duration
 	ignorePatterns ignorePatterns
 	sameOrNewer    func(*location) bool
+	// do sends a request to the source.
+	do func(*http.Request) (*http.Response, error)
+	// lastETag and lastModified make the requests conditional.
+	lastETag     string
+	lastModified time.Time
+	// since is the time of the newest entry of the changes.csv
+	// read before. If zero the changes.csv is read completely.
+	since time.Time
+}
+
+// indexResult is the result of fetching a feed index.
+type indexResult struct {
+	locations    []location
+	notModified  bool
+	etag         string
+	lastModified time.Time
+	// newest is the time of the newest entry of the changes.csv.
+	newest time.Time
+	// listed are the URLs in a completely read changes.csv.
+	listed map[string]bool
+	// indexErr is the error of reading the index.txt.
+	indexErr error
 }
 
 // rolieLocations assumes that the feed index is ROLIE.
@@ -110,8 +140,11 @@ nextEntry:
 	return dls, nil
 }
 
-// directoryLocations assumes that the feed index is changes.csv
-func (fi *feedIndex) directoryLocations(r io.Reader) ([]location, error) {
+// directoryLocations assumes that the feed index is changes.csv.
+// As changes.csv is sorted newest first the reading stops at
+// the first entry older than since. It returns true if such
+// an entry was found.
+func (fi *feedIndex) directoryLocations(r io.Reader, res *indexResult) (bool, error) {
 	c := csv.NewReader(r)
 	c.FieldsPerRecord = 2
 	c.ReuseRecord = true
@@ -122,27 +155,35 @@ func (fi *feedIndex) directoryLocations(r io.Reader) ([]location, error) {
 		cut = time.Now().Add(-*fi.age)
 	}
 
-	var dls []location
-
 	for lineNo := 1; ; lineNo++ {
 		record, err := c.Read()
 		if err == io.EOF {
-			break
+			return false, nil
 		}
 		if err != nil {
-			return nil, fmt.Errorf("CSV line %d is invalid: %w", lineNo, err)
+			return false, fmt.Errorf("CSV line %d is invalid: %w", lineNo, err)
 		}
 		doc, err := url.Parse(record[0])
 		if err != nil {
-			return nil, fmt.Errorf("column 1 in line %d is not a valid URL: %w", lineNo, err)
+			return false, fmt.Errorf("column 1 in line %d is not a valid URL: %w", lineNo, err)
 		}
 		updated, err := time.Parse(time.RFC3339, record[1])
 		if err != nil {
-			return nil, fmt.Errorf("column 2 in line %d is not a valid RFC3339 time: %w", lineNo, err)
+			return false, fmt.Errorf("column 2 in line %d is not a valid RFC3339 time: %w", lineNo, err)
+		}
+		// Everything from here on was read before.
+		if !fi.since.IsZero() && updated.Before(fi.since) {
+			return true, nil
+		}
+		if updated.After(res.newest) {
+			res.newest = updated
 		}
 		if !doc.IsAbs() {
 			doc = joinURL(fi.base, doc)
 		}
+		if res.listed != nil {
+			res.listed[doc.String()] = true
+		}
 		// Apply age filter
 		if fi.age != nil && updated.Before(cut) {
 			continue
@@ -158,8 +199,204 @@ func (fi *feedIndex) directoryLocations(r io.Reader) ([]location, error) {
 		if fi.sameOrNewer != nil && fi.sameOrNewer(&dl) {
 			continue
 		}
-		dls = append(dls, dl)
+		res.locations = append(res.locations, dl)
 	}
+}
 
-	return dls, nil
+// indexLocations adds the entries of the index.txt to the result
+// which are not listed in the changes.csv.
+func (fi *feedIndex) indexLocations(r io.Reader, res *indexResult) error {
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		doc, err := url.Parse(line)
+		if err != nil {
+			return fmt.Errorf("line %d is not a valid URL: %w", lineNo, err)
+		}
+		if !doc.IsAbs() {
+			doc = joinURL(fi.base, doc)
+		}
+		if res.listed[doc.String()] {
+			continue
+		}
+		// Without a time we cannot tell if it is too old.
+		if fi.age != nil || fi.ignorePatterns.ignore(doc) {
+			continue
+		}
+		dl := location{doc: doc}
+		if fi.sameOrNewer != nil && fi.sameOrNewer(&dl) {
+			continue
+		}
+		res.locations = append(res.locations, dl)
+	}
+	return scanner.Err()
+}
+
+// request creates a GET request which is conditional if asked for.
+func (fi *feedIndex) request(u string, conditional bool) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if conditional {
+		if fi.lastETag != "" {
+			req.Header.Add("If-None-Match", fi.lastETag)
+		}
+		if !fi.lastModified.IsZero() {
+			req.Header.Add("If-Modified-Since", fi.lastModified.Format(http.TimeFormat))
+		}
+	}
+	return req, nil
+}
+
+// newIndexResult creates a result with the tags of the response.
+func newIndexResult(resp *http.Response) *indexResult {
+	res := &indexResult{etag: resp.Header.Get("Etag")}
+	if m := resp.Header.Get("Last-Modified"); m != "" {
+		res.lastModified, _ = time.Parse(http.TimeFormat, m)
+	}
+	return res
+}
+
+// fetchROLIE fetches the ROLIE feed.
+func (fi *feedIndex) fetchROLIE() (*indexResult, error) {
+	req, err := fi.request(fi.base.String(), true)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fi.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	res := newIndexResult(resp)
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		res.notModified = true
+		return res, nil
+	case http.StatusOK:
+		res.locations, err = fi.rolieLocations(resp.Body)
+		return res, err
+	default:
+		return nil, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+}
+
+// fetchChanges fetches the entries of the changes.csv which are
+// newer than since. Only the beginning of the file is requested
+// if the server supports range requests.
+func (fi *feedIndex) fetchChanges() (*indexResult, error) {
+	if fi.since.IsZero() {
+		return fi.fetchAllChanges()
+	}
+	changesURL, err := url.JoinPath(fi.base.String(), "changes.csv")
+	if err != nil {
+		return nil, err
+	}
+	req, err := fi.request(changesURL, true)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", changesChunkSize-1))
+	resp, err := fi.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	res := newIndexResult(resp)
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		res.notModified = true
+		return res, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The changes.csv is empty.
+		return res, nil
+	case http.StatusOK:
+		// The server does not support ranges.
+		_, err := fi.directoryLocations(resp.Body, res)
+		return res, err
+	case http.StatusPartialContent:
+		data, err := io.ReadAll(io.LimitReader(resp.Body, changesChunkSize))
+		if err != nil {
+			return nil, err
+		}
+		whole := false
+		if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+			n, err := strconv.ParseInt(total, 10, 64)
+			whole = err == nil && n <= int64(len(data))
+		}
+		if !whole {
+			// Only complete lines can be parsed.
+			data = data[:bytes.LastIndexByte(data, '\n')+1]
+		}
+		found, err := fi.directoryLocations(bytes.NewReader(data), res)
+		if err != nil || found || whole {
+			return res, err
+		}
+		// There are more new entries than fit into the chunk.
+		return fi.fetchAllChanges()
+	default:
+		return nil, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+}
+
+// fetchAllChanges fetches the entries of the changes.csv newer than since.
+// The request is not conditional.
+func (fi *feedIndex) fetchAllChanges() (*indexResult, error) {
+	changesURL, err := url.JoinPath(fi.base.String(), "changes.csv")
+	if err != nil {
+		return nil, err
+	}
+	req, err := fi.request(changesURL, false)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fi.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+	res := newIndexResult(resp)
+	if fi.since.IsZero() {
+		res.listed = map[string]bool{}
+	}
+	_, err = fi.directoryLocations(resp.Body, res)
+	return res, err
+}
+
+// reconcileDirectory reads the changes.csv completely and adds the
+// entries of the index.txt which are missing in there. A failing
+// index.txt is reported in the result but is not an error.
+func (fi *feedIndex) reconcileDirectory() (*indexResult, error) {
+	fi.since = time.Time{}
+	res, err := fi.fetchAllChanges()
+	if err != nil {
+		return nil, err
+	}
+	res.indexErr = func() error {
+		indexURL, err := url.JoinPath(fi.base.String(), "index.txt")
+		if err != nil {
+			return err
+		}
+		req, err := fi.request(indexURL, false)
+		if err != nil {
+			return err
+		}
+		resp, err := fi.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status code %d", resp.StatusCode)
+		}
+		return fi.indexLocations(resp.Body, res)
+	}()
+	return res, nil
 }
@@ -0,0 +1,296 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ISDuBA/ISDuBA/pkg/csafmock"
+)
+
+// testProvider is a directory based CSAF provider serving
+// a changes.csv and an index.txt.
+type testProvider struct {
+	changes []byte
+	index   []byte
+	// noRanges lets the provider ignore range and conditional requests.
+	noRanges bool
+	modified time.Time
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// testTime is the time of the newest entry of the test provider.
+var testTime = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestProvider creates a provider with n entries in the changes.csv
+// and extra entries only listed in the index.txt.
+func newTestProvider(n, extra int) *testProvider {
+	var changes, index bytes.Buffer
+	// changes.csv is sorted newest first.
+	for i := range n {
+		name := fmt.Sprintf("2026/example-%04d.json", i)
+		updated := testTime.Add(-time.Duration(i) * time.Hour)
+		fmt.Fprintf(&changes, "%q,%q\n", name, updated.Format(time.RFC3339))
+		fmt.Fprintln(&index, name)
+	}
+	for i := range extra {
+		fmt.Fprintf(&index, "2025/extra-%04d.json\n", i)
+	}
+	return &testProvider{
+		changes:  changes.Bytes(),
+		index:    index.Bytes(),
+		modified: testTime,
+	}
+}
+
+func (tp *testProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tp.mu.Lock()
+	tp.requests = append(tp.requests, r)
+	tp.mu.Unlock()
+	var content []byte
+	switch r.URL.Path {
+	case "/csaf/changes.csv":
+		content = tp.changes
+	case "/csaf/index.txt":
+		content = tp.index
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if tp.noRanges {
+		w.Write(content)
+		return
+	}
+	w.Header().Set("Etag", fmt.Sprintf(`"%x-%d"`, tp.modified.Unix(), len(content)))
+	http.ServeContent(w, r, "", tp.modified, bytes.NewReader(content))
+}
+
+// serve starts the provider and returns a feed index for it.
+func (tp *testProvider) serve(t *testing.T) *feedIndex {
+	t.Helper()
+	server := httptest.NewTLSServer(tp)
+	t.Cleanup(server.Close)
+	base, err := url.Parse(server.URL + "/csaf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := server.Client()
+	return &feedIndex{base: base, do: client.Do}
+}
+
+func (tp *testProvider) ranges() []string {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	var ranges []string
+	for _, r := range tp.requests {
+		ranges = append(ranges, r.Header.Get("Range"))
+	}
+	return ranges
+}
+
+func TestReconcileDirectory(t *testing.T) {
+	tp := newTestProvider(10, 2)
+	fi := tp.serve(t)
+	res, err := fi.reconcileDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.indexErr != nil {
+		t.Fatalf("reading index.txt failed: %v", res.indexErr)
+	}
+	if have, want := len(res.locations), 12; have != want {
+		t.Fatalf("have %d locations want %d", have, want)
+	}
+	if !res.newest.Equal(testTime) {
+		t.Errorf("have newest %v want %v", res.newest, testTime)
+	}
+	if res.etag == "" {
+		t.Error("missing etag")
+	}
+	for _, l := range res.locations {
+		onlyIndexed := strings.Contains(l.doc.Path, "extra-")
+		if onlyIndexed != l.updated.IsZero() {
+			t.Errorf("%s: unexpected update time %v", l.doc, l.updated)
+		}
+		if l.doc.Scheme != "https" || !strings.HasPrefix(l.doc.Path, "/csaf/") {
+			t.Errorf("unexpected URL %s", l.doc)
+		}
+	}
+}
+
+func TestReconcileDirectoryAge(t *testing.T) {
+	tp := newTestProvider(10, 2)
+	fi := tp.serve(t)
+	age := time.Since(testTime.Add(-4*time.Hour - 30*time.Minute))
+	fi.age = &age
+	res, err := fi.reconcileDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Entries without time are too old when an age is set.
+	if have, want := len(res.locations), 5; have != want {
+		t.Errorf("have %d locations want %d", have, want)
+	}
+}
+
+func TestFetchChangesIncremental(t *testing.T) {
+	// Large enough to not fit into a chunk.
+	tp := newTestProvider(1000, 0)
+	if len(tp.changes) <= changesChunkSize {
+		t.Fatalf("changes.csv is too small: %d", len(tp.changes))
+	}
+	fi := tp.serve(t)
+	fi.since = testTime.Add(-5 * time.Hour)
+	res, err := fi.fetchChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The entry at since itself is read again.
+	if have, want := len(res.locations), 6; have != want {
+		t.Errorf("have %d locations want %d", have, want)
+	}
+	if ranges := tp.ranges(); len(ranges) != 1 || ranges[0] == "" {
+		t.Errorf("expected a single range request: %q", ranges)
+	}
+}
+
+func TestFetchChangesBeyondChunk(t *testing.T) {
+	tp := newTestProvider(1000, 0)
+	fi := tp.serve(t)
+	fi.since = testTime.Add(-900 * time.Hour)
+	res, err := fi.fetchChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := len(res.locations), 901; have != want {
+		t.Errorf("have %d locations want %d", have, want)
+	}
+	// The chunk is followed by a complete request.
+	if ranges := tp.ranges(); len(ranges) != 2 || ranges[0] == "" || ranges[1] != "" {
+		t.Errorf("unexpected requests: %q", ranges)
+	}
+}
+
+func TestFetchChangesSmall(t *testing.T) {
+	// Fits completely into the chunk.
+	tp := newTestProvider(10, 0)
+	fi := tp.serve(t)
+	fi.since = testTime.Add(-100 * time.Hour)
+	res, err := fi.fetchChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := len(res.locations), 10; have != want {
+		t.Errorf("have %d locations want %d", have, want)
+	}
+	if ranges := tp.ranges(); len(ranges) != 1 {
+		t.Errorf("unexpected requests: %q", ranges)
+	}
+}
+
+func TestFetchChangesNotModified(t *testing.T) {
+	tp := newTestProvider(10, 0)
+	fi := tp.serve(t)
+	res, err := fi.reconcileDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi.lastETag, fi.lastModified, fi.since = res.etag, res.lastModified, res.newest
+	if res, err = fi.fetchChanges(); err != nil {
+		t.Fatal(err)
+	}
+	if !res.notModified {
+		t.Error("expected not modified")
+	}
+}
+
+func TestFetchChangesNoRanges(t *testing.T) {
+	tp := newTestProvider(1000, 0)
+	tp.noRanges = true
+	fi := tp.serve(t)
+	fi.since = testTime.Add(-2 * time.Hour)
+	res, err := fi.fetchChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := len(res.locations), 3; have != want {
+		t.Errorf("have %d locations want %d", have, want)
+	}
+}
+
+func TestFetchChangesKnown(t *testing.T) {
+	tp := newTestProvider(10, 0)
+	fi := tp.serve(t)
+	fi.since = testTime.Add(-3 * time.Hour)
+	// Locations in the queue are not fetched again.
+	fi.sameOrNewer = func(l *location) bool {
+		return strings.HasSuffix(l.doc.Path, "example-0001.json")
+	}
+	res, err := fi.fetchChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := len(res.locations), 3; have != want {
+		t.Errorf("have %d locations want %d", have, want)
+	}
+}
+
+func TestMockProviderFeeds(t *testing.T) {
+	docs, err := csafmock.LoadDocuments("../../docs/example-advisories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := csafmock.NewProvider(docs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewTLSServer(provider)
+	defer server.Close()
+	client := server.Client()
+
+	for _, feed := range []string{
+		"/.well-known/csaf/white/csaf-feed-tlp-white.json",
+		"/.well-known/csaf/white",
+	} {
+		base, err := url.Parse(server.URL + feed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi := &feedIndex{base: base, do: client.Do}
+		var res *indexResult
+		rolie := strings.HasSuffix(feed, ".json")
+		if rolie {
+			res, err = fi.fetchROLIE()
+		} else {
+			res, err = fi.reconcileDirectory()
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", feed, err)
+		}
+		if have, want := len(res.locations), len(docs); have != want {
+			t.Errorf("%s: have %d locations want %d", feed, have, want)
+		}
+		for _, l := range res.locations {
+			// Directory feeds derive hashes and signatures when downloading.
+			if rolie && (l.hash == nil || l.signature == nil) {
+				t.Errorf("%s: %s has no hash or signature", feed, l.doc)
+			}
+		}
+	}
+}
@@ -9,16 +9,20 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/ISDuBA/ISDuBA/pkg/cache"
 	"github.com/ProtonMail/gopenpgp/v2/crypto"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type keysCache struct {
@@ -31,33 +35,135 @@ func newKeysCache(expiration time.Duration) *keysCache {
 	}
 }
 
-// openPGPKeys extracts the OpenPGP key from them PMD of a source if not already
-// in cache.
+// keyState is the trust state of a pinned OpenPGP key.
+type keyState string
+
+const (
+	keyPending  keyState = "pending"
+	keyTrusted  keyState = "trusted"
+	keyRejected keyState = "rejected"
+)
+
+// pinnedKey is an OpenPGP key pinned for a source.
+type pinnedKey struct {
+	fingerprint string
+	armored     string
+	key         *crypto.Key
+	state       keyState
+	advertised  bool
+	uploaded    bool
+}
+
+const (
+	insertKeySQL = `INSERT INTO source_keys ` +
+		`(sources_id, fingerprint, key, state, advertised, uploaded, expires, first_seen, decided, actor) ` +
+		`VALUES ($1, $2, $3, $4::source_key_state, $5, $6, $7, $8, $9, $10)`
+	logKeySQL = `INSERT INTO source_keys_log (sources_id, fingerprint, event, time, actor) ` +
+		`VALUES ($1, $2, $3::source_key_events, $4, $5)`
+	attentionSQL = `UPDATE sources SET checksum_ack = $1 WHERE id = $2`
+)
+
+// fingerprint returns the upper case fingerprint of a key.
+func fingerprint(key *crypto.Key) string {
+	return strings.ToUpper(key.GetFingerprint())
+}
+
+// keyExpires returns the expiry time of a key. Nil if it does not expire.
+func keyExpires(key *crypto.Key) *time.Time {
+	entity := key.GetEntity()
+	ident := entity.PrimaryIdentity()
+	if ident == nil || ident.SelfSignature == nil {
+		return nil
+	}
+	lifetime := ident.SelfSignature.KeyLifetimeSecs
+	if lifetime == nil || *lifetime == 0 {
+		return nil
+	}
+	expires := entity.PrimaryKey.CreationTime.
+		Add(time.Duration(*lifetime) * time.Second).UTC()
+	return &expires
+}
+
+// findKey looks up a pinned key by its fingerprint.
+func (s *source) findKey(fingerprint string) *pinnedKey {
+	for _, pk := range s.keys {
+		if strings.EqualFold(pk.fingerprint, fingerprint) {
+			return pk
+		}
+	}
+	return nil
+}
+
+// trustedKeys returns a key ring with the trusted keys of the source.
+// It is nil if the source has no pinned keys at all.
+func (s *source) trustedKeys() *crypto.KeyRing {
+	if len(s.keys) == 0 {
+		return nil
+	}
+	keys, _ := crypto.NewKeyRing(nil)
+	for _, pk := range s.keys {
+		if pk.state == keyTrusted {
+			if err := keys.AddKey(pk.key); err != nil {
+				slog.Warn("Could not add public OpenPGP key to key ring",
+					"fingerprint", pk.fingerprint, "err", err)
+			}
+		}
+	}
+	return keys
+}
+
+// raiseAttention flags the source as needing attention.
+func (s *source) raiseAttention(batch *pgx.Batch) func() {
+	if s.checksumAck.Before(s.checksumUpdated) {
+		return nil
+	}
+	when := s.checksumUpdated.Add(-time.Second)
+	batch.Queue(attentionSQL, when, s.id)
+	return func() { s.checksumAck = when }
+}
+
+// openPGPKeys returns the trusted OpenPGP keys of a source if not already
+// in cache. The keys advertised in the PMD are pinned on first use.
+// Later changes of the advertised keys need an approval.
+// The key ring is nil if the source has no keys to check against.
 func (m *Manager) openPGPKeys(source *source) (*crypto.KeyRing, error) {
 	if keys, ok := m.keysCache.Get(source.id); ok {
 		return keys, nil
 	}
-	keys, _ := crypto.NewKeyRing(nil)
-	cpmd := m.pmdCache.pmd(source.url, m.cfg)
-	if !cpmd.Valid() {
+	advertised, err := m.advertisedKeys(source)
+	var keys *crypto.KeyRing
+	m.inManager(func(m *Manager, ctx context.Context) {
+		// Removals are only detected if the PMD was loaded.
+		keys = m.pinKeys(ctx, source, advertised, err == nil)
+	})
+	if err != nil {
 		// Try again soon.
 		m.keysCache.SetWithExpiration(source.id, keys, holdingPMDsDuration)
+		return keys, err
+	}
+	m.keysCache.Set(source.id, keys)
+	return keys, nil
+}
+
+// advertisedKeys fetches the OpenPGP keys advertised in the PMD of a source.
+func (m *Manager) advertisedKeys(source *source) ([]*crypto.Key, error) {
+	cpmd := m.PMD(source.url)
+	if !cpmd.Valid() {
 		return nil, fmt.Errorf("PMD of %q is invalid", source.url)
 	}
 	pmd, err := cpmd.Model()
 	if err != nil {
-		// Try again soon.
-		m.keysCache.SetWithExpiration(source.id, keys, holdingPMDsDuration)
 		return nil, fmt.Errorf("re-marshaling failed: %w", err)
 	}
 	base, err := url.Parse(source.url)
 	if err != nil {
 		// XXX: This should not happen.
-		m.keysCache.SetWithExpiration(source.id, keys, holdingPMDsDuration)
 		return nil, fmt.Errorf("invalid PMD url: %q", source.url)
 	}
-	client := source.httpClient(m)
+	var client *http.Client
+	m.inManager(func(m *Manager, _ context.Context) { client = source.httpClient(m) })
 	defer client.CloseIdleConnections()
+	var keys []*crypto.Key
 	for i := range pmd.PGPKeys {
 		key := &pmd.PGPKeys[i]
 		if key.URL == nil {
@@ -106,16 +212,298 @@ func (m *Manager) openPGPKeys(source *source) (*crypto.KeyRing, error) {
 				"url", u)
 			continue
 		}
-		if err := keys.AddKey(ckey); err != nil {
-			slog.Warn(
-				"Could not add public OpenPGP key to key ring",
-				"url", u)
-		}
+		keys = append(keys, ckey)
 	}
-	m.keysCache.Set(source.id, keys)
 	return keys, nil
 }
 
+// pinKeys compares the advertised keys with the pinned keys of a source
+// and returns the trusted keys. If the source has no pinned keys yet the
+// advertised ones are trusted. New and vanished keys raise the attention
+// flag of the source. complete tells if the advertised keys are complete
+// so missing keys can be considered removed.
+func (m *Manager) pinKeys(
+	ctx context.Context,
+	s *source,
+	advertised []*crypto.Key,
+	complete bool,
+) *crypto.KeyRing {
+	var (
+		now     = time.Now().UTC()
+		first   = len(s.keys) == 0
+		batch   pgx.Batch
+		apply   []func()
+		changed bool
+		seen    = map[string]bool{}
+	)
+	const (
+		advertisedSQL = `UPDATE source_keys SET advertised = $3 ` +
+			`WHERE sources_id = $1 AND fingerprint = $2`
+		refreshSQL = `UPDATE source_keys SET (key, expires) = ($3, $4) ` +
+			`WHERE sources_id = $1 AND fingerprint = $2`
+	)
+	for _, key := range advertised {
+		fp := fingerprint(key)
+		if seen[fp] {
+			continue
+		}
+		seen[fp] = true
+		armored, err := key.GetArmoredPublicKey()
+		if err != nil {
+			slog.Warn("Cannot armor public OpenPGP key", "fingerprint", fp, "err", err)
+			continue
+		}
+		pk := s.findKey(fp)
+		if pk == nil {
+			state, event := keyPending, "added"
+			if first {
+				// Trust on first use.
+				state, event = keyTrusted, "pinned"
+			} else {
+				changed = true
+				slog.Warn("New OpenPGP key advertised", "source", s.name, "fingerprint", fp)
+			}
+			batch.Queue(insertKeySQL,
+				s.id, fp, armored, string(state), true, false, keyExpires(key), now, nil, nil)
+			batch.Queue(logKeySQL, s.id, fp, event, now, nil)
+			npk := &pinnedKey{
+				fingerprint: fp,
+				armored:     armored,
+				key:         key,
+				state:       state,
+				advertised:  true,
+			}
+			apply = append(apply, func() { s.keys = append(s.keys, npk) })
+			continue
+		}
+		if !pk.advertised {
+			batch.Queue(advertisedSQL, s.id, fp, true)
+			apply = append(apply, func() { pk.advertised = true })
+		}
+		// Pick up extended expiry times and new sub keys.
+		if pk.armored != armored {
+			batch.Queue(refreshSQL, s.id, fp, armored, keyExpires(key))
+			apply = append(apply, func() { pk.armored, pk.key = armored, key })
+		}
+	}
+	if complete {
+		for _, pk := range s.keys {
+			if pk.advertised && !seen[pk.fingerprint] {
+				changed = true
+				slog.Warn("OpenPGP key no longer advertised", "source", s.name, "fingerprint", pk.fingerprint)
+				batch.Queue(advertisedSQL, s.id, pk.fingerprint, false)
+				batch.Queue(logKeySQL, s.id, pk.fingerprint, "removed", now, nil)
+				apply = append(apply, func() { pk.advertised = false })
+			}
+		}
+	}
+	if changed {
+		if fn := s.raiseAttention(&batch); fn != nil {
+			apply = append(apply, fn)
+		}
+	}
+	if batch.Len() > 0 {
+		if err := m.db.Run(
+			ctx,
+			func(rctx context.Context, conn *pgxpool.Conn) error {
+				tx, err := conn.Begin(rctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback(rctx)
+				if err := tx.SendBatch(rctx, &batch).Close(); err != nil {
+					return err
+				}
+				return tx.Commit(rctx)
+			}, 0,
+		); err != nil {
+			slog.Error("Storing OpenPGP keys failed", "source", s.name, "err", err)
+			if keys := s.trustedKeys(); keys != nil || len(advertised) == 0 {
+				return keys
+			}
+			// None of the advertised keys could be pinned.
+			keys, _ := crypto.NewKeyRing(nil)
+			return keys
+		}
+		// Apply after db operations have succeeded.
+		for _, fn := range apply {
+			fn()
+		}
+	}
+	return s.trustedKeys()
+}
+
+// signingKey returns the fingerprint of the key of a key ring which
+// made a given valid signature.
+func signingKey(keys *crypto.KeyRing, pm *crypto.PlainMessage, signature *crypto.PGPSignature) string {
+	for _, key := range keys.GetKeys() {
+		single, err := crypto.NewKeyRing(key)
+		if err != nil {
+			continue
+		}
+		if single.VerifyDetached(pm, signature, crypto.GetUnixTime()) == nil {
+			return fingerprint(key)
+		}
+	}
+	return ""
+}
+
+// loadKeys loads the pinned keys of the sources.
+func (m *Manager) loadKeys(ctx context.Context, tx pgx.Tx) error {
+	const keysSQL = `SELECT sources_id, fingerprint, key, state::text, advertised, uploaded ` +
+		`FROM source_keys ORDER BY first_seen`
+	rows, err := tx.Query(ctx, keysSQL)
+	if err != nil {
+		return fmt.Errorf("querying OpenPGP keys failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			pk  pinnedKey
+			sid int64
+		)
+		if err := rows.Scan(
+			&sid, &pk.fingerprint, &pk.armored, &pk.state, &pk.advertised, &pk.uploaded,
+		); err != nil {
+			return fmt.Errorf("scanning OpenPGP keys failed: %w", err)
+		}
+		s := m.findSourceByID(sid)
+		if s == nil {
+			continue
+		}
+		if pk.key, err = crypto.NewKeyFromArmored(pk.armored); err != nil {
+			slog.Warn("Invalid pinned OpenPGP key",
+				"source", s.name, "fingerprint", pk.fingerprint, "err", err)
+			continue
+		}
+		s.keys = append(s.keys, &pk)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("collecting OpenPGP keys failed: %w", err)
+	}
+	return nil
+}
+
+// changeKeys runs a modification of the pinned keys of a source
+// in the manager and writes the given batch in a transaction.
+// The returned function is applied if the database operations succeeded.
+func (m *Manager) changeKeys(
+	sourceID int64,
+	fn func(s *source, batch *pgx.Batch) (func(), error),
+) error {
+	return m.asManager(func(m *Manager, ctx context.Context, sourceID int64) error {
+		s := m.findSourceByID(sourceID)
+		if s == nil || sourceID == 0 {
+			return NoSuchEntryError("no such source")
+		}
+		var batch pgx.Batch
+		apply, err := fn(s, &batch)
+		if err != nil {
+			return err
+		}
+		if err := m.db.Run(
+			ctx,
+			func(rctx context.Context, conn *pgxpool.Conn) error {
+				tx, err := conn.Begin(rctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback(rctx)
+				if err := tx.SendBatch(rctx, &batch).Close(); err != nil {
+					return err
+				}
+				return tx.Commit(rctx)
+			}, 0,
+		); err != nil {
+			return fmt.Errorf("storing OpenPGP keys failed: %w", err)
+		}
+		apply()
+		// Reload the keys with the next download.
+		m.keysCache.Delete(s.id)
+		return nil
+	}, sourceID)
+}
+
+// DecideKey trusts or rejects a pinned OpenPGP key of a source.
+func (m *Manager) DecideKey(sourceID int64, fingerprint string, trust bool, actor *string) error {
+	const decideSQL = `UPDATE source_keys ` +
+		`SET (state, decided, actor) = ($3::source_key_state, $4, $5) ` +
+		`WHERE sources_id = $1 AND fingerprint = $2`
+	state, event := keyRejected, "rejected"
+	if trust {
+		state, event = keyTrusted, "trusted"
+	}
+	return m.changeKeys(sourceID, func(s *source, batch *pgx.Batch) (func(), error) {
+		pk := s.findKey(fingerprint)
+		if pk == nil {
+			return nil, NoSuchEntryError("no such key")
+		}
+		if pk.state == state {
+			return func() {}, nil
+		}
+		now := time.Now().UTC()
+		batch.Queue(decideSQL, s.id, pk.fingerprint, string(state), now, actor)
+		batch.Queue(logKeySQL, s.id, pk.fingerprint, event, now, actor)
+		return func() { pk.state = state }, nil
+	})
+}
+
+// AddKey uploads an additional trusted OpenPGP key for a source.
+// It returns the fingerprint of the key.
+func (m *Manager) AddKey(sourceID int64, armored string, actor *string) (string, error) {
+	key, err := crypto.NewKeyFromArmored(armored)
+	if err != nil {
+		return "", InvalidArgumentError("invalid OpenPGP key: " + err.Error())
+	}
+	if key.IsPrivate() {
+		return "", InvalidArgumentError("not a public OpenPGP key")
+	}
+	if !key.CanVerify() {
+		return "", InvalidArgumentError("OpenPGP key cannot verify signatures")
+	}
+	if armored, err = key.GetArmoredPublicKey(); err != nil {
+		return "", InvalidArgumentError("cannot armor OpenPGP key: " + err.Error())
+	}
+	fp := fingerprint(key)
+	return fp, m.changeKeys(sourceID, func(s *source, batch *pgx.Batch) (func(), error) {
+		if s.findKey(fp) != nil {
+			return nil, InvalidArgumentError("OpenPGP key already pinned")
+		}
+		now := time.Now().UTC()
+		batch.Queue(insertKeySQL,
+			s.id, fp, armored, string(keyTrusted), false, true, keyExpires(key), now, now, actor)
+		batch.Queue(logKeySQL, s.id, fp, "uploaded", now, actor)
+		pk := &pinnedKey{
+			fingerprint: fp,
+			armored:     armored,
+			key:         key,
+			state:       keyTrusted,
+			uploaded:    true,
+		}
+		return func() { s.keys = append(s.keys, pk) }, nil
+	})
+}
+
+// RemoveKey removes an uploaded OpenPGP key from a source.
+// Keys advertised by the source cannot be removed.
+func (m *Manager) RemoveKey(sourceID int64, fingerprint string, actor *string) error {
+	const deleteSQL = `DELETE FROM source_keys WHERE sources_id = $1 AND fingerprint = $2`
+	return m.changeKeys(sourceID, func(s *source, batch *pgx.Batch) (func(), error) {
+		pk := s.findKey(fingerprint)
+		if pk == nil {
+			return nil, NoSuchEntryError("no such key")
+		}
+		if !pk.uploaded || pk.advertised {
+			return nil, InvalidArgumentError("only uploaded keys can be removed")
+		}
+		batch.Queue(deleteSQL, s.id, pk.fingerprint)
+		batch.Queue(logKeySQL, s.id, pk.fingerprint, "deleted", time.Now().UTC(), actor)
+		return func() {
+			s.keys = slices.DeleteFunc(s.keys, func(k *pinnedKey) bool { return k == pk })
+		}, nil
+	})
+}
+
 // loadSignature loads an ascii armored OpenPGP signature file from a given url.
 func (s *source) loadSignature(client *http.Client, m *Manager, u *url.URL) (*crypto.PGPSignature, []byte, error) {
 	resp, err := s.httpGet(client, m, u.String())
@@ -0,0 +1,49 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+)
+
+func TestTrustedKeys(t *testing.T) {
+	key, err := crypto.GenerateKey("test", "test@example.com", "x25519", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned := func(state keyState) *pinnedKey {
+		return &pinnedKey{fingerprint: fingerprint(key), key: key, state: state}
+	}
+
+	// Without keys there is nothing to check against.
+	if keys := (&source{}).trustedKeys(); keys != nil {
+		t.Errorf("expected no key ring got %d keys", keys.CountEntities())
+	}
+	for _, x := range []struct {
+		state    keyState
+		expected int
+	}{
+		{keyTrusted, 1},
+		{keyPending, 0},
+		{keyRejected, 0},
+	} {
+		s := &source{keys: []*pinnedKey{pinned(x.state)}}
+		keys := s.trustedKeys()
+		// An empty key ring lets the signature check fail.
+		if keys == nil {
+			t.Errorf("%s: expected key ring got nil", x.state)
+			continue
+		}
+		if n := keys.CountEntities(); n != x.expected {
+			t.Errorf("%s: expected %d keys got %d", x.state, x.expected, n)
+		}
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/database"
 	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/schedule"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
 	"github.com/gocsaf/csaf/v3/csaf"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -77,12 +79,14 @@ type Manager struct {
 	done bool
 	rnd  *rand.Rand
 
-	cipherKey []byte
+	secrets secrets.Provider
 
 	sources []*source
 
 	pmdCache  *pmdCache
 	keysCache *keysCache
+	// proxies are the proxies of the sources by url.
+	proxies sync.Map
 
 	val csaf.RemoteValidator
 
@@ -131,7 +135,19 @@ type SourceInfo struct {
 	HasClientCertPublic     bool
 	HasClientCertPrivate    bool
 	HasClientCertPassphrase bool
+	Auth                    AuthMode
+	OAuth2TokenURL          *string
+	OAuth2ClientID          *string
+	OAuth2Scopes            []string
+	HasBearerToken          bool
+	HasOAuth2ClientSecret   bool
+	Token                   *TokenState
+	Proxy                   *string
+	HasProxyCredentials     bool
+	Schedule                schedule.Schedule
+	NextRun                 *time.Time
 	Stats                   *Stats
+	Health                  *Health
 }
 
 // FeedSubscription are the ID and the URL of a subscribed feed.
@@ -156,12 +172,15 @@ type SourceSubscriptions struct {
 
 // FeedInfo are infos about a feed.
 type FeedInfo struct {
-	ID    int64
-	Label string
-	URL   *url.URL
-	Rolie bool
-	Lvl   config.FeedLogLevel
-	Stats *Stats
+	ID       int64
+	Label    string
+	URL      *url.URL
+	Rolie    bool
+	Lvl      config.FeedLogLevel
+	Schedule schedule.Schedule
+	NextRun  *time.Time
+	Stats    *Stats
+	Health   *Health
 }
 
 func (sur SourceUpdateResult) String() string {
@@ -182,22 +201,19 @@ func NewManager(
 	cfg *config.Config,
 	db *database.DB,
 	val csaf.RemoteValidator,
-) (*Manager, error) {
-	cipherKey, err := createCipherKey(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("creating cipher failed: %w", err)
-	}
+	provider secrets.Provider,
+) *Manager {
 	return &Manager{
 		cfg:       cfg,
 		db:        db,
 		fns:       make(chan func(*Manager, context.Context)),
 		jobs:      make(chan downloadJob),
 		rnd:       rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
-		cipherKey: cipherKey,
+		secrets:   provider,
 		pmdCache:  newPMDCache(),
 		keysCache: newKeysCache(cfg.Sources.OpenPGPCaching),
 		val:       val,
-	}, nil
+	}
 }
 
 func (m *Manager) numActiveFeeds() int {
@@ -286,11 +302,32 @@ func (m *Manager) findSourceByName(name string) *source {
 func (m *Manager) refreshFeeds() {
 	now := time.Now()
 	for f := range m.activeFeeds() {
+		if f.refreshBlocked {
+			continue
+		}
+		sched := f.activeSchedule()
+		if f.fetchNow {
+			sched = nil
+		}
+		// Scheduled feeds are not refreshed before their first run.
+		if sched != nil && f.nextCheck.IsZero() {
+			f.nextCheck = sched.Start(now)
+		}
 		// Does the feed need a refresh?
-		if !f.refreshBlocked && (f.nextCheck.IsZero() || !now.Before(f.nextCheck)) {
-			slog.Debug("refreshing feed", "feed", f.id, "source", f.source.name)
-			f.refresh(m)
-			// Even if there was an error try again later.
+		if !f.nextCheck.IsZero() && now.Before(f.nextCheck) {
+			continue
+		}
+		// Forced refreshes have to wait for the next time window.
+		if sched != nil && !sched.Allows(now) {
+			f.nextCheck = sched.Start(now)
+			continue
+		}
+		slog.Debug("refreshing feed", "feed", f.id, "source", f.source.name)
+		f.refresh(m)
+		// Even if there was an error try again later.
+		if sched := f.activeSchedule(); sched != nil {
+			f.nextCheck = sched.Next(now, m.cfg.Sources.FeedRefresh)
+		} else {
 			f.nextCheck = time.Now().Add(m.cfg.Sources.FeedRefresh)
 		}
 	}
@@ -298,10 +335,15 @@ func (m *Manager) refreshFeeds() {
 
 // startDownloads starts downloads if there are enough slots and
 // there are things to download.
-func (m *Manager) startDownloads() {
+func (m *Manager) startDownloads(ctx context.Context) {
+	now := time.Now()
 	for m.usedSlots < m.cfg.Sources.DownloadSlots {
 		started := false
 		for f := range m.shuffledActiveFeeds() {
+			// Is the feed allowed to download now?
+			if !f.downloadsAllowed(now) {
+				continue
+			}
 			// Has this feed a free slot?
 			maxSlots := min(m.cfg.Sources.MaxSlotsPerSource, m.cfg.Sources.DownloadSlots)
 			if f.source.slots != nil {
@@ -311,14 +353,19 @@ func (m *Manager) startDownloads() {
 				continue
 			}
 			// Find a candidate to download.
-			loc := f.findWaiting()
+			loc := f.findWaiting(now)
 			if loc == nil {
+				// A fetch bypassing the schedule is done.
+				if f.fetchNow && !f.refreshBlocked {
+					f.fetchNow = false
+				}
 				continue
 			}
 			m.usedSlots++
 			f.source.usedSlots++
 			loc.state = running
 			loc.id = m.generateID()
+			f.setQueued(ctx, m.db, loc, "running", nil)
 			started = true
 			m.jobs <- downloadJob{l: *loc, f: f}
 			if m.usedSlots >= m.cfg.Sources.DownloadSlots {
@@ -331,21 +378,32 @@ func (m *Manager) startDownloads() {
 	}
 }
 
-func (dj *downloadJob) finish(m *Manager) {
-	m.fns <- func(m *Manager, _ context.Context) {
+func (dj *downloadJob) finish(m *Manager, err error) {
+	m.fns <- func(m *Manager, ctx context.Context) {
 		dj.f.source.usedSlots = max(0, dj.f.source.usedSlots-1)
 		m.usedSlots = max(0, m.usedSlots-1)
-		if l := dj.f.findLocationByID(dj.l.id); l != nil {
-			l.state = done
+		l := dj.f.findLocationByID(dj.l.id)
+		if l == nil {
+			// Not in the queue any more so only update the database.
+			l = &dj.l
 		}
+		if err != nil {
+			dj.f.failed(ctx, m, err)
+			dj.f.downloadFailed(ctx, m, l, err)
+			return
+		}
+		dj.f.succeeded(m)
+		dj.f.recent.checked(dj.l.status != allSucceeded)
+		l.state = done
+		dj.f.dequeue(ctx, m.db, l)
 	}
 }
 
 func (m *Manager) download(wg *sync.WaitGroup) {
 	defer wg.Done()
 	for job := range m.jobs {
-		job.l.download(m, job.f)
-		job.finish(m)
+		err := job.l.download(m, job.f)
+		job.finish(m, err)
 	}
 }
 
@@ -389,7 +447,7 @@ out:
 		m.keysCache.Cleanup()
 		m.compactDone()
 		m.refreshFeeds()
-		m.startDownloads()
+		m.startDownloads(ctx)
 		select {
 		case fn := <-m.fns:
 			fn(m, ctx)
@@ -442,6 +500,8 @@ type prefetchedPMD struct {
 	id       int64
 	url      string
 	checksum []byte
+	document []byte
+	hash     []byte
 }
 
 func (m *Manager) checkSources() {
@@ -476,9 +536,16 @@ func (m *Manager) checkSources() {
 				slog.Warn("invalid PMD model", "url", s.url, "id", s.id, "err", err)
 				continue
 			}
+			document, hash, err := cpmd.document()
+			if err != nil {
+				slog.Warn("cannot serialize PMD", "url", s.url, "id", s.id, "err", err)
+				continue
+			}
 			prefetched = append(prefetched, prefetchedPMD{
 				id:       s.id,
 				checksum: checksumPMD(pmd),
+				document: document,
+				hash:     hash,
 			})
 		}
 		// Run the real checking in the manager.
@@ -508,6 +575,8 @@ func (m *Manager) realCheckSources(ctx context.Context, prefetched []prefetchedP
 			// Should not happen!
 			continue
 		}
+		// Keep the history of the PMD.
+		updates.Queue(storePMDSQL, pre.id, now, pre.hash, pre.document)
 		if !bytes.Equal(pre.checksum, s.checksum) {
 			updates.Queue(sql, pre.checksum, now, pre.id)
 			apply = append(apply, func() {
@@ -516,7 +585,6 @@ func (m *Manager) realCheckSources(ctx context.Context, prefetched []prefetchedP
 			})
 		}
 	}
-	// Only send updates if there where changes.
 	if updates.Len() > 0 {
 		if err := m.db.Run(
 			ctx,
@@ -574,7 +642,19 @@ func (m *Manager) Source(id int64, stats bool) *SourceInfo {
 			HasClientCertPublic:     s.clientCertPublic != nil,
 			HasClientCertPrivate:    s.clientCertPrivate != nil,
 			HasClientCertPassphrase: s.clientCertPassphrase != nil,
+			Auth:                    s.authMode,
+			OAuth2TokenURL:          s.oauth2TokenURL,
+			OAuth2ClientID:          s.oauth2ClientID,
+			OAuth2Scopes:            s.oauth2Scopes,
+			HasBearerToken:          s.bearerToken != nil,
+			HasOAuth2ClientSecret:   s.oauth2ClientSecret != nil,
+			Token:                   s.tokenState(),
+			Proxy:                   s.proxy,
+			HasProxyCredentials:     s.proxyCredentials != nil,
+			Schedule:                s.schedule,
+			NextRun:                 s.nextRun(),
 			Stats:                   st,
+			Health:                  s.health(m),
 		}
 	}
 	return <-siCh
@@ -604,7 +684,7 @@ func (m *Manager) Subscriptions(urls []string) []SourceSubscriptions {
 	}
 	// Resolving external PMDs is too time consuming for the
 	// manager run loop. So do it before.
-	rps.resolve(m.pmdCache, m.cfg)
+	rps.resolve(m)
 
 	// We can subscribe a source more than once.
 	sources := make(map[string][]int64, len(urlIDs))
@@ -684,7 +764,19 @@ func (m *Manager) Sources(fn func(*SourceInfo), stats bool) {
 				HasClientCertPublic:     s.clientCertPublic != nil,
 				HasClientCertPrivate:    s.clientCertPrivate != nil,
 				HasClientCertPassphrase: s.clientCertPassphrase != nil,
+				Auth:                    s.authMode,
+				OAuth2TokenURL:          s.oauth2TokenURL,
+				OAuth2ClientID:          s.oauth2ClientID,
+				OAuth2Scopes:            s.oauth2Scopes,
+				HasBearerToken:          s.bearerToken != nil,
+				HasOAuth2ClientSecret:   s.oauth2ClientSecret != nil,
+				Token:                   s.tokenState(),
+				Proxy:                   s.proxy,
+				HasProxyCredentials:     s.proxyCredentials != nil,
+				Schedule:                s.schedule,
+				NextRun:                 s.nextRun(),
 				Stats:                   st,
+				Health:                  s.health(m),
 			}
 			fn(si)
 		}
@@ -711,12 +803,15 @@ func (m *Manager) Feeds(sourceID int64, fn func(*FeedInfo), stats bool) error {
 				f.addStats(st)
 			}
 			*fi = FeedInfo{
-				ID:    f.id,
-				Label: f.label,
-				URL:   f.url,
-				Rolie: f.rolie,
-				Lvl:   config.FeedLogLevel(f.logLevel.Load()),
-				Stats: st,
+				ID:       f.id,
+				Label:    f.label,
+				URL:      f.url,
+				Rolie:    f.rolie,
+				Lvl:      config.FeedLogLevel(f.logLevel.Load()),
+				Schedule: f.schedule,
+				NextRun:  f.nextRun(),
+				Stats:    st,
+				Health:   f.health(m),
 			}
 			fn(fi)
 		}
@@ -740,12 +835,15 @@ func (m *Manager) Feed(feedID int64, stats bool) *FeedInfo {
 			f.addStats(st)
 		}
 		fiCh <- &FeedInfo{
-			ID:    f.id,
-			Label: f.label,
-			URL:   f.url,
-			Rolie: f.rolie,
-			Lvl:   config.FeedLogLevel(f.logLevel.Load()),
-			Stats: st,
+			ID:       f.id,
+			Label:    f.label,
+			URL:      f.url,
+			Rolie:    f.rolie,
+			Lvl:      config.FeedLogLevel(f.logLevel.Load()),
+			Schedule: f.schedule,
+			NextRun:  f.nextRun(),
+			Stats:    st,
+			Health:   f.health(m),
 		}
 	}
 	return <-fiCh
@@ -920,14 +1018,22 @@ func (m *Manager) removeSource(ctx context.Context, sourceID int64) error {
 	); err != nil {
 		return fmt.Errorf("deleting source from db failed: %w", err)
 	}
+	if err := m.removeSecrets(ctx, sourceID); err != nil {
+		slog.Error("removing secrets of source failed", "source", sourceID, "err", err)
+	}
+	var removed *source
 	m.sources = slices.DeleteFunc(m.sources, func(s *source) bool {
 		if s.id == sourceID {
+			removed = s
 			s.active = false
 			s.feeds = nil
 			return true
 		}
 		return false
 	})
+	if removed != nil {
+		m.unregisterProxy(removed.url)
+	}
 	// XXX: Should not happen!
 	if notFound {
 		return NoSuchEntryError("no such source")
@@ -988,11 +1094,29 @@ func (m *Manager) AddSource(
 	signatureCheck *bool,
 	age *time.Duration,
 	ignorePatterns []*regexp.Regexp,
+	sched schedule.Schedule,
 	clientCertPublic []byte,
 	clientCertPrivate []byte,
 	clientCertPassphrase []byte,
+	auth *Auth,
+	proxy *Proxy,
 ) (int64, error) {
-	cpmd := m.PMD(url)
+	if auth == nil {
+		auth = &Auth{}
+	}
+	if err := auth.validate(); err != nil {
+		return 0, err
+	}
+	if proxy == nil {
+		proxy = &Proxy{}
+	}
+	if proxy.URL != nil {
+		if err := ValidProxy(*proxy.URL); err != nil {
+			return 0, err
+		}
+	}
+	// The source may only be reachable over its own proxy.
+	cpmd := m.pmdCache.pmd(url, m.cfg, proxyConfig(m.cfg, proxy.URL, proxy.Credentials))
 	if !cpmd.Valid() {
 		return 0, InvalidArgumentError("PMD is invalid")
 	}
@@ -1000,6 +1124,10 @@ func (m *Manager) AddSource(
 	if err != nil {
 		return 0, InvalidArgumentError("PMD model is invalid")
 	}
+	document, hash, err := cpmd.document()
+	if err != nil {
+		return 0, InvalidArgumentError("PMD cannot be serialized")
+	}
 	now := time.Now().UTC()
 	errCh := make(chan error)
 	s := &source{
@@ -1013,56 +1141,81 @@ func (m *Manager) AddSource(
 		signatureCheck:       signatureCheck,
 		age:                  age,
 		ignorePatterns:       ignorePatterns,
+		schedule:             sched,
 		clientCertPublic:     clientCertPublic,
 		clientCertPrivate:    clientCertPrivate,
 		clientCertPassphrase: clientCertPassphrase,
+		authMode:             auth.Mode,
+		oauth2TokenURL:       auth.TokenURL,
+		oauth2ClientID:       auth.ClientID,
+		oauth2Scopes:         auth.Scopes,
+		bearerToken:          auth.BearerToken,
+		oauth2ClientSecret:   auth.ClientSecret,
+		proxy:                proxy.URL,
+		proxyCredentials:     proxy.Credentials,
 		checksum:             checksumPMD(model),
 		checksumAck:          now.Add(-time.Second),
 		checksumUpdated:      now,
 	}
-	if clientCertPrivate != nil {
-		var err error
-		if clientCertPrivate, err = m.encrypt(clientCertPrivate); err != nil {
-			return 0, err
-		}
-	}
-	if clientCertPassphrase != nil {
-		var err error
-		if clientCertPassphrase, err = m.encrypt(clientCertPassphrase); err != nil {
-			return 0, err
-		}
-	}
 	m.fns <- func(m *Manager, ctx context.Context) {
 		if m.findSourceByName(name) != nil {
 			errCh <- InvalidArgumentError("source already exists")
 			return
 		}
+		// The headers are only stored here if they are no secrets.
+		var plainHeaders []string
+		if m.plainHeaders() {
+			plainHeaders = s.headers
+		}
 		const sql = `INSERT INTO sources (` +
-			`name, url, rate, slots, headers, ` +
+			`name, url, rate, slots, ` +
 			`strict_mode, secure, signature_check, age, ignore_patterns, ` +
-			`client_cert_public, client_cert_private, client_cert_passphrase, ` +
-			`checksum, checksum_ack, checksum_updated) ` +
+			`client_cert_public, ` +
+			`checksum, checksum_ack, checksum_updated, schedule, ` +
+			`auth, oauth2_token_url, oauth2_client_id, oauth2_scopes, proxy, ` +
+			`headers) ` +
 			`VALUES (` +
-			`$1, $2, $3, $4, $5, ` +
-			`$6, $7, $8, $9, $10, ` +
-			`$11, $12, $13, ` +
-			`$14, $15, $16) ` +
+			`$1, $2, $3, $4, ` +
+			`$5, $6, $7, $8, $9, ` +
+			`$10, ` +
+			`$11, $12, $13, $14, ` +
+			`$15::source_auth_modes, $16, $17, $18, $19, ` +
+			`$20) ` +
 			`RETURNING id`
 		if err := m.db.Run(
 			ctx,
 			func(rctx context.Context, con *pgxpool.Conn) error {
-				return con.QueryRow(rctx, sql,
-					name, url, rate, slots, headers,
+				tx, err := con.Begin(rctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback(rctx)
+				if err := tx.QueryRow(rctx, sql,
+					name, url, rate, slots,
 					strictMode, secure, signatureCheck, age, ignorePatterns,
-					clientCertPublic, clientCertPrivate, clientCertPassphrase,
-					s.checksum, s.checksumAck, s.checksumUpdated,
-				).Scan(&s.id)
+					clientCertPublic,
+					s.checksum, s.checksumAck, s.checksumUpdated, schedule.String(sched),
+					auth.Mode.String(), auth.TokenURL, auth.ClientID, auth.Scopes, proxy.URL,
+					plainHeaders,
+				).Scan(&s.id); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(rctx, storePMDSQL, s.id, now, hash, document); err != nil {
+					return err
+				}
+				// The source is not committed if its secrets cannot be stored.
+				if err := m.storeSecrets(rctx, s); err != nil {
+					m.removeSecrets(rctx, s.id)
+					return err
+				}
+				return tx.Commit(rctx)
 			}, 0,
 		); err != nil {
 			errCh <- fmt.Errorf("adding source to database failed: %w", err)
 			return
 		}
 		m.sources = append(m.sources, s)
+		m.registerProxy(s)
 		errCh <- nil
 	}
 	return s.id, <-errCh
@@ -1074,6 +1227,7 @@ func (m *Manager) AddFeed(
 	label string,
 	url *url.URL,
 	logLevel config.FeedLogLevel,
+	sched schedule.Schedule,
 ) (int64, error) {
 	var feedID int64
 	errCh := make(chan error)
@@ -1101,8 +1255,8 @@ func (m *Manager) AddFeed(
 			errCh <- InvalidArgumentError("feed is neither ROLIE nor directory based")
 			return
 		}
-		const sql = `INSERT INTO feeds (label, sources_id, url, rolie, log_lvl) ` +
-			`VALUES ($1, $2, $3, $4, $5::feed_logs_level) ` +
+		const sql = `INSERT INTO feeds (label, sources_id, url, rolie, log_lvl, schedule) ` +
+			`VALUES ($1, $2, $3, $4, $5::feed_logs_level, $6) ` +
 			`RETURNING id`
 		if err := m.db.Run(
 			ctx,
@@ -1113,6 +1267,7 @@ func (m *Manager) AddFeed(
 					url.String(),
 					rolie,
 					logLevel,
+					schedule.String(sched),
 				).Scan(&feedID)
 			}, 0,
 		); err != nil {
@@ -1120,11 +1275,12 @@ func (m *Manager) AddFeed(
 			return
 		}
 		f := &feed{
-			id:     feedID,
-			label:  label,
-			url:    url,
-			rolie:  rolie,
-			source: s,
+			id:       feedID,
+			label:    label,
+			url:      url,
+			rolie:    rolie,
+			source:   s,
+			schedule: sched,
 		}
 		f.logLevel.Store(int32(logLevel))
 		s.feeds = append(s.feeds, f)
@@ -1151,7 +1307,7 @@ func (m *Manager) RemoveFeed(feedID int64) error {
 
 // PMD returns the provider metadata from the given url.
 func (m *Manager) PMD(url string) *CachedProviderMetadata {
-	return m.pmdCache.pmd(url, m.cfg)
+	return m.pmdCache.pmd(url, m.cfg, m.PMDProxy(url))
 }
 
 // updater collects updates so that only the first update on
@@ -1221,8 +1377,11 @@ func placeholders(n int) string {
 // (with X in Name, Rate, ...) methods to update specific fields.
 type SourceUpdater struct {
 	updater[*source]
+	secrets           []pendingSecret
+	auth              *Auth
 	clientCertUpdated bool
 	doBackgroundPing  bool
+	queuesChanged     bool
 }
 
 // applyChanges overwrites base to only issue one background ping.
@@ -1325,7 +1484,11 @@ func (su *SourceUpdater) UpdateHeaders(headers []string) error {
 		return nil
 	}
 	headers = clone(headers)
-	su.addChange(func(s *source) { s.headers = headers }, "headers", headers)
+	if su.manager.plainHeaders() {
+		su.addChange(func(s *source) { s.headers = headers }, "headers", headers)
+		return nil
+	}
+	su.addSecret(func(s *source) { s.headers = headers }, headersSecret, encodeHeaders(headers))
 	return nil
 }
 
@@ -1349,7 +1512,10 @@ func (su *SourceUpdater) UpdateSecure(secure *bool) error {
 	if su.updatable.secure != nil && secure != nil && *su.updatable.secure == *secure {
 		return nil
 	}
-	su.addChange(func(s *source) { s.secure = secure }, "secure", secure)
+	su.addChange(func(s *source) {
+		s.secure = secure
+		s.resetAuth()
+	}, "secure", secure)
 	return nil
 }
 
@@ -1376,6 +1542,7 @@ func (su *SourceUpdater) UpdateAge(age *time.Duration) error {
 	su.addChange(func(s *source) {
 		s.setAge(age)
 		su.doBackgroundPing = true
+		su.queuesChanged = true
 	}, "age", age)
 	return nil
 }
@@ -1387,7 +1554,23 @@ func (su *SourceUpdater) UpdateIgnorePatterns(ignorePatterns []*regexp.Regexp) e
 		return nil
 	}
 	ignorePatterns = clone(ignorePatterns)
-	su.addChange(func(s *source) { s.setIgnorePatterns(ignorePatterns) }, "ignore_patterns", ignorePatterns)
+	su.addChange(func(s *source) {
+		s.setIgnorePatterns(ignorePatterns)
+		su.queuesChanged = true
+	}, "ignore_patterns", ignorePatterns)
+	return nil
+}
+
+// UpdateSchedule requests an update on the schedule.
+func (su *SourceUpdater) UpdateSchedule(sched schedule.Schedule) error {
+	if equalSchedules(su.updatable.schedule, sched) {
+		return nil
+	}
+	su.addChange(func(s *source) {
+		s.schedule = sched
+		s.resetSchedule()
+		su.doBackgroundPing = true
+	}, "schedule", schedule.String(sched))
 	return nil
 }
 
@@ -1416,15 +1599,11 @@ func (su *SourceUpdater) UpdateClientCertPrivate(data []byte) error {
 	if data != nil && orig != nil && slices.Equal(data, orig) {
 		return nil
 	}
-	encrypted, err := su.manager.encrypt(data)
-	if err != nil {
-		return err
-	}
 	data = clone(data)
-	su.addChange(func(s *source) {
+	su.addSecret(func(s *source) {
 		su.clientCertUpdated = true
 		s.clientCertPrivate = data
-	}, "client_cert_private", encrypted)
+	}, clientCertPrivateSecret, data)
 	return nil
 }
 
@@ -1437,15 +1616,11 @@ func (su *SourceUpdater) UpdateClientCertPassphrase(data []byte) error {
 	if data != nil && orig != nil && slices.Equal(data, orig) {
 		return nil
 	}
-	encrypted, err := su.manager.encrypt(data)
-	if err != nil {
-		return err
-	}
 	data = clone(data)
-	su.addChange(func(s *source) {
+	su.addSecret(func(s *source) {
 		su.clientCertUpdated = true
 		s.clientCertPassphrase = data
-	}, "client_cert_passphrase", encrypted)
+	}, clientCertPassphraseSecret, data)
 	return nil
 }
 
@@ -1473,6 +1648,15 @@ func (m *Manager) UpdateSource(
 			resCh <- result{err: fmt.Errorf("updates failed: %w", err)}
 			return
 		}
+		// Switching the auth mode may leave the settings incomplete.
+		if err := su.validateAuth(); err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		if err := su.storeSecrets(ctx); err != nil {
+			resCh <- result{err: err}
+			return
+		}
 		if err := su.updateDB(ctx, "sources", s.id); err != nil {
 			resCh <- result{err: fmt.Errorf("updating database failed: %w", err)}
 			return
@@ -1482,7 +1666,11 @@ func (m *Manager) UpdateSource(
 			resCh <- result{v: SourceUnchanged}
 			return
 		}
+		if su.queuesChanged {
+			s.syncQueues(ctx, m.db)
+		}
 		if su.clientCertUpdated {
+			s.resetAuth()
 			if err := s.updateCertificate(); err != nil {
 				slog.Warn("updating client cert failed", "warn", err)
 				if s.active {
@@ -1535,6 +1723,19 @@ func (fu *FeedUpdater) UpdateLabel(label string) error {
 	return nil
 }
 
+// UpdateSchedule requests an update on the schedule of the feed.
+func (fu *FeedUpdater) UpdateSchedule(sched schedule.Schedule) error {
+	if equalSchedules(fu.updatable.schedule, sched) {
+		return nil
+	}
+	fu.addChange(func(f *feed) {
+		f.schedule = sched
+		f.nextCheck = time.Time{}
+		fu.manager.backgroundPing()
+	}, "schedule", schedule.String(sched))
+	return nil
+}
+
 // UpdateFeed passes an updater to manipulate a feed with a given id to a given callback.
 func (m *Manager) UpdateFeed(
 	feedID int64,
@@ -1570,6 +1771,50 @@ func (m *Manager) UpdateFeed(
 	return res.updated, res.err
 }
 
+// FetchSource refreshes the feeds of an active source and downloads
+// the new entries bypassing the schedules.
+func (m *Manager) FetchSource(sourceID int64) error {
+	errCh := make(chan error)
+	m.fns <- func(m *Manager, _ context.Context) {
+		s := m.findSourceByID(sourceID)
+		switch {
+		case s == nil:
+			errCh <- NoSuchEntryError("no such source")
+			return
+		case !s.active:
+			errCh <- InvalidArgumentError("source is not active")
+			return
+		}
+		for _, f := range s.feeds {
+			if !f.invalid.Load() {
+				f.fetch()
+			}
+		}
+		errCh <- nil
+	}
+	return <-errCh
+}
+
+// FetchFeed refreshes a feed of an active source and downloads
+// the new entries bypassing the schedules.
+func (m *Manager) FetchFeed(feedID int64) error {
+	errCh := make(chan error)
+	m.fns <- func(m *Manager, _ context.Context) {
+		f := m.findFeedByID(feedID)
+		switch {
+		case f == nil || f.invalid.Load():
+			errCh <- NoSuchEntryError("no such feed")
+			return
+		case !f.source.active:
+			errCh <- InvalidArgumentError("source is not active")
+			return
+		}
+		f.fetch()
+		errCh <- nil
+	}
+	return <-errCh
+}
+
 // AttentionSources calls given callback for each active source which needs attention.
 // If the all flag is not set only the active sources are evaluated.
 func (m *Manager) AttentionSources(all bool, fn func(id int64, name string)) {
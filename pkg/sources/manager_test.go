@@ -0,0 +1,217 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/csafmock"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+)
+
+// testDatabaseEnv names the scratch database for the tests which
+// need a PostgreSQL. The other connection parameters are taken
+// from the ISDUBA_DB_* variables.
+const testDatabaseEnv = "ISDUBA_TEST_DB_DATABASE"
+
+// testManager returns a running manager on a migrated scratch database.
+// The test is skipped if no scratch database is configured.
+func testManager(t *testing.T) (*Manager, *database.DB) {
+	t.Helper()
+	name := os.Getenv(testDatabaseEnv)
+	if name == "" {
+		t.Skipf("%s not set", testDatabaseEnv)
+	}
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Database.Database = name
+	cfg.Database.Migrate = true
+	cfg.Database.TerminateAfterMigration = false
+	// The mock provider is served on the local host on a random port.
+	cfg.General.BlockLoopback = false
+	cfg.General.BlockedRanges = []config.IPRange{}
+	cfg.General.AllowedPorts = []config.PortRange{}
+	cfg.Sources.Secure = false
+	cfg.Sources.DefaultAge = 0
+	cfg.Sources.StrictMode = true
+	cfg.Sources.SignatureCheck = true
+	cfg.Sources.Quarantine = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if _, err := database.CheckMigrations(ctx, &cfg.Database); err != nil {
+		t.Fatalf("migrating failed: %v", err)
+	}
+	db, err := database.NewDB(ctx, &cfg.Database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close(context.Background()) })
+
+	m := NewManager(cfg, db, nil, secrets.NewFiles(t.TempDir()))
+	if err := m.Boot(ctx); err != nil {
+		t.Fatalf("booting failed: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return m, db
+}
+
+// quarantined are the failed checks of a quarantined document.
+type quarantined struct {
+	checksumFailed  bool
+	signatureFailed bool
+}
+
+func TestManagerFetchFaults(t *testing.T) {
+	m, db := testManager(t)
+
+	docs, err := csafmock.LoadDocuments("../../docs/example-advisories")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A third document which passes all checks.
+	var clean *csafmock.Document
+	for _, doc := range docs {
+		if doc.Summary.ID == "Avendor-advisory-0005" {
+			data := bytes.ReplaceAll(doc.Data,
+				[]byte("Avendor-advisory-0005"), []byte("Avendor-advisory-0006"))
+			if clean, err = csafmock.NewDocument(data); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if clean == nil {
+		t.Fatal("Avendor-advisory-0005 not found")
+	}
+	docs = append(docs, clean)
+
+	provider, err := csafmock.NewProvider(docs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.Faults.BadHashes = regexp.MustCompile(`0004\.json$`)
+	provider.Faults.BadSignatures = regexp.MustCompile(`0005\.json$`)
+	server := httptest.NewTLSServer(provider)
+	defer server.Close()
+	// The PMD is loaded with the system roots. This only works if
+	// they were not used before in this test binary.
+	certFile := filepath.Join(t.TempDir(), "csafmock.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(certFile, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SSL_CERT_FILE", certFile)
+
+	sourceID, err := m.ProvisionSource(
+		server.URL+csafmock.ProviderMetadataPath,
+		&config.ProvisioningTemplate{
+			TLPs:     []models.TLP{models.TLPWhite},
+			Activate: true,
+		})
+	if err != nil {
+		t.Fatalf("provisioning failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.RemoveSource(sourceID); err != nil {
+			t.Errorf("removing source failed: %v", err)
+		}
+	})
+	if err := m.FetchSource(sourceID); err != nil {
+		t.Fatalf("fetching failed: %v", err)
+	}
+
+	const (
+		downloadsSQL = `SELECT count(*), ` +
+			`count(*) FILTER (WHERE NOT (checksum_failed OR signature_failed)) ` +
+			`FROM downloads JOIN feeds ON downloads.feeds_id = feeds.id ` +
+			`WHERE feeds.sources_id = $1`
+		quarantineSQL = `SELECT tracking_id, checksum_failed, signature_failed ` +
+			`FROM quarantine WHERE starts_with(url, $1)`
+	)
+	var (
+		total, passed int
+		quarantine    map[string]quarantined
+	)
+	ctx := context.Background()
+	for deadline := time.Now().Add(30 * time.Second); ; {
+		if err := db.Run(ctx, func(rctx context.Context, conn *pgxpool.Conn) error {
+			if err := conn.QueryRow(rctx, downloadsSQL, sourceID).Scan(&total, &passed); err != nil {
+				return err
+			}
+			rows, err := conn.Query(rctx, quarantineSQL, server.URL)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			quarantine = map[string]quarantined{}
+			for rows.Next() {
+				var (
+					id string
+					q  quarantined
+				)
+				if err := rows.Scan(&id, &q.checksumFailed, &q.signatureFailed); err != nil {
+					return err
+				}
+				quarantine[id] = q
+			}
+			return rows.Err()
+		}, 0); err != nil {
+			t.Fatal(err)
+		}
+		if total >= len(docs) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if total != len(docs) {
+		t.Fatalf("have %d downloads want %d", total, len(docs))
+	}
+	if passed != 1 {
+		t.Errorf("have %d passing downloads want 1", passed)
+	}
+	for id, want := range map[string]quarantined{
+		"Avendor-advisory-0004": {checksumFailed: true},
+		"Avendor-advisory-0005": {signatureFailed: true},
+	} {
+		have, ok := quarantine[id]
+		switch {
+		case !ok:
+			t.Errorf("%s is not quarantined", id)
+		case have != want:
+			t.Errorf("%s: have %+v want %+v", id, have, want)
+		}
+	}
+	if _, ok := quarantine["Avendor-advisory-0006"]; ok {
+		t.Error("Avendor-advisory-0006 is quarantined")
+	}
+}
@@ -11,6 +11,7 @@ package sources
 import (
 	"context"
 	"crypto/sha1"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -52,7 +53,7 @@ func newPMDCache() *pmdCache {
 	}
 }
 
-func (pc *pmdCache) pmd(url string, cfg *config.Config) *CachedProviderMetadata {
+func (pc *pmdCache) pmd(url string, cfg *config.Config, proxy *config.Proxy) *CachedProviderMetadata {
 
 	if cpmd, ok := pc.Get(url); ok {
 		return cpmd
@@ -62,7 +63,7 @@ func (pc *pmdCache) pmd(url string, cfg *config.Config) *CachedProviderMetadata
 	header.Add("User-Agent", UserAgent)
 
 	baseClient := &http.Client{
-		Transport: cfg.General.Transport(),
+		Transport: cfg.General.TransportVia(proxy),
 	}
 	if timeout := cfg.Sources.Timeout; timeout > 0 {
 		baseClient.Timeout = timeout
@@ -113,6 +114,21 @@ func (cpmd *CachedProviderMetadata) Model() (*csaf.ProviderMetadata, error) {
 	return model, nil
 }
 
+// storePMDSQL stores a version of a PMD if it differs from the latest stored one.
+const storePMDSQL = `INSERT INTO source_pmds (sources_id, fetched, checksum, document) ` +
+	`SELECT $1::int, $2::timestamptz, $3::bytea, $4::jsonb ` +
+	`WHERE $3::bytea IS DISTINCT FROM (` +
+	`SELECT checksum FROM source_pmds WHERE sources_id = $1 ORDER BY id DESC LIMIT 1)`
+
+// document returns the loaded PMD as JSON together with its checksum.
+func (cpmd *CachedProviderMetadata) document() ([]byte, []byte, error) {
+	data, err := json.Marshal(cpmd.Loaded.Document)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, cpmd.Loaded.Hash, nil
+}
+
 // availableFeeds returns a list of the feeds available for the given provider.
 func availableFeeds(pmd *csaf.ProviderMetadata) []string {
 	var feeds []string
@@ -195,7 +211,7 @@ func (rps *resolvedPMDs) add(urls ...string) {
 const numURLResolvers = 5
 
 // resolve resolves all urls added with add to PMDs.
-func (rps resolvedPMDs) resolve(cache *pmdCache, cfg *config.Config) {
+func (rps resolvedPMDs) resolve(m *Manager) {
 	var (
 		wg        sync.WaitGroup
 		toResolve = make(chan *resolvedPMD)
@@ -203,7 +219,7 @@ func (rps resolvedPMDs) resolve(cache *pmdCache, cfg *config.Config) {
 	worker := func() {
 		defer wg.Done()
 		for tr := range toResolve {
-			cpmd := cache.pmd(tr.url, cfg)
+			cpmd := m.PMD(tr.url)
 			if !cpmd.Valid() {
 				slog.Debug("Invalid PMD", "url", tr.url)
 				continue
@@ -0,0 +1,206 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocsaf/csaf/v3/csaf"
+)
+
+// Change is a changed value.
+type Change[T any] struct {
+	From T `json:"from"`
+	To   T `json:"to"`
+}
+
+// PMDFeed is a ROLIE feed of a PMD.
+type PMDFeed struct {
+	URL     string `json:"url"`
+	TLP     string `json:"tlp,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// PMDKey is a public OpenPGP key of a PMD.
+type PMDKey struct {
+	Fingerprint string `json:"fingerprint,omitempty"`
+	URL         string `json:"url"`
+}
+
+// PMDDiff is the structural difference between two versions of a PMD.
+type PMDDiff struct {
+	CanonicalURL       *Change[string]   `json:"canonical_url,omitempty"`
+	Role               *Change[string]   `json:"role,omitempty"`
+	Publisher          *Change[string]   `json:"publisher,omitempty"`
+	LastUpdated        *Change[string]   `json:"last_updated,omitempty"`
+	AddedFeeds         []PMDFeed         `json:"added_feeds,omitempty"`
+	RemovedFeeds       []PMDFeed         `json:"removed_feeds,omitempty"`
+	ChangedFeeds       []Change[PMDFeed] `json:"changed_feeds,omitempty"`
+	AddedDirectories   []string          `json:"added_directories,omitempty"`
+	RemovedDirectories []string          `json:"removed_directories,omitempty"`
+	AddedServices      []string          `json:"added_services,omitempty"`
+	RemovedServices    []string          `json:"removed_services,omitempty"`
+	AddedCategories    []string          `json:"added_categories,omitempty"`
+	RemovedCategories  []string          `json:"removed_categories,omitempty"`
+	AddedKeys          []PMDKey          `json:"added_keys,omitempty"`
+	RemovedKeys        []PMDKey          `json:"removed_keys,omitempty"`
+	ChangedKeys        []Change[PMDKey]  `json:"changed_keys,omitempty"`
+}
+
+// pmdParts are the parts of a PMD which are compared.
+type pmdParts struct {
+	canonicalURL string
+	role         string
+	publisher    string
+	lastUpdated  string
+	feeds        []PMDFeed
+	directories  []string
+	services     []string
+	categories   []string
+	keys         []PMDKey
+}
+
+func deref[T ~string](s *T) string {
+	if s == nil {
+		return ""
+	}
+	return string(*s)
+}
+
+// unique sorts a slice and removes the duplicates.
+func unique(s []string) []string {
+	slices.Sort(s)
+	return slices.Compact(s)
+}
+
+func newPMDParts(pmd *csaf.ProviderMetadata) *pmdParts {
+	parts := &pmdParts{
+		canonicalURL: deref(pmd.CanonicalURL),
+		role:         deref(pmd.Role),
+	}
+	if pmd.Publisher != nil {
+		parts.publisher = deref(pmd.Publisher.Name)
+		if ns := deref(pmd.Publisher.Namespace); ns != "" {
+			parts.publisher += " (" + ns + ")"
+		}
+	}
+	if pmd.LastUpdated != nil {
+		parts.lastUpdated = time.Time(*pmd.LastUpdated).UTC().Format(time.RFC3339)
+	}
+	for i := range pmd.Distributions {
+		d := &pmd.Distributions[i]
+		if d.DirectoryURL != "" {
+			parts.directories = append(parts.directories, d.DirectoryURL)
+		}
+		if d.Rolie == nil {
+			continue
+		}
+		for j := range d.Rolie.Feeds {
+			f := &d.Rolie.Feeds[j]
+			parts.feeds = append(parts.feeds, PMDFeed{
+				URL:     deref(f.URL),
+				TLP:     deref(f.TLPLabel),
+				Summary: f.Summary,
+			})
+		}
+		for _, s := range d.Rolie.Services {
+			parts.services = append(parts.services, string(s))
+		}
+		for _, c := range d.Rolie.Categories {
+			parts.categories = append(parts.categories, string(c))
+		}
+	}
+	for i := range pmd.PGPKeys {
+		k := &pmd.PGPKeys[i]
+		parts.keys = append(parts.keys, PMDKey{
+			Fingerprint: strings.ToUpper(string(k.Fingerprint)),
+			URL:         deref(k.URL),
+		})
+	}
+	parts.directories = unique(parts.directories)
+	parts.services = unique(parts.services)
+	parts.categories = unique(parts.categories)
+	return parts
+}
+
+// change returns a change if the values differ.
+func change(from, to string) *Change[string] {
+	if from == to {
+		return nil
+	}
+	return &Change[string]{From: from, To: to}
+}
+
+// diffStrings returns the added and removed strings of two sorted sets.
+func diffStrings(from, to []string) (added, removed []string) {
+	for _, s := range to {
+		if _, found := slices.BinarySearch(from, s); !found {
+			added = append(added, s)
+		}
+	}
+	for _, s := range from {
+		if _, found := slices.BinarySearch(to, s); !found {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// diffBy compares two lists of elements identified by a key.
+func diffBy[T comparable](from, to []T, key func(T) string) (added, removed []T, changed []Change[T]) {
+	index := func(list []T) map[string]T {
+		m := make(map[string]T, len(list))
+		for _, e := range list {
+			m[key(e)] = e
+		}
+		return m
+	}
+	fromIdx, toIdx := index(from), index(to)
+	for k, t := range toIdx {
+		switch f, found := fromIdx[k]; {
+		case !found:
+			added = append(added, t)
+		case f != t:
+			changed = append(changed, Change[T]{From: f, To: t})
+		}
+	}
+	for k, f := range fromIdx {
+		if _, found := toIdx[k]; !found {
+			removed = append(removed, f)
+		}
+	}
+	byKey := func(a, b T) int { return cmp.Compare(key(a), key(b)) }
+	slices.SortFunc(added, byKey)
+	slices.SortFunc(removed, byKey)
+	slices.SortFunc(changed, func(a, b Change[T]) int { return byKey(a.To, b.To) })
+	return added, removed, changed
+}
+
+// DiffPMDs returns the structural difference between two versions of a PMD.
+func DiffPMDs(from, to *csaf.ProviderMetadata) *PMDDiff {
+	f, t := newPMDParts(from), newPMDParts(to)
+	diff := &PMDDiff{
+		CanonicalURL: change(f.canonicalURL, t.canonicalURL),
+		Role:         change(f.role, t.role),
+		Publisher:    change(f.publisher, t.publisher),
+		LastUpdated:  change(f.lastUpdated, t.lastUpdated),
+	}
+	diff.AddedFeeds, diff.RemovedFeeds, diff.ChangedFeeds = diffBy(
+		f.feeds, t.feeds, func(f PMDFeed) string { return f.URL })
+	diff.AddedDirectories, diff.RemovedDirectories = diffStrings(f.directories, t.directories)
+	diff.AddedServices, diff.RemovedServices = diffStrings(f.services, t.services)
+	diff.AddedCategories, diff.RemovedCategories = diffStrings(f.categories, t.categories)
+	// Keys without fingerprints are identified by their URL.
+	diff.AddedKeys, diff.RemovedKeys, diff.ChangedKeys = diffBy(
+		f.keys, t.keys, func(k PMDKey) string { return cmp.Or(k.Fingerprint, k.URL) })
+	return diff
+}
@@ -0,0 +1,189 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocsaf/csaf/v3/csaf"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// provisionedFeed is a feed to be created for a provisioned source.
+type provisionedFeed struct {
+	label string
+	url   *url.URL
+}
+
+// templateFeeds returns the feeds of a provider matching the TLPs of a template.
+// Directory based feeds are only used if there are no ROLIE feeds
+// and have TLP:WHITE.
+func templateFeeds(pmd *csaf.ProviderMetadata, tlps []models.TLP) ([]provisionedFeed, error) {
+	var (
+		feeds  []provisionedFeed
+		labels = map[string]int{}
+		rolie  bool
+	)
+	add := func(label, feedURL string) error {
+		u, err := url.Parse(feedURL)
+		if err != nil {
+			return InvalidArgumentError(fmt.Sprintf("invalid feed URL %q: %v", feedURL, err))
+		}
+		// Labels have to be unique per source.
+		if labels[label]++; labels[label] > 1 {
+			label = fmt.Sprintf("%s-%d", label, labels[label])
+		}
+		feeds = append(feeds, provisionedFeed{label: label, url: u})
+		return nil
+	}
+	for i := range pmd.Distributions {
+		d := &pmd.Distributions[i]
+		if d.Rolie == nil {
+			continue
+		}
+		for j := range d.Rolie.Feeds {
+			f := &d.Rolie.Feeds[j]
+			if f.URL == nil || f.TLPLabel == nil {
+				continue
+			}
+			rolie = true
+			tlp := models.TLP(*f.TLPLabel)
+			if !models.ContainsTLP(tlps, tlp) {
+				continue
+			}
+			if err := add(strings.ToLower(string(tlp)), string(*f.URL)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if rolie || !models.ContainsTLP(tlps, models.TLPWhite) {
+		return feeds, nil
+	}
+	for i := range pmd.Distributions {
+		if d := &pmd.Distributions[i]; d.Rolie == nil && d.DirectoryURL != "" {
+			if err := add("directory", d.DirectoryURL); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return feeds, nil
+}
+
+// provisionedName returns an unused name for a source of the given provider.
+func (m *Manager) provisionedName(pmd *csaf.ProviderMetadata, pmdURL string) (string, error) {
+	var name string
+	if pmd.Publisher != nil && pmd.Publisher.Name != nil {
+		name = strings.TrimSpace(*pmd.Publisher.Name)
+	}
+	host := pmdURL
+	if u, err := url.Parse(pmdURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	if name == "" {
+		name = host
+	}
+	var err error
+	m.inManager(func(m *Manager, _ context.Context) {
+		if slices.ContainsFunc(m.sources, func(s *source) bool { return s.url == pmdURL }) {
+			err = InvalidArgumentError("source already exists")
+			return
+		}
+		// Mirrors share the name of the publisher.
+		candidate := name
+		for i := 1; m.findSourceByName(candidate) != nil; i++ {
+			if i == 1 {
+				candidate = fmt.Sprintf("%s (%s)", name, host)
+			} else {
+				candidate = fmt.Sprintf("%s (%s) %d", name, host, i)
+			}
+		}
+		name = candidate
+	})
+	return name, err
+}
+
+// ProvisionSource creates a source for the given provider metadata URL
+// with its feeds configured from the given template.
+// If the creation of a feed fails the source is removed again.
+func (m *Manager) ProvisionSource(
+	pmdURL string,
+	tmpl *config.ProvisioningTemplate,
+) (int64, error) {
+	cpmd := m.PMD(pmdURL)
+	if !cpmd.Valid() {
+		return 0, InvalidArgumentError("PMD is invalid")
+	}
+	pmd, err := cpmd.Model()
+	if err != nil {
+		return 0, err
+	}
+	feeds, err := templateFeeds(pmd, tmpl.TLPs)
+	if err != nil {
+		return 0, err
+	}
+	name, err := m.provisionedName(pmd, pmdURL)
+	if err != nil {
+		return 0, err
+	}
+	ignorePatterns, err := AsRegexps(tmpl.IgnorePatterns)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		rate  *float64
+		slots *int
+		age   *time.Duration
+	)
+	if tmpl.Rate != 0 {
+		rate = &tmpl.Rate
+	}
+	if tmpl.Slots != 0 {
+		slots = &tmpl.Slots
+	}
+	if tmpl.Age != 0 {
+		age = &tmpl.Age
+	} else if m.cfg.Sources.DefaultAge != 0 {
+		age = &m.cfg.Sources.DefaultAge
+	}
+	sourceID, err := m.AddSource(
+		name, pmdURL,
+		rate, slots,
+		nil, nil, nil, nil,
+		age, ignorePatterns, nil,
+		nil, nil, nil,
+		nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range feeds {
+		if _, err := m.AddFeed(sourceID, f.label, f.url, m.cfg.Sources.FeedLogLevel, nil); err != nil {
+			if rerr := m.RemoveSource(sourceID); rerr != nil {
+				slog.Error("removing provisioned source failed", "id", sourceID, "err", rerr)
+			}
+			return 0, fmt.Errorf("adding feed %q failed: %w", f.label, err)
+		}
+	}
+	// Without feeds there is nothing to download.
+	if tmpl.Activate && len(feeds) > 0 {
+		if _, err := m.UpdateSource(sourceID, func(su *SourceUpdater) error {
+			return su.UpdateActive(true)
+		}); err != nil {
+			return sourceID, fmt.Errorf("activating source failed: %w", err)
+		}
+	}
+	return sourceID, nil
+}
@@ -0,0 +1,126 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+)
+
+// Proxy is the proxy of a source.
+type Proxy struct {
+	// URL is the URL of the proxy or [config.DirectProxy].
+	// nil stands for the globally configured proxy.
+	URL *string
+	// Credentials are the credentials in the form "user:password".
+	Credentials []byte
+}
+
+// ValidProxy checks if the given URL is usable as proxy URL of a source.
+func ValidProxy(proxyURL string) error {
+	if proxyURL == config.DirectProxy {
+		return nil
+	}
+	if _, err := config.ParseProxyURL(proxyURL); err != nil {
+		return InvalidArgumentError(fmt.Sprintf("invalid proxy %q: %v", proxyURL, err))
+	}
+	return nil
+}
+
+// proxyConfig returns the proxy configuration of the given settings.
+// It returns nil if the globally configured proxy is to be used.
+func proxyConfig(cfg *config.Config, proxyURL *string, credentials []byte) *config.Proxy {
+	if proxyURL == nil {
+		return nil
+	}
+	proxy := &config.Proxy{
+		URL:       *proxyURL,
+		NoProxy:   cfg.General.Proxy.NoProxy,
+		RemoteDNS: cfg.General.Proxy.RemoteDNS,
+	}
+	if user, password, ok := bytes.Cut(credentials, []byte(":")); ok {
+		proxy.Username, proxy.Password = string(user), string(password)
+	} else {
+		proxy.Username = string(credentials)
+	}
+	return proxy
+}
+
+// proxyConfig returns the proxy configuration of the source.
+func (s *source) proxyConfig(m *Manager) *config.Proxy {
+	return proxyConfig(m.cfg, s.proxy, s.proxyCredentials)
+}
+
+// registerProxy makes the proxy of the source known to the PMD lookups.
+func (m *Manager) registerProxy(s *source) {
+	m.proxies.Store(s.url, s.proxyConfig(m))
+}
+
+// changeProxy registers the changed proxy of the source.
+// The PMD fetched over the old proxy is dropped.
+func (m *Manager) changeProxy(s *source) {
+	s.resetAuth()
+	m.registerProxy(s)
+	m.pmdCache.Delete(s.url)
+}
+
+// unregisterProxy drops the proxy of a removed source.
+// Another source with the same url takes over.
+func (m *Manager) unregisterProxy(url string) {
+	if i := slices.IndexFunc(m.sources, func(s *source) bool { return s.url == url }); i >= 0 {
+		m.registerProxy(m.sources[i])
+	} else {
+		m.proxies.Delete(url)
+	}
+	m.pmdCache.Delete(url)
+}
+
+// PMDProxy returns the proxy used to fetch the PMD from the given url.
+// This is the proxy of a source with this url. nil means the global proxy.
+func (m *Manager) PMDProxy(url string) *config.Proxy {
+	if proxy, ok := m.proxies.Load(url); ok {
+		return proxy.(*config.Proxy)
+	}
+	return nil
+}
+
+// UpdateProxy requests an update of the proxy.
+func (su *SourceUpdater) UpdateProxy(proxyURL *string) error {
+	if equalPtr(proxyURL, su.updatable.proxy) {
+		return nil
+	}
+	if proxyURL != nil {
+		if err := ValidProxy(*proxyURL); err != nil {
+			return err
+		}
+	}
+	m := su.manager
+	su.addChange(func(s *source) {
+		s.proxy = proxyURL
+		m.changeProxy(s)
+	}, "proxy", proxyURL)
+	return nil
+}
+
+// UpdateProxyCredentials requests an update of the proxy credentials.
+func (su *SourceUpdater) UpdateProxyCredentials(credentials []byte) error {
+	if slices.Equal(credentials, su.updatable.proxyCredentials) {
+		return nil
+	}
+	credentials = clone(credentials)
+	m := su.manager
+	su.addSecret(func(s *source) {
+		s.proxyCredentials = credentials
+		m.changeProxy(s)
+	}, proxyCredentialsSecret, credentials)
+	return nil
+}
@@ -0,0 +1,189 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// errNoSuchQuarantined is returned if there is no pending quarantined document with a given id.
+var errNoSuchQuarantined = NoSuchEntryError("no such pending quarantined document")
+
+// quarantineMeta are the parts of a document stored with a quarantined download.
+type quarantineMeta struct {
+	Document struct {
+		Publisher struct {
+			Name *string `json:"name"`
+		} `json:"publisher"`
+		Tracking struct {
+			ID      *string `json:"id"`
+			Version *string `json:"version"`
+		} `json:"tracking"`
+		Distribution struct {
+			TLP struct {
+				Label *string `json:"label"`
+			} `json:"tlp"`
+		} `json:"distribution"`
+	} `json:"document"`
+}
+
+// storeQuarantine returns a function which stores a download
+// failing the checks in the quarantine.
+func (l *location) storeQuarantine(
+	f *feed,
+	status dlStatus,
+	original, signature []byte,
+	filename string,
+) func(context.Context, pgx.Tx) error {
+	return func(ctx context.Context, tx pgx.Tx) error {
+		// The document was decoded before so this should not fail.
+		var meta quarantineMeta
+		if err := json.Unmarshal(original, &meta); err != nil {
+			slog.Warn("extracting meta data of quarantined document failed", "url", l.doc, "err", err)
+		}
+		checksum := sha256.Sum256(original)
+		var i inserter
+		if !f.invalid.Load() {
+			i.add("feeds_id", f.id)
+		}
+		i.add("url", l.doc.String())
+		i.add("checksum", checksum[:])
+		i.add("original", original)
+		i.add("signature", signature)
+		i.add("filename", filename)
+		i.add("publisher", meta.Document.Publisher.Name)
+		i.add("tracking_id", meta.Document.Tracking.ID)
+		i.add("version", meta.Document.Tracking.Version)
+		i.add("tlp", meta.Document.Distribution.TLP.Label)
+		i.add("filename_failed", status.has(filenameFailed))
+		i.add("schema_failed", status.has(schemaValidationFailed))
+		i.add("remote_failed", status.has(remoteValidationFailed))
+		i.add("checksum_failed", status.has(checksumFailed))
+		i.add("signature_failed", status.has(signatureFailed))
+		// Unchanged documents are downloaded again on every feed update.
+		sql := i.sql("quarantine") + ` ON CONFLICT (url, checksum) DO NOTHING`
+		_, err := tx.Exec(ctx, sql, i.values...)
+		return err
+	}
+}
+
+// ReleaseQuarantined imports a pending quarantined document despite
+// its failed checks. It returns the id of the imported document
+// which is 0 if the document is already in the database.
+func (m *Manager) ReleaseQuarantined(ctx context.Context, id int64, actor *string) (int64, error) {
+	const (
+		loadSQL = `SELECT original, signature, filename, url FROM quarantine ` +
+			`WHERE id = $1 AND state = 'pending'`
+		releasedSQL = `UPDATE quarantine ` +
+			`SET state = 'released', decided = current_timestamp, actor = $2, documents_id = $3 ` +
+			`WHERE id = $1 AND state = 'pending'`
+		signatureSQL = `UPDATE documents ` +
+			`SET (signature, filename) = ($1, $2) ` +
+			`WHERE id = $3`
+		eventSQL = `INSERT INTO events_log (event, state, actor, documents_id) ` +
+			`VALUES ('release_document', 'new', $1, $2)`
+	)
+	var (
+		original, signature []byte
+		filename            *string
+		url                 string
+		docID               int64
+	)
+	err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			if err := conn.QueryRow(rctx, loadSQL, id).Scan(
+				&original, &signature, &filename, &url,
+			); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return errNoSuchQuarantined
+				}
+				return err
+			}
+			var doc any
+			if err := json.NewDecoder(bytes.NewReader(original)).Decode(&doc); err != nil {
+				return InvalidArgumentError("quarantined document is not valid JSON: " + err.Error())
+			}
+			store := func(rctx context.Context, tx pgx.Tx, newID int64, duplicate bool) error {
+				var documentsID *int64
+				if !duplicate {
+					documentsID = &newID
+					if _, err := tx.Exec(rctx, signatureSQL, signature, filename, newID); err != nil {
+						return err
+					}
+					if _, err := tx.Exec(rctx, eventSQL, actor, newID); err != nil {
+						return err
+					}
+				}
+				// Guard against concurrent decisions.
+				switch tag, err := tx.Exec(rctx, releasedSQL, id, actor, documentsID); {
+				case err != nil:
+					return err
+				case tag.RowsAffected() == 0:
+					return errNoSuchQuarantined
+				}
+				return nil
+			}
+			var err error
+			docID, err = models.ImportDocumentData(
+				rctx, conn,
+				doc, original,
+				actor,
+				m.cfg.Sources.PublishersTLPs,
+				store,
+				false)
+			if err != nil && !errors.Is(err, models.ErrAlreadyInDatabase) {
+				return err
+			}
+			return nil
+		}, 0,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var releaser string
+	if actor != nil {
+		releaser = *actor
+	}
+	slog.Info("quarantined document released",
+		"id", id, "url", url, "document", docID, "actor", releaser)
+	return docID, nil
+}
+
+// RejectQuarantined rejects a pending quarantined document.
+func (m *Manager) RejectQuarantined(ctx context.Context, id int64, actor *string) error {
+	const rejectSQL = `UPDATE quarantine ` +
+		`SET state = 'rejected', decided = current_timestamp, actor = $2 ` +
+		`WHERE id = $1 AND state = 'pending'`
+	var rejected bool
+	if err := m.db.Run(
+		ctx,
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tag, err := conn.Exec(rctx, rejectSQL, id, actor)
+			rejected = tag.RowsAffected() > 0
+			return err
+		}, 0,
+	); err != nil {
+		return err
+	}
+	if !rejected {
+		return errNoSuchQuarantined
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// maxDownloadAttempts is the number of attempts to download a document.
+	maxDownloadAttempts = 3
+	// retryDelay is the time to wait before a failed download is tried again.
+	// It is multiplied with the number of failed attempts.
+	retryDelay = 5 * time.Minute
+)
+
+// unqueueSQL removes the locations of a feed from the download queue
+// which are not waiting or running any more.
+const unqueueSQL = `DELETE FROM download_queue ` +
+	`WHERE feeds_id = $1 AND state <> 'failed' AND NOT (url = ANY($2))`
+
+// urlString returns the textual representation of an optional URL.
+func urlString(u *url.URL) *string {
+	if u == nil {
+		return nil
+	}
+	s := u.String()
+	return &s
+}
+
+// parseOptionalURL parses an optional URL.
+func parseOptionalURL(s *string) (*url.URL, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return url.Parse(*s)
+}
+
+// queuedURLs returns the URLs of the locations in the queue
+// which are not downloaded yet.
+func (f *feed) queuedURLs() []string {
+	urls := make([]string, 0, len(f.queue))
+	for i := range f.queue {
+		if f.queue[i].state != done {
+			urls = append(urls, f.queue[i].doc.String())
+		}
+	}
+	return urls
+}
+
+// persist runs a function against the database if the feed still exists.
+// Errors are only logged as the queue in memory is the leading one.
+func (f *feed) persist(ctx context.Context, db *database.DB, fn func(context.Context, *pgxpool.Conn) error) {
+	if f.invalid.Load() {
+		return
+	}
+	if err := db.Run(ctx, fn, 0); err != nil {
+		slog.Error("updating download queue failed", "feed", f.id, "err", err)
+	}
+}
+
+// enqueue stores the given locations as waiting in the download queue.
+func (f *feed) enqueue(ctx context.Context, db *database.DB, locations []location) {
+	const upsertSQL = `INSERT INTO download_queue (feeds_id, url, updated, hash, signature) ` +
+		`VALUES ($1, $2, $3, $4, $5) ` +
+		`ON CONFLICT (feeds_id, url) DO UPDATE SET ` +
+		`(updated, hash, signature, state, retries, message, time) = ` +
+		`(EXCLUDED.updated, EXCLUDED.hash, EXCLUDED.signature, 'waiting', 0, NULL, current_timestamp)`
+	if len(locations) == 0 {
+		return
+	}
+	batch := &pgx.Batch{}
+	for i := range locations {
+		l := &locations[i]
+		batch.Queue(upsertSQL,
+			f.id, l.doc.String(), l.updated, urlString(l.hash), urlString(l.signature))
+	}
+	f.persist(ctx, db, func(ctx context.Context, conn *pgxpool.Conn) error {
+		return conn.SendBatch(ctx, batch).Close()
+	})
+}
+
+// setQueued updates the state of a location in the download queue.
+func (f *feed) setQueued(ctx context.Context, db *database.DB, l *location, state string, err error) {
+	const updateSQL = `UPDATE download_queue ` +
+		`SET (state, retries, message, time) = ($4::download_state, $5, $6, current_timestamp) ` +
+		`WHERE feeds_id = $1 AND url = $2 AND updated = $3`
+	var message *string
+	if err != nil {
+		msg := err.Error()
+		message = &msg
+	}
+	f.persist(ctx, db, func(ctx context.Context, conn *pgxpool.Conn) error {
+		_, err := conn.Exec(ctx, updateSQL,
+			f.id, l.doc.String(), l.updated, state, l.retries, message)
+		return err
+	})
+}
+
+// dequeue removes a downloaded location from the download queue.
+// Newer versions of the same URL are kept.
+func (f *feed) dequeue(ctx context.Context, db *database.DB, l *location) {
+	const deleteSQL = `DELETE FROM download_queue ` +
+		`WHERE feeds_id = $1 AND url = $2 AND updated <= $3`
+	f.persist(ctx, db, func(ctx context.Context, conn *pgxpool.Conn) error {
+		_, err := conn.Exec(ctx, deleteSQL, f.id, l.doc.String(), l.updated)
+		return err
+	})
+}
+
+// syncQueues removes the locations from the download queues of
+// the feeds of the source which were removed from memory.
+func (s *source) syncQueues(ctx context.Context, db *database.DB) {
+	for _, f := range s.feeds {
+		f.persist(ctx, db, func(ctx context.Context, conn *pgxpool.Conn) error {
+			_, err := conn.Exec(ctx, unqueueSQL, f.id, f.queuedURLs())
+			return err
+		})
+	}
+}
+
+// downloadFailed handles a failed download attempt. The location
+// is tried again later until the number of attempts is exhausted.
+func (f *feed) downloadFailed(ctx context.Context, m *Manager, l *location, err error) {
+	l.retries++
+	if l.retries < maxDownloadAttempts {
+		l.state = waiting
+		l.retryAt = time.Now().Add(time.Duration(l.retries) * retryDelay)
+		f.setQueued(ctx, m.db, l, "waiting", err)
+		return
+	}
+	l.state = done
+	f.setQueued(ctx, m.db, l, "failed", err)
+	f.log(m, config.ErrorFeedLogLevel,
+		"giving up downloading %q after %d attempts", l.doc, l.retries)
+}
+
+// loadQueues resumes the download queues of the feeds.
+func (m *Manager) loadQueues(ctx context.Context, tx pgx.Tx) error {
+	const (
+		// Downloads running at shutdown are started again.
+		resetSQL = `UPDATE download_queue SET state = 'waiting' WHERE state = 'running'`
+		queueSQL = `SELECT feeds_id, url, updated, hash, signature, retries ` +
+			`FROM download_queue WHERE state = 'waiting' ORDER BY updated`
+	)
+	if _, err := tx.Exec(ctx, resetSQL); err != nil {
+		return fmt.Errorf("resetting download queue failed: %w", err)
+	}
+	rows, err := tx.Query(ctx, queueSQL)
+	if err != nil {
+		return fmt.Errorf("querying download queue failed: %w", err)
+	}
+	for rows.Next() {
+		var (
+			feedID          int64
+			doc             string
+			l               location
+			hash, signature *string
+		)
+		if err := rows.Scan(&feedID, &doc, &l.updated, &hash, &signature, &l.retries); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning download queue failed: %w", err)
+		}
+		f := m.findFeedByID(feedID)
+		if f == nil {
+			continue
+		}
+		if l.doc, err = url.Parse(doc); err != nil {
+			slog.Warn("invalid URL in download queue", "feed", feedID, "url", doc, "err", err)
+			continue
+		}
+		if l.hash, err = parseOptionalURL(hash); err != nil {
+			slog.Warn("invalid hash URL in download queue", "feed", feedID, "url", doc, "err", err)
+		}
+		if l.signature, err = parseOptionalURL(signature); err != nil {
+			slog.Warn("invalid signature URL in download queue", "feed", feedID, "url", doc, "err", err)
+		}
+		f.queue = append(f.queue, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("collecting download queue failed: %w", err)
+	}
+	// The age and the ignore patterns may have changed in the meantime.
+	var (
+		batch   = &pgx.Batch{}
+		resumed int
+	)
+	for _, s := range m.sources {
+		s.deleteTooOld()
+		s.deleteIgnore()
+		for _, f := range s.feeds {
+			batch.Queue(unqueueSQL, f.id, f.queuedURLs())
+			resumed += len(f.queue)
+		}
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("cleaning download queue failed: %w", err)
+	}
+	slog.Info("number of resumed downloads", "num", resumed)
+	return nil
+}
@@ -0,0 +1,155 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/secrets"
+)
+
+// The kinds of the secrets of a source.
+const (
+	headersSecret              = "headers"
+	clientCertPrivateSecret    = "client_cert_private"
+	clientCertPassphraseSecret = "client_cert_passphrase"
+	bearerTokenSecret          = "bearer_token"
+	oauth2ClientSecretSecret   = "oauth2_client_secret"
+	proxyCredentialsSecret     = "proxy_credentials"
+)
+
+// sourceSecretKinds are all kinds of secrets of a source.
+var sourceSecretKinds = []string{
+	headersSecret,
+	clientCertPrivateSecret,
+	clientCertPassphraseSecret,
+	bearerTokenSecret,
+	oauth2ClientSecretSecret,
+	proxyCredentialsSecret,
+}
+
+// sourceSecret returns the name of a secret of a source.
+func sourceSecret(sourceID int64, kind string) string {
+	return fmt.Sprintf("sources/%d/%s", sourceID, kind)
+}
+
+// encodeHeaders encodes the headers as a secret value.
+func encodeHeaders(headers []string) []byte {
+	if len(headers) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(headers, "\n"))
+}
+
+// decodeHeaders decodes the headers from a secret value.
+func decodeHeaders(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(string(data), "\n")
+}
+
+// plainHeaders tells if the headers are kept in plain text in the
+// sources table. Without a configured sources.aes_key the database
+// provider generates a new key at every start and the stored
+// secrets cannot be decrypted after a restart.
+func (m *Manager) plainHeaders() bool {
+	return m.cfg.Secrets.Provider == config.SecretsDatabase &&
+		m.cfg.Sources.AESKey == ""
+}
+
+// loadSecrets loads the secrets of a source from the provider.
+func (m *Manager) loadSecrets(ctx context.Context, s *source) error {
+	load := func(kind string) ([]byte, error) {
+		return secrets.LoadOptional(ctx, m.secrets, sourceSecret(s.id, kind))
+	}
+	if !m.plainHeaders() {
+		headers, err := load(headersSecret)
+		if err != nil {
+			return err
+		}
+		s.headers = decodeHeaders(headers)
+	}
+	var err error
+	if s.clientCertPrivate, err = load(clientCertPrivateSecret); err != nil {
+		return err
+	}
+	if s.clientCertPassphrase, err = load(clientCertPassphraseSecret); err != nil {
+		return err
+	}
+	if s.bearerToken, err = load(bearerTokenSecret); err != nil {
+		return err
+	}
+	if s.oauth2ClientSecret, err = load(oauth2ClientSecretSecret); err != nil {
+		return err
+	}
+	s.proxyCredentials, err = load(proxyCredentialsSecret)
+	return err
+}
+
+// storeSecrets stores the secrets of a source in the provider.
+func (m *Manager) storeSecrets(ctx context.Context, s *source) error {
+	for kind, value := range map[string][]byte{
+		headersSecret:              encodeHeaders(s.headers),
+		clientCertPrivateSecret:    s.clientCertPrivate,
+		clientCertPassphraseSecret: s.clientCertPassphrase,
+		bearerTokenSecret:          s.bearerToken,
+		oauth2ClientSecretSecret:   s.oauth2ClientSecret,
+		proxyCredentialsSecret:     s.proxyCredentials,
+	} {
+		if value == nil || (kind == headersSecret && m.plainHeaders()) {
+			continue
+		}
+		if err := m.secrets.Store(ctx, sourceSecret(s.id, kind), value); err != nil {
+			return fmt.Errorf("storing %s failed: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// removeSecrets removes the secrets of a source from the provider.
+func (m *Manager) removeSecrets(ctx context.Context, sourceID int64) error {
+	for _, kind := range sourceSecretKinds {
+		if err := m.secrets.Store(ctx, sourceSecret(sourceID, kind), nil); err != nil {
+			return fmt.Errorf("removing %s failed: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// pendingSecret is a secret of a source to be stored by the updater.
+type pendingSecret struct {
+	name  string
+	value []byte
+}
+
+// addSecret registers a change of a secret of the source.
+// Like addChange only the first change of a secret is registered.
+func (su *SourceUpdater) addSecret(ch func(*source), kind string, value []byte) {
+	name := sourceSecret(su.updatable.id, kind)
+	if slices.ContainsFunc(su.secrets, func(ps pendingSecret) bool { return ps.name == name }) {
+		return
+	}
+	su.changes = append(su.changes, ch)
+	su.secrets = append(su.secrets, pendingSecret{name: name, value: value})
+}
+
+// storeSecrets stores the changed secrets in the provider.
+func (su *SourceUpdater) storeSecrets(ctx context.Context) error {
+	for _, ps := range su.secrets {
+		if err := su.manager.secrets.Store(ctx, ps.name, ps.value); err != nil {
+			return fmt.Errorf("storing secret %q failed: %w", ps.name, err)
+		}
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/database"
+	"github.com/ISDuBA/ISDuBA/pkg/schedule"
 	"github.com/ISDuBA/ISDuBA/pkg/version"
 	"github.com/gocsaf/csaf/v3/util"
 	"github.com/jackc/pgx/v5"
@@ -31,7 +32,10 @@ import (
 	"golang.org/x/time/rate"
 )
 
-const deactivatedDueToClientCertIssue = `Deactivated due to client cert issue.`
+const (
+	deactivatedDueToClientCertIssue = `Deactivated due to client cert issue.`
+	deactivatedDueToSecretsIssue    = `Deactivated due to unreadable secrets.`
+)
 
 // UserAgent is the name of the http client
 var UserAgent = "isduba/" + version.SemVersion
@@ -51,6 +55,12 @@ type location struct {
 	signature *url.URL
 	state     state
 	id        int64
+	// retries is the number of failed download attempts.
+	retries int
+	// retryAt is the earliest time to try a failed download again.
+	retryAt time.Time
+	// status is the result of the checks of the last download.
+	status dlStatus
 }
 
 type feed struct {
@@ -66,9 +76,23 @@ type feed struct {
 	queue     []location
 	source    *source
 
+	// schedule overrides the schedule of the source.
+	schedule schedule.Schedule
+	// fetchNow bypasses the schedule until the fetched entries are downloaded.
+	fetchNow bool
+
 	refreshBlocked bool
 	lastETag       string
 	lastModified   time.Time
+	// lastChange is the time of the newest entry of the changes.csv
+	// of a directory based feed.
+	lastChange time.Time
+	// lastReconcile is the time the changes.csv and the index.txt
+	// were read completely.
+	lastReconcile time.Time
+
+	// recent are the recent outcomes the health is derived from.
+	recent feedHealth
 }
 
 type ignorePatterns []*regexp.Regexp
@@ -91,15 +115,30 @@ type source struct {
 	signatureCheck *bool
 	age            *time.Duration
 	ignorePatterns ignorePatterns
+	schedule       schedule.Schedule
 
 	clientCertPublic     []byte
 	clientCertPrivate    []byte
 	clientCertPassphrase []byte
 	tlsCertificates      []tls.Certificate
 
+	authMode           AuthMode
+	oauth2TokenURL     *string
+	oauth2ClientID     *string
+	oauth2Scopes       []string
+	bearerToken        []byte
+	oauth2ClientSecret []byte
+	tokens             *tokenSource
+
+	proxy            *string
+	proxyCredentials []byte
+
 	checksum        []byte
 	checksumAck     time.Time
 	checksumUpdated time.Time
+
+	// keys are the pinned OpenPGP keys.
+	keys []*pinnedKey
 }
 
 // ignore returns true if the given url should be ignored.
@@ -156,6 +195,7 @@ func (f *feed) refresh(m *Manager) {
 			f.removeOutdatedWaiting(candidates)
 
 			// Merge candidates into list of locations.
+			f.enqueue(ctx, m.db, candidates)
 			f.queue = append(f.queue, candidates...)
 			slices.SortFunc(f.queue, func(a, b location) int {
 				return a.updated.Compare(b.updated)
@@ -192,6 +232,7 @@ func (f *feed) removeOutdatedWaiting(candidates []location) {
 func (f *feed) resetIndexTags() {
 	f.lastETag = ""
 	f.lastModified = time.Time{}
+	f.lastChange = time.Time{}
 }
 
 // fetchIndex fetches the content of the feed index.
@@ -199,26 +240,6 @@ func (f *feed) fetchIndex(m *Manager, fn func([]location, error)) {
 	// Prevent stacked calling
 	f.refreshBlocked = true
 
-	indexURL := f.url.String()
-	if !f.rolie {
-		var err error
-		if indexURL, err = url.JoinPath(indexURL, "changes.csv"); err != nil {
-			fn(nil, err)
-			return
-		}
-	}
-	slog.Debug("fetching index", "url", indexURL, "rolie", f.rolie)
-	req, err := http.NewRequest(http.MethodGet, indexURL, nil)
-	if err != nil {
-		fn(nil, err)
-		return
-	}
-	if f.lastETag != "" {
-		req.Header.Add("If-None-Match", f.lastETag)
-	}
-	if !f.lastModified.IsZero() {
-		req.Header.Add("If-Modified-Since", f.lastModified.Format(http.TimeFormat))
-	}
 	client := f.source.httpClient(m)
 	// Copy relevant data to avoid races.
 	fi := feedIndex{
@@ -226,44 +247,68 @@ func (f *feed) fetchIndex(m *Manager, fn func([]location, error)) {
 		age:            f.source.age,
 		ignorePatterns: f.source.ignorePatterns,
 		sameOrNewer:    f.sameOrNewer(),
-	}
+		do: func(req *http.Request) (*http.Response, error) {
+			return f.source.doRequest(client, m, req)
+		},
+		lastETag:     f.lastETag,
+		lastModified: f.lastModified,
+		since:        f.lastChange,
+	}
+	// Directory based feeds are reconciled with the index.txt from time to time.
+	now := time.Now()
+	reconcile := !f.rolie &&
+		(f.lastChange.IsZero() || !now.Before(f.lastReconcile.Add(m.cfg.Sources.FeedReconcile)))
+	slog.Debug("fetching index", "url", f.url, "rolie", f.rolie, "reconcile", reconcile)
 	// Do the actual fetching async.
 	go func() {
+		var (
+			res   *indexResult
+			err   error
+			start = time.Now()
+		)
 		defer func() {
 			client.CloseIdleConnections()
-			// Re-enable refreshing
-			m.fns <- func(*Manager, context.Context) { f.refreshBlocked = false }
+			latency := time.Since(start)
+			m.fns <- func(m *Manager, ctx context.Context) {
+				// Re-enable refreshing
+				f.refreshBlocked = false
+				f.recent.measured(latency)
+				if err != nil {
+					f.failed(ctx, m, err)
+				} else {
+					f.succeeded(m)
+				}
+			}
 		}()
-		resp, err := f.source.doRequest(client, m, req)
+		switch {
+		case f.rolie:
+			res, err = fi.fetchROLIE()
+		case reconcile:
+			res, err = fi.reconcileDirectory()
+		default:
+			res, err = fi.fetchChanges()
+		}
 		if err != nil {
 			fn(nil, err)
 			return
 		}
-		defer resp.Body.Close()
+		if res.indexErr != nil {
+			f.log(m, config.WarnFeedLogLevel, "reading index.txt failed: %v", res.indexErr)
+		}
 		// Nothing changed since last call.
-		if resp.StatusCode == http.StatusNotModified {
+		if res.notModified {
 			fn(nil, nil)
 			return
 		}
-		if resp.StatusCode != http.StatusOK {
-			fn(nil, fmt.Errorf("status code %d", resp.StatusCode))
-			return
-		}
-		var locations []location
-		if f.rolie {
-			locations, err = fi.rolieLocations(resp.Body)
-		} else {
-			locations, err = fi.directoryLocations(resp.Body)
-		}
-		if err != nil {
-			fn(nil, err)
-			return
-		}
-		fn(locations, nil)
+		fn(res.locations, nil)
 		m.fns <- func(*Manager, context.Context) {
-			f.lastETag = resp.Header.Get("Etag")
-			if m := resp.Header.Get("Last-Modified"); m != "" {
-				f.lastModified, _ = time.Parse(http.TimeFormat, m)
+			f.lastETag = res.etag
+			f.lastModified = res.lastModified
+			if res.newest.After(f.lastChange) {
+				f.lastChange = res.newest
+			}
+			if reconcile {
+				f.lastReconcile = now
 			}
 		}
 	}()
@@ -349,10 +394,10 @@ func (f *feed) findLocationByID(id int64) *location {
 }
 
 // findWaiting looks for a location ready to download.
-func (f *feed) findWaiting() *location {
+func (f *feed) findWaiting(now time.Time) *location {
 	// Backwards because the new ones are at the end.
 	for i := len(f.queue) - 1; i >= 0; i-- {
-		if location := &f.queue[i]; location.state == waiting {
+		if location := &f.queue[i]; location.state == waiting && !now.Before(location.retryAt) {
 			return location
 		}
 	}
@@ -378,6 +423,72 @@ func (s *source) addStats(st *Stats) {
 	}
 }
 
+// activeSchedule returns the schedule of the feed or of its source if it has none.
+func (f *feed) activeSchedule() schedule.Schedule {
+	if f.schedule != nil {
+		return f.schedule
+	}
+	return f.source.schedule
+}
+
+// downloadsAllowed tells if downloads of the feed may be started at the given time.
+func (f *feed) downloadsAllowed(now time.Time) bool {
+	if f.fetchNow {
+		return true
+	}
+	if f.backedOff(now) {
+		return false
+	}
+	sched := f.activeSchedule()
+	return sched == nil || sched.Allows(now)
+}
+
+// nextRun returns the time of the next scheduled refresh
+// of the feed. It is nil if the feed was not scheduled yet.
+func (f *feed) nextRun() *time.Time {
+	if f.nextCheck.IsZero() {
+		return nil
+	}
+	next := f.nextCheck
+	return &next
+}
+
+// nextRun returns the earliest time of the next
+// scheduled refresh of the feeds of the source.
+func (s *source) nextRun() *time.Time {
+	var next *time.Time
+	for _, f := range s.feeds {
+		if f.invalid.Load() {
+			continue
+		}
+		if n := f.nextRun(); n != nil && (next == nil || n.Before(*next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// fetch triggers a refresh of the feed which bypasses the schedule.
+func (f *feed) fetch() {
+	f.fetchNow = true
+	f.nextCheck = time.Time{}
+}
+
+// resetSchedule lets the feeds of the source re-calculate their next refresh.
+func (s *source) resetSchedule() {
+	for _, f := range s.feeds {
+		f.nextCheck = time.Time{}
+	}
+}
+
+// equalSchedules checks if two schedules are the same.
+func equalSchedules(a, b schedule.Schedule) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.String() == b.String()
+}
+
 // forceIndexRefresh forces an index refresh on all feeds of a source.
 func (s *source) forceIndexRefresh() {
 	past := time.Now().Add(-time.Minute)
@@ -463,7 +574,7 @@ func (s *source) httpClient(m *Manager) *http.Client {
 		tlsConfig.Certificates = s.tlsCertificates
 	}
 
-	transport := m.cfg.General.Transport()
+	transport := m.cfg.General.TransportVia(s.proxyConfig(m))
 	transport.TLSClientConfig = &tlsConfig
 
 	client := http.Client{Transport: transport}
@@ -489,16 +600,29 @@ func (s *source) doRequest(client *http.Client, m *Manager, req *http.Request) (
 	// The manager owns the configuration.
 	// So we let the manager do the adjustment of the request.
 
-	var limiter *rate.Limiter
+	var (
+		limiter *rate.Limiter
+		tokens  *tokenSource
+	)
 
 	m.inManager(func(m *Manager, _ context.Context) {
 		s.applyHeaders(req)
+		tokens = s.authorize(m, req)
 		if client == nil {
 			client = s.httpClient(m)
 		}
 		limiter = s.wait()
 	})
 
+	// Fetching a token may take a while so do it outside the manager.
+	if tokens != nil {
+		token, err := tokens.token()
+		if err != nil {
+			return nil, err
+		}
+		token.SetAuthHeader(req)
+	}
+
 	if limiter != nil {
 		limiter.Wait(context.Background())
 	}
@@ -12,6 +12,9 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
+
+	"github.com/ISDuBA/ISDuBA/pkg/schedule"
 )
 
 // AsStrings returns a slice of strings from a slice of regular expressions.
@@ -47,6 +50,19 @@ func AsRegexps(s []string) ([]*regexp.Regexp, error) {
 	return slice, nil
 }
 
+// AsSchedule returns a schedule from a string.
+// An empty or missing string means no schedule.
+func AsSchedule(s *string) (schedule.Schedule, error) {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil, nil
+	}
+	sched, err := schedule.Parse(*s)
+	if err != nil {
+		return nil, InvalidArgumentError(fmt.Sprintf("invalid schedule: %v", err))
+	}
+	return sched, nil
+}
+
 // joinURL joins the two URLs while preserving the query and fragment part of the latter.
 func joinURL(baseURL *url.URL, relativeURL *url.URL) *url.URL {
 	u := baseURL.JoinPath(relativeURL.Path)
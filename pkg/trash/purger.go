@@ -0,0 +1,129 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+// Package trash implements the purging of outdated documents from the trash bin
+// and of the files of removed comment attachments.
+package trash
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database"
+)
+
+const purgeInterval = 20 * time.Minute
+
+// Purger permanently deletes the documents which are
+// longer in the trash bin than configured.
+// It also removes the files of deleted comment attachments.
+type Purger struct {
+	cfg *config.Config
+	db  *database.DB
+}
+
+// NewPurger returns a new purger.
+func NewPurger(cfg *config.Config, db *database.DB) *Purger {
+	return &Purger{cfg: cfg, db: db}
+}
+
+// Run runs the purger. To be used in a Go routine.
+func (p *Purger) Run(ctx context.Context) {
+	// Check if there is nothing to do.
+	if p.cfg.General.KeepTrash <= 0 && p.cfg.Comments.AttachmentsDir == "" {
+		return
+	}
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		if p.cfg.General.KeepTrash > 0 {
+			p.purge(ctx)
+		}
+		if p.cfg.Comments.AttachmentsDir != "" {
+			p.removeAttachments(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	// Purged documents are logged without an actor.
+	const purgeSQL = `WITH purged AS (` +
+		`DELETE FROM documents ` +
+		`WHERE deleted < current_timestamp - $1::interval ` +
+		`RETURNING id) ` +
+		`INSERT INTO events_log (event) ` +
+		`SELECT 'purge_document'::events FROM purged`
+	var purged int64
+	if err := p.db.Run(
+		ctx,
+		func(ctx context.Context, conn *pgxpool.Conn) error {
+			tags, err := conn.Exec(ctx, purgeSQL, p.cfg.General.KeepTrash)
+			purged = tags.RowsAffected()
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("Purging trash failed", "err", err)
+		return
+	}
+	if purged > 0 {
+		slog.Info("Purged documents from trash", "num", purged)
+	}
+}
+
+// removeAttachments removes the files of the comment attachments
+// which were deleted from the database.
+func (p *Purger) removeAttachments(ctx context.Context) {
+	const (
+		fetchSQL  = `SELECT path FROM removed_attachments`
+		removeSQL = `DELETE FROM removed_attachments WHERE path = $1`
+	)
+	var removed int
+	if err := p.db.Run(
+		ctx,
+		func(ctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(ctx, fetchSQL)
+			paths, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return err
+			}
+			for _, path := range paths {
+				// Only remove files from the attachments directory.
+				fname := filepath.Join(p.cfg.Comments.AttachmentsDir, filepath.Base(path))
+				if err := os.Remove(fname); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					slog.Warn("Removing attachment file failed", "file", fname, "err", err)
+					continue
+				}
+				if _, err := conn.Exec(ctx, removeSQL, path); err != nil {
+					return err
+				}
+				removed++
+			}
+			return nil
+		}, 0,
+	); err != nil {
+		slog.Error("Removing attachment files failed", "err", err)
+		return
+	}
+	if removed > 0 {
+		slog.Info("Removed files of deleted attachments", "num", removed)
+	}
+}
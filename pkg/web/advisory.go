@@ -26,7 +26,7 @@ type advisoryStates []models.AdvisoryState
 
 func (c *Controller) changeStatusAll(ctx *gin.Context, inputs advisoryStates) {
 	const (
-		findAdvisory = `SELECT docs.id, state::text, tlp ` +
+		findAdvisory = `SELECT ads.id, docs.id, state::text, tlp ` +
 			`FROM advisories ads ` +
 			`JOIN documents docs ON ads.id = docs.advisories_id ` +
 			`WHERE ads.publisher = $1 AND ads.tracking_id = $2 ` +
@@ -34,6 +34,7 @@ func (c *Controller) changeStatusAll(ctx *gin.Context, inputs advisoryStates) {
 		updateState = `UPDATE advisories SET state = $1::workflow WHERE (tracking_id, publisher) = ($2, $3)`
 		insertLog   = `INSERT INTO events_log (event, state, actor, documents_id) ` +
 			`VALUES ('state_change', $1::workflow, $2, $3)`
+		updatePeer = `UPDATE advisories SET state = $1::workflow WHERE id = $2`
 	)
 
 	actor := c.currentUser(ctx)
@@ -53,6 +54,7 @@ func (c *Controller) changeStatusAll(ctx *gin.Context, inputs advisoryStates) {
 			for i := range inputs {
 				var (
 					input      = &inputs[i]
+					advisoryID int64
 					documentID int64
 					current    string
 					tlp        string
@@ -69,7 +71,7 @@ func (c *Controller) changeStatusAll(ctx *gin.Context, inputs advisoryStates) {
 					"state", input.State)
 
 				if err := tx.QueryRow(rctx, findAdvisory, input.Publisher, input.TrackingID).Scan(
-					&documentID, &current, &tlp,
+					&advisoryID, &documentID, &current, &tlp,
 				); err != nil {
 					return err
 				}
@@ -104,6 +106,29 @@ func (c *Controller) changeStatusAll(ctx *gin.Context, inputs advisoryStates) {
 				if _, err := tx.Exec(rctx, insertLog, string(input.State), actor, documentID); err != nil {
 					return err
 				}
+
+				// Advisories in a case sharing the workflow state follow the change.
+				peers, err := loadCasePeers(rctx, tx, "state", advisoryID)
+				if err != nil {
+					return err
+				}
+				following, err := models.StatePeers(peers, input.State, tlps,
+					func(roles ...models.WorkflowRole) bool { return c.hasAnyRole(ctx, roles...) })
+				if errors.Is(err, models.ErrPeerForbidden) {
+					forbidden = true
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				for _, peer := range following {
+					if _, err := tx.Exec(rctx, updatePeer, string(input.State), peer.AdvisoryID); err != nil {
+						return err
+					}
+					if _, err := tx.Exec(rctx, insertLog, string(input.State), actor, peer.DocumentID); err != nil {
+						return err
+					}
+				}
 			}
 
 			return tx.Commit(rctx)
@@ -179,7 +204,7 @@ func (c *Controller) changeStatusBulk(ctx *gin.Context) {
 // deleteAdvisory deletes a given advisory.
 //
 //	@Summary		Deletes an advisory.
-//	@Description	Deletes the specified advisory.
+//	@Description	Moves the documents of the specified advisory to the trash bin.
 //	@Param			publisher	path	string	true	"Publisher"
 //	@Param			trackingid	path	string	true	"Tracking ID"
 //	@Produce		json
@@ -208,9 +233,13 @@ func (c *Controller) deleteAdvisory(ctx *gin.Context) {
 			`JOIN documents docs ON ads.id = docs.advisories_id ` +
 			`WHERE ads.publisher = $1 AND ads.tracking_id = $2 ` +
 			`AND latest`
-		deleteSQL = `DELETE FROM documents WHERE ` +
-			`advisories_id = (` +
-			`SELECT id FROM advisories WHERE publisher = $1 AND tracking_id = $2)`
+		trashSQL = `WITH trashed AS (` +
+			`UPDATE documents SET deleted = current_timestamp ` +
+			`WHERE deleted IS NULL AND advisories_id = (` +
+			`SELECT id FROM advisories WHERE publisher = $1 AND tracking_id = $2) ` +
+			`RETURNING id) ` +
+			`INSERT INTO events_log (event, actor, documents_id) ` +
+			`SELECT 'delete_document'::events, $3, id FROM trashed`
 	)
 
 	var forbidden, deleted bool
@@ -233,20 +262,12 @@ func (c *Controller) deleteAdvisory(ctx *gin.Context) {
 				return nil
 			}
 
-			tags, err := tx.Exec(rctx, deleteSQL, key.Publisher, key.TrackingID)
+			actor := c.currentUser(ctx)
+			tags, err := tx.Exec(rctx, trashSQL, key.Publisher, key.TrackingID, actor)
 			if err != nil {
 				return fmt.Errorf("deleting advisory documents failed: %w", err)
 			}
-			// Log if there were documents deleted.
-			if deleted = tags.RowsAffected() > 0; deleted {
-				actor := c.currentUser(ctx)
-				const eventSQL = `INSERT INTO events_log ` +
-					`(event, actor) ` +
-					`VALUES('delete_document'::events, $1)`
-				if _, err := tx.Exec(rctx, eventSQL, actor); err != nil {
-					return fmt.Errorf("event logging failed: %w", err)
-				}
-			}
+			deleted = tags.RowsAffected() > 0
 
 			return tx.Commit(rctx)
 		}, 0,
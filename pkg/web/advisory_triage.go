@@ -0,0 +1,623 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// maxNoteLength is the maximal length of an advisory note.
+const maxNoteLength = 10000
+
+// tagRe is the allowed form of an advisory tag.
+var tagRe = regexp.MustCompile(`^[\p{L}\p{N}_.:+-]{1,64}$`)
+
+type checklistItem struct {
+	Num       int64      `json:"num"`
+	Item      string     `json:"item"`
+	Done      bool       `json:"done"`
+	ChangedBy *string    `json:"changed_by,omitempty"`
+	Changed   *time.Time `json:"changed,omitempty"`
+}
+
+type checklistTemplates struct {
+	Default    string                     `json:"default"`
+	Checklists []config.ChecklistTemplate `json:"checklists"`
+}
+
+type advisoryNote struct {
+	ID      int64      `json:"id"`
+	Author  *string    `json:"author,omitempty"`
+	Time    time.Time  `json:"time"`
+	Changed *time.Time `json:"changed,omitempty"`
+	Note    string     `json:"note"`
+}
+
+// rowQuerier is implemented by connections and transactions.
+type rowQuerier interface {
+	QueryRow(context.Context, string, ...any) pgx.Row
+}
+
+// advisoryKey extracts the advisory key from the path.
+func advisoryKey(ctx *gin.Context) (models.AdvisoryKey, bool) {
+	var key models.AdvisoryKey
+	if err := ctx.ShouldBindUri(&key); err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return key, false
+	}
+	return key, true
+}
+
+// advisoryID resolves the id of the advisory the user is allowed to see.
+// It returns pgx.ErrNoRows if there is no such advisory.
+func (c *Controller) advisoryID(
+	rctx context.Context,
+	ctx *gin.Context,
+	db rowQuerier,
+	key models.AdvisoryKey,
+) (int64, error) {
+	expr := c.andTLPExpr(ctx,
+		query.FieldEqString("tracking_id", key.TrackingID).And(
+			query.FieldEqString("publisher", key.Publisher)))
+
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(expr)
+
+	idSQL := `SELECT advisories.id FROM documents ` +
+		`JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND ` + builder.WhereClause + ` LIMIT 1`
+
+	var id int64
+	err := db.QueryRow(rctx, idSQL, builder.Replacements...).Scan(&id)
+	return id, err
+}
+
+// sendTriageError sends the error of a triage operation.
+func sendTriageError(ctx *gin.Context, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "advisory not found")
+		return
+	}
+	slog.Error("database error", "err", err)
+	models.SendError(ctx, http.StatusInternalServerError, err)
+}
+
+// viewChecklistTemplates returns the configured checklist templates.
+//
+//	@Summary		Returns the checklist templates.
+//	@Description	Returns the configured templates of the advisory checklists.
+//	@Produce		json
+//	@Success		200	{object}	web.checklistTemplates
+//	@Failure		401
+//	@Router			/checklists [get]
+func (c *Controller) viewChecklistTemplates(ctx *gin.Context) {
+	templates := c.cfg.Triage.Checklists
+	if templates == nil {
+		templates = []config.ChecklistTemplate{}
+	}
+	ctx.JSON(http.StatusOK, checklistTemplates{
+		Default:    c.cfg.Triage.DefaultChecklist,
+		Checklists: templates,
+	})
+}
+
+// viewChecklist returns the checklist of an advisory.
+//
+//	@Summary		Returns the checklist of an advisory.
+//	@Description	Returns the items of the checklist of the specified advisory.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Produce		json
+//	@Success		200	{array}		web.checklistItem
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/checklist [get]
+func (c *Controller) viewChecklist(ctx *gin.Context) {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	const fetchSQL = `SELECT num, item, done, changed_by, changed ` +
+		`FROM advisory_checklists WHERE advisories_id = $1 ORDER BY num`
+
+	var items []checklistItem
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			id, err := c.advisoryID(rctx, ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			rows, _ := conn.Query(rctx, fetchSQL, id)
+			items, err = pgx.CollectRows(rows, pgx.RowToStructByPos[checklistItem])
+			return err
+		}, 0,
+	); err != nil {
+		sendTriageError(ctx, err)
+		return
+	}
+	if items == nil {
+		items = []checklistItem{}
+	}
+	ctx.JSON(http.StatusOK, items)
+}
+
+// createChecklist creates the checklist of an advisory from a template.
+//
+//	@Summary		Creates the checklist of an advisory.
+//	@Description	Replaces the checklist of the specified advisory with the items of the given template.
+//	@Param			publisher	path		string	true	"Publisher"
+//	@Param			trackingid	path		string	true	"Tracking ID"
+//	@Param			template	formData	string	false	"Template name"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		201	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/checklist [post]
+func (c *Controller) createChecklist(ctx *gin.Context) {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	template := c.cfg.Triage.Checklist(ctx.PostForm("template"))
+	if template == nil {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "unknown checklist template")
+		return
+	}
+	const (
+		deleteSQL = `DELETE FROM advisory_checklists WHERE advisories_id = $1`
+		insertSQL = `INSERT INTO advisory_checklists (advisories_id, num, item) ` +
+			`VALUES ($1, $2, $3)`
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			id, err := c.advisoryID(rctx, ctx, tx, key)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, deleteSQL, id); err != nil {
+				return err
+			}
+			for i, item := range template.Items {
+				if _, err := tx.Exec(rctx, insertSQL, id, i+1, item); err != nil {
+					return err
+				}
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendTriageError(ctx, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusCreated, "checklist created")
+}
+
+// updateChecklistItem checks or unchecks an item of the checklist of an advisory.
+//
+//	@Summary		Updates a checklist item.
+//	@Description	Marks an item of the checklist of the specified advisory as done or not done.
+//	@Param			publisher	path		string	true	"Publisher"
+//	@Param			trackingid	path		string	true	"Tracking ID"
+//	@Param			item		path		int		true	"Item number"
+//	@Param			done		formData	bool	true	"Item is done"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/checklist/{item} [put]
+func (c *Controller) updateChecklistItem(ctx *gin.Context) {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	num, ok := parse(ctx, toInt64, ctx.Param("item"))
+	if !ok {
+		return
+	}
+	done, ok := parse(ctx, strconv.ParseBool, ctx.PostForm("done"))
+	if !ok {
+		return
+	}
+	const updateSQL = `UPDATE advisory_checklists ` +
+		`SET done = $1, changed_by = $2, changed = $3 ` +
+		`WHERE advisories_id = $4 AND num = $5`
+
+	var found bool
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			id, err := c.advisoryID(rctx, ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			tags, err := conn.Exec(rctx, updateSQL,
+				done, c.currentUser(ctx), time.Now().UTC(), id, num)
+			if err != nil {
+				return err
+			}
+			found = tags.RowsAffected() > 0
+			return nil
+		}, 0,
+	); err != nil {
+		sendTriageError(ctx, err)
+		return
+	}
+	if !found {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "checklist item not found")
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "checklist item updated")
+}
+
+// viewNotes returns the notes of an advisory.
+//
+//	@Summary		Returns the notes of an advisory.
+//	@Description	Returns the internal notes of the specified advisory.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Produce		json
+//	@Success		200	{array}		web.advisoryNote
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/notes [get]
+func (c *Controller) viewNotes(ctx *gin.Context) {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	const fetchSQL = `SELECT id, author, time, changed, note ` +
+		`FROM advisory_notes WHERE advisories_id = $1 ORDER BY time DESC`
+
+	var notes []advisoryNote
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			id, err := c.advisoryID(rctx, ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			rows, _ := conn.Query(rctx, fetchSQL, id)
+			notes, err = pgx.CollectRows(rows, pgx.RowToStructByPos[advisoryNote])
+			return err
+		}, 0,
+	); err != nil {
+		sendTriageError(ctx, err)
+		return
+	}
+	if notes == nil {
+		notes = []advisoryNote{}
+	}
+	ctx.JSON(http.StatusOK, notes)
+}
+
+// noteText extracts the text of a note from the form.
+func noteText(ctx *gin.Context) (string, bool) {
+	note := strings.TrimSpace(ctx.PostForm("note"))
+	switch {
+	case note == "":
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "missing note")
+		return "", false
+	case len(note) > maxNoteLength:
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "note too long")
+		return "", false
+	}
+	return note, true
+}
+
+// createNote adds a note to an advisory.
+//
+//	@Summary		Adds a note to an advisory.
+//	@Description	Adds an internal note to the specified advisory.
+//	@Param			publisher	path		string	true	"Publisher"
+//	@Param			trackingid	path		string	true	"Tracking ID"
+//	@Param			note		formData	string	true	"Note"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		201	{object}	web.advisoryNote
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/notes [post]
+func (c *Controller) createNote(ctx *gin.Context) {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	text, ok := noteText(ctx)
+	if !ok {
+		return
+	}
+	const insertSQL = `INSERT INTO advisory_notes (advisories_id, author, time, note) ` +
+		`VALUES ($1, $2, $3, $4) RETURNING id`
+
+	author := c.currentUser(ctx)
+	note := advisoryNote{
+		Time: time.Now().UTC(),
+		Note: text,
+	}
+	if author.Valid {
+		note.Author = &author.String
+	}
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			id, err := c.advisoryID(rctx, ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			return conn.QueryRow(rctx, insertSQL,
+				id, author, note.Time, note.Note).Scan(&note.ID)
+		}, 0,
+	); err != nil {
+		sendTriageError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, note)
+}
+
+// changeNote updates or deletes a note of an advisory.
+// Only the author of the note or an admin are allowed to do so.
+func (c *Controller) changeNote(ctx *gin.Context, text *string) bool {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return false
+	}
+	noteID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return false
+	}
+	const (
+		authorSQL = `SELECT author FROM advisory_notes ` +
+			`WHERE id = $1 AND advisories_id = $2 FOR UPDATE`
+		updateSQL = `UPDATE advisory_notes SET note = $1, changed = $2 WHERE id = $3`
+		deleteSQL = `DELETE FROM advisory_notes WHERE id = $1`
+	)
+	var forbidden bool
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			id, err := c.advisoryID(rctx, ctx, tx, key)
+			if err != nil {
+				return err
+			}
+			var author *string
+			if err := tx.QueryRow(rctx, authorSQL, noteID, id).Scan(&author); err != nil {
+				return err
+			}
+			if (author == nil || *author != ctx.GetString("uid")) && !c.hasAnyRole(ctx, models.Admin) {
+				forbidden = true
+				return nil
+			}
+			if text != nil {
+				_, err = tx.Exec(rctx, updateSQL, *text, time.Now().UTC(), noteID)
+			} else {
+				_, err = tx.Exec(rctx, deleteSQL, noteID)
+			}
+			if err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			models.SendErrorMessage(ctx, http.StatusNotFound, "note not found")
+		} else {
+			sendTriageError(ctx, err)
+		}
+		return false
+	}
+	if forbidden {
+		models.SendErrorMessage(ctx, http.StatusForbidden, "not allowed to change note")
+		return false
+	}
+	return true
+}
+
+// updateNote updates a note of an advisory.
+//
+//	@Summary		Updates a note of an advisory.
+//	@Description	Updates an internal note of the specified advisory. Only the author or an admin may do so.
+//	@Param			publisher	path		string	true	"Publisher"
+//	@Param			trackingid	path		string	true	"Tracking ID"
+//	@Param			id			path		int		true	"Note ID"
+//	@Param			note		formData	string	true	"Note"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/notes/{id} [put]
+func (c *Controller) updateNote(ctx *gin.Context) {
+	text, ok := noteText(ctx)
+	if !ok {
+		return
+	}
+	if c.changeNote(ctx, &text) {
+		models.SendSuccess(ctx, http.StatusOK, "note updated")
+	}
+}
+
+// deleteNote deletes a note of an advisory.
+//
+//	@Summary		Deletes a note of an advisory.
+//	@Description	Deletes an internal note of the specified advisory. Only the author or an admin may do so.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Param			id			path	int		true	"Note ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/notes/{id} [delete]
+func (c *Controller) deleteNote(ctx *gin.Context) {
+	if c.changeNote(ctx, nil) {
+		models.SendSuccess(ctx, http.StatusOK, "note deleted")
+	}
+}
+
+// viewTags returns the tags of an advisory.
+//
+//	@Summary		Returns the tags of an advisory.
+//	@Description	Returns the tags the specified advisory is labeled with.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Produce		json
+//	@Success		200	{array}		string
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/tags [get]
+func (c *Controller) viewTags(ctx *gin.Context) {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	const fetchSQL = `SELECT tag FROM advisory_tags WHERE advisories_id = $1 ORDER BY tag`
+
+	var tags []string
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			id, err := c.advisoryID(rctx, ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			rows, _ := conn.Query(rctx, fetchSQL, id)
+			tags, err = pgx.CollectRows(rows, pgx.RowTo[string])
+			return err
+		}, 0,
+	); err != nil {
+		sendTriageError(ctx, err)
+		return
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	ctx.JSON(http.StatusOK, tags)
+}
+
+// changeTag adds or removes a tag of an advisory.
+func (c *Controller) changeTag(ctx *gin.Context, add bool) bool {
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return false
+	}
+	tag := ctx.Param("tag")
+	if !tagRe.MatchString(tag) {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid tag")
+		return false
+	}
+	actor := c.currentUser(ctx)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			id, err := c.advisoryID(rctx, ctx, tx, key)
+			if err != nil {
+				return err
+			}
+			if err := tagAdvisory(rctx, tx, id, tag, add, actor); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendTagError(ctx, err)
+		return false
+	}
+	return true
+}
+
+// addTag labels an advisory with a tag.
+//
+//	@Summary		Adds a tag to an advisory.
+//	@Description	Labels the specified advisory with the given defined tag.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Param			tag			path	string	true	"Tag"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/tags/{tag} [put]
+func (c *Controller) addTag(ctx *gin.Context) {
+	if c.changeTag(ctx, true) {
+		models.SendSuccess(ctx, http.StatusOK, "tag added")
+	}
+}
+
+// deleteTag removes a tag from an advisory.
+//
+//	@Summary		Removes a tag from an advisory.
+//	@Description	Removes the given tag from the specified advisory.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Param			tag			path	string	true	"Tag"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisory/{publisher}/{trackingid}/tags/{tag} [delete]
+func (c *Controller) deleteTag(ctx *gin.Context) {
+	if c.changeTag(ctx, false) {
+		models.SendSuccess(ctx, http.StatusOK, "tag removed")
+	}
+}
@@ -17,7 +17,9 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ISDuBA/ISDuBA/pkg/aggregators"
 	"github.com/ISDuBA/ISDuBA/pkg/models"
 	"github.com/ISDuBA/ISDuBA/pkg/sources"
 	"github.com/gin-gonic/gin"
@@ -30,6 +32,7 @@ type custom struct {
 	ID            int64                         `json:"id,omitempty"`
 	Name          string                        `json:"name,omitempty"`
 	Attention     *bool                         `json:"attention,omitempty"`
+	Provisioning  string                        `json:"provisioning,omitempty"`
 	Subscriptions []sources.SourceSubscriptions `json:"subscriptions,omitempty"`
 }
 
@@ -58,17 +61,18 @@ func (c *Controller) aggregatorProxy(ctx *gin.Context) {
 	}
 	// search in database
 	const sql = `SELECT ` +
-		`id, name, (checksum_ack < checksum_updated) AS attention ` +
+		`id, name, (checksum_ack < checksum_updated) AS attention, provisioning::text ` +
 		`FROM aggregators WHERE url = $1`
 	var (
-		id        int64
-		name      string
-		attention bool
+		id           int64
+		name         string
+		attention    bool
+		provisioning string
 	)
 	if err := c.db.Run(
 		ctx.Request.Context(),
 		func(rctx context.Context, conn *pgxpool.Conn) error {
-			return conn.QueryRow(rctx, sql, url).Scan(&id, &name, &attention)
+			return conn.QueryRow(rctx, sql, url).Scan(&id, &name, &attention, &provisioning)
 		}, 0,
 	); err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		slog.Error("fetching aggregator failed", "err", err)
@@ -82,6 +86,7 @@ func (c *Controller) aggregatorProxy(ctx *gin.Context) {
 		custom.ID = id
 		custom.Name = name
 		custom.Attention = &attention
+		custom.Provisioning = provisioning
 	}
 	aAgg := argumentedAggregator{
 		Aggregator: ca.Raw,
@@ -101,15 +106,17 @@ func (c *Controller) aggregatorProxy(ctx *gin.Context) {
 //	@Router			/aggregators [get]
 func (c *Controller) viewAggregators(ctx *gin.Context) {
 	type aggregator struct {
-		ID        int64  `json:"id"`
-		Name      string `json:"name"`
-		URL       string `json:"url"`
-		Active    bool   `json:"active"`
-		Attention bool   `json:"attention"`
+		ID           int64  `json:"id"`
+		Name         string `json:"name"`
+		URL          string `json:"url"`
+		Active       bool   `json:"active"`
+		Attention    bool   `json:"attention"`
+		Provisioning string `json:"provisioning"`
 	}
 	var list []aggregator
 	const sql = `SELECT ` +
-		`id, name, url, active, (checksum_ack < checksum_updated) AS attention ` +
+		`id, name, url, active, (checksum_ack < checksum_updated) AS attention, ` +
+		`provisioning::text ` +
 		`FROM aggregators ORDER by name`
 	if err := c.db.Run(
 		ctx.Request.Context(),
@@ -118,7 +125,7 @@ func (c *Controller) viewAggregators(ctx *gin.Context) {
 			var err error
 			list, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (aggregator, error) {
 				var a aggregator
-				err := row.Scan(&a.ID, &a.Name, &a.URL, &a.Active, &a.Attention)
+				err := row.Scan(&a.ID, &a.Name, &a.URL, &a.Active, &a.Attention, &a.Provisioning)
 				return a, err
 			})
 			return err
@@ -150,18 +157,21 @@ func (c *Controller) viewAggregator(ctx *gin.Context) {
 		return
 	}
 	var (
-		name      string
-		url       string
-		active    bool
-		attention bool
+		name         string
+		url          string
+		active       bool
+		attention    bool
+		provisioning string
 	)
 	const sql = `SELECT ` +
-		`name, url, active, (checksum_ack < checksum_updated) AS attention ` +
+		`name, url, active, (checksum_ack < checksum_updated) AS attention, ` +
+		`provisioning::text ` +
 		`FROM aggregators WHERE id = $1`
 	switch err := c.db.Run(
 		ctx.Request.Context(),
 		func(rctx context.Context, conn *pgxpool.Conn) error {
-			return conn.QueryRow(rctx, sql, id).Scan(&name, &url, &active, &attention)
+			return conn.QueryRow(rctx, sql, id).Scan(
+				&name, &url, &active, &attention, &provisioning)
 		}, 0,
 	); {
 	case errors.Is(err, pgx.ErrNoRows):
@@ -183,6 +193,7 @@ func (c *Controller) viewAggregator(ctx *gin.Context) {
 			ID:            id,
 			Name:          name,
 			Attention:     &attention,
+			Provisioning:  provisioning,
 			Subscriptions: c.sm.Subscriptions(ca.SourceURLs()),
 		},
 	}
@@ -193,8 +204,9 @@ func (c *Controller) viewAggregator(ctx *gin.Context) {
 //
 //	@Summary		Creates an aggregator.
 //	@Description	Creates an aggregator with specified configuration.
-//	@Param			name	formData	string	true	"Aggregator name"
-//	@Param			url		formData	string	true	"Aggregator URL"
+//	@Param			name			formData	string	true	"Aggregator name"
+//	@Param			url				formData	string	true	"Aggregator URL"
+//	@Param			provisioning	formData	string	false	"Source provisioning mode (off, review, auto)"
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Success		201	{object}	models.ID
@@ -206,11 +218,12 @@ func (c *Controller) viewAggregator(ctx *gin.Context) {
 //	@Router			/aggregators [post]
 func (c *Controller) createAggregator(ctx *gin.Context) {
 	var (
-		ok     bool
-		name   string
-		url    string
-		active bool
-		id     int64
+		ok           bool
+		name         string
+		url          string
+		active       bool
+		provisioning = aggregators.ProvisioningOff
+		id           int64
 	)
 	if name, ok = parse(ctx, notEmpty, ctx.PostForm("name")); !ok {
 		return
@@ -226,12 +239,18 @@ func (c *Controller) createAggregator(ctx *gin.Context) {
 			return
 		}
 	}
+	if provisioningParam, ok := ctx.GetPostForm("provisioning"); ok {
+		if provisioning, ok = parse(ctx, aggregators.ParseProvisioning, provisioningParam); !ok {
+			return
+		}
+	}
 
-	const sql = `INSERT INTO aggregators (name, url, active) VALUES ($1, $2, $3) RETURNING id`
+	const sql = `INSERT INTO aggregators (name, url, active, provisioning) ` +
+		`VALUES ($1, $2, $3, $4::aggregator_provisioning) RETURNING id`
 	if err := c.db.Run(
 		ctx.Request.Context(),
 		func(rctx context.Context, conn *pgxpool.Conn) error {
-			return conn.QueryRow(rctx, sql, name, url, active).Scan(&id)
+			return conn.QueryRow(rctx, sql, name, url, active, string(provisioning)).Scan(&id)
 		}, 0,
 	); err != nil {
 		var pgErr *pgconn.PgError
@@ -318,11 +337,12 @@ func (c *Controller) attentionAggregators(ctx *gin.Context) {
 //
 //	@Summary		Updates aggregator configuration.
 //	@Description	Updates the aggregator configuration.
-//	@Param			id			path		int		true	"Aggregator ID"
-//	@Param			name		formData	string	false	"Aggregator name"
-//	@Param			url			formData	string	false	"Aggregator URL"
-//	@Param			active		formData	bool	false	"Aggregator active flag"
-//	@Param			attention	formData	bool	false	"Aggregator attention flag"
+//	@Param			id				path		int		true	"Aggregator ID"
+//	@Param			name			formData	string	false	"Aggregator name"
+//	@Param			url				formData	string	false	"Aggregator URL"
+//	@Param			active			formData	bool	false	"Aggregator active flag"
+//	@Param			attention		formData	bool	false	"Aggregator attention flag"
+//	@Param			provisioning	formData	string	false	"Source provisioning mode (off, review, auto)"
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Success		200	{object}	models.Success
@@ -384,6 +404,14 @@ func (c *Controller) updateAggregator(ctx *gin.Context) {
 			fields = append(fields, sqlAttFalse)
 		}
 	}
+	if provisioningParam, ok := ctx.GetPostForm("provisioning"); ok {
+		prov, ok := parse(ctx, aggregators.ParseProvisioning, provisioningParam)
+		if !ok {
+			return
+		}
+		values = append(values, string(prov))
+		fields = append(fields, fmt.Sprintf("provisioning = $%d::aggregator_provisioning", len(values)))
+	}
 
 	if len(fields) == 0 {
 		models.SendSuccess(ctx, http.StatusOK, "unchanged")
@@ -422,3 +450,127 @@ func (c *Controller) updateAggregator(ctx *gin.Context) {
 		models.SendSuccess(ctx, http.StatusOK, "unchanged")
 	}
 }
+
+// aggregatorAction is a source provisioning action of an aggregator.
+type aggregatorAction struct {
+	ID           int64      `json:"id"`
+	AggregatorID int64      `json:"aggregator_id"`
+	Action       string     `json:"action"`
+	URL          string     `json:"url"`
+	SourceID     *int64     `json:"source_id,omitempty"`
+	State        string     `json:"state"`
+	Time         time.Time  `json:"time"`
+	Decided      *time.Time `json:"decided,omitempty"`
+	Actor        *string    `json:"actor,omitempty"`
+	Message      *string    `json:"message,omitempty"`
+}
+
+// viewAggregatorActions is an endpoint that returns the source provisioning actions.
+//
+//	@Summary		Returns the source provisioning actions.
+//	@Description	Returns the pending and the recorded source provisioning actions of the aggregators.
+//	@Param			aggregator	query	int		false	"Aggregator ID"
+//	@Param			state		query	string	false	"State (pending, applied, rejected)"
+//	@Produce		json
+//	@Success		200	{array}		aggregatorAction
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/aggregators/actions [get]
+func (c *Controller) viewAggregatorActions(ctx *gin.Context) {
+	var (
+		values []any
+		conds  []string
+	)
+	if aggregatorParam, ok := ctx.GetQuery("aggregator"); ok {
+		id, ok := parse(ctx, toInt64, aggregatorParam)
+		if !ok {
+			return
+		}
+		values = append(values, id)
+		conds = append(conds, fmt.Sprintf("aggregators_id = $%d", len(values)))
+	}
+	if state, ok := ctx.GetQuery("state"); ok {
+		switch state {
+		case "pending", "applied", "rejected":
+		default:
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid state")
+			return
+		}
+		values = append(values, state)
+		conds = append(conds, fmt.Sprintf("state = $%d::aggregator_action_state", len(values)))
+	}
+	sql := `SELECT id, aggregators_id, action::text, url, sources_id, ` +
+		`state::text, time, decided, actor, message ` +
+		`FROM aggregator_actions`
+	if len(conds) > 0 {
+		sql += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	sql += ` ORDER BY id DESC`
+
+	var list []aggregatorAction
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, sql, values...)
+			var err error
+			list, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (aggregatorAction, error) {
+				var a aggregatorAction
+				err := row.Scan(
+					&a.ID, &a.AggregatorID, &a.Action, &a.URL, &a.SourceID,
+					&a.State, &a.Time, &a.Decided, &a.Actor, &a.Message)
+				return a, err
+			})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("fetching aggregator actions failed", "error", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// decideAggregatorAction is an endpoint that applies or rejects a pending source provisioning action.
+//
+//	@Summary		Applies or rejects a source provisioning action.
+//	@Description	Applies or rejects the pending source provisioning action with the specified ID.
+//	@Param			id		path		int		true	"Action ID"
+//	@Param			accept	formData	bool	true	"Apply or reject the action"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/aggregators/actions/{id} [put]
+func (c *Controller) decideAggregatorAction(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	accept, ok := parse(ctx, strconv.ParseBool, ctx.PostForm("accept"))
+	if !ok {
+		return
+	}
+	var actor *string
+	if user := c.currentUser(ctx); user.Valid {
+		actor = &user.String
+	}
+	switch err := c.am.DecideAction(ctx.Request.Context(), id, actor, accept); {
+	case err == nil:
+		if accept {
+			models.SendSuccess(ctx, http.StatusOK, "applied")
+		} else {
+			models.SendSuccess(ctx, http.StatusOK, "rejected")
+		}
+	case errors.Is(err, aggregators.ErrNoSuchAction):
+		models.SendError(ctx, http.StatusNotFound, err)
+	case errors.Is(err, sources.InvalidArgumentError("")):
+		models.SendError(ctx, http.StatusBadRequest, err)
+	default:
+		slog.Error("deciding aggregator action failed", "error", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+	}
+}
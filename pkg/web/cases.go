@@ -0,0 +1,757 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// casePeersSQL selects the other advisories of the case of the advisory
+// given by the placeholder if the case shares the given part of the workflow.
+func casePeersSQL(shared string, placeholder int) string {
+	return `SELECT peers.advisories_id FROM vulnerability_case_advisories vca ` +
+		`JOIN vulnerability_cases vc ON vc.id = vca.cases_id AND vc.shared_` + shared + ` ` +
+		`JOIN vulnerability_case_advisories peers ON peers.cases_id = vca.cases_id ` +
+		fmt.Sprintf(`WHERE vca.advisories_id = $%d `, placeholder) +
+		`AND peers.advisories_id <> vca.advisories_id`
+}
+
+// loadCasePeers loads the other advisories of the case of the advisory
+// with their latest documents if the case shares the given part of the workflow.
+// The peers are locked until the end of the transaction.
+func loadCasePeers(
+	rctx context.Context,
+	tx pgx.Tx,
+	shared string,
+	advisoryID int64,
+) ([]models.CasePeer, error) {
+	peersSQL := `SELECT ads.id, docs.id, ads.publisher, docs.tlp, ads.state::text ` +
+		`FROM advisories ads JOIN documents docs ON docs.advisories_id = ads.id ` +
+		`WHERE docs.latest AND docs.deleted IS NULL ` +
+		`AND ads.id IN (` + casePeersSQL(shared, 1) + `) ` +
+		`ORDER BY ads.id FOR UPDATE OF ads`
+	rows, _ := tx.Query(rctx, peersSQL, advisoryID)
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.CasePeer, error) {
+		var (
+			peer       models.CasePeer
+			tlp, state string
+		)
+		err := row.Scan(&peer.AdvisoryID, &peer.DocumentID, &peer.Publisher, &tlp, &state)
+		peer.TLP, peer.State = models.TLP(tlp), models.Workflow(state)
+		return peer, err
+	})
+}
+
+type caseInput struct {
+	Name           *string              `json:"name,omitempty"`
+	SharedState    *bool                `json:"shared_state,omitempty"`
+	SharedSSVC     *bool                `json:"shared_ssvc,omitempty"`
+	SharedComments *bool                `json:"shared_comments,omitempty"`
+	Advisories     []models.AdvisoryKey `json:"advisories,omitempty"`
+}
+
+type clusterResult struct {
+	Created  int `json:"created"`
+	Assigned int `json:"assigned"`
+}
+
+// errCaseForbidden is returned if the user is not allowed
+// to see all advisories of a case.
+var errCaseForbidden = errors.New("access denied")
+
+// sendCaseError sends the error of a case operation.
+func sendCaseError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+	case errors.Is(err, errCaseForbidden):
+		models.SendError(ctx, http.StatusForbidden, err)
+	default:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// checkCase checks if the case exists and that the user
+// is allowed to see all of its advisories.
+func (c *Controller) checkCase(
+	rctx context.Context,
+	ctx *gin.Context,
+	tx pgx.Tx,
+	caseID int64,
+) error {
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(c.andTLPExpr(ctx, query.BoolField("latest")))
+	idx := len(builder.Replacements) + 1
+	checkSQL := `SELECT count(vca.advisories_id), count(*) FILTER (WHERE vca.advisories_id IN (` +
+		`SELECT documents.advisories_id FROM documents ` +
+		`JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND ` + builder.WhereClause + `)) ` +
+		`FROM vulnerability_cases vc LEFT JOIN vulnerability_case_advisories vca ` +
+		`ON vca.cases_id = vc.id ` +
+		fmt.Sprintf(`WHERE vc.id = $%d GROUP BY vc.id`, idx)
+	var all, visible int64
+	if err := tx.QueryRow(rctx, checkSQL,
+		append(builder.Replacements, caseID)...).Scan(&all, &visible); err != nil {
+		return err
+	}
+	if all != visible {
+		return errCaseForbidden
+	}
+	return nil
+}
+
+// assignAdvisories moves the given advisories to the case.
+// Advisories are only moved out of other cases the user has full access to.
+func (c *Controller) assignAdvisories(
+	rctx context.Context,
+	ctx *gin.Context,
+	tx pgx.Tx,
+	caseID int64,
+	keys []models.AdvisoryKey,
+) error {
+	const (
+		currentSQL = `SELECT cases_id FROM vulnerability_case_advisories ` +
+			`WHERE advisories_id = $1 FOR UPDATE`
+		assignSQL = `INSERT INTO vulnerability_case_advisories (cases_id, advisories_id) ` +
+			`VALUES ($1, $2) ON CONFLICT (advisories_id) DO UPDATE SET cases_id = $1`
+	)
+	for _, key := range keys {
+		id, err := c.advisoryID(rctx, ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		var current int64
+		switch err := tx.QueryRow(rctx, currentSQL, id).Scan(&current); {
+		case errors.Is(err, pgx.ErrNoRows):
+		case err != nil:
+			return err
+		case current != caseID:
+			if err := c.checkCase(rctx, ctx, tx, current); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(rctx, assignSQL, caseID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeEmptyCases deletes the cases without advisories.
+func removeEmptyCases(rctx context.Context, tx pgx.Tx) error {
+	const deleteSQL = `DELETE FROM vulnerability_cases vc WHERE NOT EXISTS (` +
+		`SELECT 1 FROM vulnerability_case_advisories vca WHERE vca.cases_id = vc.id)`
+	_, err := tx.Exec(rctx, deleteSQL)
+	return err
+}
+
+// fetchCases loads the cases with the advisories the user is allowed to see.
+// If caseID is not nil only this case is loaded.
+func (c *Controller) fetchCases(
+	rctx context.Context,
+	ctx *gin.Context,
+	conn *pgxpool.Conn,
+	caseID *int64,
+) ([]*models.VulnerabilityCase, error) {
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(c.andTLPExpr(ctx, query.BoolField("latest")))
+	var b strings.Builder
+	b.WriteString(`SELECT vc.id, vc.name, vc.created, ` +
+		`vc.shared_state, vc.shared_ssvc, vc.shared_comments, ` +
+		`advisories.publisher, advisories.tracking_id, advisories.state::text ` +
+		`FROM vulnerability_cases vc ` +
+		`JOIN vulnerability_case_advisories vca ON vca.cases_id = vc.id ` +
+		`JOIN advisories ON advisories.id = vca.advisories_id ` +
+		`JOIN documents ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND `)
+	b.WriteString(builder.WhereClause)
+	args := builder.Replacements
+	if caseID != nil {
+		args = append(args, *caseID)
+		fmt.Fprintf(&b, ` AND vc.id = $%d`, len(args))
+	}
+	b.WriteString(` ORDER BY vc.id, advisories.publisher, advisories.tracking_id`)
+
+	rows, err := conn.Query(rctx, b.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cases []*models.VulnerabilityCase
+	for rows.Next() {
+		var (
+			vc  models.VulnerabilityCase
+			adv models.AdvisoryState
+		)
+		if err := rows.Scan(
+			&vc.ID, &vc.Name, &vc.Created,
+			&vc.SharedState, &vc.SharedSSVC, &vc.SharedComments,
+			&adv.Publisher, &adv.TrackingID, &adv.State,
+		); err != nil {
+			return nil, err
+		}
+		if n := len(cases); n == 0 || cases[n-1].ID != vc.ID {
+			vc.Created = vc.Created.UTC()
+			cases = append(cases, &vc)
+		}
+		last := cases[len(cases)-1]
+		last.Advisories = append(last.Advisories, adv)
+	}
+	return cases, rows.Err()
+}
+
+// viewCases returns the vulnerability cases.
+//
+//	@Summary		Returns the vulnerability cases.
+//	@Description	Returns the vulnerability cases with the advisories the user is allowed to see.
+//	@Produce		json
+//	@Success		200	{array}		models.VulnerabilityCase
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases [get]
+func (c *Controller) viewCases(ctx *gin.Context) {
+	var cases []*models.VulnerabilityCase
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var err error
+			cases, err = c.fetchCases(rctx, ctx, conn, nil)
+			return err
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	if cases == nil {
+		cases = []*models.VulnerabilityCase{}
+	}
+	ctx.JSON(http.StatusOK, cases)
+}
+
+// viewCase returns a vulnerability case.
+//
+//	@Summary		Returns a vulnerability case.
+//	@Description	Returns the vulnerability case with the advisories the user is allowed to see.
+//	@Param			id	path	int	true	"Case ID"
+//	@Produce		json
+//	@Success		200	{object}	models.VulnerabilityCase
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/{id} [get]
+func (c *Controller) viewCase(ctx *gin.Context) {
+	caseID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	var cases []*models.VulnerabilityCase
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var err error
+			cases, err = c.fetchCases(rctx, ctx, conn, &caseID)
+			return err
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	if len(cases) == 0 {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "case not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, cases[0])
+}
+
+// createCase creates a vulnerability case.
+//
+//	@Summary		Creates a vulnerability case.
+//	@Description	Creates a vulnerability case. The given advisories are moved from their former cases
+//	@Description	if the user is allowed to see all advisories of these.
+//	@Param			input	body	web.caseInput	true	"Case"
+//	@Accept			json
+//	@Produce		json
+//	@Success		201	{object}	models.ID
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases [post]
+func (c *Controller) createCase(ctx *gin.Context) {
+	var input caseInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if input.Name == nil || strings.TrimSpace(*input.Name) == "" {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "missing name")
+		return
+	}
+	const insertSQL = `INSERT INTO vulnerability_cases ` +
+		`(name, shared_state, shared_ssvc, shared_comments) ` +
+		`VALUES ($1, $2, $3, $4) RETURNING id`
+	var id int64
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			if err := tx.QueryRow(rctx, insertSQL,
+				strings.TrimSpace(*input.Name),
+				input.SharedState != nil && *input.SharedState,
+				input.SharedSSVC != nil && *input.SharedSSVC,
+				input.SharedComments != nil && *input.SharedComments,
+			).Scan(&id); err != nil {
+				return err
+			}
+			if err := c.assignAdvisories(rctx, ctx, tx, id, input.Advisories); err != nil {
+				return err
+			}
+			if err := removeEmptyCases(rctx, tx); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, models.ID{ID: id})
+}
+
+// updateCase updates a vulnerability case.
+//
+//	@Summary		Updates a vulnerability case.
+//	@Description	Updates the name and the sharing of a vulnerability case and adds the given advisories.
+//	@Param			id		path	int				true	"Case ID"
+//	@Param			input	body	web.caseInput	true	"Changes"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/{id} [put]
+func (c *Controller) updateCase(ctx *gin.Context) {
+	caseID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	var input caseInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "empty name")
+		return
+	}
+	var (
+		sets []string
+		args []any
+	)
+	set := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if input.Name != nil {
+		set("name", strings.TrimSpace(*input.Name))
+	}
+	if input.SharedState != nil {
+		set("shared_state", *input.SharedState)
+	}
+	if input.SharedSSVC != nil {
+		set("shared_ssvc", *input.SharedSSVC)
+	}
+	if input.SharedComments != nil {
+		set("shared_comments", *input.SharedComments)
+	}
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			if err := c.checkCase(rctx, ctx, tx, caseID); err != nil {
+				return err
+			}
+			if len(sets) > 0 {
+				updateSQL := `UPDATE vulnerability_cases SET ` + strings.Join(sets, ", ") +
+					fmt.Sprintf(` WHERE id = $%d`, len(args)+1)
+				if _, err := tx.Exec(rctx, updateSQL, append(args, caseID)...); err != nil {
+					return err
+				}
+			}
+			if err := c.assignAdvisories(rctx, ctx, tx, caseID, input.Advisories); err != nil {
+				return err
+			}
+			if err := removeEmptyCases(rctx, tx); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "case updated")
+}
+
+// deleteCase deletes a vulnerability case.
+//
+//	@Summary		Deletes a vulnerability case.
+//	@Description	Deletes a vulnerability case. The advisories are kept.
+//	@Param			id	path	int	true	"Case ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/{id} [delete]
+func (c *Controller) deleteCase(ctx *gin.Context) {
+	caseID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	const deleteSQL = `DELETE FROM vulnerability_cases WHERE id = $1`
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			if err := c.checkCase(rctx, ctx, tx, caseID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, deleteSQL, caseID); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "case deleted")
+}
+
+// mergeCases merges a vulnerability case into another one.
+//
+//	@Summary		Merges vulnerability cases.
+//	@Description	Moves the advisories of the other case into the case and deletes the other one.
+//	@Param			id		path	int	true	"Case ID"
+//	@Param			other	path	int	true	"ID of the case to merge"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/{id}/merge/{other} [post]
+func (c *Controller) mergeCases(ctx *gin.Context) {
+	caseID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	otherID, ok := parse(ctx, toInt64, ctx.Param("other"))
+	if !ok {
+		return
+	}
+	if caseID == otherID {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "cannot merge case with itself")
+		return
+	}
+	const (
+		moveSQL   = `UPDATE vulnerability_case_advisories SET cases_id = $1 WHERE cases_id = $2`
+		deleteSQL = `DELETE FROM vulnerability_cases WHERE id = $1`
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			for _, id := range []int64{caseID, otherID} {
+				if err := c.checkCase(rctx, ctx, tx, id); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec(rctx, moveSQL, caseID, otherID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(rctx, deleteSQL, otherID); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "cases merged")
+}
+
+// splitCase moves advisories of a vulnerability case into a new one.
+//
+//	@Summary		Splits a vulnerability case.
+//	@Description	Moves the given advisories of the case into a new case with the same sharing.
+//	@Param			id		path	int				true	"Case ID"
+//	@Param			input	body	web.caseInput	true	"Name and advisories of the new case"
+//	@Accept			json
+//	@Produce		json
+//	@Success		201	{object}	models.ID
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/{id}/split [post]
+func (c *Controller) splitCase(ctx *gin.Context) {
+	caseID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	var input caseInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if len(input.Advisories) == 0 {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "missing advisories")
+		return
+	}
+	const (
+		insertSQL = `INSERT INTO vulnerability_cases ` +
+			`(name, shared_state, shared_ssvc, shared_comments) ` +
+			`SELECT coalesce($1, name || ' (split)'), shared_state, shared_ssvc, shared_comments ` +
+			`FROM vulnerability_cases WHERE id = $2 RETURNING id`
+		moveSQL = `UPDATE vulnerability_case_advisories SET cases_id = $1 ` +
+			`WHERE cases_id = $2 AND advisories_id = $3`
+	)
+	var name *string
+	if input.Name != nil && strings.TrimSpace(*input.Name) != "" {
+		trimmed := strings.TrimSpace(*input.Name)
+		name = &trimmed
+	}
+	var id int64
+	var notMember bool
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			if err := c.checkCase(rctx, ctx, tx, caseID); err != nil {
+				return err
+			}
+			if err := tx.QueryRow(rctx, insertSQL, name, caseID).Scan(&id); err != nil {
+				return err
+			}
+			for _, key := range input.Advisories {
+				advID, err := c.advisoryID(rctx, ctx, tx, key)
+				if err != nil {
+					return err
+				}
+				tags, err := tx.Exec(rctx, moveSQL, id, caseID, advID)
+				if err != nil {
+					return err
+				}
+				if tags.RowsAffected() == 0 {
+					notMember = true
+					return nil
+				}
+			}
+			if err := removeEmptyCases(rctx, tx); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	if notMember {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "advisory not in case")
+		return
+	}
+	ctx.JSON(http.StatusCreated, models.ID{ID: id})
+}
+
+// removeCaseAdvisory removes an advisory from its vulnerability case.
+//
+//	@Summary		Removes an advisory from a vulnerability case.
+//	@Description	Removes the advisory from the case. Cases without advisories are deleted.
+//	@Param			id			path	int		true	"Case ID"
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/{id}/advisories/{publisher}/{trackingid} [delete]
+func (c *Controller) removeCaseAdvisory(ctx *gin.Context) {
+	caseID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	key, ok := advisoryKey(ctx)
+	if !ok {
+		return
+	}
+	const deleteSQL = `DELETE FROM vulnerability_case_advisories ` +
+		`WHERE cases_id = $1 AND advisories_id = $2`
+	var found bool
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			advID, err := c.advisoryID(rctx, ctx, tx, key)
+			if err != nil {
+				return err
+			}
+			tags, err := tx.Exec(rctx, deleteSQL, caseID, advID)
+			if err != nil {
+				return err
+			}
+			if found = tags.RowsAffected() > 0; !found {
+				return nil
+			}
+			if err := removeEmptyCases(rctx, tx); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	if !found {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "advisory not in case")
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "advisory removed from case")
+}
+
+// clusterCases clusters the advisories into vulnerability cases by shared CVEs.
+//
+//	@Summary		Clusters advisories by shared CVEs.
+//	@Description	Assigns advisories sharing CVEs to a common vulnerability case. Advisories already in a case are not moved.
+//	@Produce		json
+//	@Success		200	{object}	web.clusterResult
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/cases/cluster [post]
+func (c *Controller) clusterCases(ctx *gin.Context) {
+	const (
+		cvesSQL = `SELECT DISTINCT documents.advisories_id, unique_cves.cve ` +
+			`FROM documents_cves ` +
+			`JOIN unique_cves ON documents_cves.cve_id = unique_cves.id ` +
+			`JOIN documents ON documents_cves.documents_id = documents.id ` +
+			`WHERE documents.latest AND documents.deleted IS NULL`
+		membersSQL = `SELECT advisories_id, cases_id FROM vulnerability_case_advisories`
+		insertSQL  = `INSERT INTO vulnerability_cases (name) VALUES ($1) RETURNING id`
+		assignSQL  = `INSERT INTO vulnerability_case_advisories (cases_id, advisories_id) ` +
+			`VALUES ($1, $2)`
+	)
+	var result clusterResult
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+
+			rows, _ := tx.Query(rctx, cvesSQL)
+			pairs, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.AdvisoryCVE])
+			if err != nil {
+				return fmt.Errorf("loading CVEs failed: %w", err)
+			}
+			members := map[int64]int64{}
+			rows, _ = tx.Query(rctx, membersSQL)
+			var advID, caseID int64
+			if _, err := pgx.ForEachRow(rows, []any{&advID, &caseID}, func() error {
+				members[advID] = caseID
+				return nil
+			}); err != nil {
+				return fmt.Errorf("loading cases failed: %w", err)
+			}
+
+			for _, cluster := range models.ClusterByCVEs(pairs) {
+				// Join the existing case with the lowest id, if any.
+				var target int64
+				for _, id := range cluster.Advisories {
+					if cid, ok := members[id]; ok && (target == 0 || cid < target) {
+						target = cid
+					}
+				}
+				if target == 0 {
+					if err := tx.QueryRow(rctx, insertSQL,
+						strings.Join(cluster.CVEs, ", ")).Scan(&target); err != nil {
+						return err
+					}
+					result.Created++
+				}
+				for _, id := range cluster.Advisories {
+					if _, ok := members[id]; ok {
+						continue
+					}
+					if _, err := tx.Exec(rctx, assignSQL, target, id); err != nil {
+						return err
+					}
+					members[id] = target
+					result.Assigned++
+				}
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendCaseError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
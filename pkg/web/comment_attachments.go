@@ -0,0 +1,338 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// attachmentFiles returns the files attached to a comment
+// form after checking them against the configured limits.
+func (c *Controller) attachmentFiles(ctx *gin.Context) ([]*multipart.FileHeader, bool) {
+	form, err := ctx.MultipartForm()
+	if err != nil || form == nil {
+		// Forms without files are not multipart.
+		return nil, true
+	}
+	files := form.File["attachment"]
+	if len(files) > c.cfg.Comments.MaxAttachments {
+		models.SendErrorMessage(ctx, http.StatusBadRequest,
+			fmt.Sprintf("too many attachments (max %d)", c.cfg.Comments.MaxAttachments))
+		return nil, false
+	}
+	for _, file := range files {
+		if file.Size > int64(c.cfg.Comments.MaxAttachmentSize) {
+			models.SendErrorMessage(ctx, http.StatusBadRequest,
+				fmt.Sprintf("attachment %q is too large", file.Filename))
+			return nil, false
+		}
+	}
+	return files, true
+}
+
+// readAttachment reads the content of an attached file.
+func (c *Controller) readAttachment(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	limit := int64(c.cfg.Comments.MaxAttachmentSize)
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("attachment %q is too large", file.Filename)
+	}
+	return data, nil
+}
+
+// writeAttachmentFile writes the content of an attachment to a new
+// file in the attachments directory and returns its name.
+func (c *Controller) writeAttachmentFile(data []byte) (string, error) {
+	var random [16]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(random[:])
+	f, err := os.OpenFile(
+		filepath.Join(c.cfg.Comments.AttachmentsDir, name),
+		os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// removeAttachmentFiles removes files of attachments
+// which were not stored in the database.
+func (c *Controller) removeAttachmentFiles(names []string) {
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(c.cfg.Comments.AttachmentsDir, name)); err != nil {
+			slog.Warn("removing attachment file failed", "file", name, "err", err)
+		}
+	}
+}
+
+// storeAttachments stores the attached files of a comment in the database
+// or in the attachments directory. It returns the names of the written files
+// which have to be removed if the transaction fails.
+func (c *Controller) storeAttachments(
+	ctx context.Context,
+	tx pgx.Tx,
+	commentID int64,
+	uploader sql.NullString,
+	now time.Time,
+	files []*multipart.FileHeader,
+) ([]commentAttachment, []string, error) {
+	const insertSQL = `INSERT INTO comment_attachments ` +
+		`(comments_id, filename, content_type, size, uploader, time, data, path) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ` +
+		`RETURNING id`
+	var (
+		attachments []commentAttachment
+		written     []string
+	)
+	for _, file := range files {
+		data, err := c.readAttachment(file)
+		if err != nil {
+			return nil, written, err
+		}
+		contentType := file.Header.Get("Content-Type")
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			contentType = "application/octet-stream"
+		}
+		var path *string
+		if c.cfg.Comments.AttachmentsDir != "" {
+			name, err := c.writeAttachmentFile(data)
+			if err != nil {
+				return nil, written, err
+			}
+			written = append(written, name)
+			path, data = &name, nil
+		}
+		attachment := commentAttachment{
+			Filename:    filepath.Base(file.Filename),
+			ContentType: contentType,
+			Size:        file.Size,
+		}
+		if err := tx.QueryRow(ctx, insertSQL,
+			commentID, attachment.Filename, attachment.ContentType, attachment.Size,
+			uploader, now, data, path,
+		).Scan(&attachment.ID); err != nil {
+			return nil, written, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, written, nil
+}
+
+// addCommentAttachments is an endpoint that attaches files to a comment.
+//
+//	@Summary		Attaches files to a comment.
+//	@Description	Attaches files to the comment with the specified ID. Only the commentator may do this.
+//	@Param			id			path		int		true	"Comment ID"
+//	@Param			attachment	formData	file	true	"Attached files"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		201	{array}		commentAttachment
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/comments/post/{id}/attachments [post]
+func (c *Controller) addCommentAttachments(ctx *gin.Context) {
+	commentID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	files, ok := c.attachmentFiles(ctx)
+	if !ok {
+		return
+	}
+	if len(files) == 0 {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "missing attachment")
+		return
+	}
+
+	expr := c.andTLPExpr(ctx, query.FieldEqInt("comments.id", commentID))
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(expr)
+
+	var (
+		exists            bool
+		commentingAllowed bool
+		tooMany           bool
+		now               = time.Now().UTC()
+		commentator       = ctx.GetString("uid")
+		attachments       []commentAttachment
+		written           []string
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			stateSQL := `SELECT advisories.state, documents.id, ` +
+				`(SELECT count(*) FROM comment_attachments WHERE comments_id = comments.id) ` +
+				`FROM comments JOIN documents ON comments.documents_id = documents.id ` +
+				`JOIN advisories ON documents.advisories_id = advisories.id ` +
+				`WHERE documents.deleted IS NULL AND ` +
+				fmt.Sprintf(`comments.commentator = $%d AND `, len(builder.Replacements)+1) +
+				builder.WhereClause
+			var (
+				stateS string
+				docID  int64
+				count  int
+			)
+			if err := tx.QueryRow(
+				rctx, stateSQL, append(builder.Replacements, commentator)...,
+			).Scan(&stateS, &docID, &count); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil
+				}
+				return err
+			}
+			exists = true
+			state := models.Workflow(stateS)
+			if commentingAllowed = c.isCommentingAllowed(ctx, state); !commentingAllowed {
+				return nil
+			}
+			if tooMany = count+len(files) > c.cfg.Comments.MaxAttachments; tooMany {
+				return nil
+			}
+			if attachments, written, err = c.storeAttachments(
+				rctx, tx, commentID, c.currentUser(ctx), now, files,
+			); err != nil {
+				return err
+			}
+			const eventSQL = `INSERT INTO events_log ` +
+				`(event, state, time, actor, documents_id, comments_id) ` +
+				`VALUES('change_comment', $1::workflow, $2, $3, $4, $5)`
+			if _, err := tx.Exec(
+				rctx, eventSQL, stateS, now, c.currentUser(ctx), docID, commentID,
+			); err != nil {
+				return err
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		c.removeAttachmentFiles(written)
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	switch {
+	case !exists:
+		models.SendErrorMessage(ctx, http.StatusNotFound, "comment not found")
+	case !commentingAllowed:
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid state to comment")
+	case tooMany:
+		models.SendErrorMessage(ctx, http.StatusBadRequest,
+			fmt.Sprintf("too many attachments (max %d)", c.cfg.Comments.MaxAttachments))
+	default:
+		ctx.JSON(http.StatusCreated, attachments)
+	}
+}
+
+// viewCommentAttachment is an endpoint that returns an attached file.
+//
+//	@Summary		Returns an attached file.
+//	@Description	Returns the file attached to a comment with the specified ID.
+//	@Param			id	path	int	true	"Attachment ID"
+//	@Produce		octet-stream
+//	@Success		200	{file}		binary
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/comments/attachments/{id} [get]
+func (c *Controller) viewCommentAttachment(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	expr := c.andTLPExpr(ctx, query.FieldEqInt("comment_attachments.id", id))
+	builder := query.SQLBuilder{}
+	fetchSQL := `SELECT comment_attachments.filename, comment_attachments.content_type, ` +
+		`comment_attachments.data, comment_attachments.path ` +
+		`FROM comment_attachments ` +
+		`JOIN comments ON comment_attachments.comments_id = comments.id ` +
+		`JOIN documents ON comments.documents_id = documents.id ` +
+		`JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND ` + builder.CreateWhere(expr)
+	var (
+		filename    string
+		contentType string
+		data        []byte
+		path        *string
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			return conn.QueryRow(rctx, fetchSQL, builder.Replacements...).Scan(
+				&filename, &contentType, &data, &path)
+		}, 0,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			models.SendErrorMessage(ctx, http.StatusNotFound, "attachment not found")
+		} else {
+			slog.Error("database error", "err", err)
+			models.SendError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	if path != nil {
+		var err error
+		if data, err = os.ReadFile(filepath.Join(c.cfg.Comments.AttachmentsDir, *path)); err != nil {
+			slog.Error("reading attachment failed", "file", *path, "err", err)
+			models.SendErrorMessage(ctx, http.StatusInternalServerError, "attachment not readable")
+			return
+		}
+	}
+	// Never let the browser render uploaded content.
+	extraHeaders := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+		"X-Content-Type-Options": "nosniff",
+	}
+	ctx.DataFromReader(
+		http.StatusOK, int64(len(data)),
+		contentType,
+		bytes.NewReader(data),
+		extraHeaders)
+}
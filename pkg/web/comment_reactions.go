@@ -0,0 +1,145 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// reactionRe restricts reactions to short names like "+1" or "eyes".
+var reactionRe = regexp.MustCompile(`^[a-z0-9_+-]{1,32}$`)
+
+// parseReaction extracts the comment id and the reaction from the path.
+func parseReaction(ctx *gin.Context) (int64, string, bool) {
+	commentID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return 0, "", false
+	}
+	reaction := ctx.Param("reaction")
+	if !reactionRe.MatchString(reaction) {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid reaction")
+		return 0, "", false
+	}
+	return commentID, reaction, true
+}
+
+// addCommentReaction is an endpoint that adds a reaction of the user to a comment.
+//
+//	@Summary		Adds a reaction to a comment.
+//	@Description	Adds a reaction of the current user to the comment with the specified ID.
+//	@Param			id			path	int		true	"Comment ID"
+//	@Param			reaction	path	string	true	"Reaction"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/comments/post/{id}/reactions/{reaction} [put]
+func (c *Controller) addCommentReaction(ctx *gin.Context) {
+	commentID, reaction, ok := parseReaction(ctx)
+	if !ok {
+		return
+	}
+	switch visible, err := c.commentVisible(ctx, commentID); {
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	case !visible:
+		models.SendErrorMessage(ctx, http.StatusNotFound, "comment not found")
+		return
+	}
+	const insertSQL = `INSERT INTO comment_reactions (comments_id, actor, reaction, time) ` +
+		`VALUES ($1, $2, $3, $4) ` +
+		`ON CONFLICT DO NOTHING`
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			_, err := conn.Exec(rctx, insertSQL,
+				commentID, c.currentUser(ctx), reaction, time.Now().UTC())
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "reaction added")
+}
+
+// deleteCommentReaction is an endpoint that removes a reaction of the user from a comment.
+//
+//	@Summary		Removes a reaction from a comment.
+//	@Description	Removes a reaction of the current user from the comment with the specified ID.
+//	@Param			id			path	int		true	"Comment ID"
+//	@Param			reaction	path	string	true	"Reaction"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/comments/post/{id}/reactions/{reaction} [delete]
+func (c *Controller) deleteCommentReaction(ctx *gin.Context) {
+	commentID, reaction, ok := parseReaction(ctx)
+	if !ok {
+		return
+	}
+	const deleteSQL = `DELETE FROM comment_reactions ` +
+		`WHERE comments_id = $1 AND actor = $2 AND reaction = $3`
+	var deleted bool
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tags, err := conn.Exec(rctx, deleteSQL, commentID, c.currentUser(ctx), reaction)
+			if err != nil {
+				return err
+			}
+			deleted = tags.RowsAffected() > 0
+			return nil
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !deleted {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "reaction not found")
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "reaction removed")
+}
+
+// commentVisible checks if the comment exists and is visible to the user.
+func (c *Controller) commentVisible(ctx *gin.Context, commentID int64) (bool, error) {
+	expr := c.andTLPExpr(ctx, query.FieldEqInt("comments.id", commentID))
+	builder := query.SQLBuilder{}
+	existsSQL := `SELECT EXISTS(SELECT 1 ` +
+		`FROM comments JOIN documents ON comments.documents_id = documents.id ` +
+		`JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND ` + builder.CreateWhere(expr) + `)`
+	var exists bool
+	err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			return conn.QueryRow(rctx, existsSQL, builder.Replacements...).Scan(&exists)
+		}, 0)
+	return exists, err
+}
@@ -14,9 +14,13 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -43,9 +47,11 @@ func (c *Controller) isCommentingAllowed(ctx *gin.Context, state models.Workflow
 // createComment is an endpoint that creates a comment.
 //
 //	@Summary		Creates a comment.
-//	@Description	Creates a comment for the specified document.
-//	@Param			id		path		int		true	"Document ID"
-//	@Param			message	formData	string	true	"Comment message"
+//	@Description	Creates a comment or a reply to a comment of the same advisory for the specified document.
+//	@Param			id			path		int		true	"Document ID"
+//	@Param			message		formData	string	true	"Comment message"
+//	@Param			parent		formData	int		false	"ID of the comment to reply to"
+//	@Param			attachment	formData	file	false	"Attached files"
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Success		201	{object}	web.createComment.commentResult
@@ -66,6 +72,18 @@ func (c *Controller) createComment(ctx *gin.Context) {
 	if !ok {
 		return
 	}
+	var parentID *int64
+	if p, ok := ctx.GetPostForm("parent"); ok && p != "" {
+		id, ok := parse(ctx, toInt64, p)
+		if !ok {
+			return
+		}
+		parentID = &id
+	}
+	files, ok := c.attachmentFiles(ctx)
+	if !ok {
+		return
+	}
 
 	expr := c.andTLPExpr(ctx, query.FieldEqInt("id", docID))
 	builder := query.SQLBuilder{}
@@ -75,10 +93,12 @@ func (c *Controller) createComment(ctx *gin.Context) {
 		exists            bool
 		commentingAllowed bool
 		forbidden         bool
+		parentExists      = true
 		commentator       = c.currentUser(ctx)
 		message, _        = ctx.GetPostForm("message")
 		now               = time.Now().UTC()
 		commentID         *int64
+		written           []string
 	)
 
 	if err := c.db.Run(
@@ -93,7 +113,7 @@ func (c *Controller) createComment(ctx *gin.Context) {
 			stateSQL := `SELECT state, advisories.tracking_id, advisories.publisher ` +
 				`FROM documents JOIN advisories ` +
 				`ON documents.advisories_id = advisories.id ` +
-				` WHERE ` + builder.WhereClause
+				`WHERE documents.deleted IS NULL AND ` + builder.WhereClause
 
 			var (
 				stateS     string
@@ -116,6 +136,20 @@ func (c *Controller) createComment(ctx *gin.Context) {
 				return nil
 			}
 
+			// Replies have to stay within the advisory.
+			if parentID != nil {
+				const parentSQL = `SELECT EXISTS(SELECT 1 FROM comments ` +
+					`JOIN documents docs ON comments.documents_id = docs.id ` +
+					`WHERE comments.id = $1 AND docs.advisories_id = ` +
+					`(SELECT advisories_id FROM documents WHERE id = $2))`
+				if err := tx.QueryRow(rctx, parentSQL, *parentID, docID).Scan(&parentExists); err != nil {
+					return err
+				}
+				if !parentExists {
+					return nil
+				}
+			}
+
 			logEvent := func(event models.Event, state models.Workflow) error {
 				const eventSQL = `INSERT INTO events_log ` +
 					`(event, state, time, actor, documents_id, comments_id) ` +
@@ -149,17 +183,23 @@ func (c *Controller) createComment(ctx *gin.Context) {
 
 			// Now insert the comment itself
 			const insertSQL = `INSERT INTO comments ` +
-				`(documents_id, time, commentator, message) ` +
-				`VALUES ($1, $2, $3, $4) ` +
+				`(documents_id, time, commentator, message, parent_id) ` +
+				`VALUES ($1, $2, $3, $4, $5) ` +
 				`RETURNING id`
 
 			if err := tx.QueryRow(
 				rctx, insertSQL,
-				docID, now, commentator, message,
+				docID, now, commentator, message, parentID,
 			).Scan(&commentID); err != nil {
 				return err
 			}
 
+			if _, written, err = c.storeAttachments(
+				rctx, tx, *commentID, commentator, now, files,
+			); err != nil {
+				return err
+			}
+
 			// Log that we created a comment
 			if err := logEvent(models.AddCommentEvent, models.AssessingWorkflow); err != nil {
 				return err
@@ -168,6 +208,7 @@ func (c *Controller) createComment(ctx *gin.Context) {
 			return tx.Commit(rctx)
 		}, 0,
 	); err != nil {
+		c.removeAttachmentFiles(written)
 		slog.Error("database error", "err", err)
 		models.SendError(ctx, http.StatusInternalServerError, err)
 		return
@@ -177,6 +218,8 @@ func (c *Controller) createComment(ctx *gin.Context) {
 		models.SendErrorMessage(ctx, http.StatusNotFound, "document not found")
 	case !commentingAllowed:
 		models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid state to comment")
+	case !parentExists:
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "parent comment not found")
 	case forbidden:
 		models.SendErrorMessage(ctx, http.StatusForbidden, "user not allowed to change state")
 	default:
@@ -232,7 +275,7 @@ func (c *Controller) updateComment(ctx *gin.Context) {
 				`ON docs.advisories_id = ads.id ` +
 				`JOIN comments com ` +
 				`ON com.documents_id = docs.id` +
-				` WHERE ` + builder.WhereClause
+				` WHERE docs.deleted IS NULL AND ` + builder.WhereClause
 
 			var stateS string
 			if err := tx.QueryRow(rctx, stateSQL, builder.Replacements...).Scan(
@@ -300,11 +343,72 @@ func (c *Controller) updateComment(ctx *gin.Context) {
 }
 
 type comment struct {
-	DocumentID  int64     `json:"document_id"`
-	ID          int64     `json:"id"`
-	Time        time.Time `json:"time"`
-	Commentator string    `json:"commentator"`
-	Message     string    `json:"message"`
+	DocumentID  int64               `json:"document_id"`
+	ID          int64               `json:"id"`
+	ParentID    *int64              `json:"parent_id,omitempty"`
+	Time        time.Time           `json:"time"`
+	Commentator string              `json:"commentator"`
+	Message     string              `json:"message"`
+	HTML        string              `json:"html"`
+	Mentions    []string            `json:"mentions"`
+	Reactions   []commentReaction   `json:"reactions"`
+	Attachments []commentAttachment `json:"attachments"`
+}
+
+// commentReaction are the users who reacted in the same way to a comment.
+type commentReaction struct {
+	Reaction string   `json:"reaction"`
+	Actors   []string `json:"actors"`
+}
+
+// commentAttachment describes a file attached to a comment.
+type commentAttachment struct {
+	ID          int64  `json:"id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// commentColumns are the columns of a comment
+// with its mentions, reactions and attachments.
+const commentColumns = `comments.id, comments.documents_id, comments.parent_id, ` +
+	`comments.time, comments.commentator, comments.message, ` +
+	`ARRAY(SELECT mentioned FROM comment_mentions ` +
+	`WHERE comments_id = comments.id ORDER BY mentioned), ` +
+	`coalesce((SELECT jsonb_agg(r ORDER BY r.reaction) FROM (` +
+	`SELECT reaction, array_agg(actor ORDER BY time) AS actors ` +
+	`FROM comment_reactions WHERE comments_id = comments.id GROUP BY reaction) r), '[]'), ` +
+	`coalesce((SELECT jsonb_agg(jsonb_build_object(` +
+	`'id', id, 'filename', filename, 'content_type', content_type, 'size', size) ORDER BY id) ` +
+	`FROM comment_attachments WHERE comments_id = comments.id), '[]')`
+
+// scanComment scans a comment selected by the commentColumns.
+func scanComment(row pgx.Row) (comment, error) {
+	var com comment
+	var message sql.NullString
+	if err := row.Scan(
+		&com.ID, &com.DocumentID, &com.ParentID,
+		&com.Time, &com.Commentator, &message,
+		&com.Mentions, &com.Reactions, &com.Attachments,
+	); err != nil {
+		return com, err
+	}
+	com.Time = com.Time.UTC()
+	com.Message = message.String
+	com.HTML = renderMarkdown(com.Message)
+	return com, nil
+}
+
+// renderMarkdown renders a comment message to HTML.
+// Embedded HTML is dropped and only safe links are rendered.
+// The client sanitises the HTML on its own, too.
+func renderMarkdown(message string) string {
+	p := parser.NewWithExtensions(parser.CommonExtensions &^ parser.MathJax)
+	renderer := html.NewRenderer(html.RendererOptions{
+		Flags: html.SkipHTML | html.Safelink |
+			html.NofollowLinks | html.NoreferrerLinks | html.HrefTargetBlank,
+	})
+	return string(markdown.ToHTML([]byte(message), p, renderer))
 }
 
 // viewComment is an endpoint that returns the specified comment.
@@ -329,19 +433,18 @@ func (c *Controller) viewComment(ctx *gin.Context) {
 
 	builder := query.SQLBuilder{}
 
-	fetchSQL := `SELECT documents_id, time, commentator, message ` +
+	fetchSQL := `SELECT ` + commentColumns + ` ` +
 		`FROM comments JOIN documents ON comments.documents_id = documents.id ` +
-		`WHERE ` + builder.CreateWhere(expr)
+		`JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND ` + builder.CreateWhere(expr)
 
-	post := comment{ID: id}
+	var post comment
 	switch err := c.db.Run(
 		ctx.Request.Context(),
 		func(rctx context.Context, conn *pgxpool.Conn) error {
-			return conn.QueryRow(rctx, fetchSQL, builder.Replacements...).Scan(
-				&post.DocumentID,
-				&post.Time,
-				&post.Commentator,
-				&post.Message)
+			var err error
+			post, err = scanComment(conn.QueryRow(rctx, fetchSQL, builder.Replacements...))
+			return err
 		}, 0); {
 	case errors.Is(err, pgx.ErrNoRows):
 		models.SendErrorMessage(ctx, http.StatusNotFound, "comment post not found")
@@ -356,7 +459,7 @@ func (c *Controller) viewComment(ctx *gin.Context) {
 // viewComments is an endpoint that returns all comments.
 //
 //	@Summary		Returns all comments.
-//	@Description	Returns all comments for the specified advisory.
+//	@Description	Returns all comments for the specified advisory including the ones shared in its vulnerability case.
 //	@Param			publisher	path	string	true	"Publisher"
 //	@Param			trackingid	path	string	true	"Tracking ID"
 //	@Produce		json
@@ -383,7 +486,10 @@ func (c *Controller) viewComments(ctx *gin.Context) {
 			query.FieldEqString("publisher", key.Publisher)))
 
 	builder := query.SQLBuilder{}
-	builder.CreateWhere(expr)
+	where := builder.CreateWhere(expr)
+	keyArgs := len(builder.Replacements)
+	// The comments of the advisories in a case sharing the comments are included.
+	sharedWhere := builder.CreateWhere(c.andTLPExpr(ctx, query.True()))
 
 	var comments []comment
 	var exists bool
@@ -391,32 +497,31 @@ func (c *Controller) viewComments(ctx *gin.Context) {
 	if err := c.db.Run(
 		ctx.Request.Context(),
 		func(rctx context.Context, conn *pgxpool.Conn) error {
-			existsSQL := `SELECT EXISTS(` +
-				`SELECT FROM documents JOIN advisories ON documents.advisories_id = advisories.id WHERE ` +
-				builder.WhereClause + `)`
-			if err := conn.QueryRow(
-				rctx, existsSQL, builder.Replacements...).Scan(&exists); err != nil {
-				return err
-			}
-			if !exists {
+			existsSQL := `SELECT advisories.id ` +
+				`FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
+				`WHERE documents.deleted IS NULL AND ` + where + ` LIMIT 1`
+			var advisoryID int64
+			switch err := conn.QueryRow(
+				rctx, existsSQL, builder.Replacements[:keyArgs]...).Scan(&advisoryID); {
+			case errors.Is(err, pgx.ErrNoRows):
 				return nil
+			case err != nil:
+				return err
 			}
-			fetchSQL := `SELECT id, documents_id, time, commentator, message FROM comments ` +
+			exists = true
+			args := append(slices.Clone(builder.Replacements), advisoryID)
+			fetchSQL := `SELECT ` + commentColumns + ` FROM comments ` +
 				`WHERE documents_id in (` +
 				`SELECT documents.id FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
-				`WHERE ` +
-				builder.WhereClause +
+				`WHERE documents.deleted IS NULL AND (` + where + ` OR (` +
+				sharedWhere + ` AND advisories.id IN (` +
+				casePeersSQL("comments", len(args)) + `)))` +
 				` ) ORDER BY time DESC`
-			rows, _ := conn.Query(rctx, fetchSQL, builder.Replacements...)
+			rows, _ := conn.Query(rctx, fetchSQL, args...)
 			var err error
 			comments, err = pgx.CollectRows(
 				rows,
-				func(row pgx.CollectableRow) (comment, error) {
-					var com comment
-					err := row.Scan(&com.ID, &com.DocumentID, &com.Time, &com.Commentator, &com.Message)
-					com.Time = com.Time.UTC()
-					return com, err
-				})
+				func(row pgx.CollectableRow) (comment, error) { return scanComment(row) })
 			return err
 		}, 0,
 	); err != nil {
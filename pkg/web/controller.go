@@ -34,13 +34,14 @@ import (
 
 // Controller binds the endpoints to the internal logic.
 type Controller struct {
-	cfg *config.Config
-	db  *database.DB
-	fm  *forwarder.Manager
-	ts  *tempstore.Store
-	sm  *sources.Manager
-	am  *aggregators.Manager
-	val csaf.RemoteValidator
+	cfg  *config.Config
+	db   *database.DB
+	fm   *forwarder.Manager
+	ts   *tempstore.Store
+	sm   *sources.Manager
+	am   *aggregators.Manager
+	val  csaf.RemoteValidator
+	ssvc *models.SSVCModels
 }
 
 // NewController returns a new Controller.
@@ -52,15 +53,17 @@ func NewController(
 	dl *sources.Manager,
 	am *aggregators.Manager,
 	val csaf.RemoteValidator,
+	ssvc *models.SSVCModels,
 ) *Controller {
 	return &Controller{
-		cfg: cfg,
-		db:  db,
-		fm:  fm,
-		ts:  ts,
-		sm:  dl,
-		am:  am,
-		val: val,
+		cfg:  cfg,
+		db:   db,
+		fm:   fm,
+		ts:   ts,
+		sm:   dl,
+		am:   am,
+		val:  val,
+		ssvc: ssvc,
 	}
 }
 
@@ -118,23 +121,67 @@ func (c *Controller) Bind() http.Handler {
 	api.GET("/documents/:id", authAll, c.viewDocument)
 	api.GET("/documents/forward", authAdEdImReSM, c.viewForwardTargets)
 	api.GET("/documents/texts/:id", authAdEdImReSM, c.documentTexts)
+	api.GET("/documents/enrichment/:id", authAll, c.viewDocumentEnrichment)
 	api.POST("/documents/forward/:id/:target", authAdEdImReSM, c.forwardDocument)
 	// Admin can delete documents
 	api.DELETE("/documents/:id", authAd, c.deleteDocument)
 
 	api.GET("/documents/filter_help", authAll, c.filterHelp)
 
+	// Enrichment catalogues
+	api.GET("/enrichment", authAll, c.viewEnrichmentImports)
+
 	// Related CVEs
 	api.GET("/documents/:id/cve_related", authAdAuEdRe, c.cveRelatedDocuments)
 
 	// Advisories
 	api.DELETE("/advisory/:publisher/:trackingid", authAd, c.deleteAdvisory)
 
+	// Advisory checklists, notes and tags
+	api.GET("/checklists", authAdAuEdRe, c.viewChecklistTemplates)
+	api.GET("/advisory/:publisher/:trackingid/checklist", authAdAuEdRe, c.viewChecklist)
+	api.POST("/advisory/:publisher/:trackingid/checklist", authAdEdRe, c.createChecklist)
+	api.PUT("/advisory/:publisher/:trackingid/checklist/:item", authAdEdRe, c.updateChecklistItem)
+	api.GET("/advisory/:publisher/:trackingid/notes", authAdAuEdRe, c.viewNotes)
+	api.POST("/advisory/:publisher/:trackingid/notes", authAdEdRe, c.createNote)
+	api.PUT("/advisory/:publisher/:trackingid/notes/:id", authAdEdRe, c.updateNote)
+	api.DELETE("/advisory/:publisher/:trackingid/notes/:id", authAdEdRe, c.deleteNote)
+	api.GET("/advisory/:publisher/:trackingid/tags", authAdAuEdRe, c.viewTags)
+	api.PUT("/advisory/:publisher/:trackingid/tags/:tag", authAdEdRe, c.addTag)
+	api.DELETE("/advisory/:publisher/:trackingid/tags/:tag", authAdEdRe, c.deleteTag)
+
+	// Tag definitions are managed by the admin
+	api.GET("/tags", authAll, c.viewTagDefinitions)
+	api.POST("/tags", authAd, c.createTagDefinition)
+	api.PUT("/tags/:tag", authAd, c.updateTagDefinition)
+	api.DELETE("/tags/:tag", authAd, c.deleteTagDefinition)
+
+	// Vulnerability cases
+	api.GET("/cases", authAdAuEdRe, c.viewCases)
+	api.GET("/cases/:id", authAdAuEdRe, c.viewCase)
+	api.POST("/cases", authAdEdRe, c.createCase)
+	api.POST("/cases/cluster", authAd, c.clusterCases)
+	api.PUT("/cases/:id", authAdEdRe, c.updateCase)
+	api.DELETE("/cases/:id", authAdEdRe, c.deleteCase)
+	api.POST("/cases/:id/merge/:other", authAdEdRe, c.mergeCases)
+	api.POST("/cases/:id/split", authAdEdRe, c.splitCase)
+	api.DELETE("/cases/:id/advisories/:publisher/:trackingid", authAdEdRe, c.removeCaseAdvisory)
+
+	// Admin can restore and purge deleted documents
+	api.GET("/trash", authAd, c.viewTrash)
+	api.PUT("/trash/documents/:id", authAd, c.restoreDocument)
+	api.DELETE("/trash/documents/:id", authAd, c.purgeDocument)
+	api.PUT("/trash/advisory/:publisher/:trackingid", authAd, c.restoreAdvisory)
+
 	// Comments
 	api.POST("/comments/:document", authAdEdRe, c.createComment)
 	api.GET("/comments/:publisher/:trackingid", authAdAuEdRe, c.viewComments)
 	api.PUT("/comments/post/:id", authAdEdRe, c.updateComment)
 	api.GET("/comments/post/:id", authAdAuEdRe, c.viewComment)
+	api.POST("/comments/post/:id/attachments", authAdEdRe, c.addCommentAttachments)
+	api.GET("/comments/attachments/:id", authAdAuEdRe, c.viewCommentAttachment)
+	api.PUT("/comments/post/:id/reactions/:reaction", authAdEdRe, c.addCommentReaction)
+	api.DELETE("/comments/post/:id/reactions/:reaction", authAdEdRe, c.deleteCommentReaction)
 
 	// Stored queries
 	api.POST("/queries", authAll, c.createStoredQuery)
@@ -154,11 +201,16 @@ func (c *Controller) Bind() http.Handler {
 	// State change
 	api.PUT("/status/:publisher/:trackingid/:state", authAdEdRe, c.changeStatus)
 	api.PUT("/status", authAdEdRe, c.changeStatusBulk)
+	api.PUT("/advisories/tags", authAdEdRe, c.changeTagsBulk)
 
 	// SSVC view/change
 	api.PUT("/ssvc/:document", authEd, c.changeSSVC)
 	api.GET("/ssvc/documents/:document", authAll, c.viewSSVC)
 	api.GET("/ssvc/history/:publisher/:trackingid", authAll, c.viewSSVCHistory)
+	api.GET("/ssvc/models", authAll, c.viewSSVCModels)
+	api.GET("/ssvc/models/:name", authAll, c.viewSSVCModel)
+	api.POST("/ssvc/suggest/:document", authEd, c.suggestSSVC)
+	api.GET("/ssvc/suggest/:document", authAll, c.viewSSVCSuggestion)
 
 	// Calculate diff
 	api.GET("/diff/:document1/:document2", authEdRe, c.viewDiff)
@@ -190,6 +242,18 @@ func (c *Controller) Bind() http.Handler {
 	api.DELETE("/sources/:id", authSM, c.deleteSource)
 	api.GET("/sources/:id", authSM, c.viewSource)
 	api.PUT("/sources/:id", authSM, c.updateSource)
+	api.POST("/sources/:id/fetch", authSM, c.fetchSource)
+	api.GET("/sources/:id/pmd/history", authSM, c.viewPMDHistory)
+	api.GET("/sources/:id/pmd/diff", authSM, c.viewPMDDiff)
+	api.GET("/sources/:id/keys", authSM, c.viewSourceKeys)
+	api.POST("/sources/:id/keys", authSM, c.createSourceKey)
+	api.GET("/sources/:id/keys/log", authSM, c.viewSourceKeysLog)
+	api.PUT("/sources/:id/keys/:fingerprint", authSM, c.decideSourceKey)
+	api.DELETE("/sources/:id/keys/:fingerprint", authSM, c.deleteSourceKey)
+	api.GET("/sources/quarantine", authSM, c.viewQuarantine)
+	api.GET("/sources/quarantine/:id", authSM, c.viewQuarantined)
+	api.GET("/sources/quarantine/:id/diff", authSM, c.viewQuarantinedDiff)
+	api.PUT("/sources/quarantine/:id", authSM, c.decideQuarantined)
 
 	// Source feeds
 	api.GET("/sources/:id/feeds", authAuEdSM, c.viewFeeds)
@@ -197,8 +261,10 @@ func (c *Controller) Bind() http.Handler {
 	api.GET("/sources/feeds/:id", authAuEdSM, c.viewFeed)
 	api.PUT("/sources/feeds/:id", authSM, c.updateFeed)
 	api.DELETE("/sources/feeds/:id", authSM, c.deleteFeed)
+	api.POST("/sources/feeds/:id/fetch", authSM, c.fetchFeed)
 	api.GET("/sources/feeds/log", authSM, c.allFeedsLog)
 	api.GET("/sources/feeds/:id/log", authSM, c.feedLog)
+	api.GET("/sources/feeds/:id/queue", authSM, c.feedQueue)
 	api.GET("/sources/feeds/keep", authAll, c.keepFeedTime)
 
 	// Import stats
@@ -212,6 +278,7 @@ func (c *Controller) Bind() http.Handler {
 	api.GET("/stats/critical/feed/:id", authAll, c.criticalStatsFeed)
 	api.GET("/stats/critical", authAll, c.criticalStatsAllSources)
 	api.GET("/stats/totals", authAll, c.statsTotal)
+	api.GET("/stats/health", authAuEdSM, c.healthStats)
 
 	// Aggregators
 	api.GET("/aggregator", authAuEdSM, c.aggregatorProxy)
@@ -219,8 +286,14 @@ func (c *Controller) Bind() http.Handler {
 	api.GET("/aggregators/:id", authAuEdSM, c.viewAggregator)
 	api.PUT("/aggregators/:id", authSM, c.updateAggregator)
 	api.GET("/aggregators/attention", authSM, c.attentionAggregators)
+	api.GET("/aggregators/actions", authSM, c.viewAggregatorActions)
+	api.PUT("/aggregators/actions/:id", authSM, c.decideAggregatorAction)
 	api.POST("/aggregators", authSM, c.createAggregator)
 	api.DELETE("/aggregators/:id", authSM, c.deleteAggregator)
 
+	// Peer synchronization
+	api.GET("/sync/changes", c.authPeer, c.syncChanges)
+	api.GET("/sync/peers", authAd, c.viewSyncPeers)
+
 	return r
 }
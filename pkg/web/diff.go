@@ -96,7 +96,7 @@ func (c *Controller) viewDiff(ctx *gin.Context) {
 					b.CreateWhere(expr)
 					fetchSQL := `SELECT original ` +
 						`FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
-						`WHERE ` + b.WhereClause
+						`WHERE documents.deleted IS NULL AND ` + b.WhereClause
 					if err := conn.QueryRow(rctx, fetchSQL, b.Replacements...).Scan(f.doc); err != nil {
 						return fmt.Errorf("fetching data from database failed: %w", err)
 					}
@@ -136,8 +136,15 @@ func (c *Controller) viewDiff(ctx *gin.Context) {
 		*f.doc = data
 	}
 
+	sendDiff(ctx, doc[0], doc[1])
+}
+
+// sendDiff sends the JSON patch between two documents.
+// The query parameters item_op and item_path select a specific item of
+// the original document and word-diff enables word diffing of replacements.
+func sendDiff(ctx *gin.Context, from, to []byte) {
 	// Create the patch.
-	patch, err := jsonpatch.CreatePatch(doc[0], doc[1])
+	patch, err := jsonpatch.CreatePatch(from, to)
 	if err != nil {
 		slog.Error("creating patch failed", "err", err)
 		models.SendError(ctx, http.StatusInternalServerError, err)
@@ -152,7 +159,7 @@ func (c *Controller) viewDiff(ctx *gin.Context) {
 				continue
 			}
 			var d1 any
-			if err := json.Unmarshal(doc[0], &d1); err != nil {
+			if err := json.Unmarshal(from, &d1); err != nil {
 				slog.Error("unmarshaling failed", "err", err)
 				models.SendError(ctx, http.StatusInternalServerError, err)
 				return
@@ -179,7 +186,7 @@ func (c *Controller) viewDiff(ctx *gin.Context) {
 
 	// Calculate word diff for "replace" operations.
 	var d1 any
-	if err := json.Unmarshal(doc[0], &d1); err != nil {
+	if err := json.Unmarshal(from, &d1); err != nil {
 		slog.Error("unmarshaling failed", "err", err)
 		models.SendError(ctx, http.StatusInternalServerError, err)
 		return
@@ -22,7 +22,6 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gocsaf/csaf/v3/csaf"
 	"github.com/gocsaf/csaf/v3/util"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -34,10 +33,10 @@ import (
 // MinSearchLength enforces a minimal length of search phrases.
 const MinSearchLength = 2 // Makes at least "Go" searchable ;-)
 
-// deleteDocument is an end point for deleting a document.
+// deleteDocument is an end point for moving a document to the trash bin.
 //
 //	@Summary		Deletes a CSAF document.
-//	@Description	Delete endpoint for CSAF documents.
+//	@Description	Moves the CSAF document to the trash bin from where it can be restored.
 //	@Produce		json
 //	@Param			id	path		int	true	"Document ID"
 //	@Success		201	{object}	models.ID
@@ -72,25 +71,25 @@ func (c *Controller) deleteDocument(ctx *gin.Context) {
 			}
 			defer tx.Rollback(rctx)
 
-			const deletePrefix = `DELETE FROM documents WHERE `
-			deleteSQL := deletePrefix + builder.WhereClause
+			const trashPrefix = `WITH trashed AS (` +
+				`UPDATE documents SET deleted = current_timestamp ` +
+				`FROM advisories ` +
+				`WHERE advisories.id = documents.advisories_id ` +
+				`AND documents.deleted IS NULL AND `
+			trashSQL := trashPrefix + builder.WhereClause +
+				fmt.Sprintf(` RETURNING documents.id) `+
+					`INSERT INTO events_log (event, actor, documents_id) `+
+					`SELECT 'delete_document'::events, $%d, id FROM trashed`,
+					len(builder.Replacements)+1)
 			slog.Debug("delete document", "SQL",
-				query.InterpolateSQLqnd(deleteSQL, builder.Replacements))
+				query.InterpolateSQLqnd(trashSQL, builder.Replacements))
 
-			tags, err := tx.Exec(rctx, deleteSQL, builder.Replacements...)
+			tags, err := tx.Exec(rctx, trashSQL,
+				append(builder.Replacements, c.currentUser(ctx))...)
 			if err != nil {
 				return fmt.Errorf("delete failed: %w", err)
 			}
-
-			if deleted = tags.RowsAffected() > 0; deleted {
-				actor := c.currentUser(ctx)
-				const eventSQL = `INSERT INTO events_log ` +
-					`(event, actor) ` +
-					`VALUES('delete_document'::events, $1)`
-				if _, err := tx.Exec(rctx, eventSQL, actor); err != nil {
-					return fmt.Errorf("event logging failed: %w", err)
-				}
-			}
+			deleted = tags.RowsAffected() > 0
 
 			return tx.Commit(rctx)
 		}, 0,
@@ -117,7 +116,7 @@ func (c *Controller) deleteDocument(ctx *gin.Context) {
 //	@Failure		400	{object}	models.Error
 //	@Failure		401
 //	@Failure		403	{object}	models.Error	"False TLP or publisher"
-//	@Failure		409	{object}	models.Error	"Already in database"
+//	@Failure		409	{object}	models.Error	"Already in database or trash bin"
 //	@Failure		500	{object}	models.Error
 //	@Router			/documents [post]
 func (c *Controller) importDocument(ctx *gin.Context) {
@@ -149,7 +148,7 @@ func (c *Controller) importDocument(ctx *gin.Context) {
 		return
 	}
 
-	msgs, err := csaf.ValidateCSAF(document)
+	msgs, err := models.ValidateCSAF(document)
 	if err != nil {
 		models.SendErrorMessage(ctx, http.StatusBadRequest, "schema validation failed: "+err.Error())
 		return
@@ -201,6 +200,8 @@ func (c *Controller) importDocument(ctx *gin.Context) {
 	); {
 	case err == nil:
 		ctx.JSON(http.StatusCreated, models.ID{ID: id})
+	case errors.Is(err, models.ErrInTrash):
+		models.SendErrorMessage(ctx, http.StatusConflict, "already in trash bin, restore it instead")
 	case errors.Is(err, models.ErrAlreadyInDatabase):
 		models.SendErrorMessage(ctx, http.StatusConflict, "already in database")
 	case errors.Is(err, models.ErrNotAllowed):
@@ -343,6 +344,7 @@ func (c *Controller) forwardDocument(ctx *gin.Context) {
 //	@Summary		Returns documents.
 //	@Description	Returns all documents that match the specified query.
 //	@Param			advisories	query	bool	false	"Return advisories"
+//	@Param			cases		query	bool	false	"Return advisories of vulnerability cases"
 //	@Param			query		query	string	false	"Document query"
 //	@Param			columns		query	string	false	"Columns"
 //	@Param			orders		query	string	false	"Ordering"
@@ -363,6 +365,12 @@ func (c *Controller) overviewDocuments(ctx *gin.Context) {
 		return
 	}
 
+	// Use the advisories of the vulnerability cases.
+	cases, ok := parse(ctx, strconv.ParseBool, ctx.DefaultQuery("cases", "false"))
+	if !ok {
+		return
+	}
+
 	// Use the advisories.
 	aggregate, ok := parse(ctx, strconv.ParseBool, ctx.DefaultQuery("aggregate", "false"))
 	if !ok {
@@ -370,7 +378,11 @@ func (c *Controller) overviewDocuments(ctx *gin.Context) {
 	}
 
 	mode := query.DocumentMode
-	if advisory {
+	switch {
+	case cases:
+		mode = query.CaseMode
+		advisory = true
+	case advisory:
 		mode = query.AdvisoryMode
 	}
 
@@ -394,10 +406,11 @@ func (c *Controller) overviewDocuments(ctx *gin.Context) {
 		expr = expr.And(query.BoolField("latest"))
 	}
 
-	orderFields := strings.Fields(
-		ctx.DefaultQuery(
-			"orders",
-			"publisher tracking_id -current_release_date -rev_history_length"))
+	defaultOrders := "publisher tracking_id -current_release_date -rev_history_length"
+	if cases {
+		defaultOrders = "case_id " + defaultOrders
+	}
+	orderFields := strings.Fields(ctx.DefaultQuery("orders", defaultOrders))
 	if aggregate {
 		if !slices.Contains(orderFields, "id") {
 			orderFields = append(orderFields, "id")
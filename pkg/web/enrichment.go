@@ -0,0 +1,218 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// kevEntry is an entry of the KEV catalogue.
+type kevEntry struct {
+	Vendor     *string    `json:"vendor,omitempty"`
+	Product    *string    `json:"product,omitempty"`
+	Name       *string    `json:"name,omitempty"`
+	DateAdded  *time.Time `json:"date_added,omitempty"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	Ransomware bool       `json:"ransomware"`
+}
+
+// cveEnrichment is the enrichment data of a CVE.
+type cveEnrichment struct {
+	CVE            string    `json:"cve"`
+	KEV            *kevEntry `json:"kev,omitempty"`
+	EPSS           *float64  `json:"epss,omitempty"`
+	EPSSPercentile *float64  `json:"epss_percentile,omitempty"`
+}
+
+// cweEnrichment is a CWE with its name from the CWE list.
+type cweEnrichment struct {
+	ID   string  `json:"id"`
+	Name *string `json:"name,omitempty"`
+}
+
+// cvssVector is an indexed CVSS vector of a vulnerability.
+// The environmental score uses the environmental defaults of the organisation.
+type cvssVector struct {
+	CVE                *string  `json:"cve,omitempty"`
+	Version            string   `json:"version"`
+	Vector             string   `json:"vector"`
+	BaseScore          *float64 `json:"base_score,omitempty"`
+	EnvironmentalScore *float64 `json:"environmental_score,omitempty"`
+}
+
+// documentEnrichment is the enrichment data of a document.
+type documentEnrichment struct {
+	CVEs []cveEnrichment `json:"cves"`
+	CWEs []cweEnrichment `json:"cwes"`
+	CVSS []cvssVector    `json:"cvss"`
+}
+
+// enrichmentImport is the last import of an enrichment catalogue.
+type enrichmentImport struct {
+	Catalogue string    `json:"catalogue"`
+	Modified  time.Time `json:"modified"`
+	Imported  time.Time `json:"imported"`
+	Entries   int64     `json:"entries"`
+}
+
+// viewDocumentEnrichment is an endpoint that returns the enrichment data of a document.
+//
+//	@Summary		Returns the enrichment data of a document.
+//	@Description	Returns the KEV entries and EPSS scores of the CVEs, the names of the CWEs and the scored CVSS vectors of the document.
+//	@Param			id	path	int	true	"Document ID"
+//	@Produce		json
+//	@Success		200	{object}	web.documentEnrichment
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/documents/enrichment/{id} [get]
+func (c *Controller) viewDocumentEnrichment(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	expr := c.andTLPExpr(ctx, query.FieldEqInt("id", id))
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(expr)
+
+	existsSQL := `SELECT EXISTS(SELECT 1 FROM documents JOIN advisories ` +
+		`ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NULL AND ` + builder.WhereClause + `)`
+
+	const (
+		cvesSQL = `SELECT uc.cve, kev.cve IS NOT NULL, ` +
+			`kev.vendor, kev.product, kev.name, kev.date_added, kev.due_date, ` +
+			`coalesce(kev.ransomware, false), epss.epss, epss.percentile ` +
+			`FROM documents_cves dc ` +
+			`JOIN unique_cves uc ON dc.cve_id = uc.id ` +
+			`LEFT JOIN kev ON kev.cve = uc.cve ` +
+			`LEFT JOIN epss ON epss.cve = uc.cve ` +
+			`WHERE dc.documents_id = $1 ORDER BY uc.cve`
+		cwesSQL = `SELECT DISTINCT cwe.id #>> '{}', cwes.name ` +
+			`FROM documents CROSS JOIN LATERAL (` +
+			// CSAF 2.0 has a single CWE per vulnerability and CSAF 2.1 a list.
+			`SELECT jsonb_path_query(documents.document, '$.vulnerabilities[*].cwe.id') UNION ALL ` +
+			`SELECT jsonb_path_query(documents.document, '$.vulnerabilities[*].cwes[*].id')) AS cwe(id) ` +
+			`LEFT JOIN cwes ON 'CWE-' || cwes.id = cwe.id #>> '{}' ` +
+			`WHERE documents.id = $1 ORDER BY 1`
+		cvssSQL = `SELECT cve, version, vector, base_score, environmental_score ` +
+			`FROM documents_cvss WHERE documents_id = $1 ` +
+			`ORDER BY vulnerability, version DESC, vector`
+	)
+
+	var (
+		exists     bool
+		enrichment = documentEnrichment{
+			CVEs: []cveEnrichment{},
+			CWEs: []cweEnrichment{},
+			CVSS: []cvssVector{},
+		}
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			if err := conn.QueryRow(rctx, existsSQL, builder.Replacements...).Scan(&exists); err != nil || !exists {
+				return err
+			}
+			rows, _ := conn.Query(rctx, cvesSQL, id)
+			var err error
+			if enrichment.CVEs, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (cveEnrichment, error) {
+				var (
+					ce    cveEnrichment
+					ke    kevEntry
+					inKEV bool
+				)
+				err := row.Scan(
+					&ce.CVE, &inKEV,
+					&ke.Vendor, &ke.Product, &ke.Name, &ke.DateAdded, &ke.DueDate,
+					&ke.Ransomware, &ce.EPSS, &ce.EPSSPercentile)
+				if inKEV {
+					ce.KEV = &ke
+				}
+				return ce, err
+			}); err != nil {
+				return err
+			}
+			rows, _ = conn.Query(rctx, cwesSQL, id)
+			if enrichment.CWEs, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (cweEnrichment, error) {
+				var ce cweEnrichment
+				err := row.Scan(&ce.ID, &ce.Name)
+				return ce, err
+			}); err != nil {
+				return err
+			}
+			rows, _ = conn.Query(rctx, cvssSQL, id)
+			enrichment.CVSS, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (cvssVector, error) {
+				var cv cvssVector
+				err := row.Scan(&cv.CVE, &cv.Version, &cv.Vector, &cv.BaseScore, &cv.EnvironmentalScore)
+				return cv, err
+			})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !exists {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "document not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, enrichment)
+}
+
+// viewEnrichmentImports is an endpoint that returns the state of the enrichment catalogues.
+//
+//	@Summary		Returns the imports of the enrichment catalogues.
+//	@Description	Returns when the enrichment catalogues were modified and imported and how many entries they have.
+//	@Produce		json
+//	@Success		200	{array}		web.enrichmentImport
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/enrichment [get]
+func (c *Controller) viewEnrichmentImports(ctx *gin.Context) {
+	const importsSQL = `SELECT catalogue, modified, imported, entries ` +
+		`FROM enrichment_imports ORDER BY catalogue`
+	var imports []enrichmentImport
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, importsSQL)
+			var err error
+			imports, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (enrichmentImport, error) {
+				var ei enrichmentImport
+				err := row.Scan(&ei.Catalogue, &ei.Modified, &ei.Imported, &ei.Entries)
+				ei.Modified, ei.Imported = ei.Modified.UTC(), ei.Imported.UTC()
+				return ei, err
+			})
+			return err
+		}, 0,
+	); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if imports == nil {
+		imports = []enrichmentImport{}
+	}
+	ctx.JSON(http.StatusOK, imports)
+}
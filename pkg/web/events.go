@@ -186,6 +186,7 @@ func (c *Controller) viewEvents(ctx *gin.Context) {
 		Actor      *string         `json:"actor,omitempty"`
 		DocumentID int64           `json:"document_id"`
 		CommentID  *int64          `json:"comment_id,omitempty"`
+		Tag        *string         `json:"tag,omitempty"`
 	}
 
 	var events []event
@@ -196,7 +197,7 @@ func (c *Controller) viewEvents(ctx *gin.Context) {
 		func(rctx context.Context, conn *pgxpool.Conn) error {
 			existsSQL := `SELECT EXISTS(` +
 				`SELECT FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
-				`WHERE ` + builder.WhereClause + `)`
+				`WHERE documents.deleted IS NULL AND ` + builder.WhereClause + `)`
 			if err := conn.QueryRow(
 				rctx, existsSQL, builder.Replacements...).Scan(&exists); err != nil {
 				return err
@@ -204,11 +205,11 @@ func (c *Controller) viewEvents(ctx *gin.Context) {
 			if !exists {
 				return nil
 			}
-			fetchSQL := `SELECT event, documents_id, time, actor, state, comments_id FROM events_log ` +
+			fetchSQL := `SELECT event, documents_id, time, actor, state, comments_id, tag FROM events_log ` +
 				`WHERE documents_id in (` +
 				`SELECT documents.id ` +
 				`FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
-				`WHERE ` + builder.WhereClause + `) ORDER BY time DESC`
+				`WHERE documents.deleted IS NULL AND ` + builder.WhereClause + `) ORDER BY time DESC`
 			rows, _ := conn.Query(rctx, fetchSQL, builder.Replacements...)
 			var err error
 			events, err = pgx.CollectRows(
@@ -216,7 +217,7 @@ func (c *Controller) viewEvents(ctx *gin.Context) {
 				func(row pgx.CollectableRow) (event, error) {
 					var ev event
 					var act sql.NullString
-					err := row.Scan(&ev.Event, &ev.DocumentID, &ev.Time, &ev.Actor, &ev.State, &ev.CommentID)
+					err := row.Scan(&ev.Event, &ev.DocumentID, &ev.Time, &ev.Actor, &ev.State, &ev.CommentID, &ev.Tag)
 					ev.Time = ev.Time.UTC()
 					if act.Valid {
 						ev.Actor = &act.String
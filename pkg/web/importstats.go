@@ -40,7 +40,7 @@ const (
 		`FROM downloads JOIN documents ON downloads.documents_id = documents.id ` +
 		`JOIN documents_cves ON documents.id = documents_cves.documents_id ` +
 		`%s ` + // placeholder for deeper joins.
-		`WHERE time BETWEEN $2 AND $3 ` +
+		`WHERE time BETWEEN $2 AND $3 AND documents.deleted IS NULL ` +
 		`%s ` + // placeholder for more filters.
 		`GROUP BY bucket ` +
 		`ORDER BY bucket`
@@ -50,7 +50,7 @@ const (
 		`count(*) AS count ` +
 		`FROM downloads JOIN documents ON downloads.documents_id = documents.id ` +
 		`%s ` + // placeholder for deeper joins.
-		`WHERE time BETWEEN $2 AND $3 ` +
+		`WHERE time BETWEEN $2 AND $3 AND documents.deleted IS NULL ` +
 		`%s ` + // placeholder for more filters.
 		`GROUP BY bucket, critical ` +
 		`ORDER BY bucket, critical`
@@ -0,0 +1,239 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocsaf/csaf/v3/csaf"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/sources"
+)
+
+// pmdVersion is a stored version of the PMD of a source.
+type pmdVersion struct {
+	ID           int64            `json:"id"`
+	Fetched      time.Time        `json:"fetched"`
+	LastUpdated  *string          `json:"last_updated,omitempty"`
+	Acknowledged bool             `json:"acknowledged"`
+	Changes      *sources.PMDDiff `json:"changes,omitempty"`
+}
+
+// pmdVersionsDiff is the difference between two versions of the PMD of a source.
+type pmdVersionsDiff struct {
+	From int64            `json:"from"`
+	To   int64            `json:"to"`
+	Diff *sources.PMDDiff `json:"diff"`
+}
+
+// errNoSuchSource is returned if a source does not exist.
+var errNoSuchSource = errors.New("no such source")
+
+// sourceChecksumAck returns the time the PMD changes of a source were acknowledged.
+func sourceChecksumAck(ctx context.Context, conn *pgxpool.Conn, sourceID int64) (time.Time, error) {
+	const ackSQL = `SELECT checksum_ack FROM sources WHERE id = $1`
+	var ack time.Time
+	if err := conn.QueryRow(ctx, ackSQL, sourceID).Scan(&ack); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, errNoSuchSource
+		}
+		return time.Time{}, err
+	}
+	return ack, nil
+}
+
+// decodePMD decodes a stored PMD.
+func decodePMD(document []byte) (*csaf.ProviderMetadata, error) {
+	pmd := new(csaf.ProviderMetadata)
+	if err := json.Unmarshal(document, pmd); err != nil {
+		return nil, err
+	}
+	return pmd, nil
+}
+
+// viewPMDHistory is an endpoint that returns the stored versions of the PMD of a source.
+//
+//	@Summary		Returns the PMD history of a source.
+//	@Description	Returns the stored versions of the provider metadata of a source, newest first.
+//	@Param			id		path	int		true	"Source ID"
+//	@Param			diff	query	bool	false	"Include the changes to the previous version"
+//	@Produce		json
+//	@Success		200	{array}		pmdVersion
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/pmd/history [get]
+func (c *Controller) viewPMDHistory(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	diff, ok := parse(ctx, strconv.ParseBool, ctx.DefaultQuery("diff", "false"))
+	if !ok {
+		return
+	}
+	const historySQL = `SELECT id, fetched, document->>'last_updated', ` +
+		`CASE WHEN $2 THEN document END ` +
+		`FROM source_pmds WHERE sources_id = $1 ORDER BY id`
+	var versions []pmdVersion
+	switch err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			ack, err := sourceChecksumAck(rctx, conn, sourceID)
+			if err != nil {
+				return err
+			}
+			rows, _ := conn.Query(rctx, historySQL, sourceID, diff)
+			var prev *csaf.ProviderMetadata
+			versions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (pmdVersion, error) {
+				var (
+					v        pmdVersion
+					document []byte
+				)
+				if err := row.Scan(&v.ID, &v.Fetched, &v.LastUpdated, &document); err != nil {
+					return v, err
+				}
+				v.Acknowledged = !v.Fetched.After(ack)
+				if !diff {
+					return v, nil
+				}
+				pmd, err := decodePMD(document)
+				if err != nil {
+					return v, err
+				}
+				if prev != nil {
+					v.Changes = sources.DiffPMDs(prev, pmd)
+				}
+				prev = pmd
+				return v, nil
+			})
+			return err
+		}, 0,
+	); {
+	case errors.Is(err, errNoSuchSource):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	slices.Reverse(versions)
+	ctx.JSON(http.StatusOK, versions)
+}
+
+// viewPMDDiff is an endpoint that returns the difference between two versions of the PMD of a source.
+//
+//	@Summary		Returns the difference between two PMD versions.
+//	@Description	Returns the structural difference between two stored versions of the provider metadata of a source. By default the last acknowledged version is compared with the latest one.
+//	@Param			id		path	int	true	"Source ID"
+//	@Param			from	query	int	false	"Older version"
+//	@Param			to		query	int	false	"Newer version"
+//	@Produce		json
+//	@Success		200	{object}	pmdVersionsDiff
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/pmd/diff [get]
+func (c *Controller) viewPMDDiff(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	var from, to *int64
+	if fromParam, ok := ctx.GetQuery("from"); ok {
+		v, ok := parse(ctx, toInt64, fromParam)
+		if !ok {
+			return
+		}
+		from = &v
+	}
+	if toParam, ok := ctx.GetQuery("to"); ok {
+		v, ok := parse(ctx, toInt64, toParam)
+		if !ok {
+			return
+		}
+		to = &v
+	}
+	const (
+		latestSQL = `SELECT max(id) FROM source_pmds WHERE sources_id = $1`
+		// The last acknowledged version or the previous one.
+		previousSQL = `SELECT id FROM source_pmds ` +
+			`WHERE sources_id = $1 AND id < $2 ` +
+			`ORDER BY fetched <= $3 DESC, id DESC LIMIT 1`
+		documentSQL = `SELECT document FROM source_pmds WHERE sources_id = $1 AND id = $2`
+	)
+	var result pmdVersionsDiff
+	switch err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			ack, err := sourceChecksumAck(rctx, conn, sourceID)
+			if err != nil {
+				return err
+			}
+			if to == nil {
+				if err := conn.QueryRow(rctx, latestSQL, sourceID).Scan(&to); err != nil {
+					return err
+				}
+				if to == nil {
+					return pgx.ErrNoRows
+				}
+			}
+			if from == nil {
+				from = new(int64)
+				if err := conn.QueryRow(rctx, previousSQL, sourceID, *to, ack).Scan(from); err != nil {
+					return err
+				}
+			}
+			load := func(id int64) (*csaf.ProviderMetadata, error) {
+				var document []byte
+				if err := conn.QueryRow(rctx, documentSQL, sourceID, id).Scan(&document); err != nil {
+					return nil, err
+				}
+				return decodePMD(document)
+			}
+			fromPMD, err := load(*from)
+			if err != nil {
+				return err
+			}
+			toPMD, err := load(*to)
+			if err != nil {
+				return err
+			}
+			result = pmdVersionsDiff{
+				From: *from,
+				To:   *to,
+				Diff: sources.DiffPMDs(fromPMD, toPMD),
+			}
+			return nil
+		}, 0,
+	); {
+	case errors.Is(err, errNoSuchSource), errors.Is(err, pgx.ErrNoRows):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &result)
+}
@@ -0,0 +1,339 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/sources"
+)
+
+// quarantinedDocument is a download which failed the checks.
+type quarantinedDocument struct {
+	ID         int64      `json:"id"`
+	FeedID     *int64     `json:"feed_id,omitempty"`
+	URL        string     `json:"url"`
+	Time       time.Time  `json:"time"`
+	Filename   *string    `json:"filename,omitempty"`
+	Publisher  *string    `json:"publisher,omitempty"`
+	TrackingID *string    `json:"tracking_id,omitempty"`
+	Version    *string    `json:"version,omitempty"`
+	TLP        *string    `json:"tlp,omitempty"`
+	Failures   []string   `json:"failures"`
+	State      string     `json:"state"`
+	Decided    *time.Time `json:"decided,omitempty"`
+	Actor      *string    `json:"actor,omitempty"`
+	DocumentID *int64     `json:"document_id,omitempty"`
+}
+
+// quarantinedDocumentDetails is a quarantined download with its content.
+type quarantinedDocumentDetails struct {
+	quarantinedDocument
+	// Versions are the ids of the documents of the same advisory.
+	Versions  []int64         `json:"versions"`
+	Signature *string         `json:"signature,omitempty"`
+	Document  json.RawMessage `json:"document" swaggertype:"object"`
+}
+
+const quarantineColumns = `id, feeds_id, url, time, filename, publisher, tracking_id, version, tlp, ` +
+	`filename_failed, schema_failed, remote_failed, checksum_failed, signature_failed, ` +
+	`state::text, decided, actor, documents_id`
+
+// scanQuarantined scans a row selected with the quarantine columns.
+func scanQuarantined(row pgx.Row, q *quarantinedDocument, extra ...any) error {
+	var failed [5]bool
+	if err := row.Scan(append([]any{
+		&q.ID, &q.FeedID, &q.URL, &q.Time, &q.Filename,
+		&q.Publisher, &q.TrackingID, &q.Version, &q.TLP,
+		&failed[0], &failed[1], &failed[2], &failed[3], &failed[4],
+		&q.State, &q.Decided, &q.Actor, &q.DocumentID,
+	}, extra...)...); err != nil {
+		return err
+	}
+	q.Failures = []string{}
+	for i, failure := range []string{
+		"filename", "schema", "remote_validation", "checksum", "signature",
+	} {
+		if failed[i] {
+			q.Failures = append(q.Failures, failure)
+		}
+	}
+	return nil
+}
+
+// viewQuarantine is an endpoint that returns the quarantined downloads.
+//
+//	@Summary		Returns the quarantined downloads.
+//	@Description	Returns the downloads which failed the checks in strict mode, newest first.
+//	@Param			state	query	string	false	"State (pending, released, rejected)"
+//	@Param			feed	query	int		false	"Feed ID"
+//	@Produce		json
+//	@Success		200	{array}		quarantinedDocument
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/quarantine [get]
+func (c *Controller) viewQuarantine(ctx *gin.Context) {
+	var (
+		values []any
+		conds  []string
+	)
+	if state, ok := ctx.GetQuery("state"); ok {
+		switch state {
+		case "pending", "released", "rejected":
+		default:
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid state")
+			return
+		}
+		values = append(values, state)
+		conds = append(conds, fmt.Sprintf("state = $%d::quarantine_state", len(values)))
+	}
+	if feedParam, ok := ctx.GetQuery("feed"); ok {
+		id, ok := parse(ctx, toInt64, feedParam)
+		if !ok {
+			return
+		}
+		values = append(values, id)
+		conds = append(conds, fmt.Sprintf("feeds_id = $%d", len(values)))
+	}
+	sql := `SELECT ` + quarantineColumns + ` FROM quarantine`
+	if len(conds) > 0 {
+		sql += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	sql += ` ORDER BY id DESC`
+
+	var list []quarantinedDocument
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, sql, values...)
+			var err error
+			list, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (quarantinedDocument, error) {
+				var q quarantinedDocument
+				err := scanQuarantined(row, &q)
+				return q, err
+			})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// versionsExpr selects the documents of the advisory of a
+// quarantined download which the user is allowed to see.
+func (c *Controller) versionsExpr(ctx *gin.Context, publisher, trackingID string) *query.Expr {
+	return c.andTLPExpr(ctx,
+		query.FieldEqString("publisher", publisher).And(
+			query.FieldEqString("tracking_id", trackingID)))
+}
+
+// viewQuarantined is an endpoint that returns a quarantined download.
+//
+//	@Summary		Returns a quarantined download.
+//	@Description	Returns the quarantined download with the specified ID including its content and the existing versions of the advisory.
+//	@Param			id	path	int	true	"Quarantine ID"
+//	@Produce		json
+//	@Success		200	{object}	quarantinedDocumentDetails
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/quarantine/{id} [get]
+func (c *Controller) viewQuarantined(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	const fetchSQL = `SELECT ` + quarantineColumns + `, original, signature ` +
+		`FROM quarantine WHERE id = $1`
+	var details quarantinedDocumentDetails
+	switch err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var original, signature []byte
+			if err := scanQuarantined(
+				conn.QueryRow(rctx, fetchSQL, id),
+				&details.quarantinedDocument,
+				&original, &signature,
+			); err != nil {
+				return err
+			}
+			details.Document = original
+			if signature != nil {
+				s := string(signature)
+				details.Signature = &s
+			}
+			details.Versions = []int64{}
+			if details.Publisher == nil || details.TrackingID == nil {
+				return nil
+			}
+			builder := query.SQLBuilder{}
+			builder.CreateWhere(c.versionsExpr(ctx, *details.Publisher, *details.TrackingID))
+			versionsSQL := `SELECT documents.id FROM documents ` +
+				`JOIN advisories ON documents.advisories_id = advisories.id ` +
+				`WHERE documents.deleted IS NULL AND ` + builder.WhereClause +
+				` ORDER BY documents.id DESC`
+			rows, _ := conn.Query(rctx, versionsSQL, builder.Replacements...)
+			var err error
+			details.Versions, err = pgx.CollectRows(rows, pgx.RowTo[int64])
+			return err
+		}, 0,
+	); {
+	case errors.Is(err, pgx.ErrNoRows):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	// Documents failing the checks may not be valid JSON objects.
+	if !json.Valid(details.Document) {
+		details.Document = nil
+	}
+	ctx.JSON(http.StatusOK, &details)
+}
+
+// viewQuarantinedDiff is an endpoint that returns the diff between an existing document and a quarantined download.
+//
+//	@Summary		Returns a diff against a quarantined download.
+//	@Description	Returns the diff between an existing version of the advisory and the quarantined download. By default the newest version is used.
+//	@Param			id			path	int		true	"Quarantine ID"
+//	@Param			document	query	int		false	"Document ID"
+//	@Param			word-diff	query	bool	false	"Calculate word diffs of replacements"
+//	@Produce		json
+//	@Success		200	{object}	any
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/quarantine/{id}/diff [get]
+func (c *Controller) viewQuarantinedDiff(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	var documentID *int64
+	if documentParam, ok := ctx.GetQuery("document"); ok {
+		v, ok := parse(ctx, toInt64, documentParam)
+		if !ok {
+			return
+		}
+		documentID = &v
+	}
+	const fetchSQL = `SELECT original, publisher, tracking_id FROM quarantine WHERE id = $1`
+	var existing, quarantined []byte
+	switch err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var publisher, trackingID *string
+			if err := conn.QueryRow(rctx, fetchSQL, id).Scan(
+				&quarantined, &publisher, &trackingID,
+			); err != nil {
+				return err
+			}
+			if publisher == nil || trackingID == nil {
+				return pgx.ErrNoRows
+			}
+			// Only versions of the same advisory the user
+			// is allowed to see can be compared.
+			expr := c.versionsExpr(ctx, *publisher, *trackingID)
+			if documentID != nil {
+				expr = expr.And(query.FieldEqInt("id", *documentID))
+			}
+			builder := query.SQLBuilder{}
+			builder.CreateWhere(expr)
+			documentSQL := `SELECT original FROM documents ` +
+				`JOIN advisories ON documents.advisories_id = advisories.id ` +
+				`WHERE documents.deleted IS NULL AND ` + builder.WhereClause +
+				` ORDER BY documents.id DESC LIMIT 1`
+			return conn.QueryRow(rctx, documentSQL, builder.Replacements...).Scan(&existing)
+		}, 0,
+	); {
+	case errors.Is(err, pgx.ErrNoRows):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	sendDiff(ctx, existing, quarantined)
+}
+
+// decideQuarantined is an endpoint that releases or rejects a quarantined download.
+//
+//	@Summary		Releases or rejects a quarantined download.
+//	@Description	Imports the pending quarantined download with the specified ID despite its failed checks or rejects it.
+//	@Param			id		path		int		true	"Quarantine ID"
+//	@Param			accept	formData	bool	true	"Release or reject the download"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/quarantine/{id} [put]
+func (c *Controller) decideQuarantined(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	accept, ok := parse(ctx, strconv.ParseBool, ctx.PostForm("accept"))
+	if !ok {
+		return
+	}
+	var actor *string
+	if user := c.currentUser(ctx); user.Valid {
+		actor = &user.String
+	}
+	var err error
+	if accept {
+		_, err = c.sm.ReleaseQuarantined(ctx.Request.Context(), id, actor)
+	} else {
+		err = c.sm.RejectQuarantined(ctx.Request.Context(), id, actor)
+	}
+	switch {
+	case err == nil:
+		if accept {
+			models.SendSuccess(ctx, http.StatusOK, "released")
+		} else {
+			models.SendSuccess(ctx, http.StatusOK, "rejected")
+		}
+	case errors.Is(err, sources.NoSuchEntryError("")):
+		models.SendError(ctx, http.StatusNotFound, err)
+	case errors.Is(err, models.ErrNotAllowed):
+		models.SendError(ctx, http.StatusForbidden, err)
+	case errors.Is(err, sources.InvalidArgumentError("")):
+		models.SendError(ctx, http.StatusBadRequest, err)
+	default:
+		slog.Error("deciding quarantined download failed", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+	}
+}
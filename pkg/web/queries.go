@@ -107,8 +107,8 @@ func (c *Controller) createStoredQuery(ctx *gin.Context) {
 	if !ok {
 		return
 	}
-	// In advisory mode we only show the latest.
-	if sq.Kind == query.AdvisoryMode {
+	// In advisory and case mode we only show the latest.
+	if sq.Kind == query.AdvisoryMode || sq.Kind == query.CaseMode {
 		expr = expr.And(query.BoolField("latest"))
 	}
 
@@ -579,7 +579,7 @@ func (c *Controller) updateStoredQuery(ctx *gin.Context) {
 				bad = "bad 'query' value: " + err.Error()
 				return nil
 			}
-			if sq.Kind == query.AdvisoryMode {
+			if sq.Kind == query.AdvisoryMode || sq.Kind == query.CaseMode {
 				expr = expr.And(query.BoolField("latest"))
 			}
 
@@ -31,7 +31,7 @@ WITH related AS (
      JOIN unique_cves uc ON dc.cve_id          = uc.id
      JOIN documents docs ON dc.documents_id    = docs.id
      JOIN advisories ads ON docs.advisories_id = ads.id
-   WHERE documents_id = $%[2]d AND %[1]s
+   WHERE documents_id = $%[2]d AND docs.deleted IS NULL AND %[1]s
 ),
 others AS (
   SELECT
@@ -61,7 +61,7 @@ FROM others
     ORDER BY changedate DESC, change_number DESC
     LIMIT 1
   ) sh ON true
-WHERE %[1]s
+WHERE d.deleted IS NULL AND %[1]s
 `
 
 // cveRelatedDocuments is an endpoint that returns the documents
@@ -395,7 +395,7 @@ func (c *Controller) documentTexts(ctx *gin.Context) {
 			`FROM` +
 			` documents docs JOIN` +
 			` advisories ads ON docs.advisories_id = ads.id ` +
-			`WHERE docs.id = $1`
+			`WHERE docs.id = $1 AND docs.deleted IS NULL`
 		uniqueTextsSQL = `` +
 			`SELECT` +
 			` dt.num,` +
@@ -0,0 +1,283 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/sources"
+)
+
+// sourceKey is a pinned OpenPGP key of a source.
+type sourceKey struct {
+	Fingerprint string     `json:"fingerprint"`
+	State       string     `json:"state"`
+	Advertised  bool       `json:"advertised"`
+	Uploaded    bool       `json:"uploaded"`
+	Expires     *time.Time `json:"expires,omitempty"`
+	FirstSeen   time.Time  `json:"first_seen"`
+	Decided     *time.Time `json:"decided,omitempty"`
+	Actor       *string    `json:"actor,omitempty"`
+	Used        int64      `json:"used"`
+	LastUsed    *time.Time `json:"last_used,omitempty"`
+}
+
+// sourceKeyEvent is a change of the OpenPGP keys of a source.
+type sourceKeyEvent struct {
+	Fingerprint string    `json:"fingerprint"`
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	Actor       *string   `json:"actor,omitempty"`
+}
+
+// sourceExists checks if a source exists.
+func sourceExists(ctx context.Context, conn *pgxpool.Conn, sourceID int64) error {
+	const existsSQL = `SELECT EXISTS(SELECT 1 FROM sources WHERE id = $1)`
+	var exists bool
+	if err := conn.QueryRow(ctx, existsSQL, sourceID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errNoSuchSource
+	}
+	return nil
+}
+
+// viewSourceKeys is an endpoint that returns the pinned OpenPGP keys of a source.
+//
+//	@Summary		Returns the OpenPGP keys of a source.
+//	@Description	Returns the pinned OpenPGP keys of a source with their trust state, expiry and usage counts.
+//	@Param			id	path	int	true	"Source ID"
+//	@Produce		json
+//	@Success		200	{array}		sourceKey
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/keys [get]
+func (c *Controller) viewSourceKeys(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	const keysSQL = `SELECT fingerprint, state::text, advertised, uploaded, expires, ` +
+		`first_seen, decided, actor, used, last_used ` +
+		`FROM source_keys WHERE sources_id = $1 ORDER BY first_seen`
+	var keys []sourceKey
+	switch err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			if err := sourceExists(rctx, conn, sourceID); err != nil {
+				return err
+			}
+			rows, _ := conn.Query(rctx, keysSQL, sourceID)
+			var err error
+			keys, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (sourceKey, error) {
+				var k sourceKey
+				err := row.Scan(
+					&k.Fingerprint, &k.State, &k.Advertised, &k.Uploaded, &k.Expires,
+					&k.FirstSeen, &k.Decided, &k.Actor, &k.Used, &k.LastUsed)
+				return k, err
+			})
+			return err
+		}, 0,
+	); {
+	case errors.Is(err, errNoSuchSource):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if keys == nil {
+		keys = []sourceKey{}
+	}
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// viewSourceKeysLog is an endpoint that returns the changes of the OpenPGP keys of a source.
+//
+//	@Summary		Returns the OpenPGP key changes of a source.
+//	@Description	Returns the pinned, added, removed and decided OpenPGP keys of a source, newest first.
+//	@Param			id	path	int	true	"Source ID"
+//	@Produce		json
+//	@Success		200	{array}		sourceKeyEvent
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/keys/log [get]
+func (c *Controller) viewSourceKeysLog(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	const logSQL = `SELECT fingerprint, event::text, time, actor ` +
+		`FROM source_keys_log WHERE sources_id = $1 ORDER BY time DESC, id DESC`
+	var events []sourceKeyEvent
+	switch err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			if err := sourceExists(rctx, conn, sourceID); err != nil {
+				return err
+			}
+			rows, _ := conn.Query(rctx, logSQL, sourceID)
+			var err error
+			events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (sourceKeyEvent, error) {
+				var e sourceKeyEvent
+				err := row.Scan(&e.Fingerprint, &e.Event, &e.Time, &e.Actor)
+				return e, err
+			})
+			return err
+		}, 0,
+	); {
+	case errors.Is(err, errNoSuchSource):
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if events == nil {
+		events = []sourceKeyEvent{}
+	}
+	ctx.JSON(http.StatusOK, events)
+}
+
+// sendKeyError sends the error of a change of the OpenPGP keys.
+func sendKeyError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sources.NoSuchEntryError("")):
+		models.SendError(ctx, http.StatusNotFound, err)
+	case errors.Is(err, sources.InvalidArgumentError("")):
+		models.SendError(ctx, http.StatusBadRequest, err)
+	default:
+		slog.Error("changing OpenPGP keys failed", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// createSourceKey is an endpoint that uploads an additional trusted OpenPGP key.
+//
+//	@Summary		Uploads an OpenPGP key.
+//	@Description	Adds an armored public OpenPGP key to the trusted keys of a source.
+//	@Param			id	path		int		true	"Source ID"
+//	@Param			key	formData	string	true	"Armored public OpenPGP key"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		201	{object}	web.createSourceKey.fingerprint
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/keys [post]
+func (c *Controller) createSourceKey(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	armored, ok := ctx.GetPostForm("key")
+	if !ok {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "missing key")
+		return
+	}
+	var actor *string
+	if user := c.currentUser(ctx); user.Valid {
+		actor = &user.String
+	}
+	fp, err := c.sm.AddKey(sourceID, armored, actor)
+	if err != nil {
+		sendKeyError(ctx, err)
+		return
+	}
+	type fingerprint struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	ctx.JSON(http.StatusCreated, fingerprint{Fingerprint: fp})
+}
+
+// decideSourceKey is an endpoint that trusts or rejects an OpenPGP key.
+//
+//	@Summary		Trusts or rejects an OpenPGP key.
+//	@Description	Approves or rejects a pinned OpenPGP key of a source.
+//	@Param			id			path		int		true	"Source ID"
+//	@Param			fingerprint	path		string	true	"Key fingerprint"
+//	@Param			trust		formData	bool	true	"Trust or reject the key"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/keys/{fingerprint} [put]
+func (c *Controller) decideSourceKey(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	trust, ok := parse(ctx, strconv.ParseBool, ctx.PostForm("trust"))
+	if !ok {
+		return
+	}
+	var actor *string
+	if user := c.currentUser(ctx); user.Valid {
+		actor = &user.String
+	}
+	if err := c.sm.DecideKey(sourceID, ctx.Param("fingerprint"), trust, actor); err != nil {
+		sendKeyError(ctx, err)
+		return
+	}
+	if trust {
+		models.SendSuccess(ctx, http.StatusOK, "trusted")
+	} else {
+		models.SendSuccess(ctx, http.StatusOK, "rejected")
+	}
+}
+
+// deleteSourceKey is an endpoint that removes an uploaded OpenPGP key.
+//
+//	@Summary		Removes an uploaded OpenPGP key.
+//	@Description	Removes a manually uploaded OpenPGP key from a source. Advertised keys can only be rejected.
+//	@Param			id			path	int		true	"Source ID"
+//	@Param			fingerprint	path	string	true	"Key fingerprint"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/{id}/keys/{fingerprint} [delete]
+func (c *Controller) deleteSourceKey(ctx *gin.Context) {
+	sourceID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	var actor *string
+	if user := c.currentUser(ctx); user.Valid {
+		actor = &user.String
+	}
+	if err := c.sm.RemoveKey(sourceID, ctx.Param("fingerprint"), actor); err != nil {
+		sendKeyError(ctx, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "key deleted")
+}
@@ -22,10 +22,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/ISDuBA/ISDuBA/pkg/config"
 	"github.com/ISDuBA/ISDuBA/pkg/models"
+	"github.com/ISDuBA/ISDuBA/pkg/schedule"
 	"github.com/ISDuBA/ISDuBA/pkg/sources"
 	"github.com/gin-gonic/gin"
 )
@@ -51,25 +53,37 @@ func (sa sourceAge) MarshalText() ([]byte, error) {
 }
 
 type source struct {
-	ID                   int64          `json:"id" form:"id"`
-	Name                 string         `json:"name" form:"name" binding:"required,min=1"`
-	URL                  string         `json:"url" form:"url" binding:"required,min=1"`
-	Active               bool           `json:"active" form:"active"`
-	Attention            bool           `json:"attention" form:"attention"`
-	Status               []string       `json:"status,omitempty"`
-	Rate                 *float64       `json:"rate,omitempty" form:"rate" binding:"omitnil,gte=0"`
-	Slots                *int           `json:"slots,omitempty" form:"slots" binding:"omitnil,gte=0"`
-	Headers              []string       `json:"headers,omitempty" form:"headers"`
-	StrictMode           *bool          `json:"strict_mode,omitempty" form:"strict_mode"`
-	Secure               *bool          `json:"secure,omitempty" form:"secure"`
-	SignatureCheck       *bool          `json:"signature_check,omitempty" form:"signature_check"`
-	Age                  *sourceAge     `json:"age,omitempty" form:"age" swaggertype:"primitive,integer"`
-	IgnorePatterns       []string       `json:"ignore_patterns,omitempty" form:"ignore_patterns"`
-	ClientCertPublic     *string        `json:"client_cert_public,omitempty" form:"client_cert_public"`
-	ClientCertPrivate    *string        `json:"client_cert_private,omitempty" form:"client_cert_private"`
-	ClientCertPassphrase *string        `json:"client_cert_passphrase,omitempty" form:"client_cert_passphrase"`
-	Stats                *sources.Stats `json:"stats,omitempty"`
-	Healthy              *bool          `json:"healthy,omitempty"`
+	ID                   int64               `json:"id" form:"id"`
+	Name                 string              `json:"name" form:"name" binding:"required,min=1"`
+	URL                  string              `json:"url" form:"url" binding:"required,min=1"`
+	Active               bool                `json:"active" form:"active"`
+	Attention            bool                `json:"attention" form:"attention"`
+	Status               []string            `json:"status,omitempty"`
+	Rate                 *float64            `json:"rate,omitempty" form:"rate" binding:"omitnil,gte=0"`
+	Slots                *int                `json:"slots,omitempty" form:"slots" binding:"omitnil,gte=0"`
+	Headers              []string            `json:"headers,omitempty" form:"headers"`
+	StrictMode           *bool               `json:"strict_mode,omitempty" form:"strict_mode"`
+	Secure               *bool               `json:"secure,omitempty" form:"secure"`
+	SignatureCheck       *bool               `json:"signature_check,omitempty" form:"signature_check"`
+	Age                  *sourceAge          `json:"age,omitempty" form:"age" swaggertype:"primitive,integer"`
+	IgnorePatterns       []string            `json:"ignore_patterns,omitempty" form:"ignore_patterns"`
+	ClientCertPublic     *string             `json:"client_cert_public,omitempty" form:"client_cert_public"`
+	ClientCertPrivate    *string             `json:"client_cert_private,omitempty" form:"client_cert_private"`
+	ClientCertPassphrase *string             `json:"client_cert_passphrase,omitempty" form:"client_cert_passphrase"`
+	Auth                 *string             `json:"auth,omitempty" form:"auth"`
+	BearerToken          *string             `json:"bearer_token,omitempty" form:"bearer_token"`
+	OAuth2TokenURL       *string             `json:"oauth2_token_url,omitempty" form:"oauth2_token_url"`
+	OAuth2ClientID       *string             `json:"oauth2_client_id,omitempty" form:"oauth2_client_id"`
+	OAuth2ClientSecret   *string             `json:"oauth2_client_secret,omitempty" form:"oauth2_client_secret"`
+	OAuth2Scopes         []string            `json:"oauth2_scopes,omitempty" form:"oauth2_scopes"`
+	Token                *sources.TokenState `json:"token,omitempty"`
+	Proxy                *string             `json:"proxy,omitempty" form:"proxy"`
+	ProxyCredentials     *string             `json:"proxy_credentials,omitempty" form:"proxy_credentials"`
+	Schedule             *string             `json:"schedule,omitempty" form:"schedule"`
+	NextRun              *time.Time          `json:"next_run,omitempty"`
+	Stats                *sources.Stats      `json:"stats,omitempty"`
+	Healthy              *bool               `json:"healthy,omitempty"`
+	Health               *sources.Health     `json:"health,omitempty"`
 }
 
 type feed struct {
@@ -78,8 +92,11 @@ type feed struct {
 	URL      string              `json:"url"`
 	Rolie    bool                `json:"rolie"`
 	LogLevel config.FeedLogLevel `json:"log_level"`
+	Schedule *string             `json:"schedule,omitempty"`
+	NextRun  *time.Time          `json:"next_run,omitempty"`
 	Stats    *sources.Stats      `json:"stats,omitempty"`
 	Healthy  *bool               `json:"healthy,omitempty"`
+	Health   *sources.Health     `json:"health,omitempty"`
 }
 
 var stars = "***"
@@ -96,6 +113,7 @@ func newSource(si *sources.SourceInfo, healthy *bool) *source {
 	if si.Age != nil {
 		sa = &sourceAge{*si.Age}
 	}
+	auth := si.Auth.String()
 	return &source{
 		ID:                   si.ID,
 		Name:                 si.Name,
@@ -114,8 +132,20 @@ func newSource(si *sources.SourceInfo, healthy *bool) *source {
 		ClientCertPublic:     threeStars(si.HasClientCertPublic),
 		ClientCertPrivate:    threeStars(si.HasClientCertPrivate),
 		ClientCertPassphrase: threeStars(si.HasClientCertPassphrase),
+		Auth:                 &auth,
+		BearerToken:          threeStars(si.HasBearerToken),
+		OAuth2TokenURL:       si.OAuth2TokenURL,
+		OAuth2ClientID:       si.OAuth2ClientID,
+		OAuth2ClientSecret:   threeStars(si.HasOAuth2ClientSecret),
+		OAuth2Scopes:         si.OAuth2Scopes,
+		Token:                si.Token,
+		Proxy:                si.Proxy,
+		ProxyCredentials:     threeStars(si.HasProxyCredentials),
+		Schedule:             schedule.String(si.Schedule),
+		NextRun:              si.NextRun,
 		Stats:                si.Stats,
 		Healthy:              healthy,
+		Health:               si.Health,
 	}
 }
 
@@ -126,8 +156,11 @@ func newFeed(fi *sources.FeedInfo, healthy *bool) *feed {
 		URL:      fi.URL.String(),
 		Rolie:    fi.Rolie,
 		LogLevel: fi.Lvl,
+		Schedule: schedule.String(fi.Schedule),
+		NextRun:  fi.NextRun,
 		Stats:    fi.Stats,
 		Healthy:  healthy,
+		Health:   fi.Health,
 	}
 }
 
@@ -269,6 +302,11 @@ func (c *Controller) createSource(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	sched, err := sources.AsSchedule(src.Schedule)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	var clientCertPublic, clientCertPrivate, clientCertPassphrase []byte
 	if src.ClientCertPublic != nil {
 		clientCertPublic = []byte(*src.ClientCertPublic)
@@ -287,6 +325,15 @@ func (c *Controller) createSource(ctx *gin.Context) {
 	if src.ClientCertPassphrase != nil {
 		clientCertPassphrase = []byte(*src.ClientCertPassphrase)
 	}
+	auth, err := sourceAuth(&src)
+	if err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	proxy := &sources.Proxy{URL: src.Proxy}
+	if src.ProxyCredentials != nil {
+		proxy.Credentials = []byte(*src.ProxyCredentials)
+	}
 
 	var age *time.Duration
 	if src.Age != nil {
@@ -307,9 +354,12 @@ func (c *Controller) createSource(ctx *gin.Context) {
 		src.SignatureCheck,
 		age,
 		ignorePatterns,
+		sched,
 		clientCertPublic,
 		clientCertPrivate,
 		clientCertPassphrase,
+		auth,
+		proxy,
 	); {
 	case err == nil:
 		ctx.JSON(http.StatusCreated, models.ID{ID: id})
@@ -556,6 +606,16 @@ func (c *Controller) updateSource(ctx *gin.Context) {
 				return err
 			}
 		}
+		// schedule
+		if value, ok := ctx.GetPostForm("schedule"); ok {
+			sched, err := sources.AsSchedule(&value)
+			if err != nil {
+				return err
+			}
+			if err := su.UpdateSchedule(sched); err != nil {
+				return err
+			}
+		}
 		// client certificate update
 		optCert := func(option string, update func([]byte) error) error {
 			cert, ok := ctx.GetPostForm(option)
@@ -587,6 +647,62 @@ func (c *Controller) updateSource(ctx *gin.Context) {
 				return err
 			}
 		}
+		// authentication
+		if value, ok := ctx.GetPostForm("auth"); ok {
+			mode, err := sources.ParseAuthMode(value)
+			if err != nil {
+				return err
+			}
+			if err := su.UpdateAuthMode(mode); err != nil {
+				return err
+			}
+		}
+		optString := func(option string, update func(*string) error) error {
+			value, ok := ctx.GetPostForm(option)
+			if !ok {
+				return nil
+			}
+			var s *string
+			if value != "" {
+				s = &value
+			}
+			return update(s)
+		}
+		if err := optString("oauth2_token_url", su.UpdateOAuth2TokenURL); err != nil {
+			return err
+		}
+		if err := optString("oauth2_client_id", su.UpdateOAuth2ClientID); err != nil {
+			return err
+		}
+		if scopes, ok := ctx.GetPostFormArray("oauth2_scopes"); ok {
+			if err := su.UpdateOAuth2Scopes(scopes); err != nil {
+				return err
+			}
+		}
+		optSecret := func(option string, update func([]byte) error) error {
+			value, ok := ctx.GetPostForm(option)
+			if !ok {
+				return nil
+			}
+			var data []byte
+			if value != "" {
+				data = []byte(value)
+			}
+			return update(data)
+		}
+		if err := optSecret("bearer_token", su.UpdateBearerToken); err != nil {
+			return err
+		}
+		if err := optSecret("oauth2_client_secret", su.UpdateOAuth2ClientSecret); err != nil {
+			return err
+		}
+		// proxy
+		if err := optString("proxy", su.UpdateProxy); err != nil {
+			return err
+		}
+		if err := optSecret("proxy_credentials", su.UpdateProxyCredentials); err != nil {
+			return err
+		}
 		return nil
 	}); {
 	case err == nil:
@@ -601,6 +717,54 @@ func (c *Controller) updateSource(ctx *gin.Context) {
 	}
 }
 
+// fetchSource is an endpoint that fetches the feeds of a source bypassing their schedules.
+//
+//	@Summary		Fetches a source now.
+//	@Description	Refreshes the feeds of the active source with the specified ID and downloads the new entries bypassing the schedules.
+//	@Param			id	path	int	true	"Source ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Router			/sources/{id}/fetch [post]
+func (c *Controller) fetchSource(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	switch err := c.sm.FetchSource(id); {
+	case err == nil:
+		models.SendSuccess(ctx, http.StatusOK, "fetching")
+	case errors.Is(err, sources.NoSuchEntryError("")):
+		models.SendError(ctx, http.StatusNotFound, err)
+	default:
+		models.SendError(ctx, http.StatusBadRequest, err)
+	}
+}
+
+// sourceAuth extracts the authentication settings of a source.
+func sourceAuth(src *source) (*sources.Auth, error) {
+	var auth sources.Auth
+	if src.Auth != nil {
+		mode, err := sources.ParseAuthMode(*src.Auth)
+		if err != nil {
+			return nil, err
+		}
+		auth.Mode = mode
+	}
+	auth.TokenURL = src.OAuth2TokenURL
+	auth.ClientID = src.OAuth2ClientID
+	auth.Scopes = src.OAuth2Scopes
+	if src.BearerToken != nil {
+		auth.BearerToken = []byte(*src.BearerToken)
+	}
+	if src.OAuth2ClientSecret != nil {
+		auth.ClientSecret = []byte(*src.OAuth2ClientSecret)
+	}
+	return &auth, nil
+}
+
 func validateHeaders(headers []string) error {
 	for _, header := range headers {
 		if k, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(k) == "" {
@@ -694,6 +858,7 @@ func (c *Controller) createFeed(ctx *gin.Context) {
 		Label    string `form:"label" binding:"required,min=1"`
 		URL      string `form:"url" binding:"required,url"`
 		LogLevel string `form:"log_level" binding:"oneof=debug info warn error ''"`
+		Schedule string `form:"schedule"`
 	}
 	input := inputForm{}
 	if err := errors.Join(ctx.ShouldBind(&input), ctx.ShouldBindUri(&input)); err != nil {
@@ -706,12 +871,18 @@ func (c *Controller) createFeed(ctx *gin.Context) {
 	} else {
 		logLevel, _ = config.ParseFeedLogLevel(input.LogLevel)
 	}
+	sched, err := sources.AsSchedule(&input.Schedule)
+	if err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
 	parsed, _ := url.Parse(input.URL)
 	switch feedID, err := c.sm.AddFeed(
 		input.SourceID,
 		input.Label,
 		parsed,
 		logLevel,
+		sched,
 	); {
 	case err == nil:
 		ctx.JSON(http.StatusCreated, models.ID{ID: feedID})
@@ -765,6 +936,16 @@ func (c *Controller) updateFeed(ctx *gin.Context) {
 				return err
 			}
 		}
+		// schedule
+		if value, ok := ctx.GetPostForm("schedule"); ok {
+			sched, err := sources.AsSchedule(&value)
+			if err != nil {
+				return err
+			}
+			if err := fu.UpdateSchedule(sched); err != nil {
+				return err
+			}
+		}
 		return nil
 	}); {
 	case err == nil:
@@ -785,6 +966,32 @@ func (c *Controller) updateFeed(ctx *gin.Context) {
 	}
 }
 
+// fetchFeed is an endpoint that fetches a feed bypassing its schedule.
+//
+//	@Summary		Fetches a feed now.
+//	@Description	Refreshes the feed with the specified ID and downloads the new entries bypassing the schedules.
+//	@Param			id	path	int	true	"Feed ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Router			/sources/feeds/{id}/fetch [post]
+func (c *Controller) fetchFeed(ctx *gin.Context) {
+	id, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	switch err := c.sm.FetchFeed(id); {
+	case err == nil:
+		models.SendSuccess(ctx, http.StatusOK, "fetching")
+	case errors.Is(err, sources.NoSuchEntryError("")):
+		models.SendError(ctx, http.StatusNotFound, err)
+	default:
+		models.SendError(ctx, http.StatusBadRequest, err)
+	}
+}
+
 // viewFeed is an endpoint that returns the specified feed.
 //
 //	@Summary		Returns feed.
@@ -880,6 +1087,85 @@ func (c *Controller) feedLog(ctx *gin.Context) {
 	c.feedLogs(ctx, &feedID)
 }
 
+// queuedDownload is a pending download of a feed.
+type queuedDownload struct {
+	URL     string    `json:"url"`
+	Updated time.Time `json:"updated"`
+	State   string    `json:"state"`
+	Retries int       `json:"retries"`
+	Message *string   `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// feedQueue is an endpoint that returns the download queue of a feed.
+//
+//	@Summary		Returns the download queue.
+//	@Description	Returns the downloads of the specified feed which are waiting, running or failed.
+//	@Param			id		path	int		true	"Feed ID"
+//	@Param			state	query	string	false	"State (waiting, running, failed)"
+//	@Produce		json
+//	@Success		200	{array}		queuedDownload
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error	"not found"
+//	@Failure		500	{object}	models.Error
+//	@Router			/sources/feeds/{id}/queue [get]
+func (c *Controller) feedQueue(ctx *gin.Context) {
+	feedID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	const (
+		existsSQL = `SELECT EXISTS(SELECT 1 FROM feeds WHERE id = $1)`
+		queueSQL  = `SELECT url, updated, state::text, retries, message, time ` +
+			`FROM download_queue ` +
+			`WHERE feeds_id = $1 AND ($2::download_state IS NULL OR state = $2::download_state) ` +
+			`ORDER BY updated DESC`
+	)
+	var state *string
+	if s, ok := ctx.GetQuery("state"); ok {
+		switch s {
+		case "waiting", "running", "failed":
+		default:
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid state")
+			return
+		}
+		state = &s
+	}
+	var (
+		exists bool
+		queue  []queuedDownload
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			if err := conn.QueryRow(rctx, existsSQL, feedID).Scan(&exists); err != nil || !exists {
+				return err
+			}
+			rows, _ := conn.Query(rctx, queueSQL, feedID, state)
+			var err error
+			queue, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (queuedDownload, error) {
+				var qd queuedDownload
+				err := row.Scan(&qd.URL, &qd.Updated, &qd.State, &qd.Retries, &qd.Message, &qd.Time)
+				return qd, err
+			})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !exists {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "not found")
+		return
+	}
+	if queue == nil {
+		queue = []queuedDownload{}
+	}
+	ctx.JSON(http.StatusOK, queue)
+}
+
 // allFeedLog is an endpoint that returns all logs for all feeds.
 //
 //	@Summary		Returns all logs.
@@ -1076,6 +1362,18 @@ func (c *Controller) attentionSources(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, list)
 }
 
+// healthStats is an endpoint that returns the health of the sources and their feeds.
+//
+//	@Summary		Returns the health of the sources.
+//	@Description	Returns the health status, the score and the recent outcomes of all sources and their feeds.
+//	@Produce		json
+//	@Success		200	{array}		sources.SourceHealth
+//	@Failure		401
+//	@Router			/stats/health [get]
+func (c *Controller) healthStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.sm.Health())
+}
+
 // defaultSourceConfig returns the default source configuration.
 //
 //	@Summary		Returns the default configuration.
@@ -11,10 +11,12 @@ package web
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -26,9 +28,10 @@ import (
 // changeSSVC is an endpoint that changes the SSVC of the specified document.
 //
 //	@Summary		Changes the SSVC.
-//	@Description	This updates the SSVC of the specified document.
+//	@Description	This updates the SSVC of the specified document. The decision is computed from the decision table of the model.
 //	@Param			document	path	int		true	"Document ID"
 //	@Param			vector		query	string	true	"SSVC vector"
+//	@Param			model		query	string	false	"SSVC decision model"
 //	@Produce		json
 //	@Success		200	{object}	models.Success
 //	@Failure		400	{object}	models.Error
@@ -43,8 +46,13 @@ func (c *Controller) changeSSVC(ctx *gin.Context) {
 		return
 	}
 
-	vector := ctx.DefaultQuery("vector", "")
-	if err := models.ValidateSSVCv2Vector(vector); err != nil {
+	model := c.ssvc.Model(ctx.Query("model"))
+	if model == nil {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "unknown SSVC model")
+		return
+	}
+	vector, err := model.Complete(ctx.DefaultQuery("vector", ""))
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -55,24 +63,24 @@ func (c *Controller) changeSSVC(ctx *gin.Context) {
 		//			`ON docs.advisories_id = ads.id ` +
 		//			`WHERE docs.id = $1`
 		// First part taken from above
-		findSSVC = `SELECT sh.ssvc, ads.tracking_id, ads.publisher, docs.tlp, ads.state::text ` +
+		findSSVC = `SELECT sh.ssvc, sh.model, ads.id, ads.tracking_id, ads.publisher, docs.tlp, ads.state::text ` +
 			`FROM documents docs JOIN advisories ads ` +
 			`ON docs.advisories_id = ads.id ` +
 			// LEFT JOIN so we just get an empty ssvc if there is none in the history
 			// LATERAL so immediately the latest one is taken
 			// Find ssvc from ssvc_history instead
 			`LEFT JOIN LATERAL ` +
-			`(SELECT ssvc FROM ssvc_history ` +
+			`(SELECT ssvc, model FROM ssvc_history ` +
 			// find document
 			// use latest with change number as last resort tiebreaker (unique)
 			`WHERE documents_id = docs.id ORDER BY changedate DESC, change_number DESC LIMIT 1) ` +
-			`sh ON true WHERE docs.id = $1;`
+			`sh ON true WHERE docs.id = $1 AND docs.deleted IS NULL;`
 		switchToAssessing = `UPDATE advisories SET state = 'assessing' ` +
 			`WHERE (tracking_id, publisher) = ($1, $2)`
 		insertLog = `INSERT INTO events_log (event, state, actor, documents_id) ` +
 			`VALUES ($1::events, $2::workflow, $3, $4)`
-		updateSSVC = `INSERT INTO ssvc_history (actor, documents_id, ssvc) VALUES ` +
-			`($1::varchar, $2::integer, $3)`
+		updateSSVC = `INSERT INTO ssvc_history (actor, documents_id, ssvc, model) VALUES ` +
+			`($1::varchar, $2::integer, $3, $4)`
 	)
 
 	var forbidden, unchanged, bad bool
@@ -88,6 +96,8 @@ func (c *Controller) changeSSVC(ctx *gin.Context) {
 
 			var (
 				ssvc       sql.NullString
+				ssvcModel  sql.NullString
+				advisoryID int64
 				trackingID string
 				publisher  string
 				tlp        string
@@ -95,6 +105,8 @@ func (c *Controller) changeSSVC(ctx *gin.Context) {
 			)
 			if err := tx.QueryRow(rctx, findSSVC, documentID).Scan(
 				&ssvc,
+				&ssvcModel,
+				&advisoryID,
 				&trackingID,
 				&publisher,
 				&tlp,
@@ -104,13 +116,14 @@ func (c *Controller) changeSSVC(ctx *gin.Context) {
 			}
 
 			// check if we are allowed to do
-			if tlps := c.tlps(ctx); len(tlps) > 0 && !tlps.Allowed(publisher, models.TLP(tlp)) {
+			tlps := c.tlps(ctx)
+			if len(tlps) > 0 && !tlps.Allowed(publisher, models.TLP(tlp)) {
 				forbidden = true
 				return nil
 			}
 
 			// check if it's a real change
-			if ssvc.Valid && ssvc.String == vector {
+			if ssvc.Valid && ssvc.String == vector && ssvcModel.String == model.Name() {
 				unchanged = true
 				return nil
 			}
@@ -146,9 +159,28 @@ func (c *Controller) changeSSVC(ctx *gin.Context) {
 			}
 
 			// Now do the actual SSVC update.
-			if _, err := tx.Exec(rctx, updateSSVC, actor, documentID, vector); err != nil {
+			if _, err := tx.Exec(rctx, updateSSVC, actor, documentID, vector, model.Name()); err != nil {
+				return err
+			}
+
+			// The advisories in a case sharing the SSVC follow the change.
+			peers, err := loadCasePeers(rctx, tx, "ssvc", advisoryID)
+			if err != nil {
+				return err
+			}
+			following, err := models.SSVCPeers(peers, tlps)
+			if errors.Is(err, models.ErrPeerForbidden) {
+				forbidden = true
+				return nil
+			}
+			if err != nil {
 				return err
 			}
+			for _, peer := range following {
+				if _, err := tx.Exec(rctx, updateSSVC, actor, peer.DocumentID, vector, model.Name()); err != nil {
+					return err
+				}
+			}
 
 			return tx.Commit(rctx)
 		}, 0,
@@ -190,13 +222,13 @@ func (c *Controller) viewSSVC(ctx *gin.Context) {
 		return
 	}
 
-	const findSSVC = `SELECT sh.ssvc, ads.publisher, docs.tlp ` +
+	const findSSVC = `SELECT sh.ssvc, sh.model, ads.publisher, docs.tlp ` +
 		`FROM documents docs JOIN advisories ads ` +
 		`ON docs.advisories_id = ads.id ` +
 		`LEFT JOIN LATERAL ` +
-		`(SELECT ssvc FROM ssvc_history ` +
+		`(SELECT ssvc, model FROM ssvc_history ` +
 		`WHERE documents_id = docs.id ORDER BY changedate DESC, change_number DESC LIMIT 1) ` +
-		`sh ON true WHERE docs.id = $1`
+		`sh ON true WHERE docs.id = $1 AND docs.deleted IS NULL`
 
 	var (
 		forbidden bool
@@ -214,6 +246,7 @@ func (c *Controller) viewSSVC(ctx *gin.Context) {
 			)
 			if err := conn.QueryRow(rctx, findSSVC, documentID).Scan(
 				&ssvcdb,
+				&ssvc.Model,
 				&publisher,
 				&tlp,
 			); err != nil {
@@ -271,6 +304,7 @@ func (c *Controller) viewSSVCHistory(ctx *gin.Context) {
 		`JOIN advisories ads ON docs.advisories_id = ads.id ` +
 		`WHERE ads.publisher = $1 ` +
 		`AND ads.tracking_id = $2 ` +
+		`AND docs.deleted IS NULL ` +
 		`ORDER BY docs.id ` +
 		`) ` +
 		`SELECT publisher, tlp ` +
@@ -284,8 +318,9 @@ func (c *Controller) viewSSVCHistory(ctx *gin.Context) {
 		`JOIN advisories ads ON docs.advisories_id = ads.id ` +
 		`WHERE ads.publisher = $1 ` +
 		`AND ads.tracking_id = $2 ` +
+		`AND docs.deleted IS NULL ` +
 		`) ` +
-		`SELECT h.ssvc, h.changedate, h.change_number, h.actor, h.documents_id, ad.version ` +
+		`SELECT h.ssvc, h.model, h.changedate, h.change_number, h.actor, h.documents_id, ad.version ` +
 		`FROM ssvc_history h ` +
 		`JOIN advisory_docs ad ON h.documents_id = ad.id ` +
 		`ORDER BY h.documents_id ASC, h.changedate DESC, h.change_number DESC;`
@@ -320,6 +355,7 @@ func (c *Controller) viewSSVCHistory(ctx *gin.Context) {
 				var entry models.SSVCHistoryEntry
 				err := row.Scan(
 					&entry.SSVC,
+					&entry.Model,
 					&entry.ChangeDate,
 					&entry.ChangeNumber,
 					&entry.Actor,
@@ -352,6 +388,225 @@ func (c *Controller) viewSSVCHistory(ctx *gin.Context) {
 	}
 }
 
+// viewSSVCModels is an endpoint that returns the available SSVC decision models.
+//
+//	@Summary		Returns the SSVC decision models.
+//	@Description	Returns the names, titles and versions of the SSVC decision models and which one is the default.
+//	@Produce		json
+//	@Success		200	{array}		models.SSVCModelInfo
+//	@Failure		401	{object}	models.Error
+//	@Router			/ssvc/models [get]
+func (c *Controller) viewSSVCModels(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.ssvc.Infos())
+}
+
+// viewSSVCModel is an endpoint that returns a SSVC decision model.
+//
+//	@Summary		Returns a SSVC decision model.
+//	@Description	Returns the decision points and the decision table of the SSVC decision model.
+//	@Produce		json
+//	@Param			name	path		string	true	"Model name"
+//	@Success		200		{object}	any
+//	@Failure		401		{object}	models.Error
+//	@Failure		404		{object}	models.Error
+//	@Router			/ssvc/models/{name} [get]
+func (c *Controller) viewSSVCModel(ctx *gin.Context) {
+	model := c.ssvc.Model(ctx.Param("name"))
+	if model == nil {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "SSVC model not found")
+		return
+	}
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", model.Data())
+}
+
+// suggestSSVC is an endpoint that proposes a SSVC vector for the specified document.
+//
+//	@Summary		Suggests a SSVC vector.
+//	@Description	Proposes the decision points derivable from the document and the KEV catalogue and stores the suggestion.
+//	@Param			document	path	int		true	"Document ID"
+//	@Param			model		query	string	false	"SSVC decision model"
+//	@Produce		json
+//	@Success		200	{object}	models.SSVCSuggestion
+//	@Failure		400	{object}	models.Error
+//	@Failure		401	{object}	models.Error
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/ssvc/suggest/{document} [post]
+func (c *Controller) suggestSSVC(ctx *gin.Context) {
+	documentID, ok := parse(ctx, toInt64, ctx.Param("document"))
+	if !ok {
+		return
+	}
+	model := c.ssvc.Model(ctx.Query("model"))
+	if model == nil {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "unknown SSVC model")
+		return
+	}
+
+	const (
+		findSQL = `SELECT docs.original, ads.publisher, docs.tlp ` +
+			`FROM documents docs JOIN advisories ads ` +
+			`ON docs.advisories_id = ads.id ` +
+			`WHERE docs.id = $1 AND docs.deleted IS NULL`
+		kevSQL = `SELECT kev.cve FROM documents_cves dc ` +
+			`JOIN unique_cves uc ON dc.cve_id = uc.id ` +
+			`JOIN kev ON kev.cve = uc.cve ` +
+			`WHERE dc.documents_id = $1`
+		storeSQL = `INSERT INTO ssvc_suggestions (documents_id, model, ssvc, rationale, created) ` +
+			`VALUES ($1, $2, $3, $4, $5) ` +
+			`ON CONFLICT (documents_id) DO UPDATE SET ` +
+			`model = $2, ssvc = $3, rationale = $4, created = $5`
+	)
+
+	var (
+		forbidden  bool
+		suggestion *models.SSVCSuggestion
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var (
+				data      []byte
+				publisher string
+				tlp       string
+			)
+			if err := conn.QueryRow(rctx, findSQL, documentID).Scan(
+				&data,
+				&publisher,
+				&tlp,
+			); err != nil {
+				return err
+			}
+			if tlps := c.tlps(ctx); len(tlps) > 0 && !tlps.Allowed(publisher, models.TLP(tlp)) {
+				forbidden = true
+				return nil
+			}
+			// The texts of the stored document are replaced by
+			// indices so the vulnerabilities are taken from the original.
+			var doc struct {
+				Vulnerabilities []models.SSVCVulnerability `json:"vulnerabilities"`
+			}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				return fmt.Errorf("parsing vulnerabilities failed: %w", err)
+			}
+			rows, _ := conn.Query(rctx, kevSQL, documentID)
+			cves, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return fmt.Errorf("fetching KEV entries failed: %w", err)
+			}
+			kev := make(models.KEV, len(cves))
+			for _, cve := range cves {
+				kev[cve] = struct{}{}
+			}
+			suggestion = model.Suggest(doc.Vulnerabilities, kev, time.Now())
+			rationale, err := json.Marshal(suggestion.Rationale)
+			if err != nil {
+				return err
+			}
+			_, err = conn.Exec(rctx, storeSQL,
+				documentID, suggestion.Model, suggestion.SSVC, rationale, suggestion.Created)
+			return err
+		}, 0,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			models.SendErrorMessage(ctx, http.StatusNotFound, "document not found")
+		} else {
+			slog.Error("database error", "err", err)
+			models.SendError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	if forbidden {
+		models.SendErrorMessage(ctx, http.StatusForbidden, "access denied")
+		return
+	}
+	ctx.JSON(http.StatusOK, suggestion)
+}
+
+// viewSSVCSuggestion is an endpoint that returns the stored SSVC suggestion of the specified document.
+//
+//	@Summary		Returns the SSVC suggestion.
+//	@Description	Returns the last SSVC vector suggested for the document with its rationale.
+//	@Param			document	path	int	true	"Document ID"
+//	@Produce		json
+//	@Success		200	{object}	models.SSVCSuggestion
+//	@Failure		400	{object}	models.Error
+//	@Failure		401	{object}	models.Error
+//	@Failure		403	{object}	models.Error
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/ssvc/suggest/{document} [get]
+func (c *Controller) viewSSVCSuggestion(ctx *gin.Context) {
+	documentID, ok := parse(ctx, toInt64, ctx.Param("document"))
+	if !ok {
+		return
+	}
+
+	const findSQL = `SELECT s.model, s.ssvc, s.rationale, s.created, ads.publisher, docs.tlp ` +
+		`FROM documents docs JOIN advisories ads ` +
+		`ON docs.advisories_id = ads.id ` +
+		`LEFT JOIN ssvc_suggestions s ON s.documents_id = docs.id ` +
+		`WHERE docs.id = $1 AND docs.deleted IS NULL`
+
+	var (
+		forbidden  bool
+		suggestion *models.SSVCSuggestion
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			var (
+				model     sql.NullString
+				ssvc      sql.NullString
+				rationale []byte
+				created   sql.NullTime
+				publisher string
+				tlp       string
+			)
+			if err := conn.QueryRow(rctx, findSQL, documentID).Scan(
+				&model,
+				&ssvc,
+				&rationale,
+				&created,
+				&publisher,
+				&tlp,
+			); err != nil {
+				return err
+			}
+			if tlps := c.tlps(ctx); len(tlps) > 0 && !tlps.Allowed(publisher, models.TLP(tlp)) {
+				forbidden = true
+				return nil
+			}
+			if !model.Valid {
+				return nil
+			}
+			suggestion = &models.SSVCSuggestion{
+				Model:   model.String,
+				SSVC:    ssvc.String,
+				Created: created.Time.UTC(),
+			}
+			return json.Unmarshal(rationale, &suggestion.Rationale)
+		}, 0,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			models.SendErrorMessage(ctx, http.StatusNotFound, "document not found")
+		} else {
+			slog.Error("database error", "err", err)
+			models.SendError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	switch {
+	case forbidden:
+		models.SendErrorMessage(ctx, http.StatusForbidden, "access denied")
+	case suggestion == nil:
+		models.SendErrorMessage(ctx, http.StatusNotFound, "no suggestion found")
+	default:
+		ctx.JSON(http.StatusOK, suggestion)
+	}
+}
+
 // buildSSVCChange turns an array of SSVCHistoryEntry's into an Array of SSVCChange's by looking up the last ssvc if it's not the oldest entry.
 func buildSSVCChange(history []models.SSVCHistoryEntry) []models.SSVCChange {
 	var changes []models.SSVCChange
@@ -0,0 +1,184 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/config"
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// syncPeerStatus is the synchronization status of a peer.
+type syncPeerStatus struct {
+	Name      string     `json:"name"`
+	LastEvent int64      `json:"last_event"`
+	LastSync  *time.Time `json:"last_sync,omitempty"`
+	LastError *string    `json:"last_error,omitempty"`
+}
+
+// authPeer checks if the request is done by a configured peer.
+func (c *Controller) authPeer(ctx *gin.Context) {
+	token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if ok && token != "" {
+		for i := range c.cfg.Sync.Peers {
+			peer := &c.cfg.Sync.Peers[i]
+			if peer.PeerToken != "" &&
+				subtle.ConstantTimeCompare([]byte(peer.PeerToken), []byte(token)) == 1 {
+				ctx.Set("peer", peer)
+				ctx.Next()
+				return
+			}
+		}
+	}
+	ctx.AbortWithStatus(http.StatusUnauthorized)
+}
+
+// syncChanges is an endpoint that returns the changes feed for a peer.
+//
+//	@Summary		Returns the changes feed.
+//	@Description	Returns the local changes of state, SSVC and comments after a given event for a peer.
+//	@Param			since		query	int	false	"Last event already fetched"
+//	@Param			since_xact	query	int	false	"Transaction of the last event already fetched"
+//	@Param			limit		query	int	false	"Maximal number of changes"
+//	@Produce		json
+//	@Success		200	{array}		models.SyncChange
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/sync/changes [get]
+func (c *Controller) syncChanges(ctx *gin.Context) {
+	peer := ctx.MustGet("peer").(*config.SyncPeer)
+
+	since, ok := parse(ctx, toInt64, ctx.DefaultQuery("since", "0"))
+	if !ok {
+		return
+	}
+	sinceXact, ok := parse(ctx, toInt64, ctx.DefaultQuery("since_xact", "0"))
+	if !ok {
+		return
+	}
+	limit, ok := parse(ctx, strconv.Atoi, ctx.DefaultQuery("limit",
+		strconv.Itoa(c.cfg.Sync.BatchSize)))
+	if !ok {
+		return
+	}
+	limit = max(1, min(limit, c.cfg.Sync.BatchSize))
+
+	// The peer only gets what its TLP rules allow.
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(peer.PublishersTLPs.AsExpr())
+
+	events := make([]string, len(models.SyncEvents))
+	for i, event := range models.SyncEvents {
+		events[i] = string(event)
+	}
+
+	n := len(builder.Replacements)
+	fetchSQL := `SELECT ` +
+		`events_log.id, events_log.xact::text::bigint, event::text, events_log.time, actor, events_log.state::text, ` +
+		`advisories.publisher, advisories.tracking_id, documents.version, ` +
+		`sh.ssvc, sh.model, ` +
+		`comments.id, comments.parent_id, comments.message ` +
+		`FROM events_log ` +
+		`JOIN documents ON events_log.documents_id = documents.id ` +
+		`JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`LEFT JOIN comments ON events_log.comments_id = comments.id ` +
+		`LEFT JOIN LATERAL (SELECT ssvc, model FROM ssvc_history ` +
+		`WHERE event IN ('add_sscv', 'change_sscv') ` +
+		`AND ssvc_history.documents_id = events_log.documents_id ` +
+		`AND changedate <= events_log.time ` +
+		`ORDER BY change_number DESC LIMIT 1) sh ON true ` +
+		// Changes coming from peers are not passed on.
+		`WHERE events_log.origin IS NULL AND documents.deleted IS NULL ` +
+		fmt.Sprintf(`AND event = ANY($%d::events[]) `, n+1) +
+		fmt.Sprintf(`AND (events_log.xact, events_log.id) > ($%d::bigint::text::xid8, $%d) `, n+2, n+3) +
+		// Only the events of finished transactions are final.
+		// Running transactions may add events with lower ids.
+		`AND events_log.xact < pg_snapshot_xmin(pg_current_snapshot()) AND ` +
+		builder.WhereClause +
+		fmt.Sprintf(` ORDER BY events_log.xact, events_log.id LIMIT $%d`, n+4)
+
+	var changes []models.SyncChange
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, fetchSQL,
+				append(builder.Replacements, events, sinceXact, since, limit)...)
+			var err error
+			changes, err = pgx.CollectRows(
+				rows,
+				func(row pgx.CollectableRow) (models.SyncChange, error) {
+					var sc models.SyncChange
+					var event string
+					err := row.Scan(
+						&sc.ID, &sc.Xact, &event, &sc.Time, &sc.Actor, &sc.State,
+						&sc.Publisher, &sc.TrackingID, &sc.Version,
+						&sc.SSVC, &sc.SSVCModel,
+						&sc.CommentID, &sc.ParentID, &sc.Message)
+					sc.Event = models.Event(event)
+					sc.Time = sc.Time.UTC()
+					return sc, err
+				})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, changes)
+}
+
+// viewSyncPeers is an endpoint that returns the synchronization status of the peers.
+//
+//	@Summary		Returns the peers.
+//	@Description	Returns the synchronization status of the peers.
+//	@Produce		json
+//	@Success		200	{array}		syncPeerStatus
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/sync/peers [get]
+func (c *Controller) viewSyncPeers(ctx *gin.Context) {
+	const fetchSQL = `SELECT name, last_event, last_sync, last_error ` +
+		`FROM sync_peers ORDER BY name`
+	var peers []syncPeerStatus
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, fetchSQL)
+			var err error
+			peers, err = pgx.CollectRows(
+				rows,
+				func(row pgx.CollectableRow) (syncPeerStatus, error) {
+					var ps syncPeerStatus
+					err := row.Scan(&ps.Name, &ps.LastEvent, &ps.LastSync, &ps.LastError)
+					return ps, err
+				})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, peers)
+}
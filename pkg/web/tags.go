@@ -0,0 +1,382 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// colourRe is the allowed form of the colour of a tag.
+var colourRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// errUnknownTag is returned if a tag is not defined.
+var errUnknownTag = errors.New("unknown tag")
+
+type tagDefinition struct {
+	Name        string  `json:"name"`
+	Colour      *string `json:"colour,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type advisoryTagChanges struct {
+	Advisories []models.AdvisoryKey `json:"advisories" binding:"required"`
+	Add        []string             `json:"add,omitempty"`
+	Remove     []string             `json:"remove,omitempty"`
+}
+
+// tagAdvisory adds or removes a tag of an advisory and logs the change
+// as an event of the latest document of the advisory.
+func tagAdvisory(
+	rctx context.Context,
+	tx pgx.Tx,
+	advisoryID int64,
+	tag string,
+	add bool,
+	actor sql.NullString,
+) error {
+	const (
+		insertSQL = `INSERT INTO advisory_tags (advisories_id, tag) ` +
+			`VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		deleteSQL = `DELETE FROM advisory_tags WHERE advisories_id = $1 AND tag = $2`
+		eventSQL  = `INSERT INTO events_log (event, actor, documents_id, tag) ` +
+			`SELECT $1::events, $2, id, $3 FROM documents ` +
+			`WHERE advisories_id = $4 AND latest`
+	)
+	changeSQL, event := deleteSQL, models.RemoveTagEvent
+	if add {
+		changeSQL, event = insertSQL, models.AddTagEvent
+	}
+	tags, err := tx.Exec(rctx, changeSQL, advisoryID, tag)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("%w: %q", errUnknownTag, tag)
+		}
+		return err
+	}
+	// Only log real changes.
+	if tags.RowsAffected() == 0 {
+		return nil
+	}
+	_, err = tx.Exec(rctx, eventSQL, string(event), actor, tag, advisoryID)
+	return err
+}
+
+// logTagCascade logs the change of a tag definition as an event of
+// the latest documents of all advisories labeled with the tag.
+func logTagCascade(
+	rctx context.Context,
+	tx pgx.Tx,
+	tag string,
+	event models.Event,
+	logged string,
+	actor sql.NullString,
+) error {
+	const eventSQL = `INSERT INTO events_log (event, actor, documents_id, tag) ` +
+		`SELECT $1::events, $2, documents.id, $3 FROM documents ` +
+		`JOIN advisory_tags ON documents.advisories_id = advisory_tags.advisories_id ` +
+		`WHERE advisory_tags.tag = $4 AND documents.latest`
+	_, err := tx.Exec(rctx, eventSQL, string(event), actor, logged, tag)
+	return err
+}
+
+// sendTagError sends the error of changing the tags of advisories.
+func sendTagError(ctx *gin.Context, err error) {
+	if errors.Is(err, errUnknownTag) {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	sendTriageError(ctx, err)
+}
+
+// tagForm extracts the colour and the description of a tag from the form.
+func tagForm(ctx *gin.Context) (colour, description *string, ok bool) {
+	if v := ctx.PostForm("colour"); v != "" {
+		if !colourRe.MatchString(v) {
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid colour")
+			return nil, nil, false
+		}
+		colour = &v
+	}
+	if v := ctx.PostForm("description"); v != "" {
+		description = &v
+	}
+	return colour, description, true
+}
+
+// viewTagDefinitions returns the defined tags.
+//
+//	@Summary		Returns the tag definitions.
+//	@Description	Returns the tags the advisories can be labeled with.
+//	@Produce		json
+//	@Success		200	{array}	web.tagDefinition
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/tags [get]
+func (c *Controller) viewTagDefinitions(ctx *gin.Context) {
+	const fetchSQL = `SELECT name, colour, description FROM tags ORDER BY name`
+	var list []tagDefinition
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, fetchSQL)
+			var err error
+			list, err = pgx.CollectRows(rows, pgx.RowToStructByPos[tagDefinition])
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("fetching tags failed", "error", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if list == nil {
+		list = []tagDefinition{}
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// createTagDefinition defines a new tag.
+//
+//	@Summary		Defines a tag.
+//	@Description	Defines a new tag the advisories can be labeled with.
+//	@Param			name		formData	string	true	"Name"
+//	@Param			colour		formData	string	false	"Colour as #rrggbb"
+//	@Param			description	formData	string	false	"Description"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		201	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/tags [post]
+func (c *Controller) createTagDefinition(ctx *gin.Context) {
+	name := ctx.PostForm("name")
+	if !tagRe.MatchString(name) {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid tag")
+		return
+	}
+	colour, description, ok := tagForm(ctx)
+	if !ok {
+		return
+	}
+	const insertSQL = `INSERT INTO tags (name, colour, description) VALUES ($1, $2, $3)`
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			_, err := conn.Exec(rctx, insertSQL, name, colour, description)
+			return err
+		}, 0,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "tag already exists")
+		} else {
+			slog.Error("inserting tag failed", "error", err)
+			models.SendError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	models.SendSuccess(ctx, http.StatusCreated, "tag created")
+}
+
+// updateTagDefinition updates a defined tag.
+//
+//	@Summary		Updates a tag.
+//	@Description	Renames a tag and updates its colour and description. Renaming changes the labels of the advisories, too.
+//	@Param			tag			path		string	true	"Tag"
+//	@Param			name		formData	string	false	"New name"
+//	@Param			colour		formData	string	false	"Colour as #rrggbb"
+//	@Param			description	formData	string	false	"Description"
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/tags/{tag} [put]
+func (c *Controller) updateTagDefinition(ctx *gin.Context) {
+	tag := ctx.Param("tag")
+	name := ctx.DefaultPostForm("name", tag)
+	if !tagRe.MatchString(name) {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid tag")
+		return
+	}
+	colour, description, ok := tagForm(ctx)
+	if !ok {
+		return
+	}
+	const updateSQL = `UPDATE tags SET name = $1, colour = $2, description = $3 WHERE name = $4`
+	var found bool
+	actor := c.currentUser(ctx)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			// A rename relabels the advisories.
+			if name != tag {
+				if err := logTagCascade(rctx, tx, tag, models.RemoveTagEvent, tag, actor); err != nil {
+					return err
+				}
+				if err := logTagCascade(rctx, tx, tag, models.AddTagEvent, name, actor); err != nil {
+					return err
+				}
+			}
+			tags, err := tx.Exec(rctx, updateSQL, name, colour, description, tag)
+			if err != nil {
+				return err
+			}
+			if found = tags.RowsAffected() > 0; !found {
+				return nil
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "tag already exists")
+		} else {
+			slog.Error("updating tag failed", "error", err)
+			models.SendError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	if !found {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "tag not found")
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "tag updated")
+}
+
+// deleteTagDefinition deletes a defined tag.
+//
+//	@Summary		Deletes a tag.
+//	@Description	Deletes a tag and removes it from all advisories.
+//	@Param			tag	path	string	true	"Tag"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/tags/{tag} [delete]
+func (c *Controller) deleteTagDefinition(ctx *gin.Context) {
+	const deleteSQL = `DELETE FROM tags WHERE name = $1`
+	var (
+		found bool
+		tag   = ctx.Param("tag")
+		actor = c.currentUser(ctx)
+	)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			// The tag is removed from the advisories by cascade.
+			if err := logTagCascade(rctx, tx, tag, models.RemoveTagEvent, tag, actor); err != nil {
+				return err
+			}
+			tags, err := tx.Exec(rctx, deleteSQL, tag)
+			if err != nil {
+				return err
+			}
+			if found = tags.RowsAffected() > 0; !found {
+				return nil
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		slog.Error("deleting tag failed", "error", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !found {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "tag not found")
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "tag deleted")
+}
+
+// changeTagsBulk adds and removes tags of multiple advisories.
+//
+//	@Summary		Bulk changes tags.
+//	@Description	Adds and removes tags of multiple advisories. Either all changes are done or none.
+//	@Param			input	body	advisoryTagChanges	true	"Tag changes"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/advisories/tags [put]
+func (c *Controller) changeTagsBulk(ctx *gin.Context) {
+	var input advisoryTagChanges
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	for _, tag := range append(input.Add, input.Remove...) {
+		if !tagRe.MatchString(tag) {
+			models.SendErrorMessage(ctx, http.StatusBadRequest, "invalid tag")
+			return
+		}
+	}
+	actor := c.currentUser(ctx)
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tx, err := conn.BeginTx(rctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(rctx)
+			for _, key := range input.Advisories {
+				id, err := c.advisoryID(rctx, ctx, tx, key)
+				if err != nil {
+					return err
+				}
+				for _, tag := range input.Add {
+					if err := tagAdvisory(rctx, tx, id, tag, true, actor); err != nil {
+						return err
+					}
+				}
+				for _, tag := range input.Remove {
+					if err := tagAdvisory(rctx, tx, id, tag, false, actor); err != nil {
+						return err
+					}
+				}
+			}
+			return tx.Commit(rctx)
+		}, 0,
+	); err != nil {
+		sendTagError(ctx, err)
+		return
+	}
+	models.SendSuccess(ctx, http.StatusOK, "tags changed")
+}
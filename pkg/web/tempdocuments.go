@@ -20,7 +20,6 @@ import (
 	"github.com/ISDuBA/ISDuBA/pkg/models"
 	"github.com/ISDuBA/ISDuBA/pkg/tempstore"
 	"github.com/gin-gonic/gin"
-	"github.com/gocsaf/csaf/v3/csaf"
 )
 
 // importTempDocument is an endpoint that saves a temporary document.
@@ -57,7 +56,7 @@ func (c *Controller) importTempDocument(ctx *gin.Context) {
 		if err := json.NewDecoder(r).Decode(&document); err != nil {
 			return fmt.Errorf("decoding JSON failed: %w", err)
 		}
-		msgs, err := csaf.ValidateCSAF(document)
+		msgs, err := models.ValidateCSAF(document)
 		if err != nil {
 			return fmt.Errorf("schema validation failed: %w", err)
 		}
@@ -24,16 +24,13 @@ func extractTLPs(claims func(any) error, kc *ginkeycloak.KeycloakToken) error {
 	if err := claims(&wrapper); err != nil {
 		return err
 	}
-	// Merge multivalued attributes
+	// Merge multivalued attributes.
+	// Labels of the TLP 1 and TLP 2.0 vocabularies with
+	// the same meaning are only kept once.
 	tlps := models.PublishersTLPs{}
 	for _, tlp := range wrapper.TLP {
 		for key, value := range tlp {
-			_, ok := tlps[key]
-			if ok {
-				tlps[key] = append(tlps[key], value...)
-			} else {
-				tlps[key] = value
-			}
+			tlps[key] = models.MergeTLPs(tlps[key], value)
 		}
 	}
 	kc.CustomClaims = tlps
@@ -52,39 +52,45 @@ func (c *Controller) statsTotal(ctx *gin.Context) {
 	if imports {
 		documentsSQL = `SELECT count(*) FROM documents ` +
 			`JOIN downloads ON documents.id = downloads.documents_id ` +
-			`WHERE time <= $1`
+			`WHERE time <= $1 AND deleted IS NULL`
 		advisoriesSQL = `SELECT count(*) FROM documents ` +
 			`JOIN downloads ON documents.id = downloads.documents_id ` +
 			`WHERE time <= $1 AND latest = TRUE`
 	} else {
 		documentsSQL = `SELECT count(*) FROM documents ` +
-			`WHERE least(current_release_date, current_timestamp) <= $1`
+			`WHERE least(current_release_date, current_timestamp) <= $1 AND deleted IS NULL`
 		advisoriesSQL = `SELECT count(*) FROM documents ` +
 			`WHERE least(current_release_date, current_timestamp) <= $1 AND latest = TRUE`
 	}
 
+	const casesSQL = `SELECT count(*) FROM vulnerability_cases WHERE created <= $1`
+
 	list := [][]any{}
 
-	queries := func(when time.Time) (func(row pgx.Row) error, func(row pgx.Row) error) {
-		var numDocs, numAdvs int64
+	queries := func(when time.Time) (func(row pgx.Row) error, func(row pgx.Row) error, func(row pgx.Row) error) {
+		var numDocs, numAdvs, numCases int64
 		queryDocuments := func(row pgx.Row) error {
 			return row.Scan(&numDocs)
 		}
 		queryAdvisories := func(row pgx.Row) error {
-			if err := row.Scan(&numAdvs); err != nil {
+			return row.Scan(&numAdvs)
+		}
+		queryCases := func(row pgx.Row) error {
+			if err := row.Scan(&numCases); err != nil {
 				return err
 			}
-			list = append(list, []any{when.UTC(), numDocs, numAdvs})
+			list = append(list, []any{when.UTC(), numDocs, numAdvs, numCases})
 			return nil
 		}
-		return queryDocuments, queryAdvisories
+		return queryDocuments, queryAdvisories, queryCases
 	}
 
 	batch := &pgx.Batch{}
 	for when := from; !when.After(to); when = when.Add(step) {
-		docs, advs := queries(when)
+		docs, advs, cases := queries(when)
 		batch.Queue(documentsSQL, when).QueryRow(docs)
 		batch.Queue(advisoriesSQL, when).QueryRow(advs)
+		batch.Queue(casesSQL, when).QueryRow(cases)
 	}
 
 	if err := c.db.Run(
@@ -98,7 +104,7 @@ func (c *Controller) statsTotal(ctx *gin.Context) {
 			return tx.SendBatch(rctx, batch).Close()
 		}, 0,
 	); err != nil {
-		slog.Error("counting documents/advisories failed", "error", err)
+		slog.Error("counting documents/advisories/cases failed", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
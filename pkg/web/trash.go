@@ -0,0 +1,254 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSES/Apache-2.0.txt for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2026 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2026 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ISDuBA/ISDuBA/pkg/database/query"
+	"github.com/ISDuBA/ISDuBA/pkg/models"
+)
+
+// trashedDocument is a document in the trash bin.
+type trashedDocument struct {
+	ID         int64      `json:"id"`
+	Publisher  string     `json:"publisher"`
+	TrackingID string     `json:"tracking_id"`
+	Version    string     `json:"version"`
+	Title      *string    `json:"title,omitempty"`
+	TLP        *string    `json:"tlp,omitempty"`
+	Deleted    time.Time  `json:"deleted"`
+	DeletedBy  *string    `json:"deleted_by,omitempty"`
+	Comments   int64      `json:"comments"`
+	Purge      *time.Time `json:"purge,omitempty"`
+}
+
+// viewTrash is an endpoint that returns the documents in the trash bin.
+//
+//	@Summary		Returns the trash bin.
+//	@Description	Returns the deleted documents which are not purged yet.
+//	@Produce		json
+//	@Success		200	{array}		trashedDocument
+//	@Failure		401
+//	@Failure		500	{object}	models.Error
+//	@Router			/trash [get]
+func (c *Controller) viewTrash(ctx *gin.Context) {
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(c.andTLPExpr(ctx, query.True()))
+
+	fetchSQL := `SELECT ` +
+		`documents.id, advisories.publisher, advisories.tracking_id, ` +
+		`version, title, tlp, deleted, ` +
+		`(SELECT actor FROM events_log ` +
+		`WHERE event = 'delete_document' AND events_log.documents_id = documents.id ` +
+		`ORDER BY time DESC LIMIT 1), ` +
+		`(SELECT count(*) FROM comments WHERE comments.documents_id = documents.id) ` +
+		`FROM documents JOIN advisories ON documents.advisories_id = advisories.id ` +
+		`WHERE documents.deleted IS NOT NULL AND ` + builder.WhereClause +
+		` ORDER BY deleted DESC`
+
+	keep := c.cfg.General.KeepTrash
+
+	var trash []trashedDocument
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			rows, _ := conn.Query(rctx, fetchSQL, builder.Replacements...)
+			var err error
+			trash, err = pgx.CollectRows(
+				rows,
+				func(row pgx.CollectableRow) (trashedDocument, error) {
+					var td trashedDocument
+					err := row.Scan(
+						&td.ID, &td.Publisher, &td.TrackingID,
+						&td.Version, &td.Title, &td.TLP, &td.Deleted,
+						&td.DeletedBy,
+						&td.Comments)
+					td.Deleted = td.Deleted.UTC()
+					if keep > 0 {
+						purge := td.Deleted.Add(keep)
+						td.Purge = &purge
+					}
+					return td, err
+				})
+			return err
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, trash)
+}
+
+// restoreDocument is an endpoint that restores a document from the trash bin.
+//
+//	@Summary		Restores a document.
+//	@Description	Restores a document from the trash bin.
+//	@Param			id	path	int	true	"Document ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/trash/documents/{id} [put]
+func (c *Controller) restoreDocument(ctx *gin.Context) {
+	docID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	expr := c.andTLPExpr(ctx, query.FieldEqInt("id", docID))
+	switch restored, err := c.restore(ctx, expr); {
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+	case !restored:
+		models.SendErrorMessage(ctx, http.StatusNotFound, "document not found in trash")
+	default:
+		models.SendSuccess(ctx, http.StatusOK, "document restored")
+	}
+}
+
+// restoreAdvisory is an endpoint that restores the documents
+// of an advisory from the trash bin.
+//
+//	@Summary		Restores an advisory.
+//	@Description	Restores all documents of an advisory from the trash bin.
+//	@Param			publisher	path	string	true	"Publisher"
+//	@Param			trackingid	path	string	true	"Tracking ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/trash/advisory/{publisher}/{trackingid} [put]
+func (c *Controller) restoreAdvisory(ctx *gin.Context) {
+	var key models.AdvisoryKey
+	if err := ctx.ShouldBindUri(&key); err != nil {
+		models.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if key.Publisher == "" || key.TrackingID == "" {
+		models.SendErrorMessage(ctx, http.StatusBadRequest, "missing publisher or tracking_id")
+		return
+	}
+	expr := c.andTLPExpr(ctx,
+		query.FieldEqString("tracking_id", key.TrackingID).And(
+			query.FieldEqString("publisher", key.Publisher)))
+	switch restored, err := c.restore(ctx, expr); {
+	case err != nil:
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+	case !restored:
+		models.SendErrorMessage(ctx, http.StatusNotFound, "advisory not found in trash")
+	default:
+		models.SendSuccess(ctx, http.StatusOK, "advisory restored")
+	}
+}
+
+// restore takes the documents matching the given expression out of
+// the trash bin. It returns true if any documents are restored.
+func (c *Controller) restore(ctx *gin.Context, expr *query.Expr) (bool, error) {
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(expr)
+
+	restoreSQL := `WITH restored AS (` +
+		`UPDATE documents SET deleted = NULL ` +
+		`FROM advisories ` +
+		`WHERE advisories.id = documents.advisories_id ` +
+		`AND documents.deleted IS NOT NULL AND ` + builder.WhereClause +
+		` RETURNING documents.id) ` +
+		`INSERT INTO events_log (event, state, actor, documents_id) ` +
+		`SELECT 'restore_document'::events, ` +
+		`(SELECT state FROM advisories ads JOIN documents docs ` +
+		`ON ads.id = docs.advisories_id WHERE docs.id = restored.id), ` +
+		fmt.Sprintf(`$%d, id FROM restored`, len(builder.Replacements)+1)
+
+	var restored bool
+	err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tags, err := conn.Exec(rctx, restoreSQL,
+				append(builder.Replacements, c.currentUser(ctx))...)
+			if err != nil {
+				return fmt.Errorf("restoring failed: %w", err)
+			}
+			restored = tags.RowsAffected() > 0
+			return nil
+		}, 0,
+	)
+	return restored, err
+}
+
+// purgeDocument is an endpoint that permanently deletes a document
+// from the trash bin.
+//
+//	@Summary		Purges a document.
+//	@Description	Permanently deletes a document from the trash bin.
+//	@Param			id	path	int	true	"Document ID"
+//	@Produce		json
+//	@Success		200	{object}	models.Success
+//	@Failure		400	{object}	models.Error
+//	@Failure		401
+//	@Failure		404	{object}	models.Error
+//	@Failure		500	{object}	models.Error
+//	@Router			/trash/documents/{id} [delete]
+func (c *Controller) purgeDocument(ctx *gin.Context) {
+	docID, ok := parse(ctx, toInt64, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	builder := query.SQLBuilder{}
+	builder.CreateWhere(c.andTLPExpr(ctx, query.FieldEqInt("id", docID)))
+
+	// The documents_id of the event has to be NULL as the
+	// foreign key would not be valid any longer.
+	purgeSQL := `WITH purged AS (` +
+		`DELETE FROM documents USING advisories ` +
+		`WHERE advisories.id = documents.advisories_id ` +
+		`AND documents.deleted IS NOT NULL AND ` + builder.WhereClause +
+		` RETURNING documents.id) ` +
+		`INSERT INTO events_log (event, actor) ` +
+		fmt.Sprintf(`SELECT 'purge_document'::events, $%d FROM purged`,
+			len(builder.Replacements)+1)
+
+	var purged bool
+	if err := c.db.Run(
+		ctx.Request.Context(),
+		func(rctx context.Context, conn *pgxpool.Conn) error {
+			tags, err := conn.Exec(rctx, purgeSQL,
+				append(builder.Replacements, c.currentUser(ctx))...)
+			if err != nil {
+				return fmt.Errorf("purging failed: %w", err)
+			}
+			purged = tags.RowsAffected() > 0
+			return nil
+		}, 0,
+	); err != nil {
+		slog.Error("database error", "err", err)
+		models.SendError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if purged {
+		models.SendSuccess(ctx, http.StatusOK, "document purged")
+	} else {
+		models.SendErrorMessage(ctx, http.StatusNotFound, "document not found in trash")
+	}
+}